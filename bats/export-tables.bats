@@ -160,3 +160,150 @@ if rows[2] != "9,8,7,6,5,4".split(","):
     run dolt table import -u person_info export-csv.csv
     [ "$status" -eq 0 ]
 }
+
+@test "export a table to parquet and import it into a new table" {
+    dolt sql -q "insert into test_int values (0, 1, 2, 3, 4, 5), (1, null, 2, 3, 4, 5)"
+    run dolt table export test_int export.parquet
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully exported data." ]] || false
+    [ -f export.parquet ]
+
+    run dolt table import -c --pk pk test_int2 export.parquet
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt schema show test_int2
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`pk\` bigint NOT NULL" ]] || false
+    [[ "$output" =~ "\`c1\` bigint," ]] || false
+
+    run dolt sql -r csv -q "select * from test_int2 order by pk"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0,1,2,3,4,5" ]
+    [ "${lines[2]}" = "1,,2,3,4,5" ]
+}
+
+@test "export a table to jsonl and import it from stdin" {
+    dolt sql -q "insert into test_int values (0, 1, 2, 3, 4, 5), (1, null, 2, 3, 4, 5)"
+    run dolt table export test_int export.jsonl
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully exported data." ]] || false
+    run cat export.jsonl
+    [ "${lines[0]}" = '{"c1":1,"c2":2,"c3":3,"c4":4,"c5":5,"pk":0}' ]
+    [ "${lines[1]}" = '{"c2":2,"c3":3,"c4":4,"c5":5,"pk":1}' ]
+
+    dolt sql -q "delete from test_int"
+    run dolt table import -u --file-type jsonl test_int < export.jsonl
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -r csv -q "select * from test_int order by pk"
+    [ "${lines[1]}" = "0,1,2,3,4,5" ]
+    [ "${lines[2]}" = "1,,2,3,4,5" ]
+
+    echo '{"pk": 2, "c1": "not a number"}' >> export.jsonl
+    run dolt table import -u test_int export.jsonl
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "line 3:" ]] || false
+}
+
+@test "export the results of a query" {
+    dolt sql -q "insert into test_int values (0, 1, 2, 3, 4, 5), (1, 10, 2, 3, 4, 5), (2, 20, 2, 3, 4, 5)"
+    run dolt table export --query "select pk, c1 * 2 as doubled from test_int where c1 > 1 order by pk" export.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully exported data." ]] || false
+    run cat export.csv
+    [ "${lines[0]}" = "pk,doubled" ]
+    [ "${lines[1]}" = "1,20" ]
+    [ "${lines[2]}" = "2,40" ]
+    [ "${#lines[@]}" -eq 3 ]
+
+    run dolt table export -q "select count(*) as n from test_int" --file-type jsonl
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ '{"n":3}' ]] || false
+
+    run dolt table export -q "insert into test_int values (3, 1, 2, 3, 4, 5)" export2.csv
+    [ "$status" -eq 1 ]
+
+    run dolt table export -q "select * from test_int" export.sql
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot be exported to a sql file" ]] || false
+}
+
+@test "export a table as of a commit" {
+    dolt sql -q "insert into test_int values (0, 1, 2, 3, 4, 5)"
+    dolt add test_int
+    dolt commit -m "first row"
+    dolt sql -q "insert into test_int values (1, 1, 2, 3, 4, 5)"
+    dolt add test_int
+    dolt commit -m "second row"
+    dolt sql -q "delete from test_int"
+
+    run dolt table export --as-of HEAD~1 test_int export.csv
+    [ "$status" -eq 0 ]
+    run cat export.csv
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "0,1,2,3,4,5" ]
+
+    run dolt table export --as-of master -q "select count(*) as n from test_int" export2.csv
+    [ "$status" -eq 0 ]
+    run cat export2.csv
+    [ "${lines[1]}" = "2" ]
+
+    run dolt sql -q "select count(*) from test_int" -r csv
+    [ "${lines[1]}" = "0" ]
+
+    run dolt table export --as-of nonexistent test_int export3.csv
+    [ "$status" -eq 1 ]
+}
+
+@test "export a table to xlsx and import it into a new repository" {
+    dolt sql -q "insert into test_int values (0, 1, 2, 3, 4, 5), (1, 10, 2, 3, 4, 5)"
+    run dolt table export test_int export.xlsx
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully exported data." ]] || false
+    [ -f export.xlsx ]
+
+    mkdir other && cd other
+    dolt init
+    run dolt table import -c --pk pk test_int ../export.xlsx
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+    run dolt sql -r csv -q "select * from test_int order by pk"
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "0,1,2,3,4,5" ]
+    [ "${lines[2]}" = "1,10,2,3,4,5" ]
+}
+
+@test "export several tables to one xlsx workbook" {
+    dolt sql -q "insert into test_int values (0, 1, 2, 3, 4, 5)"
+    dolt sql -q "insert into test_string values ('a', 'b', 'c', 'd', 'e', 'f')"
+    dolt add .
+    dolt commit -m "rows"
+    head=$(dolt log -n 1 | head -n 1 | sed 's/^commit //' | tr -d '[:space:]')
+
+    run dolt table export test_int test_string export.xlsx
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Successfully exported data." ]] || false
+    run unzip -p export.xlsx xl/workbook.xml
+    [[ "$output" =~ 'name="test_int"' ]] || false
+    [[ "$output" =~ 'name="test_string"' ]] || false
+    run unzip -p export.xlsx docProps/core.xml
+    [[ "$output" =~ "<dc:identifier>$head</dc:identifier>" ]] || false
+    [[ "$output" =~ "Exported from dolt commit $head" ]] || false
+
+    dolt sql -q "delete from test_int"
+    run dolt table export -f test_int test_string export.xlsx
+    [ "$status" -eq 0 ]
+    run unzip -p export.xlsx docProps/core.xml
+    [[ "$output" =~ "<dc:identifier>$head</dc:identifier>" ]] || false
+    [[ "$output" =~ "uncommitted changes on top of commit $head" ]] || false
+
+    run dolt table export test_int test_string export2.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Only xlsx files can have more than one table exported to them" ]] || false
+
+    run dolt table export test_int not_a_table export3.xlsx
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "table not found" ]] || false
+    [ ! -f export3.xlsx ]
+}
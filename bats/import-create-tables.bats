@@ -529,3 +529,103 @@ DELIM
     # less than 10% smaller
     [ "$BEFORE" -lt $(($AFTER * 11 / 10)) ]
 }
+
+@test "table import -c computes fields with mapping file expressions" {
+    cat <<DELIM > people.csv
+full_name,email,age
+Tim Sehn,tim@example.com,40
+Aaron Son,aaron@example.com,17
+Zach Musgrave,zach@example.com,30
+DELIM
+    cat <<DELIM > map.json
+{
+    "first_name": "=SUBSTRING_INDEX(full_name, ' ', 1)",
+    "id": "=SHA1(email)",
+    "country": "='US'",
+    "\$where": "age >= 18"
+}
+DELIM
+
+    run dolt table import -c --pk=id -m map.json people people.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt schema show people
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`first_name\` longtext" ]] || false
+    [[ "$output" =~ "\`id\` longtext NOT NULL" ]] || false
+    [[ "$output" =~ "PRIMARY KEY (\`id\`)" ]] || false
+
+    run dolt sql -q "select first_name, country from people order by first_name" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "Tim,US" ]
+    [ "${lines[2]}" = "Zach,US" ]
+    [ "${#lines[@]}" -eq 3 ]
+
+    run dolt sql -q "select id = sha1('tim@example.com') from people where first_name = 'Tim'" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "true" ]
+}
+
+@test "table import -c fails on an invalid mapping file expression" {
+    cat <<DELIM > people.csv
+full_name,age
+Tim Sehn,40
+DELIM
+    echo '{"first_name": "=UPPER(name)"}' > map.json
+
+    run dolt table import -c --pk=full_name -m map.json people people.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "unknown field 'name'" ]] || false
+}
+
+@test "table import -c --parallel builds the table from sorted runs" {
+    echo "pk,c1" > data.csv
+    for i in $(seq 1000 -1 1); do echo "$i,$((i * 2))" >> data.csv; done
+    echo "500,dup" >> data.csv
+
+    run dolt table import -c --pk=pk --parallel test data.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "duplicate primary key" ]] || false
+
+    run dolt table import -c --pk=pk --parallel --continue test data.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Imported 1000 rows in" ]] || false
+    [[ "$output" =~ "rows/s), peak heap use" ]] || false
+    [[ "$output" =~ "duplicate key: 1" ]] || false
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt sql -q "select count(*), min(pk), max(pk) from test" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "1000,1,1000" ]
+}
+
+@test "table import --parallel is only supported when creating a table" {
+    echo "pk,c1" > data.csv
+    echo "1,2" >> data.csv
+    dolt table import -c --pk=pk test data.csv
+
+    run dolt table import -u --parallel test data.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "parallel is only supported when creating a table" ]] || false
+}
+
+@test "table import -c --sample-rows infers the schema from the first rows" {
+    echo "pk,c1" > data.csv
+    for i in $(seq 1 10); do echo "$i,$i" >> data.csv; done
+    echo "11,eleven" >> data.csv
+
+    run dolt table import -c --pk=pk --sample-rows 10 test data.csv
+    [ "$status" -eq 1 ]
+
+    run dolt table import -c --pk=pk --sample-rows 10 --continue test data.csv
+    [ "$status" -eq 0 ]
+    run dolt schema show test
+    [[ "$output" =~ "\`c1\` int unsigned" ]] || false
+    run dolt sql -q "select count(*) from test" -r csv
+    [ "${lines[1]}" = "10" ]
+
+    run dolt table import -u --sample-rows 10 test data.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "sample-rows is only supported when creating a table with an inferred schema" ]] || false
+}
@@ -0,0 +1,121 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    if ! command -v sqlite3 > /dev/null; then
+        skip "sqlite3 is not installed"
+    fi
+
+    setup_common
+    sqlite3 data.sqlite <<SQL
+CREATE TABLE people (
+  id INTEGER PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  email TEXT UNIQUE,
+  score REAL,
+  balance DECIMAL(10, 2)
+);
+CREATE INDEX people_name ON people (name);
+CREATE TABLE events (happened DATETIME, what TEXT);
+INSERT INTO people VALUES (1, 'tim', 'tim@example.com', 1.5, 12.25), (2, 'ann', NULL, NULL, NULL);
+INSERT INTO events VALUES ('2021-03-04 05:06:07', 'launch');
+SQL
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "import a table from a sqlite database" {
+    run dolt table import -c people data.sqlite
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt schema show people
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`id\` bigint NOT NULL" ]] || false
+    [[ "$output" =~ "\`name\` longtext NOT NULL" ]] || false
+    [[ "$output" =~ "\`score\` double" ]] || false
+    [[ "$output" =~ "\`balance\` decimal(10,2)" ]] || false
+    [[ "$output" =~ "PRIMARY KEY (\`id\`)" ]] || false
+    [[ "$output" =~ "UNIQUE KEY \`email\` (\`email\`)" ]] || false
+    [[ "$output" =~ "KEY \`people_name\` (\`name\`)" ]] || false
+
+    run dolt sql -q "select id, name, balance from people order by id" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,tim,12.25" ]] || false
+    [[ "$output" =~ "2,ann," ]] || false
+}
+
+@test "import a sqlite table with a mapping file keeps its indexes on the renamed columns" {
+    echo '{"id":"person_id","name":"full_name"}' > map.json
+    run dolt table import -c -m map.json people data.sqlite
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Import completed successfully." ]] || false
+
+    run dolt schema show people
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`person_id\` bigint NOT NULL" ]] || false
+    [[ "$output" =~ "\`full_name\` longtext NOT NULL" ]] || false
+    [[ "$output" =~ "PRIMARY KEY (\`person_id\`)" ]] || false
+    [[ "$output" =~ "UNIQUE KEY \`email\` (\`email\`)" ]] || false
+    [[ "$output" =~ "KEY \`people_name\` (\`full_name\`)" ]] || false
+
+    run dolt sql -q "select person_id, full_name from people where full_name = 'tim'" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,tim" ]] || false
+}
+
+@test "import a sqlite table with --source-table" {
+    run dolt table import -c logs data.sqlite --source-table events
+    [ "$status" -eq 0 ]
+
+    run dolt sql -q "select what from logs" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "launch" ]] || false
+
+    run dolt table import -c people2 data.sqlite --source-table missing
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "table 'missing' was not found in the sqlite database" ]] || false
+}
+
+@test "--source-table is only valid for sqlite databases" {
+    echo "id,name" > data.csv
+    run dolt table import -c people data.csv --source-table people
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "source-table is only supported when importing from a sqlite database" ]] || false
+}
+
+@test "dolt import-sqlite imports every table" {
+    run dolt import-sqlite data.sqlite
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Importing table events" ]] || false
+    [[ "$output" =~ "Importing table people" ]] || false
+
+    run dolt ls
+    [[ "$output" =~ "events" ]] || false
+    [[ "$output" =~ "people" ]] || false
+
+    run dolt sql -q "select count(*) from people" -r csv
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt schema show events
+    [[ "$output" =~ "\`happened\` datetime" ]] || false
+    [[ ! "$output" =~ "PRIMARY KEY" ]] || false
+}
+
+@test "dolt import-sqlite doesn't overwrite tables without -f" {
+    dolt sql -q "create table people (id int primary key)"
+    run dolt import-sqlite data.sqlite
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "people already exists. Use -f to overwrite." ]] || false
+
+    run dolt ls
+    [[ ! "$output" =~ "events" ]] || false
+
+    run dolt import-sqlite -f data.sqlite
+    [ "$status" -eq 0 ]
+    run dolt sql -q "select name from people where id = 1" -r csv
+    [[ "$output" =~ "tim" ]] || false
+}
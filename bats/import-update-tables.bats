@@ -188,4 +188,46 @@ DELIM
     [ "$status" -eq 0 ]
     [[ "$output" =~ "Rows Processed: 1, Additions: 1, Modifications: 0, Had No Effect: 0Lines skipped: 1" ]] || false
     [[ "$output" =~ "Import completed successfully." ]] || false
-}
\ No newline at end of file
+}
+@test "import-update-tables: --bad-rows writes rejected records and reports error classes" {
+    dolt sql -q "CREATE TABLE people (id int PRIMARY KEY, name varchar(20) NOT NULL, age int)"
+    cat <<DELIM > people.csv
+id,name,age
+1,tim,40
+2,aaron,notanint
+1,dup,20
+4,,22
+5,zach
+DELIM
+
+    run dolt table import -u --continue --bad-rows bad.csv people people.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Lines skipped: 4" ]] || false
+    [[ "$output" =~ "type conversion: 1" ]] || false
+    [[ "$output" =~ "null in a non-null column: 1" ]] || false
+    [[ "$output" =~ "duplicate key: 1" ]] || false
+    [[ "$output" =~ "malformed input: 1" ]] || false
+
+    run cat bad.csv
+    [ "$status" -eq 0 ]
+    [ "${lines[0]}" = "line,error,id,name,age" ]
+    [[ "$output" =~ '3,"Mapping transform: unable to cast ""notanint"" of type string to int64",2,aaron,notanint' ]] || false
+    [[ "$output" =~ "4,duplicate primary key given: (1),1,dup,20" ]] || false
+    [[ "$output" =~ "5,Mapping transform: invalid column: name (Not null constraint),4,,22" ]] || false
+    [[ "$output" =~ "6,\"csv reader's schema expects 3 fields" ]] || false
+
+    run dolt table import -u --continue --bad-rows bad.jsonl people people.csv
+    [ "$status" -eq 0 ]
+    run cat bad.jsonl
+    [[ "$output" =~ '{"age":"notanint","error":"Mapping transform: unable to cast \"notanint\" of type string to int64","id":"2","line":3,"name":"aaron"}' ]] || false
+}
+
+@test "import-update-tables: --bad-rows requires --continue and a csv or jsonl file" {
+    run dolt table import -u --bad-rows bad.csv test 1pk5col-ints.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "bad-rows can only be used with continue" ]] || false
+
+    run dolt table import -u --continue --bad-rows bad.txt test 1pk5col-ints.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "must be a .csv or .jsonl file" ]] || false
+}
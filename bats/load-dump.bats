@@ -0,0 +1,76 @@
+#!/usr/bin/env bats
+load $BATS_TEST_DIRNAME/helper/common.bash
+
+setup() {
+    setup_common
+    cat <<'SQL' > dump.sql
+-- MySQL dump 10.13  Distrib 8.0.23, for Linux (x86_64)
+--
+-- Host: localhost    Database: shop
+-- ------------------------------------------------------
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!50503 SET NAMES utf8mb4 */;
+/*!40014 SET @OLD_FOREIGN_KEY_CHECKS=@@FOREIGN_KEY_CHECKS, FOREIGN_KEY_CHECKS=0 */;
+
+CREATE DATABASE /*!32312 IF NOT EXISTS*/ `shop` /*!40100 DEFAULT CHARACTER SET utf8mb4 */;
+
+USE `shop`;
+
+DROP TABLE IF EXISTS `customers`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+CREATE TABLE `customers` (
+  `id` int NOT NULL,
+  `name` varchar(100) NOT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+LOCK TABLES `customers` WRITE;
+/*!40000 ALTER TABLE `customers` DISABLE KEYS */;
+INSERT INTO `customers` VALUES (1,'Ann'),(2,'Bob; the \'builder\''),(3,'Cy');
+/*!40000 ALTER TABLE `customers` ENABLE KEYS */;
+UNLOCK TABLES;
+SQL
+}
+
+teardown() {
+    assert_feature_version
+    teardown_common
+}
+
+@test "dolt load-dump loads a mysqldump file" {
+    run dolt load-dump dump.sql
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rows inserted: 3" ]] || false
+    [[ "$output" =~ "Skipped 6 statements" ]] || false
+    [[ "$output" =~ "2 statements (lines 10, 12): dumps are loaded into the current database" ]] || false
+    [[ "$output" =~ "2 statements (lines 23, 27): table locks aren't needed to load a dump" ]] || false
+    [[ "$output" =~ "2 statements (lines 24, 26): index maintenance can't be disabled" ]] || false
+
+    run dolt sql -q "select name from customers where id = 2" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Bob; the 'builder'" ]] || false
+}
+
+@test "dolt load-dump reads from stdin" {
+    run bash -c "dolt load-dump < dump.sql"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rows inserted: 3" ]] || false
+
+    run dolt sql -q "select count(*) from customers" -r csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+}
+
+@test "dolt load-dump stops at a failing statement" {
+    echo "INSERT INTO nope VALUES (1);" >> dump.sql
+    run dolt load-dump dump.sql
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Error loading dump" ]] || false
+    [[ "$output" =~ "error on line 28 for query INSERT INTO nope VALUES (1)" ]] || false
+    [[ "$output" =~ "table not found: nope" ]] || false
+
+    run dolt ls
+    [[ ! "$output" =~ "customers" ]] || false
+}
@@ -58,7 +58,7 @@ teardown() {
 @test "schema import dry run" {
     run dolt schema import --dry-run -c --pks=pk test 1pk5col-ints.csv
     [ "$status" -eq 0 ]
-    [ "${#lines[@]}" -eq 9 ]
+    [ "${#lines[@]}" -eq 16 ]
     [[ "${lines[0]}" =~ "test" ]] || false
     [[ "$output" =~ "\`pk\` int" ]] || false
     [[ "$output" =~ "\`c1\` int" ]] || false
@@ -75,7 +75,7 @@ teardown() {
 @test "schema import with a bunch of types" {
     run dolt schema import --dry-run -c --pks=pk test 1pksupportedtypes.csv
     [ "$status" -eq 0 ]
-    [ "${#lines[@]}" -eq 10 ]
+    [ "${#lines[@]}" -eq 18 ]
     [[ "${lines[0]}" =~ "test" ]] || false
     [[ "$output" =~ "\`pk\` int" ]] || false
     [[ "$output" =~ "\`int\` int" ]] || false
@@ -245,6 +245,74 @@ DELIM
     [[ "$output" =~ "\`c_date+time\` datetime" ]] || false
 }
 
+@test "schema import infers enums, decimals and other date layouts" {
+    echo "pk,status,price,signup,seen,clock" > inferred.csv
+    for i in `seq 0 29`; do
+        case $((i % 3)) in
+            0) status=active;;
+            1) status=inactive;;
+            2) status=banned;;
+        esac
+        echo "$i,$status,$i.$((i % 10))5,1/$((i + 1))/2021,2021-01-$((i % 9 + 10))T10:00:00,$((i % 12 + 1)):30 PM" >> inferred.csv
+    done
+
+    run dolt schema import --dry-run -c --pks=pk test inferred.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`status\` enum('active','banned','inactive')" ]] || false
+    [[ "$output" =~ "\`price\` decimal(4,2)" ]] || false
+    [[ "$output" =~ "\`signup\` date" ]] || false
+    [[ "$output" =~ "\`seen\` datetime" ]] || false
+    [[ "$output" =~ "\`clock\` time" ]] || false
+    [[ "$output" =~ "-- column types inferred from 30 rows:" ]] || false
+    [[ "$output" =~ "status ENUM('active','banned','inactive'): 3 distinct values in 30 rows" ]] || false
+    [[ "$output" =~ "price DECIMAL(4,2): every number has 2 digits after the decimal point" ]] || false
+    [[ "$output" =~ "signup DATE: every value is a date, in the layout 1/2/2006" ]] || false
+
+    run dolt schema import --dry-run -c --pks=pk --sample-rows 10 --date-layouts "2006-01-02T15:04:05" test inferred.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "\`status\` longtext" ]] || false
+    [[ "$output" =~ "\`signup\` longtext" ]] || false
+    [[ "$output" =~ "\`seen\` datetime" ]] || false
+    [[ "$output" =~ "-- column types inferred from 10 rows:" ]] || false
+    [[ "$output" =~ "too few values to tell whether it's an enum" ]] || false
+
+    run dolt schema import --dry-run -c --pks=pk --sample-rows 0 test inferred.csv
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--sample-rows must be a positive number of rows" ]] || false
+}
+
+@test "schema import then table import of other date layouts" {
+    cat <<DELIM > layouts.csv
+pk,signup,seen,clock,day
+1,1/2/2021,2021-01-10T10:00:00,3:30 PM,Jan 5 2021
+2,12/31/2020,2021-01-11T23:59:59,11:05:09 AM,Feb 14 2021
+DELIM
+
+    dolt schema import -c --pks=pk test layouts.csv
+    run dolt table import -u test layouts.csv
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Rows Processed: 2, Additions: 2, Modifications: 0, Had No Effect: 0" ]] || false
+
+    run dolt sql -r csv -q "select * from test order by pk"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,2021-01-02 00:00:00 +0000 UTC,2021-01-10 10:00:00 +0000 UTC,15:30:00,2021-01-05 00:00:00 +0000 UTC" ]] || false
+    [[ "$output" =~ "2,2020-12-31 00:00:00 +0000 UTC,2021-01-11 23:59:59 +0000 UTC,11:05:09,2021-02-14 00:00:00 +0000 UTC" ]] || false
+
+    cat <<DELIM > custom.csv
+pk,signup
+1,02.01.2021
+DELIM
+
+    dolt schema import -c --pks=pk --date-layouts "02.01.2006" custom custom.csv
+    run dolt table import -u custom custom.csv
+    [ "$status" -eq 1 ]
+    run dolt table import -u --date-layouts "02.01.2006" custom custom.csv
+    [ "$status" -eq 0 ]
+    run dolt sql -r csv -q "select * from custom"
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "1,2021-01-02 00:00:00 +0000 UTC" ]] || false
+}
+
 @test "schema import of two tables" {
     dolt schema import -c --pks=pk test1 1pksupportedtypes.csv
     dolt schema import -c --pks=pk test2 1pk5col-ints.csv
@@ -21,6 +21,7 @@ teardown() {
     [[ "$output" =~ "dolt_branches" ]] || false
     [[ "$output" =~ "dolt_query_catalog" ]] || false
     [[ "$output" =~ "dolt_status" ]] || false
+    [[ "$output" =~ "dolt_replication_status" ]] || false
     [[ ! "$output" =~ " test" ]] || false  # spaces are impt!
     run dolt ls --all
     [ $status -eq 0 ]
@@ -347,3 +348,9 @@ teardown() {
     [[ "$output" =~ "0,commit B" ]] || false
     [[ "$output" =~ "1,commit C" ]] || false
 }
+
+@test "dolt_replication_status is empty when the database isn't replicated" {
+    run dolt sql -q "SELECT count(*) FROM dolt_replication_status;" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "0" ]] || false
+}
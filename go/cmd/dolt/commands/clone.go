@@ -16,7 +16,6 @@ package commands
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -30,7 +29,6 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
-	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/remotestorage"
 	"github.com/dolthub/dolt/go/libraries/events"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
@@ -109,7 +107,7 @@ func (cmd CloneCmd) Exec(ctx context.Context, commandStr string, args []string,
 	branch := apr.GetValueOrDefault(branchParam, "")
 	dir, urlStr, verr := parseArgs(apr)
 
-	scheme, remoteUrl, err := getAbsRemoteUrl(dEnv.FS, dEnv.Config, urlStr)
+	scheme, remoteUrl, err := GetAbsRemoteUrl(dEnv.FS, dEnv.Config, urlStr)
 
 	if err != nil {
 		verr = errhand.BuildDError("error: '%s' is not valid.", urlStr).Build()
@@ -285,124 +283,14 @@ func cloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch s
 		cloneProg(eventCh)
 	}()
 
-	err := actions.Clone(ctx, srcDB, dEnv.DoltDB, eventCh)
+	err := actions.CloneRemote(ctx, srcDB, remoteName, branch, dEnv, eventCh)
 	close(eventCh)
 
 	wg.Wait()
 
 	if err != nil {
-		if err == datas.ErrNoData {
-			err = errors.New("remote at that url contains no Dolt data")
-		}
-
 		return errhand.BuildDError("error: clone failed").AddCause(err).Build()
 	}
 
-	branches, err := dEnv.DoltDB.GetBranches(ctx)
-	if err != nil {
-		return errhand.BuildDError("error: failed to list branches").AddCause(err).Build()
-	}
-
-	if branch == "" {
-		for _, brnch := range branches {
-			branch = brnch.GetPath()
-			if branch == doltdb.MasterBranch {
-				break
-			}
-		}
-	}
-
-	// If we couldn't find a branch but the repo cloned successfully, it's empty. Initialize it instead of pulling from
-	// the remote.
-	performPull := true
-	if branch == "" {
-		err = initEmptyClonedRepo(ctx, dEnv)
-		if err != nil {
-			return nil
-		}
-
-		branch = doltdb.MasterBranch
-		performPull = false
-	}
-
-	cs, _ := doltdb.NewCommitSpec(branch)
-	cm, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
-
-	if err != nil {
-		return errhand.BuildDError("error: could not get " + branch).AddCause(err).Build()
-	}
-
-	rootVal, err := cm.GetRootValue()
-	if err != nil {
-		return errhand.BuildDError("error: could not get the root value of " + branch).AddCause(err).Build()
-	}
-
-	// After actions.Clone, we have repository with a local branch for
-	// every branch in the remote. What we want is a remote branch ref for
-	// every branch in the remote. We iterate through local branches and
-	// create remote refs corresponding to each of them. We delete all of
-	// the local branches except for the one corresponding to |branch|.
-	for _, brnch := range branches {
-		cs, _ := doltdb.NewCommitSpec(brnch.GetPath())
-		cm, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
-		if err != nil {
-			return errhand.BuildDError("error: could not resolve branch ref at " + brnch.String()).AddCause(err).Build()
-		}
-
-		remoteRef := ref.NewRemoteRef(remoteName, brnch.GetPath())
-		err = dEnv.DoltDB.SetHeadToCommit(ctx, remoteRef, cm)
-		if err != nil {
-			return errhand.BuildDError("error: could not create remote ref at " + remoteRef.String()).AddCause(err).Build()
-		}
-
-		if brnch.GetPath() != branch {
-			err := dEnv.DoltDB.DeleteBranch(ctx, brnch)
-			if err != nil {
-				return errhand.BuildDError("error: could not delete local branch " + brnch.String() + " after clone.").AddCause(err).Build()
-			}
-		}
-	}
-
-	if performPull {
-		err = actions.SaveDocsFromRoot(ctx, rootVal, dEnv)
-		if err != nil {
-			return errhand.BuildDError("error: failed to update docs on the filesystem").AddCause(err).Build()
-		}
-	}
-
-	h, err := dEnv.DoltDB.WriteRootValue(ctx, rootVal)
-	if err != nil {
-		return errhand.BuildDError("error: could not write root value").AddCause(err).Build()
-	}
-
-	dEnv.RepoState.Head = ref.MarshalableRef{Ref: ref.NewBranchRef(branch)}
-	dEnv.RepoState.Staged = h.String()
-	dEnv.RepoState.Working = h.String()
-
-	err = dEnv.RepoState.Save(dEnv.FS)
-	if err != nil {
-		return errhand.BuildDError("error: failed to write repo state").AddCause(err).Build()
-	}
-
-	return nil
-}
-
-// Inits an empty, newly cloned repo. This would be unnecessary if we properly initialized the storage for a repository
-// when we created it on dolthub. If we do that, this code can be removed.
-func initEmptyClonedRepo(ctx context.Context, dEnv *env.DoltEnv) error {
-	name := dEnv.Config.GetStringOrDefault(env.UserNameKey, "")
-	email := dEnv.Config.GetStringOrDefault(env.UserEmailKey, "")
-
-	if *name == "" {
-		return errhand.BuildDError(fmt.Sprintf("error: could not determine user name. run dolt config --global --add %[1]s", env.UserNameKey)).Build()
-	} else if *email == "" {
-		return errhand.BuildDError("error: could not determine email. run dolt config --global --add %[1]s", env.UserEmailKey).Build()
-	}
-
-	err := dEnv.InitDBWithTime(ctx, types.Format_Default, *name, *email, doltdb.CommitNowFunc())
-	if err != nil {
-		return errhand.BuildDError("error: could not initialize repository").AddCause(err).Build()
-	}
-
 	return nil
 }
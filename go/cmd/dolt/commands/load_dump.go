@@ -0,0 +1,293 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/tracing"
+)
+
+var loadDumpDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Loads a mysqldump file into the working set`,
+	LongDesc: `Runs the statements of a dump written by {{.EmphasisLeft}}mysqldump{{.EmphasisRight}} against the current database, reading the dump from {{.LessThan}}file{{.GreaterThan}} or from stdin when no file is given.
+
+Statements that only make sense to a MySQL server are skipped: table locks, {{.EmphasisLeft}}ALTER TABLE ... DISABLE KEYS{{.EmphasisRight}}, and the {{.EmphasisLeft}}CREATE DATABASE{{.EmphasisRight}} and {{.EmphasisLeft}}USE{{.EmphasisRight}} statements of dumps taken with {{.EmphasisLeft}}--databases{{.EmphasisRight}}, as the dump is always loaded into the current database. The statements inside version comments such as {{.EmphasisLeft}}/*!40101 SET NAMES utf8mb4 */{{.EmphasisRight}} are run, and any {{.EmphasisLeft}}SET{{.EmphasisRight}} statement which can't be run is skipped. The statements that were skipped are listed once the dump is loaded.
+
+Extended inserts are applied in batches, as they are when piping statements to {{.EmphasisLeft}}dolt sql{{.EmphasisRight}}. Loading stops at the first other statement that fails, and the working set is left unchanged.`,
+	Synopsis: []string{
+		"[{{.LessThan}}file{{.GreaterThan}}]",
+	},
+}
+
+// versionCommentRegex matches a statement that is wholly inside a MySQL version comment, such as
+// /*!40101 SET NAMES utf8mb4 */, capturing the statement.
+var versionCommentRegex = regexp.MustCompile(`(?s)^/\*!\d*\s*(.*?)\s*\*/$`)
+
+// skippedStatement is a statement of a dump which wasn't run
+type skippedStatement struct {
+	line   int
+	reason string
+}
+
+const (
+	skipLockReason     = "table locks aren't needed to load a dump"
+	skipKeysReason     = "index maintenance can't be disabled"
+	skipDatabaseReason = "dumps are loaded into the current database"
+)
+
+type LoadDumpCmd struct{}
+
+var _ cli.Command = LoadDumpCmd{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd LoadDumpCmd) Name() string {
+	return "load-dump"
+}
+
+// Description returns a description of the command
+func (cmd LoadDumpCmd) Description() string {
+	return "Loads a mysqldump file into the working set."
+}
+
+// CreateMarkdown creates a markdown file containing the helptext for the command at the given path
+func (cmd LoadDumpCmd) CreateMarkdown(fs filesys.Filesys, path, commandStr string) error {
+	ap := cmd.createArgParser()
+	return CreateMarkdown(fs, path, cli.GetCommandDocumentation(commandStr, loadDumpDocs, ap))
+}
+
+func (cmd LoadDumpCmd) createArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file", "The dump file to load. The dump is read from stdin if no file is given."})
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd LoadDumpCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_SQL
+}
+
+// Exec executes the command
+func (cmd LoadDumpCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := cmd.createArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, loadDumpDocs, ap))
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() > 1 {
+		usage()
+		return 1
+	}
+
+	if !cli.CheckEnvIsValid(dEnv) {
+		return 2
+	}
+
+	var input io.Reader = os.Stdin
+	if apr.NArg() == 1 {
+		rd, err := dEnv.FS.OpenForRead(apr.Arg(0))
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: failed to open '%s'", apr.Arg(0)).AddCause(err).Build(), usage)
+		}
+		defer rd.Close()
+		input = rd
+	}
+
+	mrEnv := env.DoltEnvAsMultiEnv(dEnv)
+	roots, err := mrEnv.GetWorkingRoots(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	dsess := dsqle.DefaultDoltSession()
+	dsess.Username = *dEnv.Config.GetStringOrDefault(env.UserNameKey, "")
+	dsess.Email = *dEnv.Config.GetStringOrDefault(env.UserEmailKey, "")
+
+	sqlCtx := sql.NewContext(ctx,
+		sql.WithSession(dsess),
+		sql.WithIndexRegistry(sql.NewIndexRegistry()),
+		sql.WithViewRegistry(sql.NewViewRegistry()),
+		sql.WithTracer(tracing.Tracer(ctx)))
+	_ = sqlCtx.Set(sqlCtx, sql.AutoCommitSessionVar, sql.Boolean, true)
+	for name := range roots {
+		sqlCtx.SetCurrentDatabase(name)
+	}
+
+	dbs := CollectDBs(mrEnv, newBatchedDatabase)
+	se, err := newSqlEngine(sqlCtx, false, mrEnv, roots, FormatTabular, dbs...)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+	}
+
+	skipped, err := loadDump(sqlCtx, se, input)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("Error loading dump").AddCause(err).Build(), usage)
+	}
+
+	verr := writeRoots(sqlCtx, se, mrEnv, roots)
+	if verr != nil {
+		return HandleVErrAndExitCode(verr, usage)
+	}
+
+	printSkippedStatements(skipped)
+	return 0
+}
+
+// loadDump runs the statements of the dump read from |input|, returning the statements which were skipped. The Root
+// of the sqlEngine may be updated. The error of a statement that fails names its line and the statement.
+func loadDump(ctx *sql.Context, se *sqlEngine, input io.Reader) ([]skippedStatement, error) {
+	var skipped []skippedStatement
+
+	scanner := NewSqlStatementScanner(input)
+	for scanner.Scan() {
+		line := scanner.statementStartLine
+		query, reason := translateDumpStatement(scanner.Text())
+		if reason != "" {
+			skipped = append(skipped, skippedStatement{line: line, reason: reason})
+			continue
+		} else if query == "" {
+			continue
+		}
+
+		err := processBatchQuery(ctx, query, se)
+		if err != nil && isSetStatement(query) {
+			// dumps set many session variables for the benefit of MySQL servers, which may not exist here
+			skipped = append(skipped, skippedStatement{line: line, reason: err.Error()})
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error on line %d for query %s: %w", line, query, err)
+		}
+	}
+
+	updateBatchInsertOutput()
+	cli.Println()
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading the dump: %w", err)
+	}
+
+	err := flushBatchedEdits(ctx, se)
+	if err != nil {
+		return nil, fmt.Errorf("error writing the loaded rows: %w", err)
+	}
+
+	return skipped, nil
+}
+
+// translateDumpStatement returns the statement to run for the statement |query| of a dump, or the reason that it's
+// skipped. Both are empty for statements that are only comments.
+func translateDumpStatement(query string) (stmt string, skipReason string) {
+	stmt = strings.TrimSpace(stripLeadingComments(query))
+	if stmt == "" {
+		return "", ""
+	}
+
+	if matches := versionCommentRegex.FindStringSubmatch(stmt); matches != nil {
+		stmt = matches[1]
+	}
+
+	words := strings.Fields(strings.ToUpper(stmt))
+	switch {
+	case len(words) == 0:
+		return "", ""
+	case hasLeadingWords(words, "LOCK", "TABLES"), hasLeadingWords(words, "UNLOCK", "TABLES"):
+		return "", skipLockReason
+	case hasLeadingWords(words, "ALTER", "TABLE") && len(words) == 5 && words[4] == "KEYS" && (words[3] == "DISABLE" || words[3] == "ENABLE"):
+		return "", skipKeysReason
+	case hasLeadingWords(words, "CREATE", "DATABASE"), hasLeadingWords(words, "CREATE", "SCHEMA"), hasLeadingWords(words, "USE"):
+		return "", skipDatabaseReason
+	}
+
+	return stmt, ""
+}
+
+// stripLeadingComments removes the comments before the start of |query|. Version comments are left in place, as they
+// hold statements.
+func stripLeadingComments(query string) string {
+	for {
+		query = strings.TrimLeft(query, " \t\r\n")
+		switch {
+		case strings.HasPrefix(query, "--"), strings.HasPrefix(query, "#"):
+			idx := strings.IndexByte(query, '\n')
+			if idx < 0 {
+				return ""
+			}
+			query = query[idx+1:]
+		case strings.HasPrefix(query, "/*") && !strings.HasPrefix(query, "/*!"):
+			idx := strings.Index(query, "*/")
+			if idx < 0 {
+				return ""
+			}
+			query = query[idx+2:]
+		default:
+			return query
+		}
+	}
+}
+
+func hasLeadingWords(words []string, leading ...string) bool {
+	if len(words) < len(leading) {
+		return false
+	}
+	for i, word := range leading {
+		if strings.TrimRight(words[i], "`;") != word {
+			return false
+		}
+	}
+	return true
+}
+
+func isSetStatement(query string) bool {
+	return hasLeadingWords(strings.Fields(strings.ToUpper(query)), "SET")
+}
+
+// printSkippedStatements prints the line numbers of the |skipped| statements, grouped by the reason they were skipped.
+func printSkippedStatements(skipped []skippedStatement) {
+	if len(skipped) == 0 {
+		return
+	}
+
+	var reasons []string
+	linesByReason := make(map[string][]string)
+	for _, s := range skipped {
+		if _, ok := linesByReason[s.reason]; !ok {
+			reasons = append(reasons, s.reason)
+		}
+		linesByReason[s.reason] = append(linesByReason[s.reason], fmt.Sprint(s.line))
+	}
+
+	cli.Printf("Skipped %s:\n", pluralize("statement", "statements", uint64(len(skipped))))
+	for _, reason := range reasons {
+		lines := linesByReason[reason]
+		lineStr := "line"
+		if len(lines) > 1 {
+			lineStr = "lines"
+		}
+		cli.Printf("\t%s (%s %s): %s\n", pluralize("statement", "statements", uint64(len(lines))), lineStr, strings.Join(lines, ", "), reason)
+	}
+}
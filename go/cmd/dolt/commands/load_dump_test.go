@@ -0,0 +1,49 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateDumpStatement(t *testing.T) {
+	tests := []struct {
+		query      string
+		expected   string
+		skipReason string
+	}{
+		{"\n-- MySQL dump 10.13\n--\n", "", ""},
+		{"\n\n/*!40101 SET NAMES utf8mb4 */", "SET NAMES utf8mb4", ""},
+		{"/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */", "SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0", ""},
+		{"\n--\n-- Current Database: `shop`\n--\n\nCREATE DATABASE /*!32312 IF NOT EXISTS*/ `shop`", "", skipDatabaseReason},
+		{"\nUSE `shop`", "", skipDatabaseReason},
+		{"\nLOCK TABLES `customers` WRITE", "", skipLockReason},
+		{"\nUNLOCK TABLES", "", skipLockReason},
+		{"\n/*!40000 ALTER TABLE `customers` DISABLE KEYS */", "", skipKeysReason},
+		{"\n/*!40000 ALTER TABLE `customers` ENABLE KEYS */", "", skipKeysReason},
+		{"\nALTER TABLE `customers` ADD COLUMN `keys` int", "ALTER TABLE `customers` ADD COLUMN `keys` int", ""},
+		{"\n/* a comment */ INSERT INTO `t` VALUES (1,'/*!40000 not a comment */')", "INSERT INTO `t` VALUES (1,'/*!40000 not a comment */')", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, skipReason := translateDumpStatement(test.query)
+			assert.Equal(t, test.expected, stmt)
+			assert.Equal(t, test.skipReason, skipReason)
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/set"
@@ -351,7 +352,7 @@ func executeMerge(ctx context.Context, squash bool, dEnv *env.DoltEnv, cm1, cm2
 		return verr
 	}
 
-	mergedRoot, tblToStats, err := merge.MergeCommits(ctx, cm1, cm2)
+	mergedRoot, tblToStats, err := merge.MergeCommits(ctx, cm1, cm2, sqle.MergeCheckEvaluator{})
 
 	if err != nil {
 		switch err {
@@ -545,6 +546,9 @@ func printConflicts(tblToStats map[string]*merge.MergeStats) bool {
 
 			hasConflicts = true
 		}
+		if stats.CheckViolations > 0 {
+			cli.PrintErrln(color.YellowString("warning: %d merged rows in %s violate its check constraints", stats.CheckViolations, tblName))
+		}
 	}
 
 	return hasConflicts
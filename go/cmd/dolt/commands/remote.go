@@ -180,7 +180,10 @@ func removeRemote(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 	return nil
 }
 
-func getAbsRemoteUrl(fs filesys.Filesys, cfg config.ReadableConfig, urlArg string) (string, string, error) {
+// GetAbsRemoteUrl returns the scheme and the absolute url of the remote given by |urlArg|, as the remote commands
+// accept it: relative file paths are made absolute using |fs|, and urls without a scheme or host refer to the remotes
+// API host configured in |cfg|.
+func GetAbsRemoteUrl(fs filesys.Filesys, cfg config.ReadableConfig, urlArg string) (string, string, error) {
 	u, err := earl.Parse(urlArg)
 
 	if err != nil {
@@ -258,7 +261,7 @@ func addRemote(dEnv *env.DoltEnv, apr *argparser.ArgParseResults) errhand.Verbos
 	}
 
 	remoteUrl := apr.Arg(2)
-	scheme, absRemoteUrl, err := getAbsRemoteUrl(dEnv.FS, dEnv.Config, remoteUrl)
+	scheme, absRemoteUrl, err := GetAbsRemoteUrl(dEnv.FS, dEnv.Config, remoteUrl)
 
 	if err != nil {
 		return errhand.BuildDError("error: '%s' is not valid.", remoteUrl).AddCause(err).Build()
@@ -55,6 +55,8 @@ const (
 	floatThresholdParam = "float-threshold"
 	keepTypesParam      = "keep-types"
 	delimParam          = "delim"
+	sampleRowsParam     = "sample-rows"
+	dateLayoutsParam    = "date-layouts"
 )
 
 var MappingFileHelp = "A mapping file is json in the format:" + `
@@ -81,13 +83,15 @@ A mapping file can be used to map fields between the file being imported and the
 
 In create, update, and replace scenarios the file's extension is used to infer the type of the file.  If a file does not have the expected extension then the {{.EmphasisLeft}}--file-type{{.EmphasisRight}} parameter should be used to explicitly define the format of the file in one of the supported formats (Currently only csv is supported).  For files separated by a delimiter other than a ',', the --delim parameter can be used to specify a delimeter.
 
-If the parameter {{.EmphasisLeft}}--dry-run{{.EmphasisRight}} is supplied a sql statement will be generated showing what would be executed if this were run without the --dry-run flag
+If the parameter {{.EmphasisLeft}}--dry-run{{.EmphasisRight}} is supplied a sql statement will be generated showing what would be executed if this were run without the --dry-run flag, followed by comments explaining why each column was given its inferred type.
+
+Column types are inferred from all of the rows of the file, or from the first rows when {{.EmphasisLeft}}--sample-rows{{.EmphasisRight}} is given.  Text columns with a small number of distinct values, each repeated many times, are inferred to be enums, and numbers which all have the same number of digits (at least 2) after the decimal point are inferred to be decimals.  Dates, times and datetimes are recognized in the formats understood by SQL and in a set of common layouts, such as 1/2/2006 and 2006-01-02T15:04:05.  {{.EmphasisLeft}}--date-layouts{{.EmphasisRight}} replaces the common layouts with a comma separated list of layouts, which are written as the reference time Mon Jan 2 15:04:05 2006 would be formatted.  {{.EmphasisLeft}}dolt table import{{.EmphasisRight}} reads the same layouts, and takes the same {{.EmphasisLeft}}--date-layouts{{.EmphasisRight}} parameter, so files whose layouts were given here should be imported with it too.
 
 {{.EmphasisLeft}}--float-threshold{{.EmphasisRight}} is the threshold at which a string representing a floating point number should be interpreted as a float versus an int.  If FloatThreshold is 0.0 then any number with a decimal point will be interpreted as a float (such as 0.0, 1.0, etc).  If FloatThreshold is 1.0 then any number with a decimal point will be converted to an int (0.5 will be the int 0, 1.99 will be the int 1, etc.  If the FloatThreshold is 0.001 then numbers with a fractional component greater than or equal to 0.001 will be treated as a float (1.0 would be an int, 1.0009 would be an int, 1.001 would be a float, 1.1 would be a float, etc)
 `,
 
 	Synopsis: []string{
-		`[--create|--replace] [--force] [--dry-run] [--lower|--upper] [--keep-types] [--file-type <type>] [--float-threshold] [--map {{.LessThan}}mapping-file{{.GreaterThan}}] [--delim {{.LessThan}}delimiter{{.GreaterThan}}]--pks {{.LessThan}}field{{.GreaterThan}},... {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}`,
+		`[--create|--replace] [--force] [--dry-run] [--lower|--upper] [--keep-types] [--file-type <type>] [--float-threshold] [--sample-rows {{.LessThan}}n{{.GreaterThan}}] [--date-layouts {{.LessThan}}layouts{{.GreaterThan}}] [--map {{.LessThan}}mapping-file{{.GreaterThan}}] [--delim {{.LessThan}}delimiter{{.GreaterThan}}]--pks {{.LessThan}}field{{.GreaterThan}},... {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}`,
 	},
 }
 
@@ -110,6 +114,8 @@ type importOptions struct {
 	keepTypes      bool
 	colMapper      rowconv.NameMapper
 	floatThreshold float64
+	sampleRows     int
+	dateLayouts    []string
 }
 
 func (im *importOptions) ColNameMapper() rowconv.NameMapper {
@@ -118,6 +124,12 @@ func (im *importOptions) ColNameMapper() rowconv.NameMapper {
 func (im *importOptions) FloatThreshold() float64 {
 	return im.floatThreshold
 }
+func (im *importOptions) SampleRows() int {
+	return im.sampleRows
+}
+func (im *importOptions) DateLayouts() []string {
+	return im.dateLayouts
+}
 
 type ImportCmd struct{}
 
@@ -156,6 +168,8 @@ func (cmd ImportCmd) createArgParser() *argparser.ArgParser {
 	ap.SupportsString(mappingParam, "m", "mapping-file", "A file that can map a column name in {{.LessThan}}file{{.GreaterThan}} to a new value.")
 	ap.SupportsString(floatThresholdParam, "", "float", "Minimum value at which the fractional component of a value must exceed in order to be considered a float.")
 	ap.SupportsString(delimParam, "", "delimiter", "Specify a delimiter for a csv style file with a non-comma delimiter.")
+	ap.SupportsInt(sampleRowsParam, "", "n", "Infer the column types from the first {{.LessThan}}n{{.GreaterThan}} rows of the {{.LessThan}}file{{.GreaterThan}}, rather than from all of its rows.")
+	ap.SupportsString(dateLayoutsParam, "", "layouts", "Comma separated layouts of the dates, times and datetimes in the {{.LessThan}}file{{.GreaterThan}} which aren't in a format understood by SQL.")
 	return ap
 }
 
@@ -251,6 +265,17 @@ func getSchemaImportArgs(ctx context.Context, apr *argparser.ArgParseResults, dE
 		return nil, errhand.BuildDError("error: '%s' is not a valid float in the range 0.0 (all floats) to 1.0 (no floats)", floatThresholdStr).SetPrintUsage().Build()
 	}
 
+	sampleRows, hasSampleRows := apr.GetInt(sampleRowsParam)
+	if hasSampleRows && sampleRows <= 0 {
+		return nil, errhand.BuildDError("error: --%s must be a positive number of rows", sampleRowsParam).SetPrintUsage().Build()
+	}
+
+	dateLayouts := actions.CommonDateLayouts
+	if layoutsStr, ok := apr.GetValue(dateLayoutsParam); ok {
+		dateLayouts = funcitr.MapStrings(strings.Split(layoutsStr, ","), strings.TrimSpace)
+		dateLayouts = funcitr.FilterStrings(dateLayouts, func(s string) bool { return s != "" })
+	}
+
 	return &importOptions{
 		op:             op,
 		fileName:       fileName,
@@ -262,6 +287,8 @@ func getSchemaImportArgs(ctx context.Context, apr *argparser.ArgParseResults, dE
 		keepTypes:      apr.Contains(keepTypesParam),
 		colMapper:      colMapper,
 		floatThreshold: floatThreshold,
+		sampleRows:     sampleRows,
+		dateLayouts:    dateLayouts,
 	}, nil
 }
 
@@ -278,7 +305,7 @@ func importSchema(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 		return verr
 	}
 
-	sch, verr := inferSchemaFromFile(ctx, dEnv.DoltDB.ValueReadWriter().Format(), impArgs, root)
+	sch, report, verr := inferSchemaFromFile(ctx, dEnv.DoltDB.ValueReadWriter().Format(), impArgs, root)
 
 	if verr != nil {
 		return verr
@@ -294,7 +321,9 @@ func importSchema(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 	}
 	cli.Println(stmt)
 
-	if !apr.Contains(dryRunFlag) {
+	if apr.Contains(dryRunFlag) {
+		printInferenceReport(sch, report)
+	} else {
 		tbl, tblExists, err := root.GetTable(ctx, tblName)
 
 		schVal, err := encoding.MarshalSchemaAsNomsValue(context.Background(), root.VRW(), sch)
@@ -341,7 +370,18 @@ func importSchema(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 	return nil
 }
 
-func inferSchemaFromFile(ctx context.Context, nbf *types.NomsBinFormat, impOpts *importOptions, root *doltdb.RootValue) (schema.Schema, errhand.VerboseError) {
+// printInferenceReport prints why each column of |sch| was given the type it was inferred to have, as SQL comments
+func printInferenceReport(sch schema.Schema, report *actions.InferenceReport) {
+	cli.Printf("\n-- column types inferred from %d rows:\n", report.RowsSampled)
+	_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if reason, ok := report.Reasons[col.Name]; ok {
+			cli.Printf("--   %s %s: %s\n", col.Name, col.TypeInfo.ToSqlType().String(), reason)
+		}
+		return false, nil
+	})
+}
+
+func inferSchemaFromFile(ctx context.Context, nbf *types.NomsBinFormat, impOpts *importOptions, root *doltdb.RootValue) (schema.Schema, *actions.InferenceReport, errhand.VerboseError) {
 	if impOpts.fileType[0] == '.' {
 		impOpts.fileType = impOpts.fileType[1:]
 	}
@@ -357,13 +397,13 @@ func inferSchemaFromFile(ctx context.Context, nbf *types.NomsBinFormat, impOpts
 	case "psv":
 		csvInfo.SetDelim("|")
 	default:
-		return nil, errhand.BuildDError("error: unsupported file type '%s'", impOpts.fileType).Build()
+		return nil, nil, errhand.BuildDError("error: unsupported file type '%s'", impOpts.fileType).Build()
 	}
 
 	f, err := os.Open(impOpts.fileName)
 
 	if err != nil {
-		return nil, errhand.BuildDError("error: failed to open '%s'", impOpts.fileName).Build()
+		return nil, nil, errhand.BuildDError("error: failed to open '%s'", impOpts.fileName).Build()
 	}
 
 	defer f.Close()
@@ -371,18 +411,23 @@ func inferSchemaFromFile(ctx context.Context, nbf *types.NomsBinFormat, impOpts
 	rd, err = csv.NewCSVReader(nbf, f, csvInfo)
 
 	if err != nil {
-		return nil, errhand.BuildDError("error: failed to create a CSVReader.").AddCause(err).Build()
+		return nil, nil, errhand.BuildDError("error: failed to create a CSVReader.").AddCause(err).Build()
 	}
 
 	defer rd.Close(ctx)
 
-	infCols, err := actions.InferColumnTypesFromTableReader(ctx, root, rd, impOpts)
+	infCols, report, err := actions.InferColumnTypesWithReport(ctx, root, rd, impOpts)
 
 	if err != nil {
-		return nil, errhand.BuildDError("error: failed to infer schema").AddCause(err).Build()
+		return nil, nil, errhand.BuildDError("error: failed to infer schema").AddCause(err).Build()
+	}
+
+	sch, verr := CombineColCollections(ctx, root, infCols, impOpts)
+	if verr != nil {
+		return nil, nil, verr
 	}
 
-	return CombineColCollections(ctx, root, infCols, impOpts)
+	return sch, report, nil
 }
 
 func CombineColCollections(ctx context.Context, root *doltdb.RootValue, inferredCols *schema.ColCollection, impOpts *importOptions) (schema.Schema, errhand.VerboseError) {
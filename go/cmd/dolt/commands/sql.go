@@ -64,6 +64,8 @@ var sqlDocs = cli.CommandDocumentationContent{
 
 By default, {{.EmphasisLeft}}-q{{.EmphasisRight}} executes a single statement. To execute multiple SQL statements separated by semicolons, use {{.EmphasisLeft}}-b{{.EmphasisRight}} to enable batch mode. Queries can be saved with {{.EmphasisLeft}}-s{{.EmphasisRight}}. Alternatively {{.EmphasisLeft}}-x{{.EmphasisRight}} can be used to execute a saved query by name. Pipe SQL statements to dolt sql (no {{.EmphasisLeft}}-q{{.EmphasisRight}}) to execute a SQL import or update script. 
 
+User accounts can be managed with {{.EmphasisLeft}}CREATE USER{{.EmphasisRight}}, {{.EmphasisLeft}}DROP USER{{.EmphasisRight}}, {{.EmphasisLeft}}GRANT{{.EmphasisRight}}, {{.EmphasisLeft}}REVOKE{{.EmphasisRight}} and {{.EmphasisLeft}}SHOW GRANTS{{.EmphasisRight}} when {{.EmphasisLeft}}--privilege-file <file>{{.EmphasisRight}} is given. The accounts are saved to the file, which {{.EmphasisLeft}}dolt sql-server{{.EmphasisRight}} loads when it's started with the same {{.EmphasisLeft}}--privilege-file{{.EmphasisRight}}.
+
 By default this command uses the dolt data repository in the current working directory as the one and only database. Running with {{.EmphasisLeft}}--multi-db-dir <directory>{{.EmphasisRight}} uses each of the subdirectories of the supplied directory (each subdirectory must be a valid dolt data repository) as databases. Subdirectories starting with '.' are ignored. Known limitations: 
 	- No support for creating indexes 
 	- No support for foreign keys 
@@ -89,6 +91,7 @@ const (
 	messageFlag    = "message"
 	BatchFlag      = "batch"
 	multiDBDirFlag = "multi-db-dir"
+	privilegeFlag  = "privilege-file"
 	welcomeMsg     = `# Welcome to the DoltSQL shell.
 # Statements must be terminated with ';'.
 # "exit" or "quit" (or Ctrl-D) to exit.`
@@ -125,6 +128,7 @@ func (cmd SqlCmd) createArgParser() *argparser.ArgParser {
 	ap.SupportsString(messageFlag, "m", "saved query description", "Used with --query and --save, saves the query with the descriptive message given. See also --name")
 	ap.SupportsFlag(BatchFlag, "b", "batch mode, to run more than one query with --query, separated by ';'. Piping input to sql with no arguments also uses batch mode")
 	ap.SupportsString(multiDBDirFlag, "", "directory", "Defines a directory whose subdirectories should all be dolt data repositories accessible as independent databases within ")
+	ap.SupportsString(privilegeFlag, "", "file", "Defines a file that user accounts created with CREATE USER, GRANT and REVOKE are saved to, which sql-server loads as its privilege_file")
 	return ap
 }
 
@@ -234,6 +238,14 @@ func (cmd SqlCmd) Exec(ctx context.Context, commandStr string, args []string, dE
 		sql.WithTracer(tracing.Tracer(ctx)))
 	_ = sqlCtx.Set(sqlCtx, sql.AutoCommitSessionVar, sql.Boolean, true)
 
+	if privilegeFile, ok := apr.GetValue(privilegeFlag); ok {
+		accounts, err := dsqle.NewPrivilegeStore(dEnv.FS, privilegeFile, sqlCtx.Client().User, "", false, nil)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
+		}
+		dsess.SetUserAccounts(accounts)
+	}
+
 	roots := make(map[string]*doltdb.RootValue)
 
 	var name string
@@ -380,6 +392,47 @@ func execQuery(sqlCtx *sql.Context, readOnly bool, mrEnv env.MultiRepoEnv, roots
 	return writeRoots(sqlCtx, se, mrEnv, roots)
 }
 
+// QueryRoot runs the read only query |query| against |root|, which is a root of the database of |dEnv|. The rows of
+// the result set are read from the returned iterator with the returned context, and the iterator must be closed.
+func QueryRoot(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, query string) (*sql.Context, sql.Schema, sql.RowIter, errhand.VerboseError) {
+	mrEnv := env.DoltEnvAsMultiEnv(dEnv)
+	roots := make(map[string]*doltdb.RootValue)
+	for name := range mrEnv {
+		roots[name] = root
+	}
+
+	dsess := dsqle.DefaultDoltSession()
+	dsess.Username = *dEnv.Config.GetStringOrDefault(env.UserNameKey, "")
+	dsess.Email = *dEnv.Config.GetStringOrDefault(env.UserEmailKey, "")
+
+	sqlCtx := sql.NewContext(ctx,
+		sql.WithSession(dsess),
+		sql.WithIndexRegistry(sql.NewIndexRegistry()),
+		sql.WithViewRegistry(sql.NewViewRegistry()),
+		sql.WithTracer(tracing.Tracer(ctx)))
+	for name := range roots {
+		sqlCtx.SetCurrentDatabase(name)
+	}
+
+	dbs := CollectDBs(mrEnv, newDatabase)
+	se, err := newSqlEngine(sqlCtx, true, mrEnv, roots, FormatCsv, dbs...)
+	if err != nil {
+		return nil, nil, nil, errhand.VerboseErrorFromError(err)
+	}
+
+	sqlSch, rowIter, err := processQuery(sqlCtx, query, se)
+	if err != nil {
+		return nil, nil, nil, formatQueryError("", err)
+	} else if rowIter == nil || isOkResult(sqlSch) {
+		if rowIter != nil {
+			_ = rowIter.Close(sqlCtx)
+		}
+		return nil, nil, nil, errhand.BuildDError("query '%s' does not return a result set", query).Build()
+	}
+
+	return sqlCtx, sqlSch, rowIter, nil
+}
+
 // CollectDBs takes a MultiRepoEnv and creates Database objects from each environment and returns a slice of these
 // objects.
 func CollectDBs(mrEnv env.MultiRepoEnv, createDB createDBFunc) []dsqle.Database {
@@ -837,6 +890,18 @@ func prepend(s string, ss []string) []string {
 // Processes a single query. The Root of the sqlEngine will be updated if necessary.
 // Returns the schema and the row iterator for the results, which may be nil, and an error if one occurs.
 func processQuery(ctx *sql.Context, query string, se *sqlEngine) (sql.Schema, sql.RowIter, error) {
+	// the parser drops check constraints, so statements that add or drop them are run before it parses them
+	if checkStmt, err := dsqle.ParseCheckStatement(query); err != nil || checkStmt != nil {
+		return nil, nil, se.execCheckStatement(ctx, query)
+	}
+
+	// nor does it understand the statements that manage user accounts
+	if accountStmt, err := dsqle.ParseAccountStatement(query); err != nil {
+		return nil, nil, err
+	} else if accountStmt != nil {
+		return dsqle.ExecAccountStatement(ctx, accountStmt)
+	}
+
 	sqlStatement, err := sqlparser.Parse(query)
 	if err == sqlparser.ErrEmpty {
 		// silently skip empty statements
@@ -966,6 +1031,20 @@ func flushBatchedEdits(ctx *sql.Context, se *sqlEngine) error {
 
 // Processes a single query in batch mode. The Root of the sqlEngine may or may not be changed.
 func processBatchQuery(ctx *sql.Context, query string, se *sqlEngine) error {
+	if checkStmt, err := dsqle.ParseCheckStatement(query); err != nil || checkStmt != nil {
+		err = flushBatchedEdits(ctx, se)
+		if err != nil {
+			return err
+		}
+		return se.execCheckStatement(ctx, query)
+	}
+
+	if accountStmt, err := dsqle.ParseAccountStatement(query); err != nil {
+		return err
+	} else if accountStmt != nil {
+		return processAccountBatchQuery(ctx, se, accountStmt)
+	}
+
 	sqlStatement, err := sqlparser.Parse(query)
 	if err == sqlparser.ErrEmpty {
 		// silently skip empty statements
@@ -1053,6 +1132,29 @@ func processNonInsertBatchQuery(ctx *sql.Context, se *sqlEngine, query string, s
 	return flushBatchedEdits(ctx, se)
 }
 
+// processAccountBatchQuery runs |stmt|, a statement that manages user accounts, printing the grants it shows.
+func processAccountBatchQuery(ctx *sql.Context, se *sqlEngine, stmt *dsqle.AccountStatement) error {
+	err := flushBatchedEdits(ctx, se)
+	if err != nil {
+		return err
+	}
+
+	sqlSch, rowIter, err := dsqle.ExecAccountStatement(ctx, stmt)
+	if err != nil {
+		return err
+	}
+
+	if isOkResult(sqlSch) {
+		return rowIter.Close(ctx)
+	}
+
+	if displayStrLen > 0 {
+		cli.Print("\n")
+		displayStrLen = 0
+	}
+	return PrettyPrintResults(ctx, se.resultFormat, sqlSch, rowIter)
+}
+
 func processBatchInsert(ctx *sql.Context, se *sqlEngine, query string, sqlStatement sqlparser.Statement) (returnErr error) {
 	_, rowIter, err := se.query(ctx, query)
 	if err != nil {
@@ -1346,7 +1448,18 @@ func (se *sqlEngine) getRoots(sqlCtx *sql.Context) (map[string]*doltdb.RootValue
 
 // Execute a SQL statement and return values for printing.
 func (se *sqlEngine) query(ctx *sql.Context, query string) (sql.Schema, sql.RowIter, error) {
-	return se.engine.Query(ctx, query)
+	return dsqle.QueryWithChecks(ctx, se.engine, query)
+}
+
+// execCheckStatement runs |query|, a statement that adds or drops check constraints, discarding its result.
+func (se *sqlEngine) execCheckStatement(ctx *sql.Context, query string) error {
+	_, iter, err := se.query(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	_, err = sql.RowIterToRows(ctx, iter)
+	return err
 }
 
 func PrettyPrintResults(ctx *sql.Context, resultFormat resultFormat, sqlSch sql.Schema, rowIter sql.RowIter) (rerr error) {
@@ -18,6 +18,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
@@ -85,12 +86,33 @@ func sqlColToStr(col interface{}) string {
 			}
 		case time.Time:
 			return typedCol.Format("2006-01-02 15:04:05.999999 -0700 MST")
+		case []byte:
+			return string(typedCol)
+		case map[string]interface{}, []interface{}:
+			// JSON documents produced by the JSON functions
+			if doc, err := json.Marshal(typedCol); err == nil {
+				return string(doc)
+			}
 		}
 	}
 
 	return ""
 }
 
+// jsonColToStr returns the JSON encoding of a value of a JSON column
+func jsonColToStr(col interface{}) string {
+	if doc, ok := col.([]byte); ok {
+		return string(doc)
+	}
+
+	doc, err := json.Marshal(col)
+	if err != nil {
+		return "null"
+	}
+
+	return string(doc)
+}
+
 // getReadStageFunc is a general purpose stage func used by multiple pipelines to read the rows into batches
 func getReadStageFunc(iter sql.RowIter, batchSize int) pipeline.StageFunc {
 	isDone := false
@@ -222,10 +244,15 @@ func createJSONPipeline(_ context.Context, sch sql.Schema, iter sql.RowIter) *pi
 
 func getJSONProcessFunc(sch sql.Schema) pipeline.StageFunc {
 	formats := make([]string, len(sch))
+	isJSON := make([]bool, len(sch))
 	for i, col := range sch {
 		switch col.Type.(type) {
 		case sql.StringType, sql.DatetimeType, sql.EnumType, sql.TimeType:
 			formats[i] = fmt.Sprintf(`"%s":"%%s"`, col.Name)
+		case sql.JsonType:
+			// JSON documents are embedded as they are
+			formats[i] = fmt.Sprintf(`"%s":%%s`, col.Name)
+			isJSON[i] = true
 		default:
 			formats[i] = fmt.Sprintf(`"%s":%%s`, col.Name)
 		}
@@ -255,8 +282,13 @@ func getJSONProcessFunc(sch sql.Schema) pipeline.StageFunc {
 					}
 
 					validCols++
-					colStr := sqlColToStr(col)
-					colStr = strings.Replace(colStr, "\"", "\\\"", -1)
+					var colStr string
+					if isJSON[colNum] {
+						colStr = jsonColToStr(col)
+					} else {
+						colStr = sqlColToStr(col)
+						colStr = strings.Replace(colStr, "\"", "\\\"", -1)
+					}
 					str := fmt.Sprintf(formats[colNum], colStr)
 					sb.WriteString(str)
 				}
@@ -16,6 +16,8 @@ package commands
 
 import (
 	"context"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/google/uuid"
@@ -41,6 +43,9 @@ var tableName = "people"
 // Smoke test: Console opens and exits
 func TestSqlConsole(t *testing.T) {
 	t.Run("SQL console opens and exits", func(t *testing.T) {
+		// the console writes its history file to the working directory
+		chdirToTempDir(t)
+
 		dEnv := dtestutils.CreateEnvWithSeedData(t)
 		args := []string{}
 		commandStr := "dolt sql"
@@ -51,6 +56,29 @@ func TestSqlConsole(t *testing.T) {
 
 }
 
+// chdirToTempDir changes the working directory to a new temporary directory until the end of the test
+func chdirToTempDir(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "sql_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.Chdir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = os.Chdir(wd)
+		_ = os.RemoveAll(dir)
+	})
+}
+
 func TestSqlBatchMode(t *testing.T) {
 	tests := []struct {
 		query       string
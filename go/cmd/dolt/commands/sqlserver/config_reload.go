@@ -0,0 +1,205 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/sirupsen/logrus"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+var errNoConfigFile = errors.New("the config of the server can only be reloaded when it was started with --config")
+
+// configSetting is a setting of a ServerConfig, named by its key in the YAML config file.
+type configSetting struct {
+	name  string
+	value func(cfg ServerConfig) interface{}
+}
+
+// reloadableSettings are the settings that are applied to a running server when its config is reloaded.
+var reloadableSettings = []configSetting{
+	{"log_level", func(cfg ServerConfig) interface{} { return cfg.LogLevel() }},
+	{"behavior.read_only", func(cfg ServerConfig) interface{} { return cfg.ReadOnly() }},
+	{"user.name", func(cfg ServerConfig) interface{} { return cfg.User() }},
+	{"user.password", func(cfg ServerConfig) interface{} { return cfg.Password() }},
+	{"listener.read_timeout_millis", func(cfg ServerConfig) interface{} { return cfg.ReadTimeout() }},
+	{"listener.write_timeout_millis", func(cfg ServerConfig) interface{} { return cfg.WriteTimeout() }},
+}
+
+// restartSettings are the settings that are only read when a server starts. Changes to them are reported when the
+// config is reloaded, but they don't take effect until the server is restarted.
+var restartSettings = []configSetting{
+	{"behavior.autocommit", func(cfg ServerConfig) interface{} { return cfg.AutoCommit() }},
+	{"behavior.allow_drop_database", func(cfg ServerConfig) interface{} { return cfg.AllowDropDatabase() }},
+	{"listener.host", func(cfg ServerConfig) interface{} { return cfg.Host() }},
+	{"listener.port", func(cfg ServerConfig) interface{} { return cfg.Port() }},
+	{"listener.socket", func(cfg ServerConfig) interface{} { return cfg.Socket() }},
+	{"listener.max_connections", func(cfg ServerConfig) interface{} { return cfg.MaxConnections() }},
+	{"listener.tls_key", func(cfg ServerConfig) interface{} { return cfg.TLSKey() }},
+	{"listener.tls_cert", func(cfg ServerConfig) interface{} { return cfg.TLSCert() }},
+	{"listener.require_secure_transport", func(cfg ServerConfig) interface{} { return cfg.RequireSecureTransport() }},
+	{"performance.query_parallelism", func(cfg ServerConfig) interface{} { return cfg.QueryParallelism() }},
+	{"databases", func(cfg ServerConfig) interface{} {
+		return []interface{}{cfg.DatabaseNamesAndPaths(), cfg.ReplicationRemotes(), cfg.ReadReplicas()}
+	}},
+	{"data_dir", func(cfg ServerConfig) interface{} { return cfg.DataDir() }},
+	{"users", func(cfg ServerConfig) interface{} { return cfg.Users() }},
+	{"privilege_file", func(cfg ServerConfig) interface{} { return cfg.PrivilegeFilePath() }},
+	{"metrics", func(cfg ServerConfig) interface{} {
+		return []interface{}{cfg.MetricsLabels(), cfg.MetricsHost(), cfg.MetricsPort()}
+	}},
+	{"http_api", func(cfg ServerConfig) interface{} { return []interface{}{cfg.HTTPHost(), cfg.HTTPPort()} }},
+	{"slow_query_log", func(cfg ServerConfig) interface{} {
+		return []interface{}{cfg.SlowQueryThreshold(), cfg.SlowQueryLogFile(), cfg.SlowQueryLogPlans()}
+	}},
+	{"tracing.file", func(cfg ServerConfig) interface{} { return cfg.TraceFile() }},
+}
+
+// changedSettings returns the names of the |settings| whose values differ between |from| and |to|.
+func changedSettings(settings []configSetting, from, to ServerConfig) []string {
+	var changed []string
+	for _, setting := range settings {
+		if !reflect.DeepEqual(setting.value(from), setting.value(to)) {
+			changed = append(changed, setting.name)
+		}
+	}
+	return changed
+}
+
+// configReloader reloads the config file of a running server, when the server receives SIGHUP or a client calls
+// DOLT_RELOAD_CONFIG. The reloadable settings are applied to the server, and changes to the other settings are
+// logged as requiring a restart.
+type configReloader struct {
+	fs   filesys.Filesys
+	path string
+	// started is the config that the server started with
+	started    ServerConfig
+	privileges *dsqle.PrivilegeStore
+	timeouts   *connTimeouts
+
+	// mu serializes reloads, and guards current
+	mu sync.Mutex
+	// current is the config whose reloadable settings were applied most recently
+	current ServerConfig
+}
+
+var _ dsqle.ConfigReloader = (*configReloader)(nil)
+
+// newConfigReloader returns a configReloader for a server started with |serverConfig|. The config can only be
+// reloaded if it was read from a config file in |fs|.
+func newConfigReloader(fs filesys.Filesys, serverConfig ServerConfig, privileges *dsqle.PrivilegeStore, timeouts *connTimeouts) *configReloader {
+	var path string
+	if yamlConfig, ok := serverConfig.(YAMLConfig); ok {
+		path = yamlConfig.filePath
+	}
+
+	return &configReloader{
+		fs:         fs,
+		path:       path,
+		started:    serverConfig,
+		privileges: privileges,
+		timeouts:   timeouts,
+		current:    serverConfig,
+	}
+}
+
+// ReloadConfig implements dsqle.ConfigReloader. Only the server user may reload the config.
+func (r *configReloader) ReloadConfig(ctx *sql.Context) error {
+	if !r.privileges.IsSuperUser(ctx.Client().User) {
+		return mysql.NewSQLError(mysql.ERSpecifiedAccessDenied, mysql.SSAccessDeniedError, "Access denied; only the server user '%s' may reload the server's config", r.privileges.SuperUser())
+	}
+
+	return r.reload()
+}
+
+// reload reads the config file again and applies its reloadable settings. The running server is left unchanged if
+// the config file isn't valid.
+func (r *configReloader) reload() error {
+	if r.path == "" {
+		return errNoConfigFile
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := getYAMLServerConfig(r.fs, r.path)
+	if err != nil {
+		return err
+	}
+
+	if err = ValidateConfig(cfg); err != nil {
+		return err
+	}
+
+	level, err := logrus.ParseLevel(cfg.LogLevel().String())
+	if err != nil {
+		return err
+	}
+
+	err = r.privileges.Reconfigure(cfg.User(), cfg.Password(), cfg.ReadOnly())
+	if err != nil {
+		return err
+	}
+
+	logrus.SetLevel(level)
+	r.timeouts.set(time.Duration(cfg.ReadTimeout())*time.Millisecond, time.Duration(cfg.WriteTimeout())*time.Millisecond)
+
+	for _, name := range changedSettings(reloadableSettings, r.current, cfg) {
+		logrus.Infof("applied changed config setting %s from '%s'", name, r.path)
+	}
+	for _, name := range changedSettings(restartSettings, r.started, cfg) {
+		logrus.Warnf("config setting %s in '%s' differs from the running server; restart the server to apply it", name, r.path)
+	}
+
+	r.current = cfg
+	return nil
+}
+
+// reloadOnSignal reloads the config whenever the process receives SIGHUP, until the returned function is called.
+func (r *configReloader) reloadOnSignal() func() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				logrus.Infof("reloading config from '%s'", r.path)
+				if err := r.reload(); err != nil {
+					logrus.Errorf("failed to reload config: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
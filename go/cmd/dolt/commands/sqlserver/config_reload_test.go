@@ -0,0 +1,143 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	gosql "database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestChangedSettings(t *testing.T) {
+	from := DefaultServerConfig()
+	to := DefaultServerConfig().withPassword("secret").withReadOnly(true).withPort(3307)
+
+	assert.Equal(t, []string{"behavior.read_only", "user.password"}, changedSettings(reloadableSettings, from, to))
+	assert.Equal(t, []string{"listener.port"}, changedSettings(restartSettings, from, to))
+	assert.Empty(t, changedSettings(restartSettings, from, DefaultServerConfig()))
+}
+
+func TestReloadCommandLineConfig(t *testing.T) {
+	privileges, err := dsqle.NewPrivilegeStore(filesys.EmptyInMemFS(""), "", "root", "", false, nil)
+	require.NoError(t, err)
+
+	reloader := newConfigReloader(filesys.EmptyInMemFS(""), DefaultServerConfig(), privileges, newConnTimeouts(0, 0))
+	assert.Equal(t, errNoConfigFile, reloader.reload())
+}
+
+func TestServerReloadConfig(t *testing.T) {
+	const config = `
+log_level: fatal
+
+user:
+    name: root
+    password: ""
+
+listener:
+    port: 15314
+    max_connections: 10
+
+users:
+  - name: reader
+    password: reader
+`
+	const reloadedConfig = `
+log_level: fatal
+
+behavior:
+    read_only: true
+
+user:
+    name: root
+    password: secret
+
+listener:
+    port: 15399
+    max_connections: 10
+    read_timeout_millis: 60000
+
+users:
+  - name: reader
+    password: reader
+`
+
+	ctx := context.Background()
+	dEnv := dtestutils.CreateEnvWithSeedData(t)
+	require.NoError(t, dEnv.FS.WriteFile("config.yaml", []byte(config)))
+	serverConfig, err := getYAMLServerConfig(dEnv.FS, "config.yaml")
+	require.NoError(t, err)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(ctx, "", serverConfig, sc, dEnv)
+	}()
+	require.NoError(t, sc.WaitForStart())
+
+	db, err := gosql.Open("mysql", "root:@tcp(localhost:15314)/dolt")
+	require.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "INSERT INTO people (id, name, age, is_married) VALUES ('00000000-0000-0000-0000-000000000010', 'Jane Janeson', 30, false)")
+	require.NoError(t, err)
+
+	readerDb, err := gosql.Open("mysql", "reader:reader@tcp(localhost:15314)/dolt")
+	require.NoError(t, err)
+	defer readerDb.Close()
+	_, err = readerDb.ExecContext(ctx, "SELECT DOLT_RELOAD_CONFIG()")
+	assert.Error(t, err)
+
+	require.NoError(t, dEnv.FS.WriteFile("config.yaml", []byte(reloadedConfig)))
+	var res int
+	require.NoError(t, conn.QueryRowContext(ctx, "SELECT DOLT_RELOAD_CONFIG()").Scan(&res))
+	assert.Equal(t, 0, res)
+
+	// the connection survives the reload, and is now read-only
+	_, err = conn.ExecContext(ctx, "INSERT INTO people (id, name, age, is_married) VALUES ('00000000-0000-0000-0000-000000000011', 'Joe Joeson', 40, false)")
+	assert.Error(t, err)
+	var count int
+	require.NoError(t, conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM people").Scan(&count))
+	assert.Equal(t, 4, count)
+
+	// new connections use the new password, and the server still listens on its original port
+	oldPassword, err := gosql.Open("mysql", "root:@tcp(localhost:15314)/dolt")
+	require.NoError(t, err)
+	defer oldPassword.Close()
+	assert.Error(t, oldPassword.PingContext(ctx))
+
+	newPassword, err := gosql.Open("mysql", "root:secret@tcp(localhost:15314)/dolt")
+	require.NoError(t, err)
+	defer newPassword.Close()
+	assert.NoError(t, newPassword.PingContext(ctx))
+
+	// an invalid config file leaves the server unchanged
+	require.NoError(t, dEnv.FS.WriteFile("config.yaml", []byte("listener: [")))
+	_, err = conn.ExecContext(ctx, "SELECT DOLT_RELOAD_CONFIG()")
+	assert.Error(t, err)
+	assert.NoError(t, newPassword.PingContext(ctx))
+
+	sc.StopServer()
+	require.NoError(t, sc.WaitForClose())
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// connTimeouts are the read and write timeouts of the connections of a server. Unlike the timeouts of a vitess
+// listener, they can change while the server runs, and the new timeouts apply to the next read or write of every
+// connection.
+type connTimeouts struct {
+	read  int64
+	write int64
+}
+
+func newConnTimeouts(read, write time.Duration) *connTimeouts {
+	t := &connTimeouts{}
+	t.set(read, write)
+	return t
+}
+
+// set changes the timeouts. A timeout that isn't positive disables the timeout.
+func (t *connTimeouts) set(read, write time.Duration) {
+	atomic.StoreInt64(&t.read, int64(read))
+	atomic.StoreInt64(&t.write, int64(write))
+}
+
+func (t *connTimeouts) readTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&t.read))
+}
+
+func (t *connTimeouts) writeTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&t.write))
+}
+
+// timeoutListener is a net.Listener whose connections use the timeouts of a connTimeouts.
+type timeoutListener struct {
+	net.Listener
+	timeouts *connTimeouts
+}
+
+// Accept implements net.Listener.
+func (l timeoutListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &timeoutConn{conn, l.timeouts}, nil
+}
+
+// timeoutConn is a net.Conn which sets a deadline before each read and write, according to the current timeouts of a
+// connTimeouts.
+type timeoutConn struct {
+	net.Conn
+	timeouts *connTimeouts
+}
+
+// Read implements net.Conn.
+func (c *timeoutConn) Read(b []byte) (int, error) {
+	var deadline time.Time
+	if timeout := c.timeouts.readTimeout(); timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	if err := c.Conn.SetReadDeadline(deadline); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+// Write implements net.Conn.
+func (c *timeoutConn) Write(b []byte) (int, error) {
+	var deadline time.Time
+	if timeout := c.timeouts.writeTimeout(); timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	if err := c.Conn.SetWriteDeadline(deadline); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
@@ -0,0 +1,321 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/utils/earl"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var errNoDataDir = errors.New("databases can only be created when the server has a data directory, see --multi-db-dir")
+var errDropDisabled = errors.New("DROP DATABASE is disabled, see --allow-drop-database")
+
+// databaseProvider creates, clones and drops the databases in the data directory of a server.
+type databaseProvider struct {
+	// dataDir is the absolute path of the data directory, or empty if the server has none
+	dataDir   string
+	allowDrop bool
+	catalog   *sql.Catalog
+	// cfg is the config of the server's environment, which has the user name and email of the initial commit of
+	// created databases and the remotes API host of cloned urls
+	cfg     *env.DoltCliConfig
+	version string
+	// nbf is the format of created databases, which matches the format of the server's environment
+	nbf *types.NomsBinFormat
+	// pinned are the databases that can't be dropped, because they're replicated or read replicas
+	pinned map[string]bool
+
+	// mu serializes the creation and deletion of databases
+	mu   sync.Mutex
+	envs map[string]*env.DoltEnv
+}
+
+var _ dsqle.DatabaseProvider = (*databaseProvider)(nil)
+
+// newDatabaseProvider returns a databaseProvider for the databases of |mrEnv|, which have been added to |catalog|.
+func newDatabaseProvider(serverConfig ServerConfig, catalog *sql.Catalog, mrEnv env.MultiRepoEnv, dEnv *env.DoltEnv, version string) (*databaseProvider, error) {
+	var dataDir string
+	if serverConfig.DataDir() != "" {
+		var err error
+		dataDir, err = filepath.Abs(serverConfig.DataDir())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pinned := make(map[string]bool)
+	for dbName := range serverConfig.ReplicationRemotes() {
+		pinned[strings.ToLower(dbName)] = true
+	}
+	for dbName := range serverConfig.ReadReplicas() {
+		pinned[strings.ToLower(dbName)] = true
+	}
+
+	envs := make(map[string]*env.DoltEnv, len(mrEnv))
+	for name, dbEnv := range mrEnv {
+		envs[name] = dbEnv
+	}
+
+	nbf := types.Format_Default
+	if dEnv.DoltDB != nil {
+		nbf = dEnv.DoltDB.Format()
+	}
+
+	return &databaseProvider{
+		dataDir:   dataDir,
+		allowDrop: serverConfig.AllowDropDatabase(),
+		catalog:   catalog,
+		cfg:       dEnv.Config,
+		version:   version,
+		nbf:       nbf,
+		pinned:    pinned,
+		envs:      envs,
+	}, nil
+}
+
+// CreateDatabase implements dsqle.DatabaseProvider.
+func (p *databaseProvider) CreateDatabase(ctx *sql.Context, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	userName := *p.cfg.GetStringOrDefault(env.UserNameKey, "")
+	email := *p.cfg.GetStringOrDefault(env.UserEmailKey, "")
+	if userName == "" || email == "" {
+		return fmt.Errorf("databases can only be created when the %s and %s of the server are configured", env.UserNameKey, env.UserEmailKey)
+	}
+
+	return p.newDatabase(ctx, name, func(dEnv *env.DoltEnv) error {
+		return dEnv.InitRepo(ctx, p.nbf, userName, email)
+	})
+}
+
+// CloneDatabase implements dsqle.DatabaseProvider.
+func (p *databaseProvider) CloneDatabase(ctx *sql.Context, url, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dataDir == "" {
+		return errNoDataDir
+	}
+
+	remoteUrl, err := p.cloneUrl(url)
+	if err != nil {
+		return err
+	}
+
+	r := env.NewRemote("origin", remoteUrl, nil)
+	srcDB, err := r.GetRemoteDB(ctx, p.nbf)
+	if err != nil {
+		return err
+	}
+
+	return p.newDatabase(ctx, name, func(dEnv *env.DoltEnv) error {
+		return cloneInto(ctx, srcDB, r, dEnv)
+	})
+}
+
+// cloneUrl returns the absolute url of the remote |urlStr| that a database is cloned from. Clients mustn't be able to
+// read the files of the server's host by cloning them, so file urls must be in the data directory, which relative
+// paths are resolved against.
+func (p *databaseProvider) cloneUrl(urlStr string) (string, error) {
+	fs, err := filesys.LocalFilesysWithWorkingDir(p.dataDir)
+	if err != nil {
+		return "", err
+	}
+
+	scheme, remoteUrl, err := commands.GetAbsRemoteUrl(fs, p.cfg, urlStr)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a valid remote url: %w", urlStr, err)
+	}
+
+	if scheme != dbfactory.FileScheme && scheme != dbfactory.LocalBSScheme {
+		return remoteUrl, nil
+	}
+
+	u, err := earl.Parse(remoteUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// symlinks are resolved, so that they can't point out of the data directory
+	path, err := filepath.EvalSymlinks(filepath.FromSlash(u.Host + u.Path))
+	if err != nil {
+		return "", err
+	}
+	dataDir, err := filepath.EvalSymlinks(p.dataDir)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(dataDir, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("can't clone '%s'; file urls must be in the data directory", urlStr)
+	}
+
+	return remoteUrl, nil
+}
+
+// newDatabase creates the directory of the database |name| in the data directory, initializes it with |initRepo| and
+// adds the database to the catalog. The directory is deleted if |initRepo| fails.
+func (p *databaseProvider) newDatabase(ctx context.Context, name string, initRepo func(dEnv *env.DoltEnv) error) error {
+	if p.dataDir == "" {
+		return errNoDataDir
+	}
+
+	if err := validateDatabaseName(name); err != nil {
+		return err
+	}
+
+	if p.catalog.HasDB(name) {
+		return sql.ErrCannotCreateDatabaseExists.New(name)
+	}
+
+	path := filepath.Join(p.dataDir, name)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("can't create database %s; directory '%s' already exists", name, path)
+	}
+
+	err := os.MkdirAll(path, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	dEnv, err := loadEnv(ctx, path, p.version)
+	if err == nil {
+		err = initRepo(dEnv)
+	}
+
+	if err != nil {
+		_ = os.RemoveAll(path)
+		return err
+	}
+
+	p.envs[name] = dEnv
+	p.catalog.AddDatabase(newDatabase(name, dEnv))
+	return nil
+}
+
+// DropDatabase implements dsqle.DatabaseProvider.
+func (p *databaseProvider) DropDatabase(ctx *sql.Context, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, _, ok := dsqle.SplitRevisionDbName(name); ok {
+		return fmt.Errorf("can't drop database %s; it's a revision of another database", name)
+	}
+
+	if !p.allowDrop {
+		return errDropDisabled
+	}
+
+	if p.pinned[strings.ToLower(name)] {
+		return fmt.Errorf("can't drop database %s; it's replicated", name)
+	}
+
+	dEnv, ok := p.envs[name]
+	if !ok {
+		return fmt.Errorf("can't drop database %s; it isn't a dolt database", name)
+	}
+
+	path, err := dEnv.FS.Abs(".")
+	if err != nil {
+		return err
+	}
+
+	if filepath.Dir(path) != p.dataDir {
+		return fmt.Errorf("can't drop database %s; it isn't in the data directory", name)
+	}
+
+	// revision databases of the database are dropped along with it
+	for _, db := range p.catalog.AllDatabases() {
+		if srcName, _, ok := dsqle.SplitRevisionDbName(db.Name()); ok && strings.EqualFold(srcName, name) {
+			p.catalog.RemoveDatabase(db.Name())
+		}
+	}
+	p.catalog.RemoveDatabase(name)
+	delete(p.envs, name)
+
+	return os.RemoveAll(path)
+}
+
+// validateDatabaseName returns an error if |name| can't be the name of the directory of a database in the data
+// directory. Names must be loaded under the same name when the server restarts, so the characters that
+// env.DBNamesAndPathsFromDir replaces in directory names aren't allowed.
+func validateDatabaseName(name string) error {
+	invalid := name == "" ||
+		name[0] == '.' ||
+		strings.ContainsAny(name, `/\-`) ||
+		strings.Contains(name, "__") ||
+		strings.IndexFunc(name, unicode.IsSpace) >= 0
+
+	if invalid {
+		return fmt.Errorf("'%s' is not a valid database name", name)
+	}
+	return nil
+}
+
+// loadEnv loads the environment of the directory |path|, which may not be a dolt data repository yet.
+func loadEnv(ctx context.Context, path, version string) (*env.DoltEnv, error) {
+	fs, err := filesys.LocalFilesysWithWorkingDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr := earl.FileUrlFromPath(filepath.Join(path, dbfactory.DoltDataDir), os.PathSeparator)
+	return env.Load(ctx, env.GetCurrentUserHomeDir, fs, urlStr, version), nil
+}
+
+// cloneInto clones |srcDB|, the database of the remote |r|, into the new repository of |dEnv| the same way dolt clone
+// does.
+func cloneInto(ctx context.Context, srcDB *doltdb.DoltDB, r env.Remote, dEnv *env.DoltEnv) error {
+	err := dEnv.InitRepoWithNoData(ctx, srcDB.Format())
+	if err != nil {
+		return err
+	}
+
+	dEnv.RSLoadErr = nil
+	dEnv.RepoState, err = env.CloneRepoState(dEnv.FS, r)
+	if err != nil {
+		return err
+	}
+
+	eventCh := make(chan datas.TableFileEvent, 128)
+	go func() {
+		for range eventCh {
+		}
+	}()
+	defer close(eventCh)
+
+	return actions.CloneRemote(ctx, srcDB, r.Name, "", dEnv, eventCh)
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestValidateDatabaseName(t *testing.T) {
+	for _, name := range []string{"db", "my_db", "DB2", "db$"} {
+		assert.NoError(t, validateDatabaseName(name), name)
+	}
+	for _, name := range []string{"", ".db", "my-db", "my db", "my__db", "db/feature", `db\feature`} {
+		assert.Error(t, validateDatabaseName(name), name)
+	}
+}
+
+func TestCloneUrl(t *testing.T) {
+	dataDir := t.TempDir()
+	outside := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dataDir, "remote"), os.ModePerm))
+	require.NoError(t, os.Symlink(outside, filepath.Join(dataDir, "link")))
+	p := &databaseProvider{dataDir: dataDir}
+
+	for _, url := range []string{"file://remote", "file://" + filepath.ToSlash(filepath.Join(dataDir, "remote")), "https://doltremoteapi.dolthub.com/org/repo"} {
+		_, err := p.cloneUrl(url)
+		assert.NoError(t, err, url)
+	}
+	for _, url := range []string{"file://.", "file://..", "file://" + filepath.ToSlash(outside), "file://link", "file://missing"} {
+		_, err := p.cloneUrl(url)
+		assert.Error(t, err, url)
+	}
+}
+
+func TestServerCreateAndDropDatabase(t *testing.T) {
+	ctx := context.Background()
+	dataDir := t.TempDir()
+
+	// file urls can only be cloned from the data directory. The remote is in a hidden directory, so that it isn't
+	// loaded as a database.
+	primary := dtestutils.CreateEnvWithSeedData(t)
+	root, err := primary.WorkingRoot(ctx)
+	require.NoError(t, err)
+	commitRoot(t, primary, root, "add people")
+	remoteURL := "file://.remotes/origin"
+	outsideURL := "file://" + filepath.ToSlash(filepath.Join(t.TempDir(), "origin"))
+	for _, remoteDir := range []string{filepath.Join(dataDir, ".remotes", "origin"), filepath.FromSlash(outsideURL[len("file://"):])} {
+		require.NoError(t, os.MkdirAll(remoteDir, os.ModePerm))
+		remoteDB, err := doltdb.LoadDoltDB(ctx, primary.DoltDB.Format(), "file://"+filepath.ToSlash(remoteDir))
+		require.NoError(t, err)
+		pushMaster(t, primary, remoteDB)
+	}
+
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15310).withMaxConnections(2).
+		withDataDir(dataDir).withAllowDropDatabase(true)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, dtestutils.CreateTestEnv())
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	other, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer other.Close()
+
+	_, err = conn.ExecContext(ctx, "CREATE DATABASE newdb")
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dataDir, "newdb", dbfactory.DoltDir))
+	assert.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE DATABASE newdb")
+	assert.Error(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE DATABASE IF NOT EXISTS newdb")
+	assert.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE DATABASE `bad-name`")
+	assert.Error(t, err)
+
+	_, err = conn.ExecContext(ctx, "USE newdb")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE TABLE t (pk INT PRIMARY KEY)")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "INSERT INTO newdb.t VALUES (1), (2)")
+	require.NoError(t, err)
+
+	// sessions that started before the database was created can use it
+	var count int
+	err = other.QueryRowContext(ctx, "SELECT COUNT(*) FROM newdb.t").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	err = conn.QueryRowContext(ctx, "SELECT DOLT_CLONE('"+remoteURL+"', 'cloned')").Scan(&count)
+	require.NoError(t, err)
+	err = other.QueryRowContext(ctx, "SELECT COUNT(*) FROM cloned.people").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	_, err = conn.ExecContext(ctx, "SELECT DOLT_CLONE('"+remoteURL+"', 'cloned')")
+	assert.Error(t, err)
+	_, err = conn.ExecContext(ctx, "SELECT DOLT_CLONE('"+outsideURL+"', 'outside')")
+	assert.Error(t, err)
+	_, err = conn.ExecContext(ctx, "SELECT DOLT_CLONE('file://../', 'outside')")
+	assert.Error(t, err)
+
+	// the database of the working directory isn't in the data directory
+	_, err = conn.ExecContext(ctx, "DROP DATABASE dolt")
+	assert.Error(t, err)
+
+	_, err = conn.ExecContext(ctx, "DROP DATABASE newdb")
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(dataDir, "newdb"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = conn.ExecContext(ctx, "DROP DATABASE IF EXISTS newdb")
+	assert.NoError(t, err)
+
+	_, err = other.ExecContext(ctx, "SELECT COUNT(*) FROM newdb.t")
+	assert.Error(t, err)
+	err = other.QueryRowContext(ctx, "SELECT COUNT(*) FROM cloned.people").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
@@ -0,0 +1,422 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"crypto/tls"
+	"net"
+	"strings"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	querypb "github.com/dolthub/vitess/go/vt/proto/query"
+	"github.com/opentracing/opentracing-go"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// doltHandler is the mysql.Handler used by the dolt sql-server. It wraps the go-mysql-server handler, adding the
+// behavior that depends on dolt, such as resolving revision databases named by clients when they connect.
+type doltHandler struct {
+	*server.Handler
+	engine      *sqle.Engine
+	sm          *server.SessionManager
+	metrics     *serverMetrics
+	slowQueries *slowQueryLog
+	replicas    *readReplicas
+}
+
+var _ mysql.Handler = (*doltHandler)(nil)
+
+// NewConnection implements mysql.Handler.
+func (h *doltHandler) NewConnection(c *mysql.Conn) {
+	h.metrics.connectionOpened()
+	h.Handler.NewConnection(c)
+}
+
+// ConnectionClosed implements mysql.Handler. The revision databases the session used are released, so that the ones
+// no other session uses are removed from the engine.
+func (h *doltHandler) ConnectionClosed(c *mysql.Conn) {
+	h.metrics.connectionClosed()
+	if ctx, err := h.sm.NewContext(c); err == nil {
+		dsqle.ReleaseRevisionDatabases(ctx, h.engine.Catalog)
+	}
+	h.Handler.ConnectionClosed(c)
+}
+
+// ComInitDB implements mysql.Handler. Revision databases such as `mydb/feature` are registered with the engine and the
+// session before the database is selected.
+func (h *doltHandler) ComInitDB(c *mysql.Conn, schemaName string) error {
+	ctx, err := h.sm.NewContext(c)
+	if err != nil {
+		return err
+	}
+
+	err = dsqle.RegisterRevisionDatabase(ctx, h.engine.Catalog, schemaName)
+	if err != nil {
+		return err
+	}
+
+	return h.Handler.ComInitDB(c, schemaName)
+}
+
+// ComQuery implements mysql.Handler. Each query runs in the session's active transaction, or in a new one if there is
+// none, and is recorded in the server's metrics and slow query log. Statements that manage user accounts or check
+// constraints, which the SQL engine doesn't support, are run by the handler instead.
+func (h *doltHandler) ComQuery(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) (err error) {
+	start := time.Now()
+	callback, rowsSent := countRowsSent(callback)
+	defer func() {
+		h.queryFinished(c, query, start, *rowsSent, err)
+	}()
+
+	stmt, err := dsqle.ParseAccountStatement(query)
+	if err != nil {
+		return err
+	} else if stmt != nil {
+		return h.runAccountStatement(c, query, stmt, callback)
+	}
+
+	if usesChecks(query) {
+		return h.queryWithChecks(c, query, callback)
+	}
+
+	return h.inTransaction(c, query, func() error {
+		return h.Handler.ComQuery(c, query, callback)
+	})
+}
+
+// ComStmtExecute implements mysql.Handler. Each statement runs in the session's active transaction, or in a new one if
+// there is none, and is recorded in the server's metrics and slow query log. Prepared statements that manage user
+// accounts or check constraints are run by the handler, as they are by ComQuery.
+func (h *doltHandler) ComStmtExecute(c *mysql.Conn, prepare *mysql.PrepareData, callback func(*sqltypes.Result) error) (err error) {
+	start := time.Now()
+	callback, rowsSent := countRowsSent(callback)
+	defer func() {
+		h.queryFinished(c, prepare.PrepareStmt, start, *rowsSent, err)
+	}()
+
+	stmt, err := dsqle.ParseAccountStatement(prepare.PrepareStmt)
+	if err != nil {
+		return err
+	} else if stmt != nil {
+		return h.runAccountStatement(c, prepare.PrepareStmt, stmt, callback)
+	}
+
+	if usesChecks(prepare.PrepareStmt) {
+		return h.queryWithChecks(c, prepare.PrepareStmt, callback)
+	}
+
+	return h.inTransaction(c, prepare.PrepareStmt, func() error {
+		return h.Handler.ComStmtExecute(c, prepare, callback)
+	})
+}
+
+// ComPrepare implements mysql.Handler. Statements that manage user accounts or check constraints are prepared without
+// the SQL engine, and run by ComStmtExecute.
+func (h *doltHandler) ComPrepare(c *mysql.Conn, query string) ([]*querypb.Field, error) {
+	if stmt, err := dsqle.ParseAccountStatement(query); err != nil {
+		return nil, err
+	} else if stmt != nil {
+		if sch := stmt.Schema(c.User); !sch.Equals(sql.OkResultSchema) {
+			return schemaFields(sch), nil
+		}
+		return nil, nil
+	}
+
+	if usesChecks(query) {
+		return nil, nil
+	}
+
+	var fields []*querypb.Field
+	err := h.inTransaction(c, query, func() error {
+		var err error
+		fields, err = h.Handler.ComPrepare(c, query)
+		return err
+	})
+
+	return fields, err
+}
+
+// usesChecks returns whether |query| adds, drops or shows check constraints, which the SQL engine doesn't support on
+// its own. These statements are run by dsqle.QueryWithChecks.
+func usesChecks(query string) bool {
+	if checkStmt, err := dsqle.ParseCheckStatement(query); err != nil || checkStmt != nil {
+		return true
+	}
+	_, _, ok := dsqle.ParseShowCreateTable(query)
+	return ok
+}
+
+// queryWithChecks runs |query| with dsqle.QueryWithChecks in the active transaction of the connection's session, see
+// usesChecks. The transaction is committed afterwards when autocommit is enabled, as the SQL engine does for statements
+// it runs.
+func (h *doltHandler) queryWithChecks(c *mysql.Conn, query string, callback func(*sqltypes.Result) error) error {
+	return h.inTransaction(c, query, func() error {
+		ctx, err := h.sm.NewContextWithQuery(c, query)
+		if err != nil {
+			return err
+		}
+
+		sch, iter, err := dsqle.QueryWithChecks(ctx, h.engine, query)
+		var result *sqltypes.Result
+		if err == nil {
+			result, err = rowsResult(ctx, sch, iter)
+		}
+		if err != nil {
+			sqlErr, _ := sql.CastSQLError(err)
+			return sqlErr
+		}
+
+		dsess := dsqle.DSessFromSess(ctx.Session)
+		if dsess.IsAutocommit() {
+			err = dsess.CommitTransaction(ctx)
+			if err != nil {
+				sqlErr, _ := sql.CastSQLError(err)
+				return sqlErr
+			}
+		}
+
+		return callback(result)
+	})
+}
+
+// runAccountStatement runs |stmt|, a statement that manages user accounts, for the connection's session.
+func (h *doltHandler) runAccountStatement(c *mysql.Conn, query string, stmt *dsqle.AccountStatement, callback func(*sqltypes.Result) error) error {
+	ctx, err := h.sm.NewContextWithQuery(c, query)
+	if err != nil {
+		return err
+	}
+
+	sch, iter, err := execAccountStatement(ctx, h.engine, stmt)
+	var result *sqltypes.Result
+	if err == nil {
+		result, err = rowsResult(ctx, sch, iter)
+	}
+	if err != nil {
+		sqlErr, _ := sql.CastSQLError(err)
+		return sqlErr
+	}
+	return callback(result)
+}
+
+// execAccountStatement runs |stmt| with dsqle.ExecAccountStatement, recording it in the audit log of |engine| as the
+// statements run by the engine are.
+func execAccountStatement(ctx *sql.Context, engine *sqle.Engine, stmt *dsqle.AccountStatement) (sql.Schema, sql.RowIter, error) {
+	start := time.Now()
+	sch, iter, err := dsqle.ExecAccountStatement(ctx, stmt)
+	if audit, ok := engine.Auth.(*auth.Audit); ok {
+		audit.Query(ctx, time.Since(start), err)
+	}
+	return sch, iter, err
+}
+
+// rowsResult returns the rows of |iter|, whose schema is |sch|, as they're sent to clients.
+func rowsResult(ctx *sql.Context, sch sql.Schema, iter sql.RowIter) (*sqltypes.Result, error) {
+	rows, err := sql.RowIterToRows(ctx, iter)
+	if err != nil {
+		return nil, err
+	}
+
+	if sch.Equals(sql.OkResultSchema) {
+		return &sqltypes.Result{}, nil
+	}
+
+	result := &sqltypes.Result{Fields: schemaFields(sch)}
+
+	for _, row := range rows {
+		values := make([]sqltypes.Value, len(row))
+		for i, v := range row {
+			if v == nil {
+				values[i] = sqltypes.NULL
+				continue
+			}
+
+			values[i], err = sch[i].Type.SQL(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	result.RowsAffected = uint64(len(result.Rows))
+
+	return result, nil
+}
+
+func schemaFields(sch sql.Schema) []*querypb.Field {
+	fields := make([]*querypb.Field, len(sch))
+	for i, col := range sch {
+		fields[i] = &querypb.Field{Name: col.Name, Type: col.Type.Type(), Charset: mysql.CharacterSetUtf8}
+	}
+	return fields
+}
+
+// queryFinished records a query that started at |start|, sent |rowsSent| rows to the client and returned |err| in the
+// server's metrics and slow query log.
+func (h *doltHandler) queryFinished(c *mysql.Conn, query string, start time.Time, rowsSent uint64, err error) {
+	duration := time.Since(start)
+	h.metrics.queryFinished(query, start, err)
+
+	ctx, ctxErr := h.sm.NewContextWithQuery(c, query)
+	if ctxErr != nil {
+		return
+	}
+
+	// the rows examined are counted by the session, and must be reset after every query
+	var rowsExamined uint64
+	if dsess, ok := ctx.Session.(*dsqle.DoltSession); ok {
+		rowsExamined = dsess.ResetRowsExamined()
+	}
+
+	h.slowQueries.queryFinished(ctx, query, start, duration, rowsSent, rowsExamined)
+}
+
+// countRowsSent returns |callback| wrapped to count the rows it sends to the client, and the count.
+func countRowsSent(callback func(*sqltypes.Result) error) (func(*sqltypes.Result) error, *uint64) {
+	var rowsSent uint64
+	return func(res *sqltypes.Result) error {
+		rowsSent += uint64(len(res.Rows))
+		return callback(res)
+	}, &rowsSent
+}
+
+// inTransaction runs |f|, which executes the statement |query|, in the active transaction of the connection's session,
+// starting one if necessary. Failed transaction commits are reported to the client as deadlocks, so that clients know to
+// retry them.
+func (h *doltHandler) inTransaction(c *mysql.Conn, query string, f func() error) error {
+	ctx, err := h.sm.NewContext(c)
+	if err != nil {
+		return err
+	}
+
+	dsess := dsqle.DSessFromSess(ctx.Session)
+	if !dsess.InTransaction() {
+		dsqle.RemoveDroppedDatabases(ctx, h.engine.Catalog)
+		err = h.replicas.startTransaction(ctx, dsess, query)
+		if err != nil {
+			return err
+		}
+	}
+	defer dsess.FinishStatement(ctx)
+
+	err = f()
+	if sqlErr, ok := err.(*mysql.SQLError); ok && strings.HasPrefix(sqlErr.Message, dsqle.RetryTransactionMsg) {
+		return mysql.NewSQLError(mysql.ERLockDeadlock, mysql.SSLockDeadlock, "%s", sqlErr.Message)
+	}
+
+	return err
+}
+
+// serverOptions are the options of a server created by newServer which server.Config doesn't have
+type serverOptions struct {
+	// socket is a unix socket the server also listens on, if it's not empty
+	socket string
+	// timeouts are the current read and write timeouts of connections. They're used rather than the timeouts of the
+	// server.Config, so that they can change while the server runs.
+	timeouts    *connTimeouts
+	metrics     *serverMetrics
+	slowQueries *slowQueryLog
+	// replicas are the read replicas, which transactions read as of when they start
+	replicas *readReplicas
+	// tlsConfig is offered to connections if it's not nil
+	tlsConfig *tls.Config
+	// requireSecureTransport rejects connections which don't use TLS
+	requireSecureTransport bool
+}
+
+// newServer creates a server.Server the same way server.NewServer does, but with a doltHandler handling its
+// connections, configured by |opts|.
+func newServer(cfg server.Config, e *sqle.Engine, sb server.SessionBuilder, opts serverOptions) (*server.Server, error) {
+	var tracer opentracing.Tracer
+	if cfg.Tracer != nil {
+		tracer = cfg.Tracer
+	} else {
+		tracer = opentracing.NoopTracer{}
+	}
+
+	if cfg.ConnReadTimeout < 0 {
+		cfg.ConnReadTimeout = 0
+	}
+
+	if cfg.ConnWriteTimeout < 0 {
+		cfg.ConnWriteTimeout = 0
+	}
+
+	sm := server.NewSessionManager(sb, tracer, e.Catalog.HasDB, e.Catalog.MemoryManager, cfg.Address)
+	handler := &doltHandler{
+		Handler:     server.NewHandler(e, sm, cfg.ConnReadTimeout),
+		engine:      e,
+		sm:          sm,
+		metrics:     opts.metrics,
+		slowQueries: opts.slowQueries,
+		replicas:    opts.replicas,
+	}
+
+	var l net.Listener
+	l, err := server.NewListener(cfg.Protocol, cfg.Address, handler.Handler)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.socket != "" {
+		l, err = addSocketListener(l, opts.socket, handler.Handler)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	vtListener, err := mysql.NewListenerWithConfig(mysql.ListenerConfig{
+		Listener:           timeoutListener{l, opts.timeouts},
+		AuthServer:         cfg.Auth.Mysql(),
+		Handler:            handler,
+		MaxConns:           cfg.MaxConnections,
+		ConnReadBufferSize: mysql.DefaultConnBufferSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Version != "" {
+		vtListener.ServerVersion = cfg.Version
+	}
+	vtListener.TLSConfig = opts.tlsConfig
+	vtListener.RequireSecureTransport = opts.requireSecureTransport
+
+	return &server.Server{Listener: vtListener}, nil
+}
+
+// addSocketListener returns a listener that accepts the connections of |l| and of a new listener on the unix socket
+// |socket|. |l| is closed if the socket can't be listened on.
+func addSocketListener(l net.Listener, socket string, handler *server.Handler) (net.Listener, error) {
+	err := removeStaleSocket(socket)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+
+	socketListener, err := server.NewListener("unix", socket, handler)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+
+	return newMultiListener(l, socketListener), nil
+}
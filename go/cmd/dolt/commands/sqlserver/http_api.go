@@ -0,0 +1,815 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+const (
+	httpAPIPrefix   = "/api/v1"
+	httpAPIRealm    = "dolt"
+	defaultLogLimit = 100
+	// the sessions and queries of HTTP requests get ids far above those of MySQL connections, so that they don't
+	// share entries of the process list
+	httpConnIDBase = 1 << 31
+	httpPidBase    = 1 << 62
+)
+
+// httpError is an error with the HTTP status that it's reported with.
+type httpError struct {
+	status int
+	msg    string
+	// conflicts are the tables of a merge that have conflicts
+	conflicts []string
+}
+
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+func newHTTPError(status int, format string, args ...interface{}) *httpError {
+	return &httpError{status: status, msg: fmt.Sprintf(format, args...)}
+}
+
+// httpAPI serves the HTTP API of a server. Every request is authenticated as a user of the server, and runs in a
+// session of its own with the same privileges as a MySQL connection of the user.
+type httpAPI struct {
+	engine     *sqle.Engine
+	sessions   sessionFactory
+	users      *dsqle.PrivilegeStore
+	privileges dsqle.PrivilegeChecker
+	metrics    *serverMetrics
+	replicas   *readReplicas
+	host       string
+
+	connID uint32
+	pid    uint64
+}
+
+// newHTTPServer returns the server of the HTTP API |api|, listening on |host| and |port|. The API is served over TLS
+// with |tlsConfig| if it's not nil, as the credentials of every request are sent with it.
+func newHTTPServer(host string, port int, api *httpAPI, tlsConfig *tls.Config) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc(httpAPIPrefix+"/query", api.authenticated(api.serveQuery))
+	mux.HandleFunc(httpAPIPrefix+"/databases/", api.authenticated(api.serveDatabase))
+	return &http.Server{
+		Addr:      net.JoinHostPort(host, strconv.Itoa(port)),
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+	}
+}
+
+// serveHTTP serves |srv| on |l|, over TLS if the server has a TLS config.
+func serveHTTP(srv *http.Server, l net.Listener) error {
+	if srv.TLSConfig != nil {
+		// the certificates are in the TLS config, so no files are given
+		return srv.ServeTLS(l, "", "")
+	}
+	return srv.Serve(l)
+}
+
+// httpSession is the session of an HTTP request.
+type httpSession struct {
+	user string
+	sess sql.Session
+	ir   *sql.IndexRegistry
+	vr   *sql.ViewRegistry
+}
+
+// apiHandler handles a request of the session |s|, returning the value of the JSON response.
+type apiHandler func(r *http.Request, s *httpSession) (interface{}, error)
+
+// authenticated returns an http.HandlerFunc that authenticates requests with HTTP basic authentication, and passes
+// them to |h| with a new session of the user. The revision databases the session used are released once |h| returns.
+func (api *httpAPI) authenticated(h apiHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || !api.users.Authenticate(user, password) {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", httpAPIRealm))
+			writeJSONError(w, newHTTPError(http.StatusUnauthorized, "access denied for user '%s'", user))
+			return
+		}
+
+		connID := httpConnIDBase + atomic.AddUint32(&api.connID, 1)
+		sess, ir, vr, err := api.sessions(r.Context(), api.host, r.RemoteAddr, user, connID)
+		if err != nil {
+			writeJSONError(w, err)
+			return
+		}
+
+		res, err := h(r, &httpSession{user: user, sess: sess, ir: ir, vr: vr})
+		dsqle.ReleaseRevisionDatabases(sql.NewContext(r.Context(), sql.WithSession(sess)), api.engine.Catalog)
+		if err != nil {
+			writeJSONError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// newContext returns the context of the query |query| of the session |s|, using the database |dbName|. Revision
+// databases are registered, and an error is returned if the database doesn't exist.
+func (api *httpAPI) newContext(r *http.Request, s *httpSession, dbName, query string) (*sql.Context, error) {
+	ctx := sql.NewContext(
+		r.Context(),
+		sql.WithSession(s.sess),
+		sql.WithPid(httpPidBase+atomic.AddUint64(&api.pid, 1)),
+		sql.WithQuery(query),
+		sql.WithMemoryManager(api.engine.Catalog.MemoryManager),
+		sql.WithIndexRegistry(s.ir),
+		sql.WithViewRegistry(s.vr),
+	)
+
+	if dbName == "" {
+		return ctx, nil
+	}
+
+	err := dsqle.RegisterRevisionDatabase(ctx, api.engine.Catalog, dbName)
+	if err != nil && !sql.ErrDatabaseNotFound.Is(err) {
+		return nil, err
+	}
+
+	if !api.engine.Catalog.HasDB(dbName) {
+		return nil, newHTTPError(http.StatusNotFound, "database not found: %s", dbName)
+	}
+
+	ctx.SetCurrentDatabase(dbName)
+	return ctx, nil
+}
+
+// query runs |query| on the database |dbName| in a transaction of its own, which is committed if the query succeeds.
+func (api *httpAPI) query(r *http.Request, s *httpSession, dbName, query string) (res *queryResult, err error) {
+	start := time.Now()
+	defer func() {
+		api.metrics.queryFinished(query, start, err)
+	}()
+
+	ctx, err := api.newContext(r, s, dbName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	dsess := dsqle.DSessFromSess(ctx.Session)
+	err = api.replicas.startTransaction(ctx, dsess, query)
+	if err != nil {
+		return nil, err
+	}
+	defer dsess.FinishStatement(ctx)
+
+	accountStmt, err := dsqle.ParseAccountStatement(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var sch sql.Schema
+	var iter sql.RowIter
+	if accountStmt != nil {
+		sch, iter, err = execAccountStatement(ctx, api.engine, accountStmt)
+	} else {
+		sch, iter, err = dsqle.QueryWithChecks(ctx, api.engine, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sql.RowIterToRows(ctx, iter)
+	if err != nil {
+		_ = iter.Close(ctx)
+		return nil, err
+	}
+
+	err = ctx.Session.CommitTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQueryResult(sch, rows)
+}
+
+// column is a column of the schema of a query result.
+type column struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// queryResult is the result set of a query. Statements that don't return rows, such as INSERT, only have the number
+// of rows they affected.
+type queryResult struct {
+	Schema       []column        `json:"schema"`
+	Rows         [][]interface{} `json:"rows"`
+	RowsAffected *uint64         `json:"rows_affected,omitempty"`
+	InsertID     *uint64         `json:"insert_id,omitempty"`
+}
+
+func newQueryResult(sch sql.Schema, rows []sql.Row) (*queryResult, error) {
+	res := &queryResult{Schema: []column{}, Rows: [][]interface{}{}}
+	if len(rows) == 1 && len(rows[0]) == 1 {
+		if ok, isOk := rows[0][0].(sql.OkResult); isOk {
+			rowsAffected, insertID := ok.RowsAffected, ok.InsertID
+			res.RowsAffected = &rowsAffected
+			if insertID != 0 {
+				res.InsertID = &insertID
+			}
+			return res, nil
+		}
+	}
+
+	for _, col := range sch {
+		res.Schema = append(res.Schema, column{Name: col.Name, Type: col.Type.String()})
+	}
+
+	for _, row := range rows {
+		vals := make([]interface{}, len(row))
+		for i, v := range row {
+			var err error
+			vals[i], err = jsonValue(sch[i].Type, v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		res.Rows = append(res.Rows, vals)
+	}
+
+	return res, nil
+}
+
+// objects returns the rows of the result as objects keyed by column name.
+func (res *queryResult) objects() []map[string]interface{} {
+	objs := make([]map[string]interface{}, 0, len(res.Rows))
+	for _, row := range res.Rows {
+		obj := make(map[string]interface{}, len(row))
+		for i, v := range row {
+			obj[res.Schema[i].Name] = v
+		}
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+// jsonValue returns the value |v| of the type |typ| as it's encoded in JSON. Integers and floats are JSON numbers,
+// JSON documents are embedded as is, and every other value is a string in its MySQL text format, so that decimals
+// keep their precision.
+func jsonValue(typ sql.Type, v interface{}) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	if sql.IsInteger(typ) || sql.IsFloat(typ) {
+		return typ.Convert(v)
+	}
+
+	val, err := typ.SQL(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if typ == sql.JSON {
+		return json.RawMessage(val.ToBytes()), nil
+	}
+
+	return val.ToString(), nil
+}
+
+// queryRequest is the request body of the query endpoint.
+type queryRequest struct {
+	Database string `json:"database"`
+	Query    string `json:"query"`
+}
+
+// serveQuery handles POST /api/v1/query, which runs a query and returns its result set.
+func (api *httpAPI) serveQuery(r *http.Request, s *httpSession) (interface{}, error) {
+	if r.Method != http.MethodPost {
+		return nil, newHTTPError(http.StatusMethodNotAllowed, "method %s is not allowed", r.Method)
+	}
+
+	var req queryRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		return nil, newHTTPError(http.StatusBadRequest, "query is required")
+	}
+
+	return api.query(r, s, req.Database, req.Query)
+}
+
+// serveDatabase handles the requests for /api/v1/databases/<database>/<resource>.
+func (api *httpAPI) serveDatabase(r *http.Request, s *httpSession) (interface{}, error) {
+	path := strings.TrimPrefix(r.URL.Path, httpAPIPrefix+"/databases/")
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 {
+		return nil, newHTTPError(http.StatusNotFound, "not found: %s", r.URL.Path)
+	}
+	dbName, resource := path[:idx], path[idx+1:]
+
+	if _, _, ok := dsqle.SplitRevisionDbName(dbName); ok {
+		return nil, newHTTPError(http.StatusBadRequest, "%s is a revision database; the branch is given as a parameter", dbName)
+	}
+
+	if !api.engine.Catalog.HasDB(dbName) {
+		return nil, newHTTPError(http.StatusNotFound, "database not found: %s", dbName)
+	}
+
+	switch {
+	case resource == "branches" && r.Method == http.MethodGet:
+		return api.listBranches(r, s, dbName)
+	case resource == "branches" && r.Method == http.MethodPost:
+		return api.createBranch(r, s, dbName)
+	case resource == "commits" && r.Method == http.MethodGet:
+		return api.listCommits(r, s, dbName)
+	case resource == "diffs" && r.Method == http.MethodGet:
+		return api.diff(r, s, dbName)
+	case resource == "merges" && r.Method == http.MethodPost:
+		return api.merge(r, s, dbName)
+	case resource == "branches", resource == "commits", resource == "diffs", resource == "merges":
+		return nil, newHTTPError(http.StatusMethodNotAllowed, "method %s is not allowed", r.Method)
+	default:
+		return nil, newHTTPError(http.StatusNotFound, "not found: %s", r.URL.Path)
+	}
+}
+
+// listBranches handles GET /api/v1/databases/<database>/branches, which returns the rows of dolt_branches.
+func (api *httpAPI) listBranches(r *http.Request, s *httpSession, dbName string) (interface{}, error) {
+	res, err := api.query(r, s, dbName, "SELECT * FROM "+doltdb.BranchesTableName+" ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	return res.objects(), nil
+}
+
+// branchRequest is the request body of the branches endpoint.
+type branchRequest struct {
+	Name string `json:"name"`
+	// StartPoint is the branch or commit that the new branch starts at, the checked out branch if empty
+	StartPoint string `json:"start_point"`
+}
+
+// createBranch handles POST /api/v1/databases/<database>/branches, which creates a branch. Creating a branch requires
+// write privileges on every table of it.
+func (api *httpAPI) createBranch(r *http.Request, s *httpSession, dbName string) (interface{}, error) {
+	var req branchRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, newHTTPError(http.StatusBadRequest, "name is required")
+	}
+
+	if !api.privileges.HasTablePrivilege(s.user, dbName, req.Name, "", auth.WritePerm) {
+		return nil, dsqle.ErrBranchAccessDenied.New("CREATE BRANCH", s.user, req.Name, dbName)
+	}
+
+	ctx, err := api.newContext(r, s, dbName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := api.doltDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	headRef := db.GetStateReader().CWBHeadRef()
+	startPoint := req.StartPoint
+	if startPoint == "" {
+		startPoint = headRef.GetPath()
+	}
+
+	err = actions.CreateBranchOnDB(ctx, db.GetDoltDB(), req.Name, startPoint, false, headRef)
+	if err == actions.ErrAlreadyExists {
+		return nil, newHTTPError(http.StatusConflict, "a branch named '%s' already exists", req.Name)
+	} else if err != nil {
+		return nil, err
+	}
+
+	dsqle.DSessFromSess(ctx.Session).NotifyBranchUpdated(dbName)
+
+	res, err := api.query(r, s, dbName, "SELECT * FROM "+doltdb.BranchesTableName+" WHERE name = "+sqlString(req.Name))
+	if err != nil {
+		return nil, err
+	}
+	return res.objects()[0], nil
+}
+
+// listCommits handles GET /api/v1/databases/<database>/commits, which returns the rows of dolt_log for the branch
+// given by the |branch| parameter, or the checked out branch. At most |limit| commits are returned, 100 by default.
+func (api *httpAPI) listCommits(r *http.Request, s *httpSession, dbName string) (interface{}, error) {
+	limit := defaultLogLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit < 0 {
+			return nil, newHTTPError(http.StatusBadRequest, "limit must be a non-negative integer: %s", limitStr)
+		}
+	}
+
+	if branch := r.URL.Query().Get("branch"); branch != "" {
+		dbName = dbName + dsqle.DbRevisionDelimiter + branch
+	}
+
+	res, err := api.query(r, s, dbName, fmt.Sprintf("SELECT * FROM %s LIMIT %d", doltdb.LogTableName, limit))
+	if err != nil {
+		return nil, err
+	}
+	return res.objects(), nil
+}
+
+// tableDiff is a table that changed between two commits.
+type tableDiff struct {
+	Table    string `json:"table"`
+	FromName string `json:"from_name,omitempty"`
+	Change   string `json:"change"`
+}
+
+// diff handles GET /api/v1/databases/<database>/diffs, which diffs the commits given by the |from| and |to|
+// parameters, either branch names or commit hashes. With a |table| parameter, the rows of dolt_commit_diff_<table>
+// for the two commits are returned, and otherwise the tables that changed.
+func (api *httpAPI) diff(r *http.Request, s *httpSession, dbName string) (interface{}, error) {
+	params := r.URL.Query()
+	from, to, table := params.Get("from"), params.Get("to"), params.Get("table")
+	if from == "" || to == "" {
+		return nil, newHTTPError(http.StatusBadRequest, "from and to are required")
+	}
+
+	if table != "" {
+		if !doltdb.IsValidTableName(table) {
+			return nil, newHTTPError(http.StatusBadRequest, "invalid table name: %s", table)
+		}
+
+		query := fmt.Sprintf("SELECT * FROM `%s%s` WHERE from_commit = %s AND to_commit = %s", doltdb.DoltCommitDiffTablePrefix, table, sqlString(from), sqlString(to))
+		res, err := api.query(r, s, dbName, query)
+		if err != nil {
+			return nil, err
+		}
+		return res.objects(), nil
+	}
+
+	ctx, err := api.newContext(r, s, dbName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := api.doltDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCm, err := resolveCommit(ctx, db, from)
+	if err != nil {
+		return nil, err
+	}
+	toCm, err := resolveCommit(ctx, db, to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRoot, err := fromCm.GetRootValue()
+	if err != nil {
+		return nil, err
+	}
+	toRoot, err := toCm.GetRootValue()
+	if err != nil {
+		return nil, err
+	}
+
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]tableDiff, 0, len(deltas))
+	for _, td := range deltas {
+		if !api.canReadTable(ctx, db, s.user, from, td.FromName) || !api.canReadTable(ctx, db, s.user, to, td.ToName) {
+			continue
+		}
+
+		d := tableDiff{Table: td.CurName(), Change: "modified"}
+		switch {
+		case td.IsAdd():
+			d.Change = "added"
+		case td.IsDrop():
+			d.Change = "dropped"
+		case td.IsRename():
+			d.Change, d.FromName = "renamed", td.FromName
+		}
+		diffs = append(diffs, d)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Table < diffs[j].Table
+	})
+
+	return diffs, nil
+}
+
+// canReadTable returns whether |user| may read the table |table| of the revision |rev| of |db|. Revisions that aren't
+// branches are checked as commits.
+func (api *httpAPI) canReadTable(ctx *sql.Context, db dsqle.Database, user, rev, table string) bool {
+	if table == "" {
+		return true
+	}
+
+	var branch string
+	if isBranch, err := actions.IsBranchOnDB(ctx, db.GetDoltDB(), rev); err == nil && isBranch {
+		branch = rev
+	}
+
+	return api.privileges.HasTablePrivilege(user, db.Name(), branch, table, auth.ReadPerm)
+}
+
+// mergeRequest is the request body of the merges endpoint.
+type mergeRequest struct {
+	// From is the branch or commit that is merged
+	From string `json:"from"`
+	// Into is the branch that From is merged into
+	Into string `json:"into"`
+	// Message is the message of the merge commit, if one is created
+	Message string `json:"message"`
+}
+
+// mergeResult is the response of the merges endpoint.
+type mergeResult struct {
+	Commit      string `json:"commit"`
+	FastForward bool   `json:"fast_forward"`
+}
+
+// merge handles POST /api/v1/databases/<database>/merges, which merges a branch or commit into a branch. The branch
+// is fast-forwarded when possible, and otherwise a merge commit is created, unless the merge has conflicts. A merge
+// into the checked out branch of the database requires that it has no uncommitted changes, and updates its working
+// set. Merging requires write privileges on every table of the branch merged into.
+func (api *httpAPI) merge(r *http.Request, s *httpSession, dbName string) (interface{}, error) {
+	var req mergeRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+
+	if req.From == "" || req.Into == "" {
+		return nil, newHTTPError(http.StatusBadRequest, "from and into are required")
+	}
+
+	if !api.privileges.HasTablePrivilege(s.user, dbName, req.Into, "", auth.WritePerm) {
+		return nil, dsqle.ErrBranchAccessDenied.New("MERGE", s.user, req.Into, dbName)
+	}
+
+	ctx, err := api.newContext(r, s, dbName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := api.doltDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	ddb := db.GetDoltDB()
+	intoRef := ref.NewBranchRef(req.Into)
+	if isBranch, err := ddb.HasRef(ctx, intoRef); err != nil {
+		return nil, err
+	} else if !isBranch {
+		return nil, newHTTPError(http.StatusNotFound, "branch not found: %s", req.Into)
+	}
+
+	intoCm, err := ddb.ResolveRef(ctx, intoRef)
+	if err != nil {
+		return nil, err
+	}
+
+	fromCm, err := resolveCommit(ctx, db, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	dsess := dsqle.DSessFromSess(ctx.Session)
+	dbData, ok := dsess.GetDbData(dbName)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	checkedOut := ref.Equals(intoRef, dbData.Rsr.CWBHeadRef())
+	if checkedOut {
+		headRoot, err := intoCm.GetRootValue()
+		if err != nil {
+			return nil, err
+		}
+
+		headHash, err := headRoot.HashOf()
+		if err != nil {
+			return nil, err
+		}
+
+		if headHash != dbData.Rsr.WorkingHash() {
+			return nil, newHTTPError(http.StatusConflict, "branch %s is checked out and has uncommitted changes", req.Into)
+		}
+	}
+
+	canFF, err := intoCm.CanFastForwardTo(ctx, fromCm)
+	if err == doltdb.ErrUpToDate || err == doltdb.ErrIsAhead {
+		return nil, newHTTPError(http.StatusConflict, "branch %s is already up to date with %s", req.Into, req.From)
+	} else if err != nil {
+		return nil, err
+	}
+
+	mergedCm := fromCm
+	if canFF {
+		err = ddb.FastForward(ctx, intoRef, fromCm)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		mergedCm, err = mergeCommits(ctx, dsess, ddb, intoRef, intoCm, fromCm, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if checkedOut {
+		mergedRoot, err := mergedCm.GetRootValue()
+		if err != nil {
+			return nil, err
+		}
+
+		err = dsqle.ResetWorkingRoot(ctx, dbData, mergedRoot)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dsess.NotifyMerged(dbName, false)
+	dsess.NotifyBranchUpdated(dbName)
+
+	h, err := mergedCm.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeResult{Commit: h.String(), FastForward: canFF}, nil
+}
+
+// mergeCommits merges |fromCm| into |intoCm|, the head of the branch |intoRef|, and commits the result to the branch.
+// Returns an httpError listing the tables with conflicts if the merge has any.
+func mergeCommits(ctx *sql.Context, dsess *dsqle.DoltSession, ddb *doltdb.DoltDB, intoRef ref.DoltRef, intoCm, fromCm *doltdb.Commit, req mergeRequest) (*doltdb.Commit, error) {
+	mergedRoot, stats, err := merge.MergeCommits(ctx, intoCm, fromCm, dsqle.MergeCheckEvaluator{})
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []string
+	for tblName, stat := range stats {
+		if stat.Conflicts > 0 {
+			conflicts = append(conflicts, tblName)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		dsess.NotifyMerged(ctx.GetCurrentDatabase(), true)
+		return nil, &httpError{
+			status:    http.StatusConflict,
+			msg:       fmt.Sprintf("merging %s into %s has conflicts in tables %s", req.From, req.Into, strings.Join(conflicts, ", ")),
+			conflicts: conflicts,
+		}
+	}
+
+	h, err := ddb.WriteRootValue(ctx, mergedRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := req.Message
+	if msg == "" {
+		msg = fmt.Sprintf("Merge %s into %s", req.From, req.Into)
+	}
+
+	meta, err := doltdb.NewCommitMeta(dsess.Username, dsess.Email, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return ddb.CommitWithParentCommits(ctx, h, intoRef, []*doltdb.Commit{fromCm}, meta)
+}
+
+// doltDatabase returns the dolt database |dbName| of the server.
+func (api *httpAPI) doltDatabase(dbName string) (dsqle.Database, error) {
+	sqlDb, err := api.engine.Catalog.Database(dbName)
+	if err != nil {
+		return dsqle.Database{}, newHTTPError(http.StatusNotFound, "database not found: %s", dbName)
+	}
+
+	db, ok := sqlDb.(dsqle.Database)
+	if !ok {
+		return dsqle.Database{}, newHTTPError(http.StatusBadRequest, "%s is not a dolt database", dbName)
+	}
+
+	return db, nil
+}
+
+// resolveCommit returns the commit of |db| named by |rev|, a branch name or commit hash.
+func resolveCommit(ctx *sql.Context, db dsqle.Database, rev string) (*doltdb.Commit, error) {
+	cs, err := doltdb.NewCommitSpec(rev)
+	if err != nil {
+		return nil, newHTTPError(http.StatusBadRequest, "invalid commit: %s", rev)
+	}
+
+	cm, err := db.GetDoltDB().Resolve(ctx, cs, db.GetStateReader().CWBHeadRef())
+	if err != nil {
+		return nil, newHTTPError(http.StatusNotFound, "%s is not a branch or commit of database %s", rev, db.Name())
+	}
+
+	return cm, nil
+}
+
+// sqlString returns |s| as an SQL string literal.
+func sqlString(s string) string {
+	buf := &bytes.Buffer{}
+	sqltypes.NewVarChar(s).EncodeSQL(buf)
+	return buf.String()
+}
+
+// readJSON decodes the JSON body of |r| into |v|.
+func readJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return newHTTPError(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Warnf("failed to write HTTP API response: %v", err)
+	}
+}
+
+// errorResponse is the response of failed requests.
+type errorResponse struct {
+	Error     string   `json:"error"`
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// writeJSONError writes |err| with the HTTP status that matches it. Errors of queries are bad requests, unless they
+// were denied access or should be retried.
+func writeJSONError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	res := errorResponse{Error: err.Error()}
+
+	var httpErr *httpError
+	switch {
+	case errors.As(err, &httpErr):
+		status = httpErr.status
+		res.Conflicts = httpErr.conflicts
+	case sql.ErrDatabaseNotFound.Is(err), sql.ErrTableNotFound.Is(err):
+		status = http.StatusNotFound
+	case auth.ErrNotAuthorized.Is(err),
+		dsqle.ErrTableAccessDenied.Is(err),
+		dsqle.ErrBranchAccessDenied.Is(err),
+		dsqle.ErrDatabaseAccessDenied.Is(err):
+		status = http.StatusForbidden
+	case dsqle.ErrRetryTransaction.Is(err):
+		status = http.StatusConflict
+	}
+
+	writeJSON(w, status, res)
+}
@@ -0,0 +1,305 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+const httpAPITestURL = "http://localhost:15312" + httpAPIPrefix
+
+// apiRequest sends a request to the HTTP API of the test server as |user| and decodes the JSON response into |res|.
+// Returns the status of the response.
+func apiRequest(t *testing.T, method, path, user, password string, body, res interface{}) int {
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+
+	req, err := http.NewRequest(method, httpAPITestURL+path, bytes.NewReader(data))
+	require.NoError(t, err)
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if res != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+	return resp.StatusCode
+}
+
+func TestJSONValue(t *testing.T) {
+	tests := []struct {
+		typ      sql.Type
+		val      interface{}
+		expected interface{}
+	}{
+		{sql.Int32, int32(5), int32(5)},
+		{sql.Float64, 1.5, 1.5},
+		{sql.Text, "abc", "abc"},
+		{sql.MustCreateDecimalType(5, 2), "1.5", "1.50"},
+		{sql.Datetime, "2021-03-04 05:06:07", "2021-03-04 05:06:07"},
+		{sql.JSON, []byte(`{"a":1}`), json.RawMessage(`{"a":1}`)},
+		{sql.Int64, nil, nil},
+	}
+
+	for _, test := range tests {
+		val, err := jsonValue(test.typ, test.val)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, val, test.typ.String())
+	}
+}
+
+func TestHTTPAPI(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateEnvWithSeedData(t)
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+	commitRoot(t, dEnv, root, "add people")
+
+	serverConfig, err := newYamlConfig([]byte(`
+log_level: fatal
+
+listener:
+    port: 15311
+
+http_api:
+    host: localhost
+    port: 15312
+
+users:
+    - name: reader
+      password: pass
+      grants:
+          - database: dolt
+            permissions: [read]
+`))
+	require.NoError(t, err)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, dEnv)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	query := func(db, q string) (*queryResult, int) {
+		var res queryResult
+		status := apiRequest(t, http.MethodPost, "/query", "root", "", queryRequest{Database: db, Query: q}, &res)
+		return &res, status
+	}
+
+	t.Run("authentication", func(t *testing.T) {
+		var res errorResponse
+		assert.Equal(t, http.StatusUnauthorized, apiRequest(t, http.MethodPost, "/query", "", "", queryRequest{Query: "SELECT 1"}, &res))
+		assert.Equal(t, http.StatusUnauthorized, apiRequest(t, http.MethodPost, "/query", "reader", "wrong", queryRequest{Query: "SELECT 1"}, &res))
+		assert.Equal(t, http.StatusOK, apiRequest(t, http.MethodPost, "/query", "reader", "pass", queryRequest{Query: "SELECT 1"}, nil))
+	})
+
+	t.Run("query", func(t *testing.T) {
+		res, status := query("dolt", "CREATE TABLE t (pk INT PRIMARY KEY, d DECIMAL(5,2), s VARCHAR(10))")
+		require.Equal(t, http.StatusOK, status)
+		res, status = query("dolt", "INSERT INTO t VALUES (1, 1.5, 'a'), (2, NULL, 'b')")
+		require.Equal(t, http.StatusOK, status)
+		require.NotNil(t, res.RowsAffected)
+		assert.Equal(t, uint64(2), *res.RowsAffected)
+
+		res, status = query("dolt", "SELECT * FROM t ORDER BY pk")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, []column{{"pk", "INT"}, {"d", "DECIMAL(5,2)"}, {"s", "VARCHAR(10)"}}, res.Schema)
+		assert.Equal(t, [][]interface{}{{1.0, "1.50", "a"}, {2.0, nil, "b"}}, res.Rows)
+
+		_, status = query("dolt", "SELECT DOLT_COMMIT('-a', '-m', 'add t')")
+		require.Equal(t, http.StatusOK, status)
+
+		var errRes errorResponse
+		assert.Equal(t, http.StatusNotFound, apiRequest(t, http.MethodPost, "/query", "root", "", queryRequest{Database: "nodb", Query: "SELECT 1"}, &errRes))
+		assert.Equal(t, http.StatusBadRequest, apiRequest(t, http.MethodPost, "/query", "root", "", queryRequest{Database: "dolt", Query: "SELEC 1"}, &errRes))
+		assert.Equal(t, http.StatusForbidden, apiRequest(t, http.MethodPost, "/query", "reader", "pass", queryRequest{Database: "dolt", Query: "SELECT DOLT_COMMIT('-m', 'denied')"}, &errRes))
+		assert.Equal(t, http.StatusMethodNotAllowed, apiRequest(t, http.MethodGet, "/query", "root", "", nil, &errRes))
+	})
+
+	t.Run("branches", func(t *testing.T) {
+		var branches []map[string]interface{}
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodGet, "/databases/dolt/branches", "root", "", nil, &branches))
+		require.Len(t, branches, 1)
+		assert.Equal(t, "master", branches[0]["name"])
+		assert.Equal(t, "add t", branches[0]["latest_commit_message"])
+
+		var branch map[string]interface{}
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodPost, "/databases/dolt/branches", "root", "", branchRequest{Name: "feature"}, &branch))
+		assert.Equal(t, "feature", branch["name"])
+		assert.Equal(t, branches[0]["hash"], branch["hash"])
+
+		var errRes errorResponse
+		assert.Equal(t, http.StatusConflict, apiRequest(t, http.MethodPost, "/databases/dolt/branches", "root", "", branchRequest{Name: "feature"}, &errRes))
+		assert.Equal(t, http.StatusForbidden, apiRequest(t, http.MethodPost, "/databases/dolt/branches", "reader", "pass", branchRequest{Name: "other"}, &errRes))
+		assert.Equal(t, http.StatusNotFound, apiRequest(t, http.MethodGet, "/databases/nodb/branches", "root", "", nil, &errRes))
+	})
+
+	t.Run("commits", func(t *testing.T) {
+		_, status := query("dolt/feature", "INSERT INTO t VALUES (3, 2.25, 'c')")
+		require.Equal(t, http.StatusOK, status)
+		_, status = query("dolt/feature", "SELECT DOLT_COMMIT('-a', '-m', 'feature row')")
+		require.Equal(t, http.StatusOK, status)
+
+		var commits []map[string]interface{}
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodGet, "/databases/dolt/commits?branch=feature&limit=2", "root", "", nil, &commits))
+		require.Len(t, commits, 2)
+		assert.Equal(t, "feature row", commits[0]["message"])
+		assert.Equal(t, "add t", commits[1]["message"])
+
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodGet, "/databases/dolt/commits", "reader", "pass", nil, &commits))
+		assert.Len(t, commits, 3)
+	})
+
+	t.Run("diffs", func(t *testing.T) {
+		var tables []tableDiff
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodGet, "/databases/dolt/diffs?from=master&to=feature", "root", "", nil, &tables))
+		assert.Equal(t, []tableDiff{{Table: "t", Change: "modified"}}, tables)
+
+		var rows []map[string]interface{}
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodGet, "/databases/dolt/diffs?from=master&to=feature&table=t", "root", "", nil, &rows))
+		require.Len(t, rows, 1)
+		assert.Equal(t, 3.0, rows[0]["to_pk"])
+		assert.Equal(t, "added", rows[0]["diff_type"])
+
+		var errRes errorResponse
+		assert.Equal(t, http.StatusBadRequest, apiRequest(t, http.MethodGet, "/databases/dolt/diffs?from=master", "root", "", nil, &errRes))
+		assert.Equal(t, http.StatusNotFound, apiRequest(t, http.MethodGet, "/databases/dolt/diffs?from=master&to=nobranch", "root", "", nil, &errRes))
+	})
+
+	t.Run("merges", func(t *testing.T) {
+		var errRes errorResponse
+		assert.Equal(t, http.StatusForbidden, apiRequest(t, http.MethodPost, "/databases/dolt/merges", "reader", "pass", mergeRequest{From: "feature", Into: "master"}, &errRes))
+
+		var merged mergeResult
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodPost, "/databases/dolt/merges", "root", "", mergeRequest{From: "feature", Into: "master"}, &merged))
+		assert.True(t, merged.FastForward)
+
+		// the working set of the checked out branch is updated by the merge
+		res, status := query("dolt", "SELECT COUNT(*) FROM t")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, [][]interface{}{{3.0}}, res.Rows)
+
+		assert.Equal(t, http.StatusConflict, apiRequest(t, http.MethodPost, "/databases/dolt/merges", "root", "", mergeRequest{From: "feature", Into: "master"}, &errRes))
+
+		// diverged branches get a merge commit, unless they conflict
+		_, status = query("dolt/feature", "INSERT INTO t VALUES (4, 1, 'feature')")
+		require.Equal(t, http.StatusOK, status)
+		_, status = query("dolt/feature", "SELECT DOLT_COMMIT('-a', '-m', 'feature 4')")
+		require.Equal(t, http.StatusOK, status)
+		_, status = query("dolt", "INSERT INTO t VALUES (5, 1, 'master')")
+		require.Equal(t, http.StatusOK, status)
+		_, status = query("dolt", "SELECT DOLT_COMMIT('-a', '-m', 'master 5')")
+		require.Equal(t, http.StatusOK, status)
+
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodPost, "/databases/dolt/merges", "root", "", mergeRequest{From: "feature", Into: "master", Message: "merge feature"}, &merged))
+		assert.False(t, merged.FastForward)
+
+		var commits []map[string]interface{}
+		require.Equal(t, http.StatusOK, apiRequest(t, http.MethodGet, "/databases/dolt/commits?limit=1", "root", "", nil, &commits))
+		require.Len(t, commits, 1)
+		assert.Equal(t, merged.Commit, commits[0]["commit_hash"])
+		assert.Equal(t, "merge feature", commits[0]["message"])
+
+		res, status = query("dolt", "SELECT COUNT(*) FROM t")
+		require.Equal(t, http.StatusOK, status)
+		assert.Equal(t, [][]interface{}{{5.0}}, res.Rows)
+
+		_, status = query("dolt/feature", "UPDATE t SET s = 'feature' WHERE pk = 1")
+		require.Equal(t, http.StatusOK, status)
+		_, status = query("dolt/feature", "SELECT DOLT_COMMIT('-a', '-m', 'feature update')")
+		require.Equal(t, http.StatusOK, status)
+		_, status = query("dolt", "UPDATE t SET s = 'master' WHERE pk = 1")
+		require.Equal(t, http.StatusOK, status)
+		_, status = query("dolt", "SELECT DOLT_COMMIT('-a', '-m', 'master update')")
+		require.Equal(t, http.StatusOK, status)
+
+		require.Equal(t, http.StatusConflict, apiRequest(t, http.MethodPost, "/databases/dolt/merges", "root", "", mergeRequest{From: "feature", Into: "master"}, &errRes))
+		assert.Equal(t, []string{"t"}, errRes.Conflicts)
+	})
+}
+
+func TestHTTPAPITLS(t *testing.T) {
+	dEnv := dtestutils.CreateEnvWithSeedData(t)
+	keyPath, certPath, cert := writeSelfSignedCert(t, t.TempDir())
+
+	serverConfig, err := newYamlConfig([]byte(fmt.Sprintf(`
+log_level: fatal
+
+listener:
+    port: 15313
+    tls_key: %s
+    tls_cert: %s
+    require_secure_transport: true
+
+http_api:
+    host: localhost
+    port: 15314
+`, keyPath, certPath)))
+	require.NoError(t, err)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, dEnv)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+
+	body := bytes.NewReader([]byte(`{"query": "SELECT 1"}`))
+	req, err := http.NewRequest(http.MethodPost, "https://localhost:15314"+httpAPIPrefix+"/query", body)
+	require.NoError(t, err)
+	req.SetBasicAuth("root", "")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// plaintext requests, which would send their credentials in the clear, are refused
+	body = bytes.NewReader([]byte(`{"query": "SELECT 1"}`))
+	req, err = http.NewRequest(http.MethodPost, "http://localhost:15314"+httpAPIPrefix+"/query", body)
+	require.NoError(t, err)
+	req.SetBasicAuth("root", "")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
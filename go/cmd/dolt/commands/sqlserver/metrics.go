@@ -0,0 +1,257 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"net"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/metrics"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+const metricsNamespace = "dolt"
+
+// serverMetrics are the Prometheus metrics of a running sql-server. They're served by the server's metrics listener,
+// if it has one.
+type serverMetrics struct {
+	registry *prometheus.Registry
+
+	connections   prometheus.Gauge
+	connects      prometheus.Counter
+	queryDuration *prometheus.HistogramVec
+	queryErrors   *prometheus.CounterVec
+	commits       *prometheus.CounterVec
+	merges        *prometheus.CounterVec
+	conflicts     *prometheus.CounterVec
+}
+
+var _ dsqle.SessionEventListener = (*serverMetrics)(nil)
+
+// newServerMetrics returns the metrics of a server, each labeled with |labels|. Chunk store statistics are collected
+// from the dolt databases returned by |dbs| each time the metrics are read.
+func newServerMetrics(labels map[string]string, dbs func() sql.Databases) (*serverMetrics, error) {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		connections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql_server",
+			Name:      "connections",
+			Help:      "The number of open client connections.",
+		}),
+		connects: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql_server",
+			Name:      "connects_total",
+			Help:      "The number of client connections that have been opened.",
+		}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql_server",
+			Name:      "query_duration_seconds",
+			Help:      "The time taken to run queries, by statement type.",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 10),
+		}, []string{"type"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql_server",
+			Name:      "query_errors_total",
+			Help:      "The number of queries that failed, by statement type.",
+		}, []string{"type"}),
+		commits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql_server",
+			Name:      "commits_total",
+			Help:      "The number of dolt commits created by clients, by database.",
+		}, []string{"database"}),
+		merges: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql_server",
+			Name:      "merges_total",
+			Help:      "The number of merges run by clients, by database.",
+		}, []string{"database"}),
+		conflicts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "sql_server",
+			Name:      "conflicts_total",
+			Help:      "The number of merges that left conflicts and of transactions rolled back because of conflicting concurrent changes, by database.",
+		}, []string{"database", "source"}),
+	}
+
+	reg := prometheus.WrapRegistererWith(labels, m.registry)
+	collectors := []prometheus.Collector{
+		m.connections,
+		m.connects,
+		m.queryDuration,
+		m.queryErrors,
+		m.commits,
+		m.merges,
+		m.conflicts,
+		&chunkStoreCollector{dbs: dbs},
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// connectionOpened records a new client connection.
+func (m *serverMetrics) connectionOpened() {
+	m.connects.Inc()
+	m.connections.Inc()
+}
+
+// connectionClosed records the end of a client connection.
+func (m *serverMetrics) connectionClosed() {
+	m.connections.Dec()
+}
+
+// queryFinished records a query that started at |start| and returned |err|.
+func (m *serverMetrics) queryFinished(query string, start time.Time, err error) {
+	stmtType := strings.ToLower(sqlparser.Preview(query).String())
+	m.queryDuration.WithLabelValues(stmtType).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.queryErrors.WithLabelValues(stmtType).Inc()
+	}
+}
+
+// Committed implements dsqle.SessionEventListener.
+func (m *serverMetrics) Committed(dbName string) {
+	m.commits.WithLabelValues(dbName).Inc()
+}
+
+// Merged implements dsqle.SessionEventListener.
+func (m *serverMetrics) Merged(dbName string, conflicts bool) {
+	m.merges.WithLabelValues(dbName).Inc()
+	if conflicts {
+		m.conflicts.WithLabelValues(dbName, "merge").Inc()
+	}
+}
+
+// BranchUpdated implements dsqle.SessionEventListener.
+func (m *serverMetrics) BranchUpdated(string) {}
+
+// TransactionConflicted implements dsqle.SessionEventListener.
+func (m *serverMetrics) TransactionConflicted(dbName string) {
+	m.conflicts.WithLabelValues(dbName, "transaction").Inc()
+}
+
+// newMetricsServer returns an HTTP server that serves |m| at /metrics on |host|:|port|.
+func newMetricsServer(host string, port int, m *serverMetrics) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	return &http.Server{
+		Addr:    net.JoinHostPort(host, strconv.Itoa(port)),
+		Handler: mux,
+	}
+}
+
+// chunkStoreCollector collects the statistics of the chunk stores of dolt databases, which are read from nbs.Stats
+// and chunks.CSMetrics. It's an unchecked collector, as the databases of a server may change.
+type chunkStoreCollector struct {
+	dbs func() sql.Databases
+}
+
+var chunkStoreLabels = []string{"database"}
+
+var (
+	chunkGetsDesc = prometheus.NewDesc("dolt_chunk_store_gets_total", "The number of chunks read from the chunk store.", chunkStoreLabels, nil)
+	chunkHasDesc  = prometheus.NewDesc("dolt_chunk_store_has_checks_total", "The number of chunk presence checks made against the chunk store.", chunkStoreLabels, nil)
+	chunkPutsDesc = prometheus.NewDesc("dolt_chunk_store_puts_total", "The number of chunks written to the chunk store.", chunkStoreLabels, nil)
+	histogramType = reflect.TypeOf(metrics.Histogram{})
+)
+
+// Describe implements prometheus.Collector. Nothing is described, making this an unchecked collector.
+func (c *chunkStoreCollector) Describe(chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector.
+func (c *chunkStoreCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, db := range c.dbs() {
+		doltDb, ok := db.(dsqle.Database)
+		if !ok {
+			continue
+		}
+		// revision databases share the chunk store of their source database
+		if _, _, isRevision := dsqle.SplitRevisionDbName(db.Name()); isRevision {
+			continue
+		}
+		collectChunkStoreStats(ch, db.Name(), doltDb.GetDoltDB().Stats())
+	}
+}
+
+func collectChunkStoreStats(ch chan<- prometheus.Metric, dbName string, stats interface{}) {
+	switch stats := stats.(type) {
+	case chunks.CSMetrics:
+		ch <- prometheus.MustNewConstMetric(chunkGetsDesc, prometheus.CounterValue, float64(stats.TotalChunkGets), dbName)
+		ch <- prometheus.MustNewConstMetric(chunkHasDesc, prometheus.CounterValue, float64(stats.TotalChunkHasChecks), dbName)
+		ch <- prometheus.MustNewConstMetric(chunkPutsDesc, prometheus.CounterValue, float64(stats.TotalChunkPuts), dbName)
+		collectChunkStoreStats(ch, dbName, stats.Delegate)
+	case nbs.Stats:
+		collectNBSStats(ch, dbName, stats)
+	}
+}
+
+// collectNBSStats reports each histogram of |stats| as a summary of its sample count and sum. Latencies are reported
+// in seconds.
+func collectNBSStats(ch chan<- prometheus.Metric, dbName string, stats nbs.Stats) {
+	val := reflect.ValueOf(stats)
+	for i := 0; i < val.NumField(); i++ {
+		field := val.Type().Field(i)
+		if field.Type != histogramType {
+			continue
+		}
+
+		h := val.Field(i).Interface().(metrics.Histogram)
+		name := "dolt_nbs_" + toSnakeCase(field.Name)
+		sum := float64(h.Sum())
+		if strings.HasSuffix(field.Name, "Latency") {
+			name += "_seconds"
+			sum = sum / float64(time.Second)
+		}
+
+		desc := prometheus.NewDesc(name, "The "+field.Name+" statistic of the noms block store.", chunkStoreLabels, nil)
+		ch <- prometheus.MustNewConstSummary(desc, h.Samples(), sum, nil, dbName)
+	}
+}
+
+// toSnakeCase converts a CamelCase name to snake_case, keeping runs of capitals such as `NBS` together.
+func toSnakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+			sb.WriteRune('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
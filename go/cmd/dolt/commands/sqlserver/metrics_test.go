@@ -0,0 +1,144 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/store/chunks"
+	"github.com/dolthub/dolt/go/store/nbs"
+)
+
+type collectorFunc func(ch chan<- prometheus.Metric)
+
+func (f collectorFunc) Describe(chan<- *prometheus.Desc) {}
+
+func (f collectorFunc) Collect(ch chan<- prometheus.Metric) {
+	f(ch)
+}
+
+func TestCollectChunkStoreStats(t *testing.T) {
+	stats := nbs.NewStats()
+	stats.GetLatency.Sample(uint64(2 * time.Second))
+	stats.GetLatency.Sample(uint64(time.Second))
+	stats.FileBytesPerRead.Sample(4096)
+	csMetrics := chunks.CSMetrics{TotalChunkGets: 3, TotalChunkPuts: 1, Delegate: *stats}
+
+	collector := collectorFunc(func(ch chan<- prometheus.Metric) {
+		collectChunkStoreStats(ch, "mydb", csMetrics)
+	})
+
+	expected := `
+# HELP dolt_chunk_store_gets_total The number of chunks read from the chunk store.
+# TYPE dolt_chunk_store_gets_total counter
+dolt_chunk_store_gets_total{database="mydb"} 3
+# HELP dolt_chunk_store_puts_total The number of chunks written to the chunk store.
+# TYPE dolt_chunk_store_puts_total counter
+dolt_chunk_store_puts_total{database="mydb"} 1
+# HELP dolt_nbs_get_latency_seconds The GetLatency statistic of the noms block store.
+# TYPE dolt_nbs_get_latency_seconds summary
+dolt_nbs_get_latency_seconds_sum{database="mydb"} 3
+dolt_nbs_get_latency_seconds_count{database="mydb"} 2
+# HELP dolt_nbs_file_bytes_per_read The FileBytesPerRead statistic of the noms block store.
+# TYPE dolt_nbs_file_bytes_per_read summary
+dolt_nbs_file_bytes_per_read_sum{database="mydb"} 4096
+dolt_nbs_file_bytes_per_read_count{database="mydb"} 1
+`
+	err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"dolt_chunk_store_gets_total", "dolt_chunk_store_puts_total", "dolt_nbs_get_latency_seconds", "dolt_nbs_file_bytes_per_read")
+	assert.NoError(t, err)
+}
+
+func TestToSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"GetLatency":           "get_latency",
+		"S3ReadLatency":        "s3_read_latency",
+		"ChunksPerGet":         "chunks_per_get",
+		"NBSBytesPerRead":      "nbs_bytes_per_read",
+		"WriteManifestLatency": "write_manifest_latency",
+	}
+
+	for in, expected := range tests {
+		assert.Equal(t, expected, toSnakeCase(in))
+	}
+}
+
+func TestServerMetrics(t *testing.T) {
+	ctx := context.Background()
+	env := dtestutils.CreateEnvWithSeedData(t)
+	serverConfig, err := newYamlConfig([]byte(`
+log_level: fatal
+
+listener:
+    port: 15306
+
+metrics:
+    host: localhost
+    port: 15307
+    labels:
+        instance: test
+`))
+	require.NoError(t, err)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "SELECT * FROM people")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "SELECT * FROM not_a_table")
+	require.Error(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE TABLE metrics_test (pk INT PRIMARY KEY)")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "SELECT DOLT_COMMIT('-a', '-m', 'metrics')")
+	require.NoError(t, err)
+
+	resp, err := http.Get("http://localhost:15307/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	metrics := string(body)
+
+	assert.Contains(t, metrics, `dolt_sql_server_connections{instance="test"} 1`)
+	assert.Contains(t, metrics, `dolt_sql_server_query_duration_seconds_count{instance="test",type="select"} 3`)
+	assert.Contains(t, metrics, `dolt_sql_server_query_duration_seconds_count{instance="test",type="ddl"} 1`)
+	assert.Contains(t, metrics, `dolt_sql_server_query_errors_total{instance="test",type="select"} 1`)
+	assert.Contains(t, metrics, `dolt_sql_server_commits_total{database="dolt",instance="test"} 1`)
+	assert.Contains(t, metrics, `go_goroutines{instance="test"}`)
+}
@@ -0,0 +1,337 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// readReplicas keeps the server's read replica databases current with their remotes. Each transaction of a session
+// reads the commit at the head of the checked out branch of each replica at the time the transaction started.
+type readReplicas struct {
+	replicas map[string]*readReplica
+}
+
+// newReadReplicas starts pulling the branches of each database configured in |configs|, by database name.
+func newReadReplicas(configs map[string]ReadReplicaConfig, mrEnv env.MultiRepoEnv) (*readReplicas, error) {
+	rr := &readReplicas{replicas: make(map[string]*readReplica)}
+	for dbName, cfg := range configs {
+		dEnv, ok := mrEnv[dbName]
+		if !ok {
+			return nil, fmt.Errorf("unknown read replica database '%s'", dbName)
+		}
+
+		dbRemotes, err := dEnv.GetRemotes()
+		if err != nil {
+			return nil, err
+		}
+
+		remote, ok := dbRemotes[cfg.Remote]
+		if !ok {
+			return nil, fmt.Errorf("read replica database '%s' has no remote '%s'", dbName, cfg.Remote)
+		}
+
+		branches := cfg.Branches
+		if len(branches) == 0 {
+			branches = []string{dEnv.RepoState.CWBHeadRef().GetPath()}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		rr.replicas[strings.ToLower(dbName)] = &readReplica{
+			dbName:   dbName,
+			remote:   remote,
+			branches: branches,
+			interval: time.Duration(cfg.PullIntervalMillis) * time.Millisecond,
+			dEnv:     dEnv,
+			ctx:      ctx,
+			cancel:   cancel,
+			done:     make(chan struct{}),
+		}
+	}
+
+	for _, r := range rr.replicas {
+		if err := r.pull(r.ctx); err != nil {
+			logrus.Warnf("failed to pull read replica database '%s' from remote '%s': %v", r.dbName, r.remote.Name, err)
+		}
+		if r.interval > 0 {
+			go r.pullPeriodically()
+		} else {
+			close(r.done)
+		}
+	}
+
+	return rr, nil
+}
+
+// isReplica returns whether |dbName|, which may be a revision database, is a read replica.
+func (rr *readReplicas) isReplica(dbName string) bool {
+	if rr == nil {
+		return false
+	}
+	if srcName, _, ok := dsqle.SplitRevisionDbName(dbName); ok {
+		dbName = srcName
+	}
+	_, ok := rr.replicas[strings.ToLower(dbName)]
+	return ok
+}
+
+// startTransaction starts a transaction in |dsess| for the statement |query|. The replicas which are pulled before
+// each transaction, those without a pull interval, are pulled first if the statement uses them. Replicas with a pull
+// interval are never pulled here: their transactions read the commit of the last periodic pull. The session's head of each replica is moved to the
+// head of the replica's checked out branch, so that the transaction reads a commit of the remote.
+func (rr *readReplicas) startTransaction(ctx *sql.Context, dsess *dsqle.DoltSession, query string) error {
+	if rr == nil || len(rr.replicas) == 0 {
+		return dsess.StartTransaction(ctx)
+	}
+
+	for _, r := range rr.replicas {
+		if r.interval > 0 || !usesDatabase(ctx, query, r.dbName) {
+			continue
+		}
+		if err := r.pull(ctx); err != nil {
+			logrus.Warnf("failed to pull read replica database '%s' from remote '%s': %v", r.dbName, r.remote.Name, err)
+		}
+	}
+
+	for _, r := range rr.replicas {
+		if err := r.setSessionHead(ctx, dsess); err != nil {
+			return err
+		}
+	}
+
+	return dsess.StartTransaction(ctx)
+}
+
+// usesDatabase returns whether the statement |query| uses the database |dbName|, because it's the current database of
+// the session of |ctx|, or because the parsed statement names it or one of its revision databases. If the statement
+// can't be parsed, only the current database is considered, as the statement fails without reading any database.
+func usesDatabase(ctx *sql.Context, query, dbName string) bool {
+	dbNames := []string{ctx.GetCurrentDatabase()}
+	if node, err := parse.Parse(ctx, query); err == nil {
+		dbNames = dsqle.ReferencedDatabaseNames(ctx, node)
+	}
+
+	for _, name := range dbNames {
+		if srcName, _, ok := dsqle.SplitRevisionDbName(name); ok {
+			name = srcName
+		}
+		if strings.EqualFold(name, dbName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Close stops pulling the replicas.
+func (rr *readReplicas) Close() {
+	if rr == nil {
+		return
+	}
+	for _, r := range rr.replicas {
+		r.cancel()
+		<-r.done
+	}
+}
+
+// readReplica pulls branches of a remote into a database, mirroring the remote's branches.
+type readReplica struct {
+	dbName   string
+	remote   env.Remote
+	branches []string
+	interval time.Duration
+	dEnv     *env.DoltEnv
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// mu is held for writing while the database is updated, and for reading while transactions start
+	mu    sync.RWMutex
+	srcDB *doltdb.DoltDB
+}
+
+// setSessionHead moves the head of the replica in |dsess| to the head of the replica's checked out branch, if the
+// session has the replica's database. Pulls wait for it, so that the session's head and working root are read from the
+// same commit.
+func (r *readReplica) setSessionHead(ctx *sql.Context, dsess *dsqle.DoltSession) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if _, ok := dsess.GetDbData(r.dbName); !ok {
+		return nil
+	}
+
+	head, err := r.dEnv.DoltDB.ResolveRef(ctx, r.dEnv.RepoState.CWBHeadRef())
+	if err != nil {
+		return err
+	}
+
+	h, err := head.HashOf()
+	if err != nil {
+		return err
+	}
+
+	_, sessHead, err := dsess.GetParentCommit(ctx, r.dbName)
+	if err != nil || sessHead != h {
+		return dsess.Set(ctx, r.dbName+dsqle.HeadKeySuffix, sql.Text, h.String())
+	}
+
+	return nil
+}
+
+func (r *readReplica) pullPeriodically() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.pull(r.ctx); err != nil {
+				logrus.Warnf("failed to pull read replica database '%s' from remote '%s': %v", r.dbName, r.remote.Name, err)
+			}
+		}
+	}
+}
+
+// pull fetches the replica's branches from its remote and moves the local branches to them. The working set of the
+// checked out branch is replaced with the root of its new head.
+func (r *readReplica) pull(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.srcDB == nil {
+		srcDB, err := r.remote.GetRemoteDB(ctx, r.dEnv.DoltDB.Format())
+		if err != nil {
+			return err
+		}
+		r.srcDB = srcDB
+	} else if err := r.srcDB.Rebase(ctx); err != nil {
+		r.srcDB = nil
+		return err
+	}
+
+	err := r.pullBranches(ctx)
+	if err != nil {
+		// the remote is reloaded for the next pull, in case the failure was caused by a lost connection
+		r.srcDB = nil
+	}
+	return err
+}
+
+func (r *readReplica) pullBranches(ctx context.Context) error {
+	ddb := r.dEnv.DoltDB
+	cwb := r.dEnv.RepoState.CWBHeadRef()
+
+	for _, branch := range r.branches {
+		branchRef := ref.NewBranchRef(branch)
+		cm, err := r.srcDB.ResolveRef(ctx, branchRef)
+		if err != nil {
+			return fmt.Errorf("failed to resolve branch '%s' of the remote: %w", branch, err)
+		}
+
+		stRef, err := cm.GetStRef()
+		if err != nil {
+			return err
+		}
+
+		pullerEventCh := discardPullerEvents()
+		err = ddb.PullChunks(ctx, r.dEnv.TempTableFilesDir(), r.srcDB, stRef, nil, pullerEventCh)
+		close(pullerEventCh)
+		if err != nil {
+			return err
+		}
+
+		// read the commit from the local database, now that its chunks have been pulled
+		h, err := cm.HashOf()
+		if err != nil {
+			return err
+		}
+		cs, err := doltdb.NewCommitSpec(h.String())
+		if err != nil {
+			return err
+		}
+		cm, err = ddb.Resolve(ctx, cs, nil)
+		if err != nil {
+			return err
+		}
+
+		err = ddb.SetHeadToCommit(ctx, ref.NewRemoteRef(r.remote.Name, branch), cm)
+		if err != nil {
+			return err
+		}
+
+		if ref.Equals(branchRef, cwb) {
+			local, err := ddb.ResolveRef(ctx, branchRef)
+			if err == nil {
+				if localHash, err := local.HashOf(); err == nil && localHash == h {
+					continue
+				}
+			}
+		}
+
+		err = ddb.SetHeadToCommit(ctx, branchRef, cm)
+		if err != nil {
+			return err
+		}
+
+		if ref.Equals(branchRef, cwb) {
+			root, err := cm.GetRootValue()
+			if err != nil {
+				return err
+			}
+
+			err = dsqle.ResetWorkingRoot(ctx, r.dEnv.DbData(), root)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// replicaPrivileges denies writes to read replicas, whatever the privileges of the user.
+type replicaPrivileges struct {
+	dsqle.PrivilegeChecker
+	replicas *readReplicas
+}
+
+var _ dsqle.PrivilegeChecker = replicaPrivileges{}
+
+// HasTablePrivilege implements dsqle.PrivilegeChecker.
+func (p replicaPrivileges) HasTablePrivilege(user, db, branch, table string, perm auth.Permission) bool {
+	if perm&auth.WritePerm != 0 && p.replicas.isReplica(db) {
+		return false
+	}
+	return p.PrivilegeChecker.HasTablePrivilege(user, db, branch, table, perm)
+}
@@ -0,0 +1,166 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+// readReplicaServerConfig is a command line server config with read replicas, which can only be configured in a
+// config file.
+type readReplicaServerConfig struct {
+	*commandLineServerConfig
+	replicas map[string]ReadReplicaConfig
+}
+
+func (cfg readReplicaServerConfig) ReadReplicas() map[string]ReadReplicaConfig {
+	return cfg.replicas
+}
+
+// commitRoot commits |root| to the master branch of |dEnv|.
+func commitRoot(t *testing.T, dEnv *env.DoltEnv, root *doltdb.RootValue, msg string) {
+	ctx := context.Background()
+	valHash, err := dEnv.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := doltdb.NewCommitMeta("test", "test@example.com", msg)
+	require.NoError(t, err)
+	_, err = dEnv.DoltDB.Commit(ctx, valHash, ref.NewBranchRef("master"), meta)
+	require.NoError(t, err)
+}
+
+// pushMaster pushes the master branch of |dEnv| to |remoteDB|.
+func pushMaster(t *testing.T, dEnv *env.DoltEnv, remoteDB *doltdb.DoltDB) {
+	ctx := context.Background()
+	master, err := dEnv.DoltDB.ResolveRef(ctx, ref.NewBranchRef("master"))
+	require.NoError(t, err)
+	stRef, err := master.GetStRef()
+	require.NoError(t, err)
+
+	pullerEventCh := discardPullerEvents()
+	err = remoteDB.PullChunks(ctx, dEnv.TempTableFilesDir(), dEnv.DoltDB, stRef, nil, pullerEventCh)
+	close(pullerEventCh)
+	require.NoError(t, err)
+	require.NoError(t, remoteDB.SetHeadToCommit(ctx, ref.NewBranchRef("master"), master))
+}
+
+func TestServerReadReplica(t *testing.T) {
+	ctx := context.Background()
+	remoteDir := filepath.Join(t.TempDir(), "origin")
+	require.NoError(t, os.MkdirAll(remoteDir, os.ModePerm))
+	remoteURL := "file://" + filepath.ToSlash(remoteDir)
+
+	primary := dtestutils.CreateEnvWithSeedData(t)
+	root, err := primary.WorkingRoot(ctx)
+	require.NoError(t, err)
+	commitRoot(t, primary, root, "add people")
+	remoteDB, err := doltdb.LoadDoltDB(ctx, primary.DoltDB.Format(), remoteURL)
+	require.NoError(t, err)
+	pushMaster(t, primary, remoteDB)
+
+	replica := dtestutils.CreateTestEnv()
+	replica.RepoState.AddRemote(env.NewRemote("origin", remoteURL, nil))
+
+	serverConfig := readReplicaServerConfig{
+		commandLineServerConfig: DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15309),
+		replicas:                map[string]ReadReplicaConfig{"dolt": {Remote: "origin"}},
+	}
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, replica)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var count int
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM people").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	_, err = conn.ExecContext(ctx, "DELETE FROM people")
+	assert.Error(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE TABLE replica_table (pk INT PRIMARY KEY)")
+	assert.Error(t, err)
+
+	// a new commit pushed by the primary is read by the next transaction
+	root, err = dsqle.ExecuteSql(primary, root, "CREATE TABLE primary_table (pk INT PRIMARY KEY);\nINSERT INTO primary_table VALUES (1), (2);")
+	require.NoError(t, err)
+	commitRoot(t, primary, root, "add primary_table")
+	pushMaster(t, primary, remoteDB)
+
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM primary_table").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM dolt_log").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestUsesDatabase(t *testing.T) {
+	tests := []struct {
+		currentDB string
+		query     string
+		expected  bool
+	}{
+		{"replica", "SELECT * FROM people", true},
+		{"replica/feature", "SELECT * FROM people", true},
+		{"REPLICA", "SELECT * FROM people", true},
+		{"other", "SELECT * FROM people", false},
+		{"other", "SELECT * FROM replica.people", true},
+		{"other", "SELECT * FROM `Replica`.people", true},
+		{"other", "SELECT * FROM `replica/feature`.people", true},
+		{"other", "USE replica", true},
+		{"other", "SELECT * FROM replicas.people", false},
+		{"other", "SELECT * FROM my_replica.people", false},
+		{"other", "SELECT my_replica, replica2 FROM t WHERE replica_id = 1", false},
+		{"other", "SELECT 'replica' FROM t", false},
+		{"other", "SELECT replica FROM t", false},
+		{"other", "SELECT * FROM t WHERE pk IN (SELECT pk FROM replica.people)", true},
+		{"other", "USE `replica/feature`", true},
+		{"other", "SELECT * FROM", false},
+		{"", "SELECT 1", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.currentDB+": "+test.query, func(t *testing.T) {
+			ctx := sql.NewEmptyContext()
+			ctx.SetCurrentDatabase(test.currentDB)
+			assert.Equal(t, test.expected, usesDatabase(ctx, test.query, "replica"))
+		})
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+const (
+	// minReplicationBackoff is the time waited before retrying a failed push, which doubles with each consecutive
+	// failure up to maxReplicationBackoff.
+	minReplicationBackoff = time.Second
+	maxReplicationBackoff = 5 * time.Minute
+)
+
+// replication pushes the branches of the server's replicated databases to their remotes after each commit or branch
+// update made by a client. It's notified of the updates as a dsqle.SessionEventListener, and serves the
+// dolt_replication_status system table as a dtables.ReplicationStatusProvider.
+type replication struct {
+	replicators map[string]*replicator
+}
+
+var _ dsqle.SessionEventListener = (*replication)(nil)
+var _ dtables.ReplicationStatusProvider = (*replication)(nil)
+
+// newReplication starts the replication of each database named in |remotes| to the remote it's mapped to, which must
+// be one of the database's remotes.
+func newReplication(remotes map[string]string, mrEnv env.MultiRepoEnv) (*replication, error) {
+	r := &replication{replicators: make(map[string]*replicator)}
+	for dbName, remoteName := range remotes {
+		dEnv, ok := mrEnv[dbName]
+		if !ok {
+			return nil, fmt.Errorf("cannot replicate unknown database '%s'", dbName)
+		}
+
+		dbRemotes, err := dEnv.GetRemotes()
+		if err != nil {
+			return nil, err
+		}
+
+		remote, ok := dbRemotes[remoteName]
+		if !ok {
+			return nil, fmt.Errorf("cannot replicate database '%s' to unknown remote '%s'", dbName, remoteName)
+		}
+
+		r.replicators[strings.ToLower(dbName)] = newReplicator(dbName, remote, dEnv, minReplicationBackoff, maxReplicationBackoff)
+	}
+
+	for _, rep := range r.replicators {
+		go rep.run()
+	}
+
+	return r, nil
+}
+
+func (r *replication) notify(dbName string) {
+	if srcName, _, ok := dsqle.SplitRevisionDbName(dbName); ok {
+		dbName = srcName
+	}
+	if rep, ok := r.replicators[strings.ToLower(dbName)]; ok {
+		rep.notify()
+	}
+}
+
+// Committed implements dsqle.SessionEventListener.
+func (r *replication) Committed(dbName string) {
+	r.notify(dbName)
+}
+
+// Merged implements dsqle.SessionEventListener.
+func (r *replication) Merged(dbName string, _ bool) {
+	r.notify(dbName)
+}
+
+// BranchUpdated implements dsqle.SessionEventListener.
+func (r *replication) BranchUpdated(dbName string) {
+	r.notify(dbName)
+}
+
+// TransactionConflicted implements dsqle.SessionEventListener.
+func (r *replication) TransactionConflicted(string) {}
+
+// ReplicationStatus implements dtables.ReplicationStatusProvider.
+func (r *replication) ReplicationStatus(dbName string) []dtables.BranchReplicationStatus {
+	if rep, ok := r.replicators[strings.ToLower(dbName)]; ok {
+		return rep.status()
+	}
+	return nil
+}
+
+// Close stops replication, abandoning any pushes in progress.
+func (r *replication) Close() {
+	for _, rep := range r.replicators {
+		rep.close()
+	}
+}
+
+// branchReplication is the state of the replication of a branch.
+type branchReplication struct {
+	localHash    string
+	pushedHash   string
+	pendingSince time.Time
+	lastPush     time.Time
+	failures     int
+	lastError    error
+}
+
+// replicator pushes the branches of a database to a remote. Pushes run in the background: each notification of an
+// update causes every branch whose head has changed since it was last pushed to be pushed. Failed pushes are retried
+// with exponential backoff.
+type replicator struct {
+	dbName     string
+	remote     env.Remote
+	dEnv       *env.DoltEnv
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	updates chan struct{}
+	done    chan struct{}
+
+	// destDB is loaded on the first push, and reloaded after failures
+	destDB *doltdb.DoltDB
+
+	mu       sync.Mutex
+	branches map[string]*branchReplication
+}
+
+func newReplicator(dbName string, remote env.Remote, dEnv *env.DoltEnv, minBackoff, maxBackoff time.Duration) *replicator {
+	ctx, cancel := context.WithCancel(context.Background())
+	rep := &replicator{
+		dbName:     dbName,
+		remote:     remote,
+		dEnv:       dEnv,
+		minBackoff: minBackoff,
+		maxBackoff: maxBackoff,
+		ctx:        ctx,
+		cancel:     cancel,
+		updates:    make(chan struct{}, 1),
+		done:       make(chan struct{}),
+		branches:   make(map[string]*branchReplication),
+	}
+
+	// branches updated while the server was stopped are pushed when it starts
+	rep.notify()
+	return rep
+}
+
+// notify schedules a push of the branches that have changed. It doesn't block.
+func (rep *replicator) notify() {
+	select {
+	case rep.updates <- struct{}{}:
+	default:
+	}
+}
+
+func (rep *replicator) run() {
+	defer close(rep.done)
+
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-rep.ctx.Done():
+			return
+		case <-rep.updates:
+		case <-retry:
+		}
+
+		retry = nil
+		if backoff, failed := rep.sync(); failed {
+			retry = time.After(backoff)
+		}
+	}
+}
+
+// sync pushes each branch whose head isn't the commit last pushed. If any push fails, it returns the time to wait
+// before retrying.
+func (rep *replicator) sync() (backoff time.Duration, failed bool) {
+	heads, err := rep.branchHeads()
+	if err != nil {
+		logrus.Errorf("replication of database '%s' failed to read its branches: %v", rep.dbName, err)
+		return rep.maxBackoff, true
+	}
+
+	now := time.Now()
+	rep.mu.Lock()
+	for name := range rep.branches {
+		if _, ok := heads[name]; !ok {
+			delete(rep.branches, name)
+		}
+	}
+	var toPush []string
+	for name, cm := range heads {
+		h, err := cm.HashOf()
+		if err != nil {
+			rep.mu.Unlock()
+			return rep.maxBackoff, true
+		}
+
+		br, ok := rep.branches[name]
+		if !ok {
+			br = &branchReplication{}
+			rep.branches[name] = br
+		}
+		if br.localHash != h.String() {
+			br.localHash = h.String()
+			if br.pendingSince.IsZero() {
+				br.pendingSince = now
+			}
+		}
+		if br.pushedHash != br.localHash {
+			toPush = append(toPush, name)
+		}
+	}
+	rep.mu.Unlock()
+
+	sort.Strings(toPush)
+	for _, name := range toPush {
+		err := rep.push(name, heads[name])
+
+		rep.mu.Lock()
+		br := rep.branches[name]
+		if err != nil {
+			br.failures++
+			br.lastError = err
+			b := rep.minBackoff << uint(br.failures-1)
+			if b <= 0 || b > rep.maxBackoff {
+				b = rep.maxBackoff
+			}
+			if !failed || b < backoff {
+				backoff = b
+			}
+			failed = true
+			logrus.Warnf("replication of branch '%s' of database '%s' to remote '%s' failed, attempt %d: %v", name, rep.dbName, rep.remote.Name, br.failures, err)
+		} else {
+			h, _ := heads[name].HashOf()
+			br.pushedHash = h.String()
+			br.lastPush = time.Now()
+			br.failures = 0
+			br.lastError = nil
+			if br.pushedHash == br.localHash {
+				br.pendingSince = time.Time{}
+			}
+		}
+		rep.mu.Unlock()
+	}
+
+	return backoff, failed
+}
+
+func (rep *replicator) branchHeads() (map[string]*doltdb.Commit, error) {
+	ddb := rep.dEnv.DoltDB
+	branches, err := ddb.GetBranches(rep.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	heads := make(map[string]*doltdb.Commit, len(branches))
+	for _, br := range branches {
+		cm, err := ddb.ResolveRef(rep.ctx, br)
+		if err != nil {
+			return nil, err
+		}
+		heads[br.GetPath()] = cm
+	}
+
+	return heads, nil
+}
+
+// push force pushes |cm| to the branch |branch| of the remote, so that the remote mirrors the database.
+func (rep *replicator) push(branch string, cm *doltdb.Commit) error {
+	if rep.destDB == nil {
+		destDB, err := rep.remote.GetRemoteDB(rep.ctx, rep.dEnv.DoltDB.Format())
+		if err != nil {
+			return err
+		}
+		rep.destDB = destDB
+	}
+
+	pullerEventCh := discardPullerEvents()
+	defer close(pullerEventCh)
+
+	destRef := ref.NewBranchRef(branch)
+	remoteRef := ref.NewRemoteRef(rep.remote.Name, branch)
+	err := actions.Push(rep.ctx, rep.dEnv, ref.ForceUpdate, destRef, remoteRef, rep.dEnv.DoltDB, rep.destDB, cm, nil, pullerEventCh)
+	if err != nil {
+		// the remote is reloaded for the next attempt, in case the failure was caused by a lost connection
+		rep.destDB = nil
+	}
+
+	return err
+}
+
+// discardPullerEvents returns a channel for the events of a push or pull, which the server doesn't report. The channel
+// must be closed when the push or pull is done.
+func discardPullerEvents() chan datas.PullerEvent {
+	ch := make(chan datas.PullerEvent, 128)
+	go func() {
+		for range ch {
+		}
+	}()
+	return ch
+}
+
+func (rep *replicator) status() []dtables.BranchReplicationStatus {
+	rep.mu.Lock()
+	defer rep.mu.Unlock()
+
+	now := time.Now()
+	statuses := make([]dtables.BranchReplicationStatus, 0, len(rep.branches))
+	for name, br := range rep.branches {
+		st := dtables.BranchReplicationStatus{
+			Remote:     rep.remote.Name,
+			Branch:     name,
+			LocalHash:  br.localHash,
+			RemoteHash: br.pushedHash,
+			LastPush:   br.lastPush,
+			Failures:   br.failures,
+		}
+		if !br.pendingSince.IsZero() {
+			st.Lag = now.Sub(br.pendingSince)
+		}
+		if br.lastError != nil {
+			st.LastError = br.lastError.Error()
+		}
+		statuses = append(statuses, st)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Branch < statuses[j].Branch
+	})
+
+	return statuses
+}
+
+func (rep *replicator) close() {
+	rep.cancel()
+	<-rep.done
+}
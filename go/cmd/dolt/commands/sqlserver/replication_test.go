@@ -0,0 +1,115 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+)
+
+// waitForReplication waits for |cond| to hold for the replication status of the database dolt.
+func waitForReplication(t *testing.T, r *replication, cond func([]dtables.BranchReplicationStatus) bool) []dtables.BranchReplicationStatus {
+	var status []dtables.BranchReplicationStatus
+	for start := time.Now(); time.Since(start) < 10*time.Second; time.Sleep(10 * time.Millisecond) {
+		status = r.ReplicationStatus("dolt")
+		if cond(status) {
+			return status
+		}
+	}
+	require.Fail(t, "timed out waiting for replication", "%+v", status)
+	return nil
+}
+
+func TestReplication(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateEnvWithSeedData(t)
+	remoteDir := filepath.Join(t.TempDir(), "backup")
+	dEnv.RepoState.AddRemote(env.NewRemote("backup", "file://"+filepath.ToSlash(remoteDir), nil))
+
+	mrEnv := env.MultiRepoEnv{"dolt": dEnv}
+	_, err := newReplication(map[string]string{"dolt": "missing"}, mrEnv)
+	assert.Error(t, err)
+	_, err = newReplication(map[string]string{"other": "backup"}, mrEnv)
+	assert.Error(t, err)
+
+	// the remote's directory doesn't exist, so pushes fail until it's created
+	remote, err := dEnv.GetRemotes()
+	require.NoError(t, err)
+	rep := newReplicator("dolt", remote["backup"], dEnv, 10*time.Millisecond, 50*time.Millisecond)
+	r := &replication{replicators: map[string]*replicator{"dolt": rep}}
+	go rep.run()
+	defer r.Close()
+
+	status := waitForReplication(t, r, func(st []dtables.BranchReplicationStatus) bool {
+		return len(st) == 1 && st[0].Failures > 1
+	})
+	assert.Equal(t, "backup", status[0].Remote)
+	assert.Equal(t, "master", status[0].Branch)
+	assert.Equal(t, "", status[0].RemoteHash)
+	assert.NotEmpty(t, status[0].LastError)
+	assert.True(t, status[0].Lag > 0)
+
+	require.NoError(t, os.MkdirAll(remoteDir, os.ModePerm))
+	status = waitForReplication(t, r, func(st []dtables.BranchReplicationStatus) bool {
+		return len(st) == 1 && st[0].Failures == 0
+	})
+	assert.Equal(t, status[0].LocalHash, status[0].RemoteHash)
+	assert.Equal(t, time.Duration(0), status[0].Lag)
+	assert.Empty(t, status[0].LastError)
+
+	// new branches are pushed when the replication is notified of them
+	master, err := dEnv.DoltDB.ResolveRef(ctx, ref.NewBranchRef("master"))
+	require.NoError(t, err)
+	require.NoError(t, dEnv.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("feature"), master))
+	r.BranchUpdated("dolt/master")
+	status = waitForReplication(t, r, func(st []dtables.BranchReplicationStatus) bool {
+		return len(st) == 2 && st[0].RemoteHash != ""
+	})
+	assert.Equal(t, "feature", status[0].Branch)
+
+	remoteDB, err := doltdb.LoadDoltDB(ctx, dEnv.DoltDB.Format(), "file://"+filepath.ToSlash(remoteDir))
+	require.NoError(t, err)
+	remoteHead, err := remoteDB.ResolveRef(ctx, ref.NewBranchRef("feature"))
+	require.NoError(t, err)
+	h, err := remoteHead.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, status[0].LocalHash, h.String())
+
+	sqlCtx := sql.NewEmptyContext()
+	table := dtables.NewReplicationStatusTable(sqlCtx, "dolt", r)
+	iter, err := table.PartitionRows(sqlCtx, nil)
+	require.NoError(t, err)
+	row, err := iter.Next()
+	require.NoError(t, err)
+	assert.Equal(t, sql.NewRow("backup", "feature", h.String(), h.String(), status[0].LastPush, float64(0), int64(0), nil), row)
+	_, err = iter.Next()
+	require.NoError(t, err)
+	_, err = iter.Next()
+	assert.Equal(t, io.EOF, err)
+}
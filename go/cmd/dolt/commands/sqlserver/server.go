@@ -16,7 +16,11 @@ package sqlserver
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net"
+	"net/http"
+	"os"
 	"strconv"
 	"time"
 
@@ -27,7 +31,9 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/analyzer"
 	"github.com/dolthub/go-mysql-server/sql/information_schema"
 	"github.com/dolthub/vitess/go/mysql"
+	"github.com/opentracing/opentracing-go"
 	"github.com/sirupsen/logrus"
+	"github.com/uber/jaeger-client-go"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/commands"
@@ -35,6 +41,8 @@ import (
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
 	_ "github.com/dolthub/dolt/go/libraries/doltcore/sqle/dfunctions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/tracing"
 )
 
@@ -51,13 +59,45 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 	}
 
 	var mySQLServer *server.Server
-	// This guarantees unblocking on any routines with a waiting `ServerController`
-	defer func() {
+	var metricsServer *http.Server
+	var httpServer *http.Server
+	var slowQueries *slowQueryLog
+	var tracerCloser io.Closer
+	var repl *replication
+	var replicas *readReplicas
+	var stopReloading func()
+	closeServers := func() error {
+		if stopReloading != nil {
+			stopReloading()
+		}
+		if metricsServer != nil {
+			_ = metricsServer.Close()
+		}
+		if httpServer != nil {
+			_ = httpServer.Close()
+		}
+
+		var err error
 		if mySQLServer != nil {
-			serverController.registerCloseFunction(startError, mySQLServer.Close)
-		} else {
-			serverController.registerCloseFunction(startError, func() error { return nil })
+			err = mySQLServer.Close()
+		}
+
+		if repl != nil {
+			repl.Close()
+		}
+		replicas.Close()
+
+		// the trace and slow query log are closed last, so that they include every query the server ran
+		if tracerCloser != nil {
+			_ = tracerCloser.Close()
 		}
+		_ = slowQueries.Close()
+		return err
+	}
+
+	// This guarantees unblocking on any routines with a waiting `ServerController`
+	defer func() {
+		serverController.registerCloseFunction(startError, closeServers)
 		serverController.StopServer()
 		serverController.serverStopped(closeError)
 	}()
@@ -76,18 +116,23 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 		logrus.SetLevel(level)
 	}
 
-	permissions := auth.AllPermissions
-	if serverConfig.ReadOnly() {
-		permissions = auth.ReadPerm
+	privileges, err := dsqle.NewPrivilegeStore(dEnv.FS, serverConfig.PrivilegeFilePath(), serverConfig.User(), serverConfig.Password(), serverConfig.ReadOnly(), serverConfig.Users())
+	if err != nil {
+		return err, nil
 	}
 
-	userAuth := auth.NewAudit(auth.NewNativeSingle(serverConfig.User(), serverConfig.Password(), permissions), auth.NewAuditLog(logrus.StandardLogger()))
+	userAuth := auth.NewAudit(privileges, auth.NewAuditLog(logrus.StandardLogger()))
 
 	c := sql.NewCatalog()
-	a := analyzer.NewBuilder(c).WithParallelism(serverConfig.QueryParallelism()).Build()
-	sqlEngine := sqle.New(c, a, nil)
+	a := analyzer.NewBuilder(c).
+		WithParallelism(serverConfig.QueryParallelism()).
+		AddPreAnalyzeRule("resolve_revision_databases", dsqle.ResolveRevisionDatabases).
+		AddPreAnalyzeRule("resolve_transaction_statements", dsqle.ResolveTransactionStatements).
+		AddPreAnalyzeRule("resolve_database_ddl", dsqle.ResolveDatabaseDDL).
+		Build()
+	sqlEngine := sqle.New(c, a, &sqle.Config{Auth: userAuth})
 
-	err := sqlEngine.Catalog.Register(dfunctions.DoltFunctions...)
+	err = sqlEngine.Catalog.Register(dfunctions.DoltFunctions...)
 
 	if err != nil {
 		return nil, err
@@ -97,6 +142,15 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 	var email string
 	var mrEnv env.MultiRepoEnv
 	dbNamesAndPaths := serverConfig.DatabaseNamesAndPaths()
+	if dataDir := serverConfig.DataDir(); dataDir != "" {
+		// databases are created in the data directory on the local filesystem, see databaseProvider
+		dataDirDbs, err := env.DBNamesAndPathsFromDir(filesys.LocalFS, dataDir)
+		if err != nil {
+			return fmt.Errorf("failed to read databases in data directory '%s'. error: %v", dataDir, err), nil
+		}
+
+		dbNamesAndPaths = append(dbNamesAndPaths, dataDirDbs...)
+	}
 	if len(dbNamesAndPaths) == 0 {
 		var err error
 		mrEnv = env.DoltEnvAsMultiEnv(dEnv)
@@ -124,10 +178,62 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 
 	sqlEngine.AddDatabase(information_schema.NewInformationSchemaDatabase(sqlEngine.Catalog))
 
-	hostPort := net.JoinHostPort(serverConfig.Host(), strconv.Itoa(serverConfig.Port()))
+	databases, err := newDatabaseProvider(serverConfig, sqlEngine.Catalog, mrEnv, dEnv, version)
+	if err != nil {
+		return err, nil
+	}
+
+	tlsConfig, startError := LoadTLSConfig(serverConfig)
+	if startError != nil {
+		cli.PrintErr(startError)
+		return
+	}
+
+	metrics, startError := newServerMetrics(serverConfig.MetricsLabels(), sqlEngine.Catalog.AllDatabases)
+	if startError != nil {
+		cli.PrintErr(startError)
+		return
+	}
+
+	repl, startError = newReplication(serverConfig.ReplicationRemotes(), mrEnv)
+	if startError != nil {
+		cli.PrintErr(startError)
+		return
+	}
+
+	replicas, startError = newReadReplicas(serverConfig.ReadReplicas(), mrEnv)
+	if startError != nil {
+		cli.PrintErr(startError)
+		return
+	}
+
+	slowQueries, startError = newSlowQueryLog(serverConfig, sqlEngine)
+	if startError != nil {
+		cli.PrintErr(startError)
+		return
+	}
+
+	var tracer opentracing.Tracer
+	if serverConfig.TraceFile() != "" {
+		var traceFile *os.File
+		traceFile, startError = os.Create(serverConfig.TraceFile())
+		if startError != nil {
+			cli.PrintErr(startError)
+			return
+		}
+		tracer, tracerCloser = jaeger.NewTracer("dolt-sql-server", jaeger.NewConstSampler(true), tracing.NewChromeTraceReporter(traceFile))
+	}
+
 	readTimeout := time.Duration(serverConfig.ReadTimeout()) * time.Millisecond
 	writeTimeout := time.Duration(serverConfig.WriteTimeout()) * time.Millisecond
-	mySQLServer, startError = server.NewServer(
+	timeouts := newConnTimeouts(readTimeout, writeTimeout)
+	reloader := newConfigReloader(dEnv.FS, serverConfig, privileges, timeouts)
+
+	sessionPrivileges := replicaPrivileges{privileges, replicas}
+	sessions := newSessionFactory(sqlEngine, sessionPrivileges, privileges, dsqle.SessionEventListeners{metrics, repl}, repl, databases, reloader, username, email, serverConfig.AutoCommit())
+
+	hostPort := net.JoinHostPort(serverConfig.Host(), strconv.Itoa(serverConfig.Port()))
+	mySQLServer, startError = newServer(
 		server.Config{
 			Protocol:         "tcp",
 			Address:          hostPort,
@@ -135,11 +241,21 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 			ConnReadTimeout:  readTimeout,
 			ConnWriteTimeout: writeTimeout,
 			MaxConnections:   serverConfig.MaxConnections(),
+			Tracer:           tracer,
 			// Do not set the value of Version.  Let it default to what go-mysql-server uses.  This should be equivalent
 			// to the value of mysql that we support.
 		},
 		sqlEngine,
-		newSessionBuilder(sqlEngine, username, email, serverConfig.AutoCommit()),
+		newSessionBuilder(sessions),
+		serverOptions{
+			socket:                 serverConfig.Socket(),
+			timeouts:               timeouts,
+			metrics:                metrics,
+			slowQueries:            slowQueries,
+			replicas:               replicas,
+			tlsConfig:              tlsConfig,
+			requireSecureTransport: serverConfig.RequireSecureTransport(),
+		},
 	)
 
 	if startError != nil {
@@ -147,7 +263,54 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 		return
 	}
 
-	serverController.registerCloseFunction(startError, mySQLServer.Close)
+	if serverConfig.MetricsPort() != defaultMetricsPort {
+		metricsServer = newMetricsServer(serverConfig.MetricsHost(), serverConfig.MetricsPort(), metrics)
+		var metricsListener net.Listener
+		metricsListener, startError = net.Listen("tcp", metricsServer.Addr)
+		if startError != nil {
+			cli.PrintErr(startError)
+			metricsServer = nil
+			return
+		}
+		go func() {
+			_ = metricsServer.Serve(metricsListener)
+		}()
+	}
+
+	if serverConfig.HTTPPort() != defaultHTTPPort {
+		api := &httpAPI{
+			engine:     sqlEngine,
+			sessions:   sessions,
+			users:      privileges,
+			privileges: sessionPrivileges,
+			metrics:    metrics,
+			replicas:   replicas,
+			host:       serverConfig.HTTPHost(),
+		}
+		if tlsConfig == nil && serverConfig.RequireSecureTransport() {
+			startError = fmt.Errorf("the HTTP API can't be served without TLS when require_secure_transport is set")
+			cli.PrintErr(startError)
+			return
+		}
+
+		httpServer = newHTTPServer(serverConfig.HTTPHost(), serverConfig.HTTPPort(), api, tlsConfig)
+		var httpListener net.Listener
+		httpListener, startError = net.Listen("tcp", httpServer.Addr)
+		if startError != nil {
+			cli.PrintErr(startError)
+			httpServer = nil
+			return
+		}
+		go func() {
+			_ = serveHTTP(httpServer, httpListener)
+		}()
+	}
+
+	if reloader.path != "" {
+		stopReloading = reloader.reloadOnSignal()
+	}
+
+	serverController.registerCloseFunction(startError, closeServers)
 	closeError = mySQLServer.Start()
 	if closeError != nil {
 		cli.PrintErr(closeError)
@@ -156,15 +319,38 @@ func Serve(ctx context.Context, version string, serverConfig ServerConfig, serve
 	return
 }
 
-func newSessionBuilder(sqlEngine *sqle.Engine, username, email string, autocommit bool) server.SessionBuilder {
+// sessionFactory creates the session of the connection |connID| of |user| from the address |client| to the server at
+// |host|.
+type sessionFactory func(ctx context.Context, host, client, user string, connID uint32) (sql.Session, *sql.IndexRegistry, *sql.ViewRegistry, error)
+
+// newSessionBuilder returns the server.SessionBuilder that creates the sessions of MySQL connections with |sessions|.
+func newSessionBuilder(sessions sessionFactory) server.SessionBuilder {
 	return func(ctx context.Context, conn *mysql.Conn, host string) (sql.Session, *sql.IndexRegistry, *sql.ViewRegistry, error) {
-		mysqlSess := sql.NewSession(host, conn.RemoteAddr().String(), conn.User, conn.ConnectionID)
+		return sessions(ctx, host, conn.RemoteAddr().String(), conn.User, conn.ConnectionID)
+	}
+}
+
+// newSessionFactory returns the sessionFactory that creates the sessions of the server, for MySQL connections and HTTP
+// API requests alike.
+func newSessionFactory(sqlEngine *sqle.Engine, privileges dsqle.PrivilegeChecker, accounts *dsqle.PrivilegeStore, events dsqle.SessionEventListener, replication dtables.ReplicationStatusProvider, databases dsqle.DatabaseProvider, reloader dsqle.ConfigReloader, username, email string, autocommit bool) sessionFactory {
+	// the sessions of the engine share the counts of the revision databases they use
+	revisionDbs := dsqle.NewRevisionDatabases()
+	return func(ctx context.Context, host, client, user string, connID uint32) (sql.Session, *sql.IndexRegistry, *sql.ViewRegistry, error) {
+		mysqlSess := sql.NewSession(host, client, user, connID)
 		doltSess, err := dsqle.NewDoltSession(ctx, mysqlSess, username, email, dbsAsDSQLDBs(sqlEngine.Catalog.AllDatabases())...)
 
 		if err != nil {
 			return nil, nil, nil, err
 		}
 
+		doltSess.SetPrivilegeChecker(privileges)
+		doltSess.SetUserAccounts(accounts)
+		doltSess.SetEventListener(events)
+		doltSess.SetReplicationStatusProvider(replication)
+		doltSess.SetDatabaseProvider(databases)
+		doltSess.SetConfigReloader(reloader)
+		doltSess.SetRevisionDatabases(revisionDbs)
+
 		err = doltSess.Set(ctx, sql.AutoCommitSessionVar, sql.Boolean, autocommit)
 
 		if err != nil {
@@ -208,10 +394,16 @@ func newDatabase(name string, dEnv *env.DoltEnv) dsqle.Database {
 	return dsqle.NewDatabase(name, dEnv.DbData())
 }
 
+// dbsAsDSQLDBs returns the Databases in |dbs| that sessions start with. Revision databases are left out; sessions
+// register the ones they use, see dsqle.RegisterRevisionDatabase.
 func dbsAsDSQLDBs(dbs []sql.Database) []dsqle.Database {
 	dsqlDBs := make([]dsqle.Database, 0, len(dbs))
 
 	for _, db := range dbs {
+		if _, _, ok := dsqle.SplitRevisionDbName(db.Name()); ok {
+			continue
+		}
+
 		dsqlDB, ok := db.(dsqle.Database)
 
 		if ok {
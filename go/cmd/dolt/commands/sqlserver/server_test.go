@@ -15,16 +15,32 @@
 package sqlserver
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	gosql "database/sql"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"github.com/gocraft/dbr/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/net/context"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 )
 
 type testPerson struct {
@@ -110,6 +126,11 @@ func TestServerBadArgs(t *testing.T) {
 		{"-P", "90000"},
 		{"-u", ""},
 		{"-l", "everything"},
+		{"--tls-key", "key.pem"},
+		{"--require-secure-transport"},
+		{"--tls-key", "missing-key.pem", "--tls-cert", "missing-cert.pem"},
+		{"--slow-query-threshold", "-5"},
+		{"--log-query-plans"},
 	}
 
 	for _, test := range tests {
@@ -216,3 +237,548 @@ func TestServerSelect(t *testing.T) {
 		})
 	}
 }
+
+func TestServerRevisionDatabases(t *testing.T) {
+	ctx := context.Background()
+	env := dtestutils.CreateEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15301)
+
+	head, err := env.DoltDB.ResolveRef(ctx, env.RepoState.CWBHeadRef())
+	require.NoError(t, err)
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	err = env.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("feature"), head)
+	require.NoError(t, err)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "USE `dolt/feature`")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE TABLE feature_table (pk INT PRIMARY KEY)")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "INSERT INTO feature_table VALUES (1), (2)")
+	require.NoError(t, err)
+
+	var count int
+	err = conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM `dolt/feature`.feature_table JOIN dolt.people").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 6, count)
+
+	_, err = conn.ExecContext(ctx, "SELECT * FROM dolt.feature_table")
+	assert.Error(t, err)
+
+	_, err = conn.ExecContext(ctx, "USE `dolt/"+headHash.String()+"`")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "CREATE TABLE commit_table (pk INT PRIMARY KEY)")
+	assert.Error(t, err)
+
+	_, err = conn.ExecContext(ctx, "USE `dolt/not_a_branch`")
+	assert.Error(t, err)
+}
+
+func TestServerRevisionDatabaseWorkingSets(t *testing.T) {
+	ctx := context.Background()
+	env := dtestutils.CreateEnvWithSeedData(t)
+
+	head, err := env.DoltDB.ResolveRef(ctx, env.RepoState.CWBHeadRef())
+	require.NoError(t, err)
+	feature := ref.NewBranchRef("feature")
+	err = env.DoltDB.NewBranchAtCommit(ctx, feature, head)
+	require.NoError(t, err)
+
+	serve := func(port int) (*ServerController, *dbr.Connection) {
+		serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(port).withMaxConnections(2)
+		sc := CreateServerController()
+		go func() {
+			_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+		}()
+		err := sc.WaitForStart()
+		require.NoError(t, err)
+
+		db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+		require.NoError(t, err)
+		// connections are closed when they're released, which ends their sessions
+		db.SetMaxIdleConns(0)
+		return sc, db
+	}
+	hasFeatureDb := func(db *dbr.Connection) bool {
+		rows, err := db.QueryContext(ctx, "SHOW DATABASES")
+		require.NoError(t, err)
+		defer rows.Close()
+
+		var found bool
+		for rows.Next() {
+			var name string
+			require.NoError(t, rows.Scan(&name))
+			found = found || name == "dolt/feature"
+		}
+		require.NoError(t, rows.Err())
+		return found
+	}
+
+	sc, db := serve(15316)
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	for _, query := range []string{
+		"USE `dolt/feature`",
+		"CREATE TABLE feature_table (pk INT PRIMARY KEY)",
+		"INSERT INTO feature_table VALUES (1), (2)",
+	} {
+		_, err = conn.ExecContext(ctx, query)
+		require.NoError(t, err, query)
+	}
+	assert.True(t, hasFeatureDb(db))
+
+	// the revision database is removed once no session uses it
+	require.NoError(t, conn.Close())
+	assert.Eventually(t, func() bool { return !hasFeatureDb(db) }, 5*time.Second, 10*time.Millisecond)
+
+	// the uncommitted changes to the branch are kept when the server restarts
+	require.NoError(t, db.Close())
+	sc.StopServer()
+	require.NoError(t, sc.WaitForClose())
+	sc, db = serve(15317)
+	defer sc.StopServer()
+	defer db.Close()
+
+	var count int
+	err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM `dolt/feature`.feature_table").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// the uncommitted changes are merged onto commits made to the branch while no session used it
+	assert.Eventually(t, func() bool { return !hasFeatureDb(db) }, 5*time.Second, 10*time.Millisecond)
+	head, err = env.DoltDB.ResolveRef(ctx, feature)
+	require.NoError(t, err)
+	root, err := head.GetRootValue()
+	require.NoError(t, err)
+	root, err = dsqle.ExecuteSql(env, root, "CREATE TABLE other_table (pk INT PRIMARY KEY);")
+	require.NoError(t, err)
+	valHash, err := env.DoltDB.WriteRootValue(ctx, root)
+	require.NoError(t, err)
+	meta, err := doltdb.NewCommitMeta("test", "test@example.com", "add other_table")
+	require.NoError(t, err)
+	head, err = env.DoltDB.Commit(ctx, valHash, feature, meta)
+	require.NoError(t, err)
+
+	for query, expected := range map[string]int{
+		"SELECT COUNT(*) FROM `dolt/feature`.feature_table": 2,
+		"SELECT COUNT(*) FROM `dolt/feature`.other_table":   0,
+	} {
+		err = db.QueryRowContext(ctx, query).Scan(&count)
+		require.NoError(t, err, query)
+		assert.Equal(t, expected, count, query)
+	}
+
+	headHash, err := head.HashOf()
+	require.NoError(t, err)
+	working, _, ok, err := env.DoltDB.GetBranchWorkingSet(ctx, feature)
+	require.NoError(t, err)
+	require.True(t, ok)
+	baseHash, err := working.Base.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, headHash, baseHash)
+
+	conn, err = db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, "USE `dolt/feature`")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "SELECT DOLT_COMMIT('-a', '-m', 'add feature_table')")
+	require.NoError(t, err)
+
+	_, _, ok, err = env.DoltDB.GetBranchWorkingSet(ctx, feature)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestServerConcurrentTransactions(t *testing.T) {
+	ctx := context.Background()
+	env := dtestutils.CreateEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15302).withMaxConnections(2)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	defer db.Close()
+	conn1, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn1.Close()
+	conn2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn2.Close()
+
+	count := func(conn *gosql.Conn) int {
+		var n int
+		err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM tx_test").Scan(&n)
+		require.NoError(t, err)
+		return n
+	}
+	exec := func(conn *gosql.Conn, query string) {
+		_, err := conn.ExecContext(ctx, query)
+		require.NoError(t, err, query)
+	}
+
+	exec(conn1, "CREATE TABLE tx_test (pk INT PRIMARY KEY, c INT)")
+
+	// concurrent inserts are merged
+	exec(conn1, "BEGIN")
+	exec(conn1, "INSERT INTO tx_test VALUES (1, 1)")
+	exec(conn2, "INSERT INTO tx_test VALUES (2, 2)")
+	assert.Equal(t, 1, count(conn2))
+	exec(conn1, "COMMIT")
+	assert.Equal(t, 2, count(conn1))
+	assert.Equal(t, 2, count(conn2))
+
+	// rolled back changes are discarded
+	exec(conn1, "START TRANSACTION")
+	exec(conn1, "INSERT INTO tx_test VALUES (3, 3)")
+	assert.Equal(t, 3, count(conn1))
+	exec(conn1, "ROLLBACK")
+	assert.Equal(t, 2, count(conn1))
+	assert.Equal(t, 2, count(conn2))
+
+	// conflicting changes fail the transaction with a retryable error
+	exec(conn1, "BEGIN")
+	exec(conn1, "UPDATE tx_test SET c = 10 WHERE pk = 1")
+	exec(conn2, "UPDATE tx_test SET c = 20 WHERE pk = 1")
+	_, err = conn1.ExecContext(ctx, "COMMIT")
+	require.Error(t, err)
+	mysqlErr, ok := err.(*mysql.MySQLError)
+	require.True(t, ok)
+	assert.Equal(t, uint16(1213), mysqlErr.Number)
+
+	var c int
+	err = conn1.QueryRowContext(ctx, "SELECT c FROM tx_test WHERE pk = 1").Scan(&c)
+	require.NoError(t, err)
+	assert.Equal(t, 20, c)
+
+	// changes that violate a check added by a concurrent transaction fail the transaction with a retryable error
+	exec(conn1, "BEGIN")
+	exec(conn1, "INSERT INTO tx_test VALUES (4, 500)")
+	exec(conn2, "ALTER TABLE tx_test ADD CONSTRAINT small_c CHECK (c < 100)")
+	_, err = conn1.ExecContext(ctx, "COMMIT")
+	require.Error(t, err)
+	mysqlErr, ok = err.(*mysql.MySQLError)
+	require.True(t, ok)
+	assert.Equal(t, uint16(1213), mysqlErr.Number)
+	assert.Contains(t, mysqlErr.Message, "violate check constraints in tables tx_test")
+	assert.Equal(t, 2, count(conn1))
+}
+
+func TestServerChecks(t *testing.T) {
+	ctx := context.Background()
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15318)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, dtestutils.CreateEnvWithSeedData(t))
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "CREATE TABLE checked (pk INT PRIMARY KEY, c INT, CONSTRAINT positive_c CHECK (c > 0))")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "INSERT INTO checked VALUES (1, 0)")
+	assert.Error(t, err)
+
+	var name, stmt string
+	err = conn.QueryRowContext(ctx, "SHOW CREATE TABLE checked").Scan(&name, &stmt)
+	require.NoError(t, err)
+	assert.Contains(t, stmt, "CONSTRAINT `positive_c` CHECK")
+
+	_, err = conn.ExecContext(ctx, "ALTER TABLE checked DROP CHECK positive_c")
+	require.NoError(t, err)
+	_, err = conn.ExecContext(ctx, "INSERT INTO checked VALUES (1, 0)")
+	assert.NoError(t, err)
+}
+
+func TestServerMultiDatabaseTransactions(t *testing.T) {
+	ctx := context.Background()
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15315).withMaxConnections(3).
+		withDataDir(t.TempDir())
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, dtestutils.CreateTestEnv())
+	}()
+	err := sc.WaitForStart()
+	require.NoError(t, err)
+
+	db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	defer db.Close()
+	setup, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer setup.Close()
+	for _, query := range []string{
+		"CREATE DATABASE db1",
+		"CREATE DATABASE db2",
+		"USE db1",
+		"CREATE TABLE t (pk INT PRIMARY KEY, c INT)",
+		"INSERT INTO t VALUES (1, 1)",
+		"USE db2",
+		"CREATE TABLE t (pk INT PRIMARY KEY, c INT)",
+		"INSERT INTO t VALUES (1, 1)",
+	} {
+		_, err = setup.ExecContext(ctx, query)
+		require.NoError(t, err, query)
+	}
+
+	conn1, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn1.Close()
+	conn2, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn2.Close()
+
+	for _, query := range []string{"BEGIN", "UPDATE db1.t SET c = 10", "UPDATE db2.t SET c = 10"} {
+		_, err = conn1.ExecContext(ctx, query)
+		require.NoError(t, err, query)
+	}
+	_, err = conn2.ExecContext(ctx, "UPDATE db2.t SET c = 20")
+	require.NoError(t, err)
+
+	// the changes to db2 conflict, so the changes to db1 aren't committed either
+	_, err = conn1.ExecContext(ctx, "COMMIT")
+	require.Error(t, err)
+	var c1, c2 int
+	err = conn2.QueryRowContext(ctx, "SELECT c FROM db1.t").Scan(&c1)
+	require.NoError(t, err)
+	err = conn2.QueryRowContext(ctx, "SELECT c FROM db2.t").Scan(&c2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, c1)
+	assert.Equal(t, 20, c2)
+}
+
+func TestServerUserPrivileges(t *testing.T) {
+	ctx := context.Background()
+	env := dtestutils.CreateEnvWithSeedData(t)
+	serverConfig, err := newYamlConfig([]byte(`
+log_level: fatal
+
+listener:
+    port: 15303
+    max_connections: 10
+
+users:
+    - name: reader
+      password: readpass
+      grants:
+        - database: dolt
+          branch: master
+          permissions: [read]
+    - name: dev
+      password: devpass
+      grants:
+        - database: dolt
+          permissions: [read]
+        - database: dolt
+          branch: dev/*
+          permissions: [read, write]
+`))
+	require.NoError(t, err)
+
+	head, err := env.DoltDB.ResolveRef(ctx, env.RepoState.CWBHeadRef())
+	require.NoError(t, err)
+	err = env.DoltDB.NewBranchAtCommit(ctx, ref.NewBranchRef("dev/feature"), head)
+	require.NoError(t, err)
+
+	sc := CreateServerController()
+	defer sc.StopServer()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	connect := func(user, password string) *gosql.Conn {
+		db, err := dbr.Open("mysql", user+":"+password+"@tcp(localhost:15303)/dolt", nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	count := func(conn *gosql.Conn, table string) int {
+		var n int
+		err := conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table).Scan(&n)
+		require.NoError(t, err)
+		return n
+	}
+
+	reader := connect("reader", "readpass")
+	assert.Equal(t, 3, count(reader, "people"))
+	_, err = reader.ExecContext(ctx, "INSERT INTO people (id, first_name, last_name, is_married, age) VALUES ('00000000-0000-0000-0000-000000000010', 'a', 'b', false, 1)")
+	assert.Error(t, err)
+	_, err = reader.ExecContext(ctx, "USE `dolt/dev/feature`")
+	require.NoError(t, err)
+	_, err = reader.ExecContext(ctx, "SELECT * FROM people")
+	assert.Error(t, err)
+	_, err = reader.ExecContext(ctx, "CREATE USER eve")
+	assert.Error(t, err)
+
+	dev := connect("dev", "devpass")
+	_, err = dev.ExecContext(ctx, "CREATE TABLE dev_table (pk INT PRIMARY KEY)")
+	assert.Error(t, err)
+	_, err = dev.ExecContext(ctx, "USE `dolt/dev/feature`")
+	require.NoError(t, err)
+	_, err = dev.ExecContext(ctx, "CREATE TABLE dev_table (pk INT PRIMARY KEY)")
+	require.NoError(t, err)
+	_, err = dev.ExecContext(ctx, "INSERT INTO dev_table VALUES (1), (2)")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count(dev, "dev_table"))
+	_, err = dev.ExecContext(ctx, "SELECT DOLT_COMMIT('-a', '-m', 'add dev_table')")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count(dev, "dev_table AS OF 'HEAD'"))
+
+	// tables read as of another revision are checked against the branch they're read from
+	_, err = reader.ExecContext(ctx, "USE dolt")
+	require.NoError(t, err)
+	_, err = reader.ExecContext(ctx, "SELECT * FROM dev_table AS OF 'dev/feature'")
+	assert.Error(t, err)
+
+	root := connect("root", "")
+	_, err = root.ExecContext(ctx, "CREATE USER 'newbie'@'%' IDENTIFIED BY 'newpass'")
+	require.NoError(t, err)
+	_, err = root.ExecContext(ctx, "GRANT SELECT ON dolt.people TO newbie")
+	require.NoError(t, err)
+	var grant string
+	err = root.QueryRowContext(ctx, "SHOW GRANTS FOR newbie").Scan(&grant)
+	require.NoError(t, err)
+	assert.Equal(t, "GRANT SELECT ON `dolt`.`people` TO `newbie`", grant)
+	_, err = root.ExecContext(ctx, "GRANT INSERT ON dolt.people TO newbie")
+	assert.Error(t, err)
+
+	newbie := connect("newbie", "newpass")
+	assert.Equal(t, 3, count(newbie, "people"))
+	_, err = newbie.ExecContext(ctx, "SELECT * FROM dolt_log")
+	assert.Error(t, err)
+
+	// prepared statements are handled the same way
+	showGrants, err := newbie.PrepareContext(ctx, "SHOW GRANTS")
+	require.NoError(t, err)
+	defer showGrants.Close()
+	err = showGrants.QueryRowContext(ctx).Scan(&grant)
+	require.NoError(t, err)
+	assert.Equal(t, "GRANT SELECT ON `dolt`.`people` TO `newbie`", grant)
+}
+
+// writeSelfSignedCert writes a new self-signed certificate for localhost and its private key to |dir|, returning their
+// paths and the certificate.
+func writeSelfSignedCert(t *testing.T, dir string) (keyPath, certPath string, cert *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	keyPath = filepath.Join(dir, "key.pem")
+	certPath = filepath.Join(dir, "cert.pem")
+	err = ioutil.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	require.NoError(t, err)
+
+	return keyPath, certPath, cert
+}
+
+func TestServerTLS(t *testing.T) {
+	ctx := context.Background()
+	env := dtestutils.CreateEnvWithSeedData(t)
+	keyPath, certPath, cert := writeSelfSignedCert(t, t.TempDir())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	err := mysql.RegisterTLSConfig("dolt-test", &tls.Config{RootCAs: roots, ServerName: "localhost"})
+	require.NoError(t, err)
+	defer mysql.DeregisterTLSConfig("dolt-test")
+
+	tests := []struct {
+		name          string
+		requireSecure bool
+		port          int
+	}{
+		{"optional", false, 15304},
+		{"required", true, 15305},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(test.port).withMaxConnections(2).
+				withTLS(keyPath, certPath, test.requireSecure)
+
+			sc := CreateServerController()
+			defer sc.StopServer()
+			go func() {
+				_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+			}()
+			err := sc.WaitForStart()
+			require.NoError(t, err)
+
+			secure, err := gosql.Open("mysql", ConnectionString(serverConfig)+"dolt?tls=dolt-test")
+			require.NoError(t, err)
+			defer secure.Close()
+			var count string
+			err = secure.QueryRowContext(ctx, "SELECT COUNT(*) FROM people").Scan(&count)
+			require.NoError(t, err)
+			assert.Equal(t, "3", count)
+
+			insecure, err := gosql.Open("mysql", ConnectionString(serverConfig)+"dolt?tls=false")
+			require.NoError(t, err)
+			defer insecure.Close()
+			err = insecure.PingContext(ctx)
+			if test.requireSecure {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
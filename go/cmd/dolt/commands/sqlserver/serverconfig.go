@@ -15,10 +15,12 @@
 package sqlserver
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 )
 
 // LogLevel defines the available levels of logging for the server.
@@ -40,10 +42,17 @@ const (
 	defaultPass             = ""
 	defaultTimeout          = 8 * 60 * 60 * 1000 // 8 hours, same as MySQL
 	defaultReadOnly         = false
+	defaultAllowDropDb      = false
 	defaultLogLevel         = LogLevel_Info
 	defaultAutoCommit       = true
 	defaultMaxConnections   = 1
 	defaultQueryParallelism = 2
+	defaultMetricsHost      = ""
+	defaultMetricsPort      = -1
+	defaultHTTPHost         = ""
+	defaultHTTPPort         = -1
+	// slow queries aren't logged by default
+	defaultSlowQueryThreshold = -1
 )
 
 // String returns the string representation of the log level.
@@ -90,15 +99,61 @@ type ServerConfig interface {
 	// a multiple db configuration. If nil is returned the server will look for a database in the current directory and
 	// give it a name automatically.
 	DatabaseNamesAndPaths() []env.EnvNameAndPath
+	// ReplicationRemotes returns the name of the remote that the branches of each replicated database are pushed to,
+	// by database name.
+	ReplicationRemotes() map[string]string
+	// ReadReplicas returns the configuration of each read replica database, by database name.
+	ReadReplicas() map[string]ReadReplicaConfig
+	// DataDir returns the directory whose subdirectories are loaded as databases, and that CREATE DATABASE and
+	// DOLT_CLONE create databases in. If empty, databases can't be created while the server runs.
+	DataDir() string
+	// AllowDropDatabase returns whether DROP DATABASE may delete the databases in the data directory.
+	AllowDropDatabase() bool
 	// MaxConnections returns the maximum number of simultaneous connections the server will allow.  The default is 1
 	MaxConnections() uint64
+	// Socket returns the path of the unix socket that the server listens on, in addition to its host and port. If
+	// empty, the server only listens on its host and port.
+	Socket() string
 	// QueryParallelism returns the parallelism that should be used by the go-mysql-server analyzer
 	QueryParallelism() int
+	// Users returns the user accounts, other than the server user, that clients may connect with.
+	Users() []dsqle.UserAccount
+	// PrivilegeFilePath returns the path of the file that user accounts created by clients are saved to. If empty, they
+	// are lost when the server stops.
+	PrivilegeFilePath() string
+	// TLSKey returns a path to the servers PEM-encoded private TLS key. "" if there is none.
+	TLSKey() string
+	// TLSCert returns a path to the servers PEM-encoded TLS certificate chain. "" if there is none.
+	TLSCert() string
+	// RequireSecureTransport is true if the server should reject non-TLS connections.
+	RequireSecureTransport() bool
+	// MetricsLabels returns the labels that are added to every metric of the server.
+	MetricsLabels() map[string]string
+	// MetricsHost returns the host that the server's metrics are served on.
+	MetricsHost() string
+	// MetricsPort returns the port that the server's metrics are served on, or -1 if they aren't served.
+	MetricsPort() int
+	// HTTPHost returns the host that the server's HTTP API is served on.
+	HTTPHost() string
+	// HTTPPort returns the port that the server's HTTP API is served on, or -1 if it isn't served.
+	HTTPPort() int
+	// SlowQueryThreshold returns the number of milliseconds a query must run for to be written to the slow query log,
+	// or -1 if slow queries aren't logged.
+	SlowQueryThreshold() int
+	// SlowQueryLogFile returns the path of the file that slow queries are written to. If empty, they are written to
+	// the server's log.
+	SlowQueryLogFile() string
+	// SlowQueryLogPlans returns whether the slow query log includes the plan of each query.
+	SlowQueryLogPlans() bool
+	// TraceFile returns the path of the file that the trace of each query is written to. If empty, queries aren't
+	// traced.
+	TraceFile() string
 }
 
 type commandLineServerConfig struct {
 	host             string
 	port             int
+	socket           string
 	user             string
 	password         string
 	timeout          uint64
@@ -108,6 +163,16 @@ type commandLineServerConfig struct {
 	autoCommit       bool
 	maxConnections   uint64
 	queryParallelism int
+	privilegeFile    string
+	tlsKey           string
+	tlsCert          string
+	requireSecure    bool
+	slowQueryMillis  int
+	slowQueryLog     string
+	slowQueryPlans   bool
+	traceFile        string
+	dataDir          string
+	allowDropDb      bool
 }
 
 // Host returns the domain that the server will run on. Accepts an IPv4 or IPv6 address, in addition to localhost.
@@ -160,6 +225,11 @@ func (cfg *commandLineServerConfig) MaxConnections() uint64 {
 	return cfg.maxConnections
 }
 
+// Socket returns the path of the unix socket that the server listens on, or "" if there is none.
+func (cfg *commandLineServerConfig) Socket() string {
+	return cfg.socket
+}
+
 // QueryParallelism returns the parallelism that should be used by the go-mysql-server analyzer
 func (cfg *commandLineServerConfig) QueryParallelism() int {
 	return cfg.queryParallelism
@@ -172,6 +242,41 @@ func (cfg *commandLineServerConfig) DatabaseNamesAndPaths() []env.EnvNameAndPath
 	return cfg.dbNamesAndPaths
 }
 
+// ReplicationRemotes returns the name of the remote that the branches of each replicated database are pushed to, by
+// database name. Replication can only be configured in a config file.
+func (cfg *commandLineServerConfig) ReplicationRemotes() map[string]string {
+	return nil
+}
+
+// ReadReplicas returns the configuration of each read replica database, by database name. Read replicas can only be
+// configured in a config file.
+func (cfg *commandLineServerConfig) ReadReplicas() map[string]ReadReplicaConfig {
+	return nil
+}
+
+// DataDir returns the directory whose subdirectories are loaded as databases, and that CREATE DATABASE and
+// DOLT_CLONE create databases in. If empty, databases can't be created while the server runs.
+func (cfg *commandLineServerConfig) DataDir() string {
+	return cfg.dataDir
+}
+
+// AllowDropDatabase returns whether DROP DATABASE may delete the databases in the data directory.
+func (cfg *commandLineServerConfig) AllowDropDatabase() bool {
+	return cfg.allowDropDb
+}
+
+// Users returns the user accounts, other than the server user, that clients may connect with. Only the server user
+// can be given on the command line.
+func (cfg *commandLineServerConfig) Users() []dsqle.UserAccount {
+	return nil
+}
+
+// PrivilegeFilePath returns the path of the file that user accounts created by clients are saved to. If empty, they
+// are lost when the server stops.
+func (cfg *commandLineServerConfig) PrivilegeFilePath() string {
+	return cfg.privilegeFile
+}
+
 // withHost updates the host and returns the called `*commandLineServerConfig`, which is useful for chaining calls.
 func (cfg *commandLineServerConfig) withHost(host string) *commandLineServerConfig {
 	cfg.host = host
@@ -208,6 +313,18 @@ func (cfg *commandLineServerConfig) withReadOnly(readonly bool) *commandLineServ
 	return cfg
 }
 
+// withDataDir updates the data directory and returns the called `*commandLineServerConfig`, which is useful for chaining calls.
+func (cfg *commandLineServerConfig) withDataDir(dataDir string) *commandLineServerConfig {
+	cfg.dataDir = dataDir
+	return cfg
+}
+
+// withAllowDropDatabase updates whether databases may be dropped and returns the called `*commandLineServerConfig`, which is useful for chaining calls.
+func (cfg *commandLineServerConfig) withAllowDropDatabase(allow bool) *commandLineServerConfig {
+	cfg.allowDropDb = allow
+	return cfg
+}
+
 // withLogLevel updates the log level and returns the called `*commandLineServerConfig`, which is useful for chaining calls.
 func (cfg *commandLineServerConfig) withLogLevel(loglevel LogLevel) *commandLineServerConfig {
 	cfg.logLevel = loglevel
@@ -221,12 +338,116 @@ func (cfg *commandLineServerConfig) withMaxConnections(maxConnections uint64) *c
 	return cfg
 }
 
+// withSocket updates the path of the unix socket and returns the called `*commandLineServerConfig`, which is useful for
+// chaining calls.
+func (cfg *commandLineServerConfig) withSocket(socket string) *commandLineServerConfig {
+	cfg.socket = socket
+	return cfg
+}
+
 // withQueryParallelism updates the query parallelism and returns the called `*commandLineServerConfig`, which is useful for chaining calls.
 func (cfg *commandLineServerConfig) withQueryParallelism(queryParallelism int) *commandLineServerConfig {
 	cfg.queryParallelism = queryParallelism
 	return cfg
 }
 
+// TLSKey returns a path to the servers PEM-encoded private TLS key. "" if there is none.
+func (cfg *commandLineServerConfig) TLSKey() string {
+	return cfg.tlsKey
+}
+
+// TLSCert returns a path to the servers PEM-encoded TLS certificate chain. "" if there is none.
+func (cfg *commandLineServerConfig) TLSCert() string {
+	return cfg.tlsCert
+}
+
+// RequireSecureTransport is true if the server should reject non-TLS connections.
+func (cfg *commandLineServerConfig) RequireSecureTransport() bool {
+	return cfg.requireSecure
+}
+
+// MetricsLabels returns the labels that are added to every metric of the server. Metrics can only be configured in a
+// config file.
+func (cfg *commandLineServerConfig) MetricsLabels() map[string]string {
+	return nil
+}
+
+// MetricsHost returns the host that the server's metrics are served on.
+func (cfg *commandLineServerConfig) MetricsHost() string {
+	return defaultMetricsHost
+}
+
+// MetricsPort returns the port that the server's metrics are served on, or -1 if they aren't served.
+func (cfg *commandLineServerConfig) MetricsPort() int {
+	return defaultMetricsPort
+}
+
+// HTTPHost returns the host that the server's HTTP API is served on. The HTTP API can only be configured in a config
+// file.
+func (cfg *commandLineServerConfig) HTTPHost() string {
+	return defaultHTTPHost
+}
+
+// HTTPPort returns the port that the server's HTTP API is served on, or -1 if it isn't served.
+func (cfg *commandLineServerConfig) HTTPPort() int {
+	return defaultHTTPPort
+}
+
+// SlowQueryThreshold returns the number of milliseconds a query must run for to be written to the slow query log,
+// or -1 if slow queries aren't logged.
+func (cfg *commandLineServerConfig) SlowQueryThreshold() int {
+	return cfg.slowQueryMillis
+}
+
+// SlowQueryLogFile returns the path of the file that slow queries are written to. If empty, they are written to the
+// server's log.
+func (cfg *commandLineServerConfig) SlowQueryLogFile() string {
+	return cfg.slowQueryLog
+}
+
+// SlowQueryLogPlans returns whether the slow query log includes the plan of each query.
+func (cfg *commandLineServerConfig) SlowQueryLogPlans() bool {
+	return cfg.slowQueryPlans
+}
+
+// TraceFile returns the path of the file that the trace of each query is written to. If empty, queries aren't
+// traced.
+func (cfg *commandLineServerConfig) TraceFile() string {
+	return cfg.traceFile
+}
+
+// withSlowQueryLog updates the slow query log settings and returns the called `*commandLineServerConfig`, which is
+// useful for chaining calls.
+func (cfg *commandLineServerConfig) withSlowQueryLog(thresholdMillis int, file string, logPlans bool) *commandLineServerConfig {
+	cfg.slowQueryMillis = thresholdMillis
+	cfg.slowQueryLog = file
+	cfg.slowQueryPlans = logPlans
+	return cfg
+}
+
+// withTraceFile updates the trace file path and returns the called `*commandLineServerConfig`, which is useful for
+// chaining calls.
+func (cfg *commandLineServerConfig) withTraceFile(traceFile string) *commandLineServerConfig {
+	cfg.traceFile = traceFile
+	return cfg
+}
+
+// withTLS updates the paths of the TLS key and certificate and returns the called `*commandLineServerConfig`, which is
+// useful for chaining calls.
+func (cfg *commandLineServerConfig) withTLS(tlsKey, tlsCert string, requireSecure bool) *commandLineServerConfig {
+	cfg.tlsKey = tlsKey
+	cfg.tlsCert = tlsCert
+	cfg.requireSecure = requireSecure
+	return cfg
+}
+
+// withPrivilegeFile updates the privilege file path and returns the called `*commandLineServerConfig`, which is useful
+// for chaining calls.
+func (cfg *commandLineServerConfig) withPrivilegeFile(privilegeFile string) *commandLineServerConfig {
+	cfg.privilegeFile = privilegeFile
+	return cfg
+}
+
 func (cfg *commandLineServerConfig) withDBNamesAndPaths(dbNamesAndPaths []env.EnvNameAndPath) *commandLineServerConfig {
 	cfg.dbNamesAndPaths = dbNamesAndPaths
 	return cfg
@@ -241,10 +462,12 @@ func DefaultServerConfig() *commandLineServerConfig {
 		password:         defaultPass,
 		timeout:          defaultTimeout,
 		readOnly:         defaultReadOnly,
+		allowDropDb:      defaultAllowDropDb,
 		logLevel:         defaultLogLevel,
 		autoCommit:       defaultAutoCommit,
 		maxConnections:   defaultMaxConnections,
 		queryParallelism: defaultQueryParallelism,
+		slowQueryMillis:  defaultSlowQueryThreshold,
 	}
 }
 
@@ -265,11 +488,66 @@ func ValidateConfig(config ServerConfig) error {
 	if config.LogLevel().String() == "unknown" {
 		return fmt.Errorf("loglevel is invalid: %v\n", string(config.LogLevel()))
 	}
+	if (config.TLSKey() == "") != (config.TLSCert() == "") {
+		return fmt.Errorf("tls_key and tls_cert must both be provided to enable TLS")
+	}
+	if config.MetricsPort() != defaultMetricsPort && (config.MetricsPort() < 1024 || config.MetricsPort() > 65535) {
+		return fmt.Errorf("metrics port is not in the range between 1024-65535: %v\n", config.MetricsPort())
+	}
+	if config.HTTPPort() != defaultHTTPPort && (config.HTTPPort() < 1024 || config.HTTPPort() > 65535) {
+		return fmt.Errorf("http api port is not in the range between 1024-65535: %v\n", config.HTTPPort())
+	}
+	if config.SlowQueryThreshold() < defaultSlowQueryThreshold {
+		return fmt.Errorf("slow query threshold must be -1, to disable the slow query log, or a number of milliseconds: %v\n", config.SlowQueryThreshold())
+	}
+	if config.SlowQueryThreshold() == defaultSlowQueryThreshold && (config.SlowQueryLogFile() != "" || config.SlowQueryLogPlans()) {
+		return fmt.Errorf("slow_query_log.file and slow_query_log.log_plans require slow_query_log.threshold_millis")
+	}
+	for dbName, remote := range config.ReplicationRemotes() {
+		if remote == "" {
+			return fmt.Errorf("replication of database '%s' requires a remote", dbName)
+		}
+	}
+	if config.AllowDropDatabase() && config.DataDir() == "" {
+		return fmt.Errorf("allow_drop_database requires a data directory")
+	}
+	for dbName, replica := range config.ReadReplicas() {
+		if replica.Remote == "" {
+			return fmt.Errorf("read replica database '%s' requires a remote", dbName)
+		}
+		if _, ok := config.ReplicationRemotes()[dbName]; ok {
+			return fmt.Errorf("database '%s' can't be both replicated and a read replica", dbName)
+		}
+	}
+	if config.RequireSecureTransport() && config.TLSKey() == "" {
+		return fmt.Errorf("require_secure_transport can only be `true` when a tls_key and tls_cert are provided")
+	}
 	return nil
 }
 
-// ConnectionString returns a Data Source Name (DSN) to be used by go clients for connecting to a running server.
+// LoadTLSConfig returns the tls.Config that the server should use for the key and certificate of |cfg|, or nil if
+// TLS is not configured.
+func LoadTLSConfig(cfg ServerConfig) (*tls.Config, error) {
+	if cfg.TLSKey() == "" || cfg.TLSCert() == "" {
+		return nil, nil
+	}
+
+	c, err := tls.LoadX509KeyPair(cfg.TLSCert(), cfg.TLSKey())
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{c},
+	}, nil
+}
+
+// ConnectionString returns a Data Source Name (DSN) to be used by go clients for connecting to a running server. When
+// the server listens on a unix socket, clients connect through the socket.
 func ConnectionString(config ServerConfig) string {
+	if config.Socket() != "" {
+		return fmt.Sprintf("%v:%v@unix(%v)/", config.User(), config.Password(), config.Socket())
+	}
 	return fmt.Sprintf("%v:%v@tcp(%v:%v)/", config.User(), config.Password(), config.Host(), config.Port())
 }
 
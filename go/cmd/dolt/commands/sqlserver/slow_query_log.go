@@ -0,0 +1,159 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+	"github.com/sirupsen/logrus"
+)
+
+// slowQueryLog logs the queries that take at least a threshold to run, with the number of rows they examined and
+// returned. Queries are appended to a file in the format of the MySQL slow query log, which tools such as
+// mysqldumpslow and pt-query-digest read, or are written to the server's log.
+type slowQueryLog struct {
+	threshold time.Duration
+	logPlans  bool
+	engine    *sqle.Engine
+
+	mu  sync.Mutex
+	out io.WriteCloser
+}
+
+// newSlowQueryLog returns the slow query log configured by |cfg|, or nil if slow queries aren't logged.
+func newSlowQueryLog(cfg ServerConfig, engine *sqle.Engine) (*slowQueryLog, error) {
+	if cfg.SlowQueryThreshold() == defaultSlowQueryThreshold {
+		return nil, nil
+	}
+
+	l := &slowQueryLog{
+		threshold: time.Duration(cfg.SlowQueryThreshold()) * time.Millisecond,
+		logPlans:  cfg.SlowQueryLogPlans(),
+		engine:    engine,
+	}
+
+	if cfg.SlowQueryLogFile() != "" {
+		f, err := os.OpenFile(cfg.SlowQueryLogFile(), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, err
+		}
+		l.out = f
+	}
+
+	return l, nil
+}
+
+// queryFinished logs |query| if it ran for at least the log's threshold.
+func (l *slowQueryLog) queryFinished(ctx *sql.Context, query string, start time.Time, duration time.Duration, rowsSent, rowsExamined uint64) {
+	if l == nil || duration < l.threshold {
+		return
+	}
+
+	var plan string
+	if l.logPlans {
+		plan = l.queryPlan(ctx, query)
+	}
+
+	if l.out == nil {
+		fields := logrus.Fields{
+			"query_time":    duration.Seconds(),
+			"rows_sent":     rowsSent,
+			"rows_examined": rowsExamined,
+			"user":          ctx.Client().User,
+			"host":          ctx.Client().Address,
+		}
+		if plan != "" {
+			fields["plan"] = plan
+		}
+		logrus.WithFields(fields).Warnf("slow query: %s", query)
+		return
+	}
+
+	entry := formatSlowQuery(ctx, query, start, duration, rowsSent, rowsExamined, plan)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := io.WriteString(l.out, entry); err != nil {
+		logrus.Errorf("failed to write to the slow query log: %v", err)
+	}
+}
+
+// formatSlowQuery returns the entry of the MySQL slow query log for |query|. The plan of the query, if given, is
+// included as comment lines.
+func formatSlowQuery(ctx *sql.Context, query string, start time.Time, duration time.Duration, rowsSent, rowsExamined uint64, plan string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Time: %s\n", start.UTC().Format("2006-01-02T15:04:05.000000Z"))
+	fmt.Fprintf(&sb, "# User@Host: %s[%s] @ %s []  Id: %d\n", ctx.Client().User, ctx.Client().User, ctx.Client().Address, ctx.ID())
+	fmt.Fprintf(&sb, "# Query_time: %.6f  Lock_time: 0.000000 Rows_sent: %d  Rows_examined: %d\n", duration.Seconds(), rowsSent, rowsExamined)
+	if plan != "" {
+		sb.WriteString("# Plan:\n")
+		for _, line := range strings.Split(strings.TrimRight(plan, "\n"), "\n") {
+			fmt.Fprintf(&sb, "#   %s\n", line)
+		}
+	}
+	if db := ctx.GetCurrentDatabase(); db != "" {
+		fmt.Fprintf(&sb, "use `%s`;\n", db)
+	}
+	fmt.Fprintf(&sb, "SET timestamp=%d;\n", start.Unix())
+
+	query = strings.TrimSpace(query)
+	if !strings.HasSuffix(query, ";") {
+		query += ";"
+	}
+	sb.WriteString(query)
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// queryPlan returns the plan the analyzer chooses for |query|, or an empty string if it can't be explained. Only the
+// statements that EXPLAIN supports are analyzed, as analyzing other statements could have side effects.
+func (l *slowQueryLog) queryPlan(ctx *sql.Context, query string) string {
+	switch sqlparser.Preview(query) {
+	case sqlparser.StmtSelect, sqlparser.StmtInsert, sqlparser.StmtReplace, sqlparser.StmtUpdate, sqlparser.StmtDelete:
+	default:
+		return ""
+	}
+
+	node, err := parse.Parse(ctx, query)
+	if err != nil {
+		return ""
+	}
+
+	analyzed, err := l.engine.Analyzer.Analyze(ctx, node, nil)
+	if err != nil {
+		logrus.Debugf("failed to analyze slow query for the slow query log: %v", err)
+		return ""
+	}
+
+	return analyzed.String()
+}
+
+// Close closes the file the log is written to, if any.
+func (l *slowQueryLog) Close() error {
+	if l == nil || l.out == nil {
+		return nil
+	}
+	return l.out.Close()
+}
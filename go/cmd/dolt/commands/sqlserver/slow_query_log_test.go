@@ -0,0 +1,132 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestFormatSlowQuery(t *testing.T) {
+	sess := sql.NewSession("localhost", "127.0.0.1:4321", "root", 7)
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sess))
+	ctx.SetCurrentDatabase("mydb")
+	start := time.Date(2021, 3, 5, 12, 30, 0, 0, time.UTC)
+
+	entry := formatSlowQuery(ctx, "SELECT * FROM people", start, 1500*time.Millisecond, 3, 10, "Project\n └─ Table(people)\n")
+	assert.Equal(t, `# Time: 2021-03-05T12:30:00.000000Z
+# User@Host: root[root] @ 127.0.0.1:4321 []  Id: 7
+# Query_time: 1.500000  Lock_time: 0.000000 Rows_sent: 3  Rows_examined: 10
+# Plan:
+#   Project
+#    └─ Table(people)
+use `+"`mydb`"+`;
+SET timestamp=1614947400;
+SELECT * FROM people;
+`, entry)
+}
+
+func TestServerSlowQueryLog(t *testing.T) {
+	ctx := context.Background()
+	env := dtestutils.CreateEnvWithSeedData(t)
+	dir := t.TempDir()
+	slowLog := filepath.Join(dir, "slow.log")
+	traceFile := filepath.Join(dir, "trace.json")
+
+	serverConfig, err := newYamlConfig([]byte(`
+log_level: fatal
+
+listener:
+    port: 15308
+
+slow_query_log:
+    threshold_millis: 0
+    file: ` + slowLog + `
+    log_plans: true
+
+tracing:
+    file: ` + traceFile + `
+`))
+	require.NoError(t, err)
+
+	sc := CreateServerController()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	err = sc.WaitForStart()
+	require.NoError(t, err)
+
+	db, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	sess := db.NewSession(nil)
+
+	var people []testPerson
+	_, err = sess.Select("*").From("people").LoadContext(ctx, &people)
+	require.NoError(t, err)
+	require.Len(t, people, 3)
+
+	var names []string
+	_, err = sess.Select("name").From("people").Where("age > 30").LoadContext(ctx, &names)
+	require.NoError(t, err)
+	require.Equal(t, []string{bill.Name}, names)
+
+	names = nil
+	_, err = sess.Select("name").From("people").Where("id = ?", dtestutils.UUIDS[0].String()).LoadContext(ctx, &names)
+	require.NoError(t, err)
+	require.Len(t, names, 1)
+
+	require.NoError(t, db.Close())
+	sc.StopServer()
+	require.NoError(t, sc.WaitForClose())
+
+	data, err := ioutil.ReadFile(slowLog)
+	require.NoError(t, err)
+	log := string(data)
+	assert.Contains(t, log, "Rows_sent: 3  Rows_examined: 3\n")
+	assert.Contains(t, log, "Rows_sent: 1  Rows_examined: 3\n")
+	assert.Contains(t, log, "Rows_sent: 1  Rows_examined: 1\n")
+	assert.Contains(t, log, "# Plan:\n")
+	assert.Contains(t, log, "SELECT name FROM people WHERE (age > 30);\n")
+
+	data, err = ioutil.ReadFile(traceFile)
+	require.NoError(t, err)
+	var events []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &events))
+
+	ops := make(map[string]bool)
+	for _, ev := range events {
+		ops[ev["name"].(string)] = true
+		if ev["name"] == "dolt.table_read" {
+			args := ev["args"].(map[string]interface{})
+			assert.Equal(t, "people", args["table"])
+		}
+	}
+	assert.True(t, ops["query"])
+	assert.True(t, ops["parse"])
+	assert.True(t, ops["analyze"])
+	assert.True(t, ops["dolt.table_read"])
+	assert.True(t, ops["dolt.index_lookup"])
+}
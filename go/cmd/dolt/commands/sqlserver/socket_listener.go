@@ -0,0 +1,138 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+var errListenerClosed = errors.New("listener closed")
+
+// staleSocketDialTimeout is how long to wait for a server listening on an existing socket file to accept a connection
+const staleSocketDialTimeout = time.Second
+
+// removeStaleSocket removes the socket file at |path| if it was left behind by a server that didn't shut down cleanly.
+// Returns an error if another server is still listening on the socket, or if |path| exists and isn't a socket.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("can't listen on socket '%s'; the file exists and isn't a socket", path)
+	}
+
+	conn, err := net.DialTimeout("unix", path, staleSocketDialTimeout)
+	if err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("can't listen on socket '%s'; another server is listening on it", path)
+	}
+
+	return os.Remove(path)
+}
+
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// multiListener is a net.Listener which accepts the connections of several listeners, such as the TCP listener and
+// the unix socket listener of a server. Connections are handed out by a single listener so that the server numbers
+// them from one sequence, no matter which listener accepted them.
+type multiListener struct {
+	listeners []net.Listener
+	conns     chan acceptResult
+	closed    chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+var _ net.Listener = (*multiListener)(nil)
+
+// newMultiListener returns a multiListener that accepts the connections of |listeners|. The first listener is the
+// one whose address is returned by Addr.
+func newMultiListener(listeners ...net.Listener) *multiListener {
+	ml := &multiListener{
+		listeners: listeners,
+		conns:     make(chan acceptResult),
+		closed:    make(chan struct{}),
+	}
+
+	for _, l := range listeners {
+		ml.wg.Add(1)
+		go ml.accept(l)
+	}
+
+	return ml
+}
+
+func (ml *multiListener) accept(l net.Listener) {
+	defer ml.wg.Done()
+	for {
+		conn, err := l.Accept()
+		select {
+		case ml.conns <- acceptResult{conn, err}:
+		case <-ml.closed:
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Accept implements net.Listener. Returns an error once any of the listeners fails, which happens when the
+// multiListener is closed.
+func (ml *multiListener) Accept() (net.Conn, error) {
+	select {
+	case res := <-ml.conns:
+		return res.conn, res.err
+	case <-ml.closed:
+		return nil, errListenerClosed
+	}
+}
+
+// Close implements net.Listener by closing all of the listeners. Closing a unix socket listener removes its socket
+// file.
+func (ml *multiListener) Close() error {
+	var err error
+	ml.closeOnce.Do(func() {
+		close(ml.closed)
+		for _, l := range ml.listeners {
+			if closeErr := l.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+		ml.wg.Wait()
+	})
+	return err
+}
+
+// Addr implements net.Listener.
+func (ml *multiListener) Addr() net.Addr {
+	return ml.listeners[0].Addr()
+}
@@ -0,0 +1,119 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlserver
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/gocraft/dbr/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("missing", func(t *testing.T) {
+		assert.NoError(t, removeStaleSocket(filepath.Join(dir, "missing.sock")))
+	})
+
+	t.Run("not a socket", func(t *testing.T) {
+		path := filepath.Join(dir, "file.sock")
+		require.NoError(t, ioutil.WriteFile(path, []byte("data"), 0644))
+		assert.Error(t, removeStaleSocket(path))
+		assert.FileExists(t, path)
+	})
+
+	t.Run("in use", func(t *testing.T) {
+		path := filepath.Join(dir, "live.sock")
+		l, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		defer l.Close()
+		go func() {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				_ = conn.Close()
+			}
+		}()
+
+		assert.Error(t, removeStaleSocket(path))
+		assert.FileExists(t, path)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		path := filepath.Join(dir, "stale.sock")
+		l, err := net.Listen("unix", path)
+		require.NoError(t, err)
+		// leave the socket file behind, as a server that crashed would
+		l.(*net.UnixListener).SetUnlinkOnClose(false)
+		require.NoError(t, l.Close())
+		require.FileExists(t, path)
+
+		assert.NoError(t, removeStaleSocket(path))
+		assert.NoFileExists(t, path)
+	})
+}
+
+func TestServerSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "dolt.sock")
+
+	// a socket file left behind by a previous server is replaced
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	require.NoError(t, l.Close())
+
+	env := dtestutils.CreateEnvWithSeedData(t)
+	serverConfig := DefaultServerConfig().withLogLevel(LogLevel_Fatal).withPort(15313).withMaxConnections(2).withSocket(socket)
+
+	sc := CreateServerController()
+	go func() {
+		_, _ = Serve(context.Background(), "", serverConfig, sc, env)
+	}()
+	require.NoError(t, sc.WaitForStart())
+
+	socketConn, err := dbr.Open("mysql", ConnectionString(serverConfig)+"dolt", nil)
+	require.NoError(t, err)
+	defer socketConn.Close()
+	tcpConn, err := dbr.Open("mysql", "root:@tcp(localhost:15313)/dolt", nil)
+	require.NoError(t, err)
+	defer tcpConn.Close()
+
+	var socketID, tcpID uint32
+	for _, conn := range []*dbr.Connection{socketConn, tcpConn} {
+		var count int
+		err = conn.NewSession(nil).Select("COUNT(*)").From("people").LoadOne(&count)
+		require.NoError(t, err)
+		assert.Equal(t, 3, count)
+	}
+
+	// connections are numbered from one sequence, whichever listener accepted them
+	require.NoError(t, socketConn.QueryRow("SELECT CONNECTION_ID()").Scan(&socketID))
+	require.NoError(t, tcpConn.QueryRow("SELECT CONNECTION_ID()").Scan(&tcpID))
+	assert.NotEqual(t, socketID, tcpID)
+
+	sc.StopServer()
+	require.NoError(t, sc.WaitForClose())
+	assert.NoFileExists(t, socket)
+}
@@ -42,6 +42,16 @@ const (
 	noAutoCommitFlag     = "no-auto-commit"
 	configFileFlag       = "config"
 	queryParallelismFlag = "query-parallelism"
+	privilegeFileFlag    = "privilege-file"
+	tlsKeyFlag           = "tls-key"
+	tlsCertFlag          = "tls-cert"
+	requireSecureFlag    = "require-secure-transport"
+	slowQueryFlag        = "slow-query-threshold"
+	slowQueryLogFlag     = "slow-query-log"
+	logQueryPlansFlag    = "log-query-plans"
+	traceFileFlag        = "trace-file"
+	allowDropDbFlag      = "allow-drop-database"
+	socketFlag           = "socket"
 )
 
 var sqlServerDocs = cli.CommandDocumentationContent{
@@ -63,6 +73,8 @@ SUPPORTED CONFIG FILE FIELDS:
 
 		{{.EmphasisLeft}}behavior.autocommit{{.EmphasisRight}} - If true write queries will automatically alter the working set. When working with autocommit enabled it is highly recommended that listener.max_connections be set to 1 as concurrency issues will arise otherwise
 
+		{{.EmphasisLeft}}behavior.allow_drop_database{{.EmphasisRight}} - If true {{.EmphasisLeft}}DROP DATABASE{{.EmphasisRight}} deletes databases in the data directory, along with their data. Requires {{.EmphasisLeft}}data_dir{{.EmphasisRight}}
+
 		{{.EmphasisLeft}}user.name{{.EmphasisRight}} - The username that connections should use for authentication
 
 		{{.EmphasisLeft}}user.password{{.EmphasisRight}} - The password that connections should use for authentication.
@@ -71,24 +83,74 @@ SUPPORTED CONFIG FILE FIELDS:
 
 		{{.EmphasisLeft}}listener.port{{.EmphasisRight}} - The port that the server should listen on
 
+		{{.EmphasisLeft}}listener.socket{{.EmphasisRight}} - A path to a unix socket that the server listens on, in addition to its host and port. A stale socket file left by a server that didn't shut down cleanly is removed. {{.EmphasisLeft}}dolt sql-client{{.EmphasisRight}} connects through the socket when one is configured
+
 		{{.EmphasisLeft}}listener.max_connections{{.EmphasisRight}} - The number of simultaneous connections that the server will accept
 
 		{{.EmphasisLeft}}listener.read_timeout_millis{{.EmphasisRight}} - The number of milliseconds that the server will wait for a read operation
 
 		{{.EmphasisLeft}}listener.write_timeout_millis{{.EmphasisRight}} - The number of milliseconds that the server will wait for a write operation
 
+		{{.EmphasisLeft}}listener.tls_key{{.EmphasisRight}} - A path to the unencrypted PEM-encoded private key that the server uses for TLS connections
+
+		{{.EmphasisLeft}}listener.tls_cert{{.EmphasisRight}} - A path to the PEM-encoded certificate chain that the server uses for TLS connections
+
+		{{.EmphasisLeft}}listener.require_secure_transport{{.EmphasisRight}} - If true connections that don't use TLS are rejected. Requires {{.EmphasisLeft}}listener.tls_key{{.EmphasisRight}} and {{.EmphasisLeft}}listener.tls_cert{{.EmphasisRight}}
+
 		{{.EmphasisLeft}}performance.query_parallelism{{.EmphasisRight}} - Amount of go routines spawned to process each query
 
 		{{.EmphasisLeft}}databases{{.EmphasisRight}} - a list of dolt data repositories to make available as SQL databases. If databases is missing or empty then the working directory must be a valid dolt data repository which will be made available as a SQL database
 		
+		{{.EmphasisLeft}}data_dir{{.EmphasisRight}} - A directory whose subdirectories are dolt data repositories to make available as SQL databases, in addition to those in databases. {{.EmphasisLeft}}CREATE DATABASE{{.EmphasisRight}} and {{.EmphasisLeft}}DOLT_CLONE(url, name){{.EmphasisRight}} create new databases in this directory while the server runs. File urls given to {{.EmphasisLeft}}DOLT_CLONE{{.EmphasisRight}} must be in this directory
+
 		{{.EmphasisLeft}}databases[i].path{{.EmphasisRight}} - A path to a dolt data repository
 		
 		{{.EmphasisLeft}}databases[i].name{{.EmphasisRight}} - The name that the database corresponding to the given path should be referenced via SQL
 
-If a config file is not provided many of these settings may be configured on the command line.`,
+		{{.EmphasisLeft}}databases[i].replication.remote{{.EmphasisRight}} - The name of a remote of the database that its branches are pushed to, in the background, after each commit or branch update. Failed pushes are retried with backoff, and the state of replication is shown by the {{.EmphasisLeft}}dolt_replication_status{{.EmphasisRight}} system table
+
+		{{.EmphasisLeft}}databases[i].read_replica.remote{{.EmphasisRight}} - The name of a remote of the database that it follows as a read replica. The branches of the remote are pulled into the database, and writes to the database are rejected. A database can't be both replicated and a read replica
+
+		{{.EmphasisLeft}}databases[i].read_replica.branches{{.EmphasisRight}} - The branches of the remote that a read replica pulls. Defaults to the checked out branch
+
+		{{.EmphasisLeft}}databases[i].read_replica.pull_interval_millis{{.EmphasisRight}} - The time between pulls of a read replica. If 0, the default, the branches are pulled before each transaction whose statement uses the database: when it is the current database, or the statement names it or switches to it with {{.EmphasisLeft}}USE{{.EmphasisRight}}. If greater than 0, the branches are only pulled periodically, never before a transaction, and transactions read the commit of the last pull. Each transaction reads the commit at the head of the checked out branch when it started
+
+		{{.EmphasisLeft}}metrics.host{{.EmphasisRight}} - The host address that Prometheus metrics are served on, at {{.EmphasisLeft}}/metrics{{.EmphasisRight}}
+
+		{{.EmphasisLeft}}metrics.port{{.EmphasisRight}} - The port that Prometheus metrics are served on. Metrics are only served if a port is given
+
+		{{.EmphasisLeft}}metrics.labels{{.EmphasisRight}} - A map of labels, such as {{.EmphasisLeft}}instance{{.EmphasisRight}}, that are added to every metric
+
+		{{.EmphasisLeft}}http_api.host{{.EmphasisRight}} - The host address that the HTTP API is served on
+
+		{{.EmphasisLeft}}http_api.port{{.EmphasisRight}} - The port that the HTTP API is served on. The API is only served if a port is given. The API is served over HTTPS when {{.EmphasisLeft}}listener.tls_key{{.EmphasisRight}} and {{.EmphasisLeft}}listener.tls_cert{{.EmphasisRight}} are given, and must be when {{.EmphasisLeft}}listener.require_secure_transport{{.EmphasisRight}} is set. Requests authenticate with HTTP basic authentication as the users of the server, and have the same privileges as their MySQL connections. {{.EmphasisLeft}}POST /api/v1/query{{.EmphasisRight}} runs the SQL query of a JSON request body such as {{.EmphasisLeft}}{"database": "mydb", "query": "SELECT * FROM t"}{{.EmphasisRight}} and returns its typed result set. {{.EmphasisLeft}}/api/v1/databases/{{.LessThan}}database{{.GreaterThan}}/branches{{.EmphasisRight}}, {{.EmphasisLeft}}commits{{.EmphasisRight}}, {{.EmphasisLeft}}diffs{{.EmphasisRight}} and {{.EmphasisLeft}}merges{{.EmphasisRight}} list and create branches, read the commit log of a branch, diff two commits and merge branches
+
+		{{.EmphasisLeft}}slow_query_log.threshold_millis{{.EmphasisRight}} - Queries that take at least this many milliseconds are written to the slow query log, with the number of rows they examined and returned. Slow queries are not logged if this is not given
+
+		{{.EmphasisLeft}}slow_query_log.file{{.EmphasisRight}} - The file that slow queries are appended to, in the format of the MySQL slow query log. If not given, slow queries are written to the server's log
+
+		{{.EmphasisLeft}}slow_query_log.log_plans{{.EmphasisRight}} - If true the plan of each slow query is logged with it
+
+		{{.EmphasisLeft}}tracing.file{{.EmphasisRight}} - If given, the parsing, analysis, table reads and index lookups of every query are traced, and the spans are written to this file in the Trace Event Format read by chrome://tracing and Perfetto
+
+		{{.EmphasisLeft}}users{{.EmphasisRight}} - a list of user accounts, in addition to {{.EmphasisLeft}}user.name{{.EmphasisRight}}, that connections may authenticate as. {{.EmphasisLeft}}user.name{{.EmphasisRight}} has every privilege, and the other accounts only have the privileges granted to them
+
+		{{.EmphasisLeft}}users[i].name{{.EmphasisRight}} - The name of the account
+
+		{{.EmphasisLeft}}users[i].password{{.EmphasisRight}} - The password of the account, in plain text
+
+		{{.EmphasisLeft}}users[i].password_hash{{.EmphasisRight}} - The mysql_native_password hash of the password of the account, used instead of {{.EmphasisLeft}}password{{.EmphasisRight}}
+
+		{{.EmphasisLeft}}users[i].grants{{.EmphasisRight}} - The privileges of the account. Each grant has a {{.EmphasisLeft}}database{{.EmphasisRight}}, {{.EmphasisLeft}}branch{{.EmphasisRight}} and {{.EmphasisLeft}}table{{.EmphasisRight}} pattern, such as {{.EmphasisLeft}}dev/*{{.EmphasisRight}}, and a list of {{.EmphasisLeft}}permissions{{.EmphasisRight}}, which may be {{.EmphasisLeft}}read{{.EmphasisRight}} and {{.EmphasisLeft}}write{{.EmphasisRight}}. Missing patterns match everything
+
+		{{.EmphasisLeft}}privilege_file{{.EmphasisRight}} - A file that accounts created with CREATE USER, GRANT and REVOKE are saved to, and loaded from when the server starts
+
+If a config file is not provided many of these settings may be configured on the command line.
+
+A server started with a config file reloads it when it receives SIGHUP, or when the server user calls {{.EmphasisLeft}}DOLT_RELOAD_CONFIG(){{.EmphasisRight}}. The {{.EmphasisLeft}}log_level{{.EmphasisRight}}, {{.EmphasisLeft}}behavior.read_only{{.EmphasisRight}}, {{.EmphasisLeft}}user.name{{.EmphasisRight}}, {{.EmphasisLeft}}user.password{{.EmphasisRight}}, {{.EmphasisLeft}}listener.read_timeout_millis{{.EmphasisRight}} and {{.EmphasisLeft}}listener.write_timeout_millis{{.EmphasisRight}} settings are applied without dropping any connections. Changes to the other settings are reported in the server's log, and take effect when the server is restarted.`,
 	Synopsis: []string{
 		"--config {{.LessThan}}file{{.GreaterThan}}",
-		"[-H {{.LessThan}}host{{.GreaterThan}}] [-P {{.LessThan}}port{{.GreaterThan}}] [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [-t {{.LessThan}}timeout{{.GreaterThan}}] [-l {{.LessThan}}loglevel{{.GreaterThan}}] [--multi-db-dir {{.LessThan}}directory{{.GreaterThan}}] [--query-parallelism {{.LessThan}}num-go-routines{{.GreaterThan}}] [-r]",
+		"[-H {{.LessThan}}host{{.GreaterThan}}] [-P {{.LessThan}}port{{.GreaterThan}}] [--socket {{.LessThan}}file{{.GreaterThan}}] [-u {{.LessThan}}user{{.GreaterThan}}] [-p {{.LessThan}}password{{.GreaterThan}}] [-t {{.LessThan}}timeout{{.GreaterThan}}] [-l {{.LessThan}}loglevel{{.GreaterThan}}] [--multi-db-dir {{.LessThan}}directory{{.GreaterThan}}] [--query-parallelism {{.LessThan}}num-go-routines{{.GreaterThan}}] [--privilege-file {{.LessThan}}file{{.GreaterThan}}] [--tls-key {{.LessThan}}file{{.GreaterThan}} --tls-cert {{.LessThan}}file{{.GreaterThan}} [--require-secure-transport]] [--slow-query-threshold {{.LessThan}}millis{{.GreaterThan}} [--slow-query-log {{.LessThan}}file{{.GreaterThan}}] [--log-query-plans]] [--trace-file {{.LessThan}}file{{.GreaterThan}}] [--allow-drop-database] [-r]",
 	},
 }
 
@@ -119,14 +181,24 @@ func (cmd SqlServerCmd) CreateArgParser() *argparser.ArgParser {
 	ap.SupportsString(configFileFlag, "", "file", "When provided configuration is taken from the yaml config file and all command line parameters are ignored.")
 	ap.SupportsString(hostFlag, "H", "Host address", fmt.Sprintf("Defines the host address that the server will run on (default `%v`)", serverConfig.Host()))
 	ap.SupportsUint(portFlag, "P", "Port", fmt.Sprintf("Defines the port that the server will run on (default `%v`)", serverConfig.Port()))
+	ap.SupportsString(socketFlag, "", "file", "Defines a unix socket that the server listens on, in addition to its host and port.")
 	ap.SupportsString(userFlag, "u", "User", fmt.Sprintf("Defines the server user (default `%v`)", serverConfig.User()))
 	ap.SupportsString(passwordFlag, "p", "Password", fmt.Sprintf("Defines the server password (default `%v`)", serverConfig.Password()))
 	ap.SupportsInt(timeoutFlag, "t", "Connection timeout", fmt.Sprintf("Defines the timeout, in seconds, used for connections\nA value of `0` represents an infinite timeout (default `%v`)", serverConfig.ReadTimeout()))
 	ap.SupportsFlag(readonlyFlag, "r", "Disables modification of the database")
 	ap.SupportsString(logLevelFlag, "l", "Log level", fmt.Sprintf("Defines the level of logging provided\nOptions are: `trace', `debug`, `info`, `warning`, `error`, `fatal` (default `%v`)", serverConfig.LogLevel()))
-	ap.SupportsString(multiDBDirFlag, "", "directory", "Defines a directory whose subdirectories should all be dolt data repositories accessible as independent databases.")
+	ap.SupportsString(multiDBDirFlag, "", "directory", "Defines a directory whose subdirectories should all be dolt data repositories accessible as independent databases. CREATE DATABASE and DOLT_CLONE create new databases in this directory.")
 	ap.SupportsFlag(noAutoCommitFlag, "", "When provided sessions will not automatically commit their changes to the working set. Anything not manually committed will be lost.")
 	ap.SupportsInt(queryParallelismFlag, "", "num-go-routines", fmt.Sprintf("Set the number of go routines spawned to handle each query (default `%d`)", serverConfig.QueryParallelism()))
+	ap.SupportsString(privilegeFileFlag, "", "file", "Defines a file that user accounts and their privileges are saved to and loaded from.")
+	ap.SupportsString(tlsKeyFlag, "", "file", "Defines the unencrypted PEM-encoded private key used for TLS connections.")
+	ap.SupportsString(tlsCertFlag, "", "file", "Defines the PEM-encoded certificate chain used for TLS connections.")
+	ap.SupportsFlag(requireSecureFlag, "", "When provided connections that don't use TLS are rejected.")
+	ap.SupportsInt(slowQueryFlag, "", "millis", "When provided queries that take at least this many milliseconds are written to the slow query log.")
+	ap.SupportsString(slowQueryLogFlag, "", "file", "Defines the file that slow queries are appended to. If not provided they are written to the server's log.")
+	ap.SupportsFlag(logQueryPlansFlag, "", "When provided the plan of each slow query is written to the slow query log.")
+	ap.SupportsString(traceFileFlag, "", "file", "When provided every query is traced, and the traces are written to this file in the Trace Event Format.")
+	ap.SupportsFlag(allowDropDbFlag, "", "When provided DROP DATABASE deletes databases in the --multi-db-dir directory, along with their data.")
 	return ap
 }
 
@@ -200,6 +272,9 @@ func getCommandLineServerConfig(dEnv *env.DoltEnv, apr *argparser.ArgParseResult
 	if port, ok := apr.GetInt(portFlag); ok {
 		serverConfig.withPort(port)
 	}
+	if socket, ok := apr.GetValue(socketFlag); ok {
+		serverConfig.withSocket(socket)
+	}
 	if user, ok := apr.GetValue(userFlag); ok {
 		serverConfig.withUser(user)
 	}
@@ -222,13 +297,7 @@ func getCommandLineServerConfig(dEnv *env.DoltEnv, apr *argparser.ArgParseResult
 		serverConfig.withLogLevel(LogLevel(logLevel))
 	}
 	if multiDBDir, ok := apr.GetValue(multiDBDirFlag); ok {
-		dbNamesAndPaths, err := env.DBNamesAndPathsFromDir(dEnv.FS, multiDBDir)
-
-		if err != nil {
-			return nil, errors.New("failed to read databases in path specified by --multi-db-dir. error: " + err.Error())
-		}
-
-		serverConfig.withDBNamesAndPaths(dbNamesAndPaths)
+		serverConfig.withDataDir(multiDBDir)
 	} else {
 		if !cli.CheckEnvIsValid(dEnv) {
 			return nil, errors.New("not a valid dolt directory")
@@ -239,6 +308,27 @@ func getCommandLineServerConfig(dEnv *env.DoltEnv, apr *argparser.ArgParseResult
 		serverConfig.withQueryParallelism(queryParallelism)
 	}
 
+	if privilegeFile, ok := apr.GetValue(privilegeFileFlag); ok {
+		serverConfig.withPrivilegeFile(privilegeFile)
+	}
+
+	tlsKey, _ := apr.GetValue(tlsKeyFlag)
+	tlsCert, _ := apr.GetValue(tlsCertFlag)
+	serverConfig.withTLS(tlsKey, tlsCert, apr.Contains(requireSecureFlag))
+
+	slowQueryMillis, ok := apr.GetInt(slowQueryFlag)
+	if !ok {
+		slowQueryMillis = defaultSlowQueryThreshold
+	}
+	slowQueryLog, _ := apr.GetValue(slowQueryLogFlag)
+	serverConfig.withSlowQueryLog(slowQueryMillis, slowQueryLog, apr.Contains(logQueryPlansFlag))
+
+	if traceFile, ok := apr.GetValue(traceFileFlag); ok {
+		serverConfig.withTraceFile(traceFile)
+	}
+
+	serverConfig.withAllowDropDatabase(apr.Contains(allowDropDbFlag))
+
 	serverConfig.autoCommit = !apr.Contains(noAutoCommitFlag)
 	return serverConfig, nil
 }
@@ -254,5 +344,6 @@ func getYAMLServerConfig(fs filesys.Filesys, path string) (ServerConfig, error)
 		return nil, fmt.Errorf("Failed to parse yaml file '%s'. Error: %s", path, err.Error())
 	}
 
+	cfg.filePath = path
 	return cfg, nil
 }
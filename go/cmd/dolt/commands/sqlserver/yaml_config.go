@@ -22,6 +22,7 @@ import (
 	"gopkg.in/yaml.v2"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 )
 
 func strPtr(s string) *string {
@@ -40,10 +41,33 @@ func intPtr(n int) *int {
 	return &n
 }
 
+func nillableStrPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nillableIntPtr returns nil for -1, the value of settings that are disabled by default.
+func nillableIntPtr(n int) *int {
+	if n == -1 {
+		return nil
+	}
+	return &n
+}
+
+func nillableBoolPtr(b bool) *bool {
+	if !b {
+		return nil
+	}
+	return &b
+}
+
 // BehaviorYAMLConfig contains server configuration regarding how the server should behave
 type BehaviorYAMLConfig struct {
-	ReadOnly   *bool `yaml:"read_only"`
-	AutoCommit *bool
+	ReadOnly          *bool `yaml:"read_only"`
+	AutoCommit        *bool
+	AllowDropDatabase *bool `yaml:"allow_drop_database"`
 }
 
 // UserYAMLConfig contains server configuration regarding the user account clients must use to connect
@@ -52,10 +76,53 @@ type UserYAMLConfig struct {
 	Password *string
 }
 
+// MetricsYAMLConfig contains the configuration of the listener that serves the server's Prometheus metrics
+type MetricsYAMLConfig struct {
+	Labels map[string]string `yaml:"labels"`
+	Host   *string           `yaml:"host"`
+	Port   *int              `yaml:"port"`
+}
+
+// HTTPYAMLConfig contains the configuration of the listener that serves the server's HTTP API
+type HTTPYAMLConfig struct {
+	Host *string `yaml:"host"`
+	Port *int    `yaml:"port"`
+}
+
+// SlowQueryLogYAMLConfig contains the configuration of the log of queries that take longer than a threshold to run
+type SlowQueryLogYAMLConfig struct {
+	ThresholdMillis *int    `yaml:"threshold_millis"`
+	File            *string `yaml:"file"`
+	LogPlans        *bool   `yaml:"log_plans"`
+}
+
+// TracingYAMLConfig contains the configuration of query tracing
+type TracingYAMLConfig struct {
+	File *string `yaml:"file"`
+}
+
 // DatabaseYAMLConfig contains information on a database that this server will provide access to
 type DatabaseYAMLConfig struct {
-	Name string
-	Path string
+	Name        string
+	Path        string
+	Replication *ReplicationYAMLConfig `yaml:"replication,omitempty"`
+	ReadReplica *ReadReplicaConfig     `yaml:"read_replica,omitempty"`
+}
+
+// ReplicationYAMLConfig contains the configuration of the replication of a database's branches to one of its remotes
+type ReplicationYAMLConfig struct {
+	Remote string `yaml:"remote"`
+}
+
+// ReadReplicaConfig contains the configuration of a database that follows branches of one of its remotes, which
+// another server pushes to. Read replicas don't accept writes.
+type ReadReplicaConfig struct {
+	Remote string `yaml:"remote"`
+	// Branches are the branches that are pulled. If empty, the checked out branch is pulled.
+	Branches []string `yaml:"branches,omitempty"`
+	// PullIntervalMillis is the time between pulls. If zero, the branches are pulled before each transaction which uses
+	// the database. Otherwise they are only pulled periodically, and transactions read the commit of the last pull.
+	PullIntervalMillis uint64 `yaml:"pull_interval_millis,omitempty"`
 }
 
 // ListenerYAMLConfig contains information on the network connection that the server will open
@@ -65,6 +132,14 @@ type ListenerYAMLConfig struct {
 	MaxConnections     *uint64 `yaml:"max_connections"`
 	ReadTimeoutMillis  *uint64 `yaml:"read_timeout_millis"`
 	WriteTimeoutMillis *uint64 `yaml:"write_timeout_millis"`
+	// TLSKey is a file system path to an unencrypted private TLS key in PEM format.
+	TLSKey *string `yaml:"tls_key"`
+	// TLSCert is a file system path to a TLS certificate chain in PEM format.
+	TLSCert *string `yaml:"tls_cert"`
+	// RequireSecureTransport can enable a mode where non-TLS connections are turned away.
+	RequireSecureTransport *bool `yaml:"require_secure_transport"`
+	// Socket is a file system path to a unix socket that the server listens on, in addition to its host and port.
+	Socket *string `yaml:"socket"`
 }
 
 // PerformanceYAMLConfig contains configuration parameters for performance tweaking
@@ -74,12 +149,22 @@ type PerformanceYAMLConfig struct {
 
 // YAMLConfig is a ServerConfig implementation which is read from a yaml file
 type YAMLConfig struct {
-	LogLevelStr       *string               `yaml:"log_level"`
-	BehaviorConfig    BehaviorYAMLConfig    `yaml:"behavior"`
-	UserConfig        UserYAMLConfig        `yaml:"user"`
-	ListenerConfig    ListenerYAMLConfig    `yaml:"listener"`
-	DatabaseConfig    []DatabaseYAMLConfig  `yaml:"databases"`
-	PerformanceConfig PerformanceYAMLConfig `yaml:"performance"`
+	LogLevelStr       *string                `yaml:"log_level"`
+	BehaviorConfig    BehaviorYAMLConfig     `yaml:"behavior"`
+	UserConfig        UserYAMLConfig         `yaml:"user"`
+	ListenerConfig    ListenerYAMLConfig     `yaml:"listener"`
+	DatabaseConfig    []DatabaseYAMLConfig   `yaml:"databases"`
+	DataDirStr        *string                `yaml:"data_dir"`
+	PerformanceConfig PerformanceYAMLConfig  `yaml:"performance"`
+	MetricsConfig     MetricsYAMLConfig      `yaml:"metrics"`
+	HTTPConfig        HTTPYAMLConfig         `yaml:"http_api"`
+	SlowQueryConfig   SlowQueryLogYAMLConfig `yaml:"slow_query_log"`
+	TracingConfig     TracingYAMLConfig      `yaml:"tracing"`
+	UsersConfig       []dsqle.UserAccount    `yaml:"users"`
+	PrivilegeFile     *string                `yaml:"privilege_file"`
+
+	// filePath is the path of the file that the config was read from, if any, which it's reloaded from
+	filePath string
 }
 
 func newYamlConfig(configFileData []byte) (YAMLConfig, error) {
@@ -91,7 +176,7 @@ func newYamlConfig(configFileData []byte) (YAMLConfig, error) {
 func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 	return YAMLConfig{
 		LogLevelStr:    strPtr(string(cfg.LogLevel())),
-		BehaviorConfig: BehaviorYAMLConfig{boolPtr(cfg.ReadOnly()), boolPtr(cfg.AutoCommit())},
+		BehaviorConfig: BehaviorYAMLConfig{boolPtr(cfg.ReadOnly()), boolPtr(cfg.AutoCommit()), nillableBoolPtr(cfg.AllowDropDatabase())},
 		UserConfig:     UserYAMLConfig{strPtr(cfg.User()), strPtr(cfg.Password())},
 		ListenerConfig: ListenerYAMLConfig{
 			strPtr(cfg.Host()),
@@ -99,8 +184,28 @@ func serverConfigAsYAMLConfig(cfg ServerConfig) YAMLConfig {
 			uint64Ptr(cfg.MaxConnections()),
 			uint64Ptr(cfg.ReadTimeout()),
 			uint64Ptr(cfg.WriteTimeout()),
+			nillableStrPtr(cfg.TLSKey()),
+			nillableStrPtr(cfg.TLSCert()),
+			nillableBoolPtr(cfg.RequireSecureTransport()),
+			nillableStrPtr(cfg.Socket()),
 		},
 		DatabaseConfig: nil,
+		DataDirStr:     nillableStrPtr(cfg.DataDir()),
+		MetricsConfig: MetricsYAMLConfig{
+			Labels: cfg.MetricsLabels(),
+			Host:   nillableStrPtr(cfg.MetricsHost()),
+			Port:   nillableIntPtr(cfg.MetricsPort()),
+		},
+		HTTPConfig: HTTPYAMLConfig{
+			Host: nillableStrPtr(cfg.HTTPHost()),
+			Port: nillableIntPtr(cfg.HTTPPort()),
+		},
+		SlowQueryConfig: SlowQueryLogYAMLConfig{
+			ThresholdMillis: nillableIntPtr(cfg.SlowQueryThreshold()),
+			File:            nillableStrPtr(cfg.SlowQueryLogFile()),
+			LogPlans:        nillableBoolPtr(cfg.SlowQueryLogPlans()),
+		},
+		TracingConfig: TracingYAMLConfig{File: nillableStrPtr(cfg.TraceFile())},
 	}
 }
 
@@ -229,6 +334,58 @@ func (cfg YAMLConfig) DatabaseNamesAndPaths() []env.EnvNameAndPath {
 	return dbNamesAndPaths
 }
 
+// ReplicationRemotes returns the name of the remote that the branches of each replicated database are pushed to, by
+// database name.
+func (cfg YAMLConfig) ReplicationRemotes() map[string]string {
+	var remotes map[string]string
+	for _, dbConfig := range cfg.DatabaseConfig {
+		if dbConfig.Replication == nil {
+			continue
+		}
+		if remotes == nil {
+			remotes = make(map[string]string)
+		}
+		remotes[dbConfig.Name] = dbConfig.Replication.Remote
+	}
+
+	return remotes
+}
+
+// ReadReplicas returns the configuration of each read replica database, by database name.
+func (cfg YAMLConfig) ReadReplicas() map[string]ReadReplicaConfig {
+	var replicas map[string]ReadReplicaConfig
+	for _, dbConfig := range cfg.DatabaseConfig {
+		if dbConfig.ReadReplica == nil {
+			continue
+		}
+		if replicas == nil {
+			replicas = make(map[string]ReadReplicaConfig)
+		}
+		replicas[dbConfig.Name] = *dbConfig.ReadReplica
+	}
+
+	return replicas
+}
+
+// DataDir returns the directory whose subdirectories are loaded as databases, and that CREATE DATABASE and
+// DOLT_CLONE create databases in. If empty, databases can't be created while the server runs.
+func (cfg YAMLConfig) DataDir() string {
+	if cfg.DataDirStr == nil {
+		return ""
+	}
+
+	return *cfg.DataDirStr
+}
+
+// AllowDropDatabase returns whether DROP DATABASE may delete the databases in the data directory.
+func (cfg YAMLConfig) AllowDropDatabase() bool {
+	if cfg.BehaviorConfig.AllowDropDatabase == nil {
+		return defaultAllowDropDb
+	}
+
+	return *cfg.BehaviorConfig.AllowDropDatabase
+}
+
 // MaxConnections returns the maximum number of simultaneous connections the server will allow.  The default is 1
 func (cfg YAMLConfig) MaxConnections() uint64 {
 	if cfg.ListenerConfig.MaxConnections == nil {
@@ -238,6 +395,15 @@ func (cfg YAMLConfig) MaxConnections() uint64 {
 	return *cfg.ListenerConfig.MaxConnections
 }
 
+// Socket returns the path of the unix socket that the server listens on, or "" if there is none.
+func (cfg YAMLConfig) Socket() string {
+	if cfg.ListenerConfig.Socket == nil {
+		return ""
+	}
+
+	return *cfg.ListenerConfig.Socket
+}
+
 // QueryParallelism returns the parallelism that should be used by the go-mysql-server analyzer
 func (cfg YAMLConfig) QueryParallelism() int {
 	if cfg.PerformanceConfig.QueryParallelism == nil {
@@ -246,3 +412,124 @@ func (cfg YAMLConfig) QueryParallelism() int {
 
 	return *cfg.PerformanceConfig.QueryParallelism
 }
+
+// TLSKey returns a path to the servers PEM-encoded private TLS key. "" if there is none.
+func (cfg YAMLConfig) TLSKey() string {
+	if cfg.ListenerConfig.TLSKey == nil {
+		return ""
+	}
+
+	return *cfg.ListenerConfig.TLSKey
+}
+
+// TLSCert returns a path to the servers PEM-encoded TLS certificate chain. "" if there is none.
+func (cfg YAMLConfig) TLSCert() string {
+	if cfg.ListenerConfig.TLSCert == nil {
+		return ""
+	}
+
+	return *cfg.ListenerConfig.TLSCert
+}
+
+// RequireSecureTransport is true if the server should reject non-TLS connections.
+func (cfg YAMLConfig) RequireSecureTransport() bool {
+	if cfg.ListenerConfig.RequireSecureTransport == nil {
+		return false
+	}
+
+	return *cfg.ListenerConfig.RequireSecureTransport
+}
+
+// MetricsLabels returns the labels that are added to every metric of the server.
+func (cfg YAMLConfig) MetricsLabels() map[string]string {
+	return cfg.MetricsConfig.Labels
+}
+
+// MetricsHost returns the host that the server's metrics are served on.
+func (cfg YAMLConfig) MetricsHost() string {
+	if cfg.MetricsConfig.Host == nil {
+		return defaultMetricsHost
+	}
+
+	return *cfg.MetricsConfig.Host
+}
+
+// MetricsPort returns the port that the server's metrics are served on, or -1 if they aren't served.
+func (cfg YAMLConfig) MetricsPort() int {
+	if cfg.MetricsConfig.Port == nil {
+		return defaultMetricsPort
+	}
+
+	return *cfg.MetricsConfig.Port
+}
+
+// HTTPHost returns the host that the server's HTTP API is served on.
+func (cfg YAMLConfig) HTTPHost() string {
+	if cfg.HTTPConfig.Host == nil {
+		return defaultHTTPHost
+	}
+
+	return *cfg.HTTPConfig.Host
+}
+
+// HTTPPort returns the port that the server's HTTP API is served on, or -1 if it isn't served.
+func (cfg YAMLConfig) HTTPPort() int {
+	if cfg.HTTPConfig.Port == nil {
+		return defaultHTTPPort
+	}
+
+	return *cfg.HTTPConfig.Port
+}
+
+// Users returns the user accounts, other than the server user, that clients may connect with.
+func (cfg YAMLConfig) Users() []dsqle.UserAccount {
+	return cfg.UsersConfig
+}
+
+// PrivilegeFilePath returns the path of the file that user accounts created by clients are saved to. If empty, they
+// are lost when the server stops.
+func (cfg YAMLConfig) PrivilegeFilePath() string {
+	if cfg.PrivilegeFile == nil {
+		return ""
+	}
+
+	return *cfg.PrivilegeFile
+}
+
+// SlowQueryThreshold returns the number of milliseconds a query must run for to be written to the slow query log, or
+// -1 if slow queries aren't logged.
+func (cfg YAMLConfig) SlowQueryThreshold() int {
+	if cfg.SlowQueryConfig.ThresholdMillis == nil {
+		return defaultSlowQueryThreshold
+	}
+
+	return *cfg.SlowQueryConfig.ThresholdMillis
+}
+
+// SlowQueryLogFile returns the path of the file that slow queries are written to. If empty, they are written to the
+// server's log.
+func (cfg YAMLConfig) SlowQueryLogFile() string {
+	if cfg.SlowQueryConfig.File == nil {
+		return ""
+	}
+
+	return *cfg.SlowQueryConfig.File
+}
+
+// SlowQueryLogPlans returns whether the slow query log includes the plan of each query.
+func (cfg YAMLConfig) SlowQueryLogPlans() bool {
+	if cfg.SlowQueryConfig.LogPlans == nil {
+		return false
+	}
+
+	return *cfg.SlowQueryConfig.LogPlans
+}
+
+// TraceFile returns the path of the file that the trace of each query is written to. If empty, queries aren't traced.
+func (cfg YAMLConfig) TraceFile() string {
+	if cfg.TracingConfig.File == nil {
+		return ""
+	}
+
+	return *cfg.TracingConfig.File
+}
@@ -111,4 +111,150 @@ func TestYAMLConfigDefaults(t *testing.T) {
 	assert.Equal(t, defaultLogLevel, cfg.LogLevel())
 	assert.Equal(t, defaultAutoCommit, cfg.AutoCommit())
 	assert.Equal(t, uint64(defaultMaxConnections), cfg.MaxConnections())
+	assert.Equal(t, "", cfg.TLSKey())
+	assert.Equal(t, "", cfg.TLSCert())
+	assert.Equal(t, false, cfg.RequireSecureTransport())
+	assert.Equal(t, "", cfg.Socket())
+	assert.Equal(t, defaultSlowQueryThreshold, cfg.SlowQueryThreshold())
+	assert.Equal(t, "", cfg.SlowQueryLogFile())
+	assert.Equal(t, false, cfg.SlowQueryLogPlans())
+	assert.Equal(t, "", cfg.TraceFile())
+}
+
+func TestYAMLConfigTLS(t *testing.T) {
+	cfg, err := newYamlConfig([]byte(`
+listener:
+    tls_key: ./key.pem
+    tls_cert: ./cert.pem
+    require_secure_transport: true
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "./key.pem", cfg.TLSKey())
+	assert.Equal(t, "./cert.pem", cfg.TLSCert())
+	assert.True(t, cfg.RequireSecureTransport())
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.ListenerConfig.TLSCert = nil
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestYAMLConfigSlowQueryLog(t *testing.T) {
+	cfg, err := newYamlConfig([]byte(`
+slow_query_log:
+    threshold_millis: 250
+    file: ./slow.log
+    log_plans: true
+
+tracing:
+    file: ./trace.json
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, 250, cfg.SlowQueryThreshold())
+	assert.Equal(t, "./slow.log", cfg.SlowQueryLogFile())
+	assert.True(t, cfg.SlowQueryLogPlans())
+	assert.Equal(t, "./trace.json", cfg.TraceFile())
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.SlowQueryConfig.ThresholdMillis = nil
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestYAMLConfigReplication(t *testing.T) {
+	cfg, err := newYamlConfig([]byte(`
+databases:
+    - name: primary
+      path: ./primary
+      replication:
+          remote: backup
+    - name: other
+      path: ./other
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"primary": "backup"}, cfg.ReplicationRemotes())
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.DatabaseConfig[0].Replication.Remote = ""
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestYAMLConfigReadReplica(t *testing.T) {
+	cfg, err := newYamlConfig([]byte(`
+databases:
+    - name: replica
+      path: ./replica
+      read_replica:
+          remote: origin
+          branches: [master, release]
+          pull_interval_millis: 500
+    - name: other
+      path: ./other
+`))
+	require.NoError(t, err)
+
+	expected := map[string]ReadReplicaConfig{
+		"replica": {Remote: "origin", Branches: []string{"master", "release"}, PullIntervalMillis: 500},
+	}
+	assert.Equal(t, expected, cfg.ReadReplicas())
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.DatabaseConfig[0].Replication = &ReplicationYAMLConfig{Remote: "origin"}
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.DatabaseConfig[0].Replication = nil
+	cfg.DatabaseConfig[0].ReadReplica.Remote = ""
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestYAMLConfigDataDir(t *testing.T) {
+	cfg, err := newYamlConfig([]byte(`
+data_dir: ./databases
+behavior:
+    allow_drop_database: true
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "./databases", cfg.DataDir())
+	assert.True(t, cfg.AllowDropDatabase())
+	assert.NoError(t, ValidateConfig(cfg))
+
+	cfg.DataDirStr = nil
+	assert.Error(t, ValidateConfig(cfg))
+}
+
+func TestYAMLConfigSocket(t *testing.T) {
+	cfg, err := newYamlConfig([]byte(`
+listener:
+    port: 3307
+    socket: /tmp/dolt.sock
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "/tmp/dolt.sock", cfg.Socket())
+	assert.Equal(t, "root:@unix(/tmp/dolt.sock)/", ConnectionString(cfg))
+
+	cfg.ListenerConfig.Socket = nil
+	assert.Equal(t, "root:@tcp(localhost:3307)/", ConnectionString(cfg))
+}
+
+func TestYAMLConfigHTTPAPI(t *testing.T) {
+	cfg, err := newYamlConfig([]byte(`
+http_api:
+    host: localhost
+    port: 8080
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "localhost", cfg.HTTPHost())
+	assert.Equal(t, 8080, cfg.HTTPPort())
+	assert.NoError(t, ValidateConfig(cfg))
+
+	port := 80
+	cfg.HTTPConfig.Port = &port
+	assert.Error(t, ValidateConfig(cfg))
+
+	cfg.HTTPConfig.Port = nil
+	assert.Equal(t, defaultHTTPPort, cfg.HTTPPort())
 }
@@ -31,25 +31,44 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/xlsx"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/libraries/utils/funcitr"
 	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
 )
 
+const (
+	queryParam = "query"
+	asOfParam  = "as-of"
+)
+
 var exportDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Export the contents of a table to a file.`,
 	LongDesc: `{{.EmphasisLeft}}dolt table export{{.EmphasisRight}} will export the contents of {{.LessThan}}table{{.GreaterThan}} to {{.LessThan}}|file{{.GreaterThan}}
 
 See the help for {{.EmphasisLeft}}dolt table import{{.EmphasisRight}} as the options are the same.
+
+Tables can be exported to csv, psv, json, jsonl, sql, parquet and xlsx files. When no file is given, the table is written to stdout as csv, psv or jsonl. Parquet files are written with a column for each column of the table, using the parquet type that matches the column's type.
+
+Xlsx files are written with a sheet for the table, whose first row is the names of its columns. Numbers, dates, times and booleans are written as cells of those types, and values of every other type as text. More than one table can be exported to an xlsx file, each to its own sheet, by giving several tables before the file. Sheet names are table names cut to 31 characters. The hash of the commit the tables were exported from is recorded in the workbook's document properties.
+
+If {{.EmphasisLeft}}--query{{.EmphasisRight}} is given, the result set of a SQL query is exported instead of a table, and the only argument is the file being exported to. The query is run with {{.EmphasisLeft}}dolt sql{{.EmphasisRight}}'s engine, can't modify the database, and may read any table. The exported file has a column for each column of the result set.
+
+If {{.EmphasisLeft}}--as-of{{.EmphasisRight}} is given, the table is exported as it was at the given commit, which may be a branch, a commit hash, or an ancestor spec like {{.EmphasisLeft}}HEAD~3{{.EmphasisRight}}. The working set is not changed. When given with {{.EmphasisLeft}}--query{{.EmphasisRight}} the query is run against the database as it was at that commit.
 `,
 	Synopsis: []string{
-		"[-f] [-pk {{.LessThan}}field{{.GreaterThan}}] [-schema {{.LessThan}}file{{.GreaterThan}}] [-map {{.LessThan}}file{{.GreaterThan}}] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"[-f] [-pk {{.LessThan}}field{{.GreaterThan}}] [-schema {{.LessThan}}file{{.GreaterThan}}] [-map {{.LessThan}}file{{.GreaterThan}}] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] [--as-of {{.LessThan}}commit{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"[-f] [-continue] [--as-of {{.LessThan}}commit{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}}... {{.LessThan}}file.xlsx{{.GreaterThan}}",
+		"[-f] [-continue] [-file-type {{.LessThan}}type{{.GreaterThan}}] [--as-of {{.LessThan}}commit{{.GreaterThan}}] --query {{.LessThan}}query{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
 	},
 }
 
 type exportOptions struct {
 	tableName   string
+	tableNames  []string
+	query       string
+	asOf        string
 	contOnErr   bool
 	force       bool
 	schFile     string
@@ -58,6 +77,8 @@ type exportOptions struct {
 	src         mvdata.TableDataLocation
 	dest        mvdata.DataLocation
 	srcOptions  interface{}
+	commitHash  string
+	uncommitted bool
 }
 
 func (m exportOptions) checkOverwrite(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS) (bool, error) {
@@ -75,9 +96,18 @@ func (m exportOptions) WritesToTable() bool {
 }
 
 func (m exportOptions) SrcName() string {
+	if m.query != "" {
+		return "query"
+	}
 	return m.src.Name
 }
 
+// SrcCommit returns the hash of the commit that the exported data is read at, and whether it's read from a working
+// set with uncommitted changes on top of that commit.
+func (m exportOptions) SrcCommit() (string, bool) {
+	return m.commitHash, m.uncommitted
+}
+
 func (m exportOptions) DestName() string {
 	if t, tblDest := m.dest.(mvdata.TableDataLocation); tblDest {
 		return t.Name
@@ -89,24 +119,40 @@ func (m exportOptions) DestName() string {
 }
 
 // validateExportArgs validates the input from the arg parser, and returns the tuple:
-// (table name to export, data location of table to export, data location to export to)
-func validateExportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter) (string, mvdata.TableDataLocation, mvdata.DataLocation) {
-	if apr.NArg() == 0 || apr.NArg() > 2 {
+// (table names to export, data location of the first table to export, data location to export to). When a query is
+// being exported there are no table names.
+func validateExportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter) ([]string, mvdata.TableDataLocation, mvdata.DataLocation) {
+	if apr.Contains(queryParam) {
+		if apr.NArg() > 1 {
+			usage()
+			return nil, mvdata.TableDataLocation{}, nil
+		}
+	} else if apr.NArg() == 0 {
 		usage()
-		return "", mvdata.TableDataLocation{}, nil
-	}
-
-	tableName := apr.Arg(0)
-	if !doltdb.IsValidTableName(tableName) {
-		cli.PrintErrln(
-			color.RedString("'%s' is not a valid table name\n", tableName),
-			"table names must match the regular expression:", doltdb.TableNameRegexStr)
-		return "", mvdata.TableDataLocation{}, nil
+		return nil, mvdata.TableDataLocation{}, nil
 	}
 
+	var tableNames []string
 	path := ""
-	if apr.NArg() > 1 {
-		path = apr.Arg(1)
+	if apr.Contains(queryParam) {
+		if apr.NArg() > 0 {
+			path = apr.Arg(0)
+		}
+	} else {
+		tableNames = apr.Args()[:1]
+		if apr.NArg() > 1 {
+			tableNames = apr.Args()[:apr.NArg()-1]
+			path = apr.Arg(apr.NArg() - 1)
+		}
+
+		for _, tableName := range tableNames {
+			if !doltdb.IsValidTableName(tableName) {
+				cli.PrintErrln(
+					color.RedString("'%s' is not a valid table name\n", tableName),
+					"table names must match the regular expression:", doltdb.TableNameRegexStr)
+				return nil, mvdata.TableDataLocation{}, nil
+			}
+		}
 	}
 
 	fType, _ := apr.GetValue(fileTypeParam)
@@ -118,30 +164,43 @@ func validateExportArgs(apr *argparser.ArgParseResults, usage cli.UsagePrinter)
 			cli.PrintErrln(
 				color.RedString("Could not infer type file '%s'\n", path),
 				"File extensions should match supported file types, or should be explicitly defined via the file-type parameter")
-			return "", mvdata.TableDataLocation{}, nil
+			return nil, mvdata.TableDataLocation{}, nil
+		} else if val.Format == mvdata.SqlFile && apr.Contains(queryParam) {
+			cli.PrintErrln(color.RedString("The results of a query cannot be exported to a sql file"))
+			return nil, mvdata.TableDataLocation{}, nil
+		} else if val.Format != mvdata.XlsxFile && len(tableNames) > 1 {
+			cli.PrintErrln(color.RedString("Only xlsx files can have more than one table exported to them"))
+			return nil, mvdata.TableDataLocation{}, nil
 		}
 
 	case mvdata.StreamDataLocation:
 		if val.Format == mvdata.InvalidDataFormat {
 			val = mvdata.StreamDataLocation{Format: mvdata.CsvFile, Reader: os.Stdin, Writer: iohelp.NopWrCloser(cli.CliOut)}
 			destLoc = val
-		} else if val.Format != mvdata.CsvFile && val.Format != mvdata.PsvFile {
+		} else if val.Format != mvdata.CsvFile && val.Format != mvdata.PsvFile && val.Format != mvdata.JsonlFile {
 			cli.PrintErrln(color.RedString("Cannot export this format to stdout"))
-			return "", mvdata.TableDataLocation{}, nil
+			return nil, mvdata.TableDataLocation{}, nil
+		} else if len(tableNames) > 1 {
+			usage()
+			return nil, mvdata.TableDataLocation{}, nil
 		}
 	}
 
-	tableLoc := mvdata.TableDataLocation{Name: tableName}
+	tableLoc := mvdata.TableDataLocation{}
+	if len(tableNames) > 0 {
+		tableLoc.Name = tableNames[0]
+	}
 
-	return tableName, tableLoc, destLoc
+	return tableNames, tableLoc, destLoc
 }
 
 func parseExportArgs(ap *argparser.ArgParser, commandStr string, args []string) (*exportOptions, errhand.VerboseError) {
 	help, usage := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, exportDocs, ap))
 	apr := cli.ParseArgs(ap, args, help)
-	tableName, tableLoc, fileLoc := validateExportArgs(apr, usage)
+	tableNames, tableLoc, fileLoc := validateExportArgs(apr, usage)
 
-	if fileLoc == nil || len(tableLoc.Name) == 0 {
+	query, _ := apr.GetValue(queryParam)
+	if fileLoc == nil || (len(tableLoc.Name) == 0 && query == "") {
 		return nil, errhand.BuildDError("could not validate table export args").Build()
 	}
 
@@ -153,7 +212,10 @@ func parseExportArgs(ap *argparser.ArgParser, commandStr string, args []string)
 	pks = funcitr.FilterStrings(pks, func(s string) bool { return s != "" })
 
 	return &exportOptions{
-		tableName:   tableName,
+		tableName:   tableLoc.Name,
+		tableNames:  tableNames,
+		query:       query,
+		asOf:        apr.GetValueOrDefault(asOfParam, ""),
 		contOnErr:   apr.Contains(contOnErrParam),
 		force:       apr.Contains(forceParam),
 		schFile:     schemaFile,
@@ -184,7 +246,7 @@ func (cmd ExportCmd) CreateMarkdown(fs filesys.Filesys, path, commandStr string)
 
 func (cmd ExportCmd) createArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParser()
-	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"table", "The table being exported."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"table", "The table being exported. Several tables can be exported to an xlsx file."})
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"file", "The file being output to."})
 	ap.SupportsFlag(forceParam, "f", "If data already exists in the destination, the force flag will allow the target to be overwritten.")
 	ap.SupportsFlag(contOnErrParam, "", "Continue exporting when row export errors are encountered.")
@@ -192,6 +254,8 @@ func (cmd ExportCmd) createArgParser() *argparser.ArgParser {
 	ap.SupportsString(mappingFileParam, "m", "mapping_file", "A file that lays out how fields should be mapped from input data to output data.")
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
+	ap.SupportsString(queryParam, "q", "query", "Export the result set of a SQL query instead of a table.")
+	ap.SupportsString(asOfParam, "", "commit", "Export the table as it was at the given commit.")
 	return ap
 }
 
@@ -215,13 +279,41 @@ func (cmd ExportCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
 
-	mover, verr := NewExportDataMover(ctx, root, dEnv, exOpts, importStatsCB)
+	var cm *doltdb.Commit
+	if exOpts.asOf != "" {
+		cm, verr = commands.ResolveCommitWithVErr(dEnv, exOpts.asOf)
+		if verr != nil {
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+
+		var err error
+		root, err = cm.GetRootValue()
+		if err != nil {
+			verr = errhand.BuildDError("Unable to read the root value of commit '%s'.", exOpts.asOf).AddCause(err).Build()
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+	}
 
-	if verr != nil {
-		return commands.HandleVErrAndExitCode(verr, usage)
+	if fileLoc, ok := exOpts.dest.(mvdata.FileDataLocation); ok && fileLoc.Format == mvdata.XlsxFile {
+		verr = setExportCommit(ctx, dEnv, root, cm, exOpts)
+		if verr != nil {
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
 	}
 
-	skipped, verr := mvdata.MoveData(ctx, dEnv, mover, exOpts)
+	var skipped int64
+	if len(exOpts.tableNames) > 1 {
+		skipped, verr = exportTablesToWorkbook(ctx, root, dEnv, exOpts)
+	} else {
+		var mover *mvdata.DataMover
+		mover, verr = NewExportDataMover(ctx, root, dEnv, exOpts, importStatsCB)
+
+		if verr != nil {
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+
+		skipped, verr = mvdata.MoveData(ctx, dEnv, mover, exOpts)
+	}
 
 	if skipped > 0 {
 		cli.PrintErrln(color.YellowString("Lines skipped: %d", skipped))
@@ -234,6 +326,107 @@ func (cmd ExportCmd) Exec(ctx context.Context, commandStr string, args []string,
 	return 0
 }
 
+// setExportCommit records the commit that |root| is exported from in |exOpts|. |cm| is the commit given with --as-of,
+// or nil when the working set is exported, in which case the working set is exported from HEAD.
+func setExportCommit(ctx context.Context, dEnv *env.DoltEnv, root *doltdb.RootValue, cm *doltdb.Commit, exOpts *exportOptions) errhand.VerboseError {
+	if cm == nil {
+		var verr errhand.VerboseError
+		cm, verr = commands.ResolveCommitWithVErr(dEnv, "HEAD")
+		if verr != nil {
+			return verr
+		}
+
+		headRoot, err := cm.GetRootValue()
+		if err != nil {
+			return errhand.BuildDError("Unable to read the root value of HEAD.").AddCause(err).Build()
+		}
+
+		headHash, err := headRoot.HashOf()
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+
+		rootHash, err := root.HashOf()
+		if err != nil {
+			return errhand.VerboseErrorFromError(err)
+		}
+
+		exOpts.uncommitted = headHash != rootHash
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return errhand.VerboseErrorFromError(err)
+	}
+	exOpts.commitHash = h.String()
+
+	return nil
+}
+
+// exportTablesToWorkbook exports each of the tables of |exOpts| to its own sheet of the xlsx workbook being exported
+// to, and returns the number of rows skipped.
+func exportTablesToWorkbook(ctx context.Context, root *doltdb.RootValue, dEnv *env.DoltEnv, exOpts *exportOptions) (int64, errhand.VerboseError) {
+	ow, err := exOpts.checkOverwrite(ctx, root, dEnv.FS)
+	if err != nil {
+		return 0, errhand.VerboseErrorFromError(err)
+	}
+	if ow {
+		return 0, errhand.BuildDError("%s already exists. Use -f to overwrite.", exOpts.DestName()).Build()
+	}
+
+	// check every table exists before creating the workbook, so it isn't left with some of the tables
+	for _, tableName := range exOpts.tableNames {
+		ok, err := root.HasTable(ctx, tableName)
+		if err != nil {
+			return 0, errhand.BuildDError("Error creating reader for %s.", tableName).AddCause(err).Build()
+		}
+		if !ok {
+			return 0, errhand.BuildDError("Error creating reader for %s.", tableName).AddCause(doltdb.ErrTableNotFound).Build()
+		}
+	}
+
+	wb, err := xlsx.OpenXLSXWorkbook(exOpts.DestName(), dEnv.FS)
+	if err != nil {
+		return 0, errhand.BuildDError("Could not create %s.", exOpts.DestName()).AddCause(err).Build()
+	}
+	wb.SetProperties(mvdata.XlsxDocProperties(exOpts))
+
+	var skipped int64
+	for _, tableName := range exOpts.tableNames {
+		tblOpts := *exOpts
+		tblOpts.tableName = tableName
+		tblOpts.src = mvdata.TableDataLocation{Name: tableName}
+
+		rd, _, err := tblOpts.src.NewReader(ctx, root, dEnv.FS, nil)
+		if err != nil {
+			_ = wb.Close(ctx)
+			return skipped, errhand.BuildDError("Error creating reader for %s.", tableName).AddCause(err).Build()
+		}
+
+		wr, err := wb.NewSheetWriter(tableName, rd.GetSchema())
+		if err != nil {
+			_ = rd.Close(ctx)
+			_ = wb.Close(ctx)
+			return skipped, errhand.BuildDError("Could not create table writer for %s", tableName).AddCause(err).Build()
+		}
+
+		mover := &mvdata.DataMover{Rd: rd, Transforms: pipeline.NewTransformCollection(), Wr: wr, ContOnErr: exOpts.contOnErr}
+		tblSkipped, verr := mvdata.MoveData(ctx, dEnv, mover, tblOpts)
+		skipped += tblSkipped
+		if verr != nil {
+			_ = wb.Close(ctx)
+			return skipped, verr
+		}
+	}
+
+	err = wb.Close(ctx)
+	if err != nil {
+		return skipped, errhand.BuildDError("Failed to write %s.", exOpts.DestName()).AddCause(err).Build()
+	}
+
+	return skipped, nil
+}
+
 func NewExportDataMover(ctx context.Context, root *doltdb.RootValue, dEnv *env.DoltEnv, exOpts *exportOptions, statsCB noms.StatsCB) (*mvdata.DataMover, errhand.VerboseError) {
 	var rd table.TableReadCloser
 	var err error
@@ -246,7 +439,20 @@ func NewExportDataMover(ctx context.Context, root *doltdb.RootValue, dEnv *env.D
 		return nil, errhand.BuildDError("%s already exists. Use -f to overwrite.", exOpts.DestName()).Build()
 	}
 
-	rd, srcIsSorted, err := exOpts.src.NewReader(ctx, root, dEnv.FS, exOpts.srcOptions)
+	var srcIsSorted bool
+	if exOpts.query != "" {
+		sqlCtx, sqlSch, rowIter, verr := commands.QueryRoot(ctx, dEnv, root, exOpts.query)
+		if verr != nil {
+			return nil, verr
+		}
+
+		rd, err = mvdata.NewQueryResultReader(sqlCtx, root.VRW(), sqlSch, rowIter)
+		if err != nil {
+			_ = rowIter.Close(sqlCtx)
+		}
+	} else {
+		rd, srcIsSorted, err = exOpts.src.NewReader(ctx, root, dEnv.FS, exOpts.srcOptions)
+	}
 
 	if err != nil {
 		return nil, errhand.BuildDError("Error creating reader for %s.", exOpts.SrcName()).AddCause(err).Build()
@@ -19,8 +19,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"runtime"
 	"strings"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/fatih/color"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
@@ -30,10 +33,12 @@ import (
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/mvdata"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
@@ -55,17 +60,24 @@ const (
 	primaryKeyParam  = "pk"
 	fileTypeParam    = "file-type"
 	delimParam       = "delim"
+	sourceTableParam = "source-table"
+	badRowsParam     = "bad-rows"
+	parallelParam    = "parallel"
+	sampleRowsParam  = "sample-rows"
+	dateLayoutsParam = "date-layouts"
 )
 
 var importDocs = cli.CommandDocumentationContent{
 	ShortDesc: `Imports data into a dolt table`,
 	LongDesc: `If {{.EmphasisLeft}}--create-table | -c{{.EmphasisRight}} is given the operation will create {{.LessThan}}table{{.GreaterThan}} and import the contents of file into it.  If a table already exists at this location then the operation will fail, unless the {{.EmphasisLeft}}--force | -f{{.EmphasisRight}} flag is provided. The force flag forces the existing table to be overwritten.
 
-The schema for the new table can be specified explicitly by providing a SQL schema definition file, or will be inferred from the imported file.  All schemas, inferred or explicitly defined must define a primary key.  If the file format being imported does not support defining a primary key, then the {{.EmphasisLeft}}--pk{{.EmphasisRight}} parameter must supply the name of the field that should be used as the primary key.
+The schema for the new table can be specified explicitly by providing a SQL schema definition file, or will be inferred from the imported file.  Column types are inferred from all of the rows of the file, or from its first rows when {{.EmphasisLeft}}--sample-rows{{.EmphasisRight}} is given, in which case rows after the sample which don't fit the inferred types fail to import.  The column types of parquet files are read from the file's schema.  All schemas, inferred or explicitly defined must define a primary key.  If the file format being imported does not support defining a primary key, then the {{.EmphasisLeft}}--pk{{.EmphasisRight}} parameter must supply the name of the field that should be used as the primary key.
 
 If {{.EmphasisLeft}}--update-table | -u{{.EmphasisRight}} is given the operation will update {{.LessThan}}table{{.GreaterThan}} with the contents of file. The table's existing schema will be used, and field names will be used to match file fields with table fields unless a mapping file is specified.
 
-During import, if there is an error importing any row, the import will be aborted by default.  Use the {{.EmphasisLeft}}--continue{{.EmphasisRight}} flag to continue importing when an error is encountered.
+During import, if there is an error importing any row, the import will be aborted by default.  Use the {{.EmphasisLeft}}--continue{{.EmphasisRight}} flag to continue importing when an error is encountered.  When the import completes, the number of rows that were skipped is reported for each class of error: type conversion, null in a non-null column, duplicate key, foreign key violation, constraint failure and malformed input.  With {{.EmphasisLeft}}--bad-rows{{.EmphasisRight}}, each skipped record is also written unchanged to a .csv or .jsonl file, along with the line of the input it was read from and its error.
+
+When creating a table, {{.EmphasisLeft}}--parallel{{.EmphasisRight}} converts the imported rows on every core, and sorts them into runs as they're converted. Once all of the rows are read the runs are merged and streamed into the new table, and the throughput and memory use of the import are reported. The sorted runs are held in memory until the import completes. Rows with duplicate primary keys are found when the runs are merged, and one of the rows with each key is imported. The others are skipped with {{.EmphasisLeft}}--continue{{.EmphasisRight}}, and written to the {{.EmphasisLeft}}--bad-rows{{.EmphasisRight}} file with their lines like any other bad row. Tables without a primary key are imported without --parallel.
 
 If {{.EmphasisLeft}}--replace-table | -r{{.EmphasisRight}} is given the operation will replace {{.LessThan}}table{{.GreaterThan}} with the contents of the file. The table's existing schema will be used, and field names will be used to match file fields with table fields unless a mapping file is specified.
 
@@ -73,15 +85,30 @@ If the schema for the existing table does not match the schema for the new file,
 
 A mapping file can be used to map fields between the file being imported and the table being written to. This can be used when creating a new table, or updating or replacing an existing table.
 
-` + schcmds.MappingFileHelp +
+` + schcmds.MappingFileHelp + `
+The mapping file of a table import can also compute fields with SQL expressions, and skip rows which don't match a filter. A value beginning with {{.EmphasisLeft}}={{.EmphasisRight}} is an expression which computes the field named by its key, and the value of the {{.EmphasisLeft}}$where{{.EmphasisRight}} key is an expression that rows must match to be imported. Expressions reference the fields of the file being imported by their names in the file, and may call any of the SQL functions supported by {{.EmphasisLeft}}dolt sql{{.EmphasisRight}}. For example:
+
+	{
+		"first_name": "=SUBSTRING_INDEX(full_name, ' ', 1)",
+		"id": "=SHA1(CONCAT(email, signup_date))",
+		"country": "='US'",
+		"$where": "age >= 18"
+	}
+
+When a table is created the types of the computed fields are the types of their expressions.
+
+Dates, times and datetimes are read in the formats understood by SQL and in the same common layouts that {{.EmphasisLeft}}dolt schema import{{.EmphasisRight}} recognizes, such as 1/2/2006 and 2006-01-02T15:04:05, so that a table whose schema was imported from a file can be loaded from it.  {{.EmphasisLeft}}--date-layouts{{.EmphasisRight}} replaces the common layouts with a comma separated list of layouts, which are written as the reference time Mon Jan 2 15:04:05 2006 would be formatted.
+
+In create, update, and replace scenarios the file's extension is used to infer the type of the file.  If a file does not have the expected extension then the {{.EmphasisLeft}}--file-type{{.EmphasisRight}} parameter should be used to explicitly define the format of the file in one of the supported formats (csv, psv, json, jsonl, xlsx, parquet, sqlite).  For files separated by a delimiter other than a ',' (type csv) or a '|' (type psv), the --delim parameter can be used to specify a delimeter
+
+Newline delimited json files (with the extension .jsonl or .ndjson) hold a json object for each row on its own line, and are read one line at a time.  Nested objects and arrays can be imported into string and json columns.  When no file is given, rows are read from stdin in the format given by {{.EmphasisLeft}}--file-type{{.EmphasisRight}}, which may be csv, psv or jsonl.
 
-		`
-In create, update, and replace scenarios the file's extension is used to infer the type of the file.  If a file does not have the expected extension then the {{.EmphasisLeft}}--file-type{{.EmphasisRight}} parameter should be used to explicitly define the format of the file in one of the supported formats (csv, psv, json, xlsx).  For files separated by a delimiter other than a ',' (type csv) or a '|' (type psv), the --delim parameter can be used to specify a delimeter`,
+SQLite databases (with the extension .sqlite, .sqlite3 or .db) are imported one table at a time. The table of the database that is imported is given by {{.EmphasisLeft}}--source-table{{.EmphasisRight}}, and defaults to {{.LessThan}}table{{.GreaterThan}}. When a table is created from a SQLite table it keeps the SQLite table's primary key and indexes, on the columns as renamed by any mapping file, and its column types follow the type affinities of the SQLite columns. Use {{.EmphasisLeft}}dolt import-sqlite{{.EmphasisRight}} to import every table of a SQLite database.`,
 
 	Synopsis: []string{
-		"-c [-f] [--pk {{.LessThan}}field{{.GreaterThan}}] [--schema {{.LessThan}}file{{.GreaterThan}}] [--map {{.LessThan}}file{{.GreaterThan}}] [--continue] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
-		"-u [--map {{.LessThan}}file{{.GreaterThan}}] [--continue] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
-		"-r [--map {{.LessThan}}file{{.GreaterThan}}] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"-c [-f] [--parallel] [--sample-rows {{.LessThan}}n{{.GreaterThan}}] [--pk {{.LessThan}}field{{.GreaterThan}}] [--schema {{.LessThan}}file{{.GreaterThan}}] [--map {{.LessThan}}file{{.GreaterThan}}] [--continue [--bad-rows {{.LessThan}}file{{.GreaterThan}}]] [--date-layouts {{.LessThan}}layouts{{.GreaterThan}}] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"-u [--map {{.LessThan}}file{{.GreaterThan}}] [--continue [--bad-rows {{.LessThan}}file{{.GreaterThan}}]] [--date-layouts {{.LessThan}}layouts{{.GreaterThan}}] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
+		"-r [--map {{.LessThan}}file{{.GreaterThan}}] [--date-layouts {{.LessThan}}layouts{{.GreaterThan}}] [--file-type {{.LessThan}}type{{.GreaterThan}}] {{.LessThan}}table{{.GreaterThan}} {{.LessThan}}file{{.GreaterThan}}",
 	},
 }
 
@@ -98,10 +125,15 @@ type importOptions struct {
 	operation   tableImportOp
 	tableName   string
 	contOnErr   bool
+	badRowsFile string
+	workers     int
+	sampleRows  int
+	dateLayouts []string
 	force       bool
 	schFile     string
 	primaryKeys []string
 	nameMapper  rowconv.NameMapper
+	exprMapping rowconv.ExprMapping
 	src         mvdata.DataLocation
 	dest        mvdata.TableDataLocation
 	srcOptions  interface{}
@@ -133,6 +165,14 @@ func (m importOptions) FloatThreshold() float64 {
 	return 0.0
 }
 
+func (m importOptions) SampleRows() int {
+	return m.sampleRows
+}
+
+func (m importOptions) DateLayouts() []string {
+	return m.dateLayouts
+}
+
 func (m importOptions) checkOverwrite(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS) (bool, error) {
 	if !m.force && m.operation == CreateOp {
 		return m.dest.Exists(ctx, root, fs)
@@ -145,6 +185,16 @@ func (m importOptions) srcIsJson() bool {
 	return isJson
 }
 
+func (m importOptions) srcIsParquet() bool {
+	fileLoc, isFile := m.src.(mvdata.FileDataLocation)
+	return isFile && fileLoc.Format == mvdata.ParquetFile
+}
+
+func (m importOptions) srcIsSqlite() bool {
+	fileLoc, isFile := m.src.(mvdata.FileDataLocation)
+	return isFile && fileLoc.Format == mvdata.SqliteFile
+}
+
 func (m importOptions) srcIsStream() bool {
 	_, isStream := m.src.(mvdata.StreamDataLocation)
 	return isStream
@@ -166,6 +216,11 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 	force := apr.Contains(forceParam)
 	contOnErr := apr.Contains(contOnErrParam)
 
+	var workers int
+	if apr.Contains(parallelParam) {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
 	val, _ := apr.GetValue(primaryKeyParam)
 	pks := funcitr.MapStrings(strings.Split(val, ","), strings.TrimSpace)
 	pks = funcitr.FilterStrings(pks, func(s string) bool { return s != "" })
@@ -175,6 +230,13 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 	if err != nil {
 		return nil, errhand.VerboseErrorFromError(err)
 	}
+	colMapper, exprMapping := rowconv.SplitExprMapping(colMapper)
+
+	dateLayouts := actions.CommonDateLayouts
+	if layoutsStr, ok := apr.GetValue(dateLayoutsParam); ok {
+		dateLayouts = funcitr.MapStrings(strings.Split(layoutsStr, ","), strings.TrimSpace)
+		dateLayouts = funcitr.FilterStrings(dateLayouts, func(s string) bool { return s != "" })
+	}
 
 	var srcOpts interface{}
 	switch val := srcLoc.(type) {
@@ -191,8 +253,10 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 		if val.Format == mvdata.XlsxFile {
 			// table name must match sheet name currently
 			srcOpts = mvdata.XlsxOptions{SheetName: tableName}
-		} else if val.Format == mvdata.JsonFile {
+		} else if val.Format == mvdata.JsonFile || val.Format == mvdata.JsonlFile {
 			srcOpts = mvdata.JSONOptions{TableName: tableName, SchFile: schemaFile}
+		} else if val.Format == mvdata.SqliteFile {
+			srcOpts = mvdata.SqliteOptions{TableName: apr.GetValueOrDefault(sourceTableParam, tableName)}
 		}
 
 	case mvdata.StreamDataLocation:
@@ -203,6 +267,8 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 
 		if hasDelim {
 			srcOpts = mvdata.CsvOptions{Delim: delim}
+		} else if val.Format == mvdata.JsonlFile {
+			srcOpts = mvdata.JSONOptions{TableName: tableName, SchFile: schemaFile}
 		}
 	}
 
@@ -236,9 +302,14 @@ func getImportMoveOptions(ctx context.Context, apr *argparser.ArgParseResults, d
 		operation:   moveOp,
 		tableName:   tableName,
 		contOnErr:   contOnErr,
+		badRowsFile: apr.GetValueOrDefault(badRowsParam, ""),
+		workers:     workers,
+		sampleRows:  apr.GetIntOrDefault(sampleRowsParam, 0),
+		dateLayouts: dateLayouts,
 		force:       force,
 		schFile:     schemaFile,
 		nameMapper:  colMapper,
+		exprMapping: exprMapping,
 		primaryKeys: pks,
 		src:         srcLoc,
 		dest:        tableLoc,
@@ -264,6 +335,28 @@ func validateImportArgs(apr *argparser.ArgParseResults) errhand.VerboseError {
 		return errhand.BuildDError("fatal: " + schemaParam + " is not supported for update or replace operations").Build()
 	}
 
+	if apr.Contains(parallelParam) && !apr.Contains(createParam) {
+		return errhand.BuildDError("fatal: %s is only supported when creating a table", parallelParam).Build()
+	}
+
+	if sampleRows, ok := apr.GetInt(sampleRowsParam); ok {
+		if !apr.Contains(createParam) || apr.Contains(schemaParam) {
+			return errhand.BuildDError("fatal: %s is only supported when creating a table with an inferred schema", sampleRowsParam).Build()
+		} else if sampleRows <= 0 {
+			return errhand.BuildDError("fatal: %s must be a positive number of rows", sampleRowsParam).Build()
+		}
+	}
+
+	if badRowsFile, ok := apr.GetValue(badRowsParam); ok {
+		if !apr.Contains(contOnErrParam) {
+			return errhand.BuildDError("fatal: %s can only be used with %s", badRowsParam, contOnErrParam).Build()
+		}
+
+		if badRowsLoc, ok := mvdata.NewDataLocation(badRowsFile, "").(mvdata.FileDataLocation); !ok || (badRowsLoc.Format != mvdata.CsvFile && badRowsLoc.Format != mvdata.JsonlFile) {
+			return errhand.BuildDError("fatal: the %s file '%s' must be a .csv or .jsonl file", badRowsParam, badRowsFile).Build()
+		}
+	}
+
 	tableName := apr.Arg(0)
 	if err := schcmds.ValidateTableNameForCreate(tableName); err != nil {
 		return err
@@ -305,9 +398,28 @@ func validateImportArgs(apr *argparser.ArgParseResults) errhand.VerboseError {
 		}
 	}
 
+	if isJsonl(srcLoc) && apr.Contains(createParam) && !apr.Contains(schemaParam) {
+		return errhand.BuildDError("Please specify schema file for .jsonl tables.").Build()
+	}
+
+	if srcFileLoc, isFileType := srcLoc.(mvdata.FileDataLocation); apr.Contains(sourceTableParam) && (!isFileType || srcFileLoc.Format != mvdata.SqliteFile) {
+		return errhand.BuildDError("%s is only supported when importing from a sqlite database", sourceTableParam).Build()
+	}
+
 	return nil
 }
 
+// isJsonl returns whether |loc| is a newline delimited json file or stream
+func isJsonl(loc mvdata.DataLocation) bool {
+	switch val := loc.(type) {
+	case mvdata.FileDataLocation:
+		return val.Format == mvdata.JsonlFile
+	case mvdata.StreamDataLocation:
+		return val.Format == mvdata.JsonlFile
+	}
+	return false
+}
+
 type ImportCmd struct{}
 
 // Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
@@ -369,7 +481,14 @@ func (cmd ImportCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return commands.HandleVErrAndExitCode(verr, usage)
 	}
 
-	mover, nDMErr := newImportDataMover(ctx, root, dEnv, mvOpts, importStatsCB)
+	statsCB := importStatsCB
+	var progress *bulkImportProgress
+	if mvOpts.workers > 0 {
+		progress = newBulkImportProgress()
+		statsCB = progress.statsCB
+	}
+
+	mover, nDMErr := newImportDataMover(ctx, root, dEnv, mvOpts, statsCB)
 
 	if nDMErr != nil {
 
@@ -379,8 +498,13 @@ func (cmd ImportCmd) Exec(ctx context.Context, commandStr string, args []string,
 
 	skipped, verr := mvdata.MoveData(ctx, dEnv, mover, mvOpts)
 
+	if progress != nil && verr == nil {
+		progress.printSummary()
+	}
+
 	if skipped > 0 {
 		cli.PrintErrln(color.YellowString("Lines skipped: %d", skipped))
+		printBadRowReport(mover.BadRows, mvOpts.badRowsFile)
 	}
 	if verr == nil {
 		cli.PrintErrln(color.CyanString("Import completed successfully."))
@@ -398,11 +522,16 @@ func createArgParser() *argparser.ArgParser {
 	ap.SupportsFlag(forceParam, "f", "If a create operation is being executed, data already exists in the destination, the force flag will allow the target to be overwritten.")
 	ap.SupportsFlag(replaceParam, "r", "Replace existing table with imported data while preserving the original schema.")
 	ap.SupportsFlag(contOnErrParam, "", "Continue importing when row import errors are encountered.")
+	ap.SupportsString(badRowsParam, "", "file", "Write the records that fail to import to a .csv or .jsonl file, along with their line numbers and errors. Requires --continue.")
+	ap.SupportsFlag(parallelParam, "", "Convert the rows of a new table on every core, and build the table from sorted runs of rows. Only supported with -c.")
+	ap.SupportsInt(sampleRowsParam, "", "n", "Infer the column types of a new table from the first {{.LessThan}}n{{.GreaterThan}} rows of the file, rather than from all of its rows.")
+	ap.SupportsString(dateLayoutsParam, "", "layouts", "Comma separated layouts of the dates, times and datetimes in the {{.LessThan}}file{{.GreaterThan}} which aren't in a format understood by SQL.")
 	ap.SupportsString(schemaParam, "s", "schema_file", "The schema for the output data.")
 	ap.SupportsString(mappingFileParam, "m", "mapping_file", "A file that lays out how fields should be mapped from input data to output data.")
 	ap.SupportsString(primaryKeyParam, "pk", "primary_key", "Explicitly define the name of the field in the schema which should be used as the primary key.")
 	ap.SupportsString(fileTypeParam, "", "file_type", "Explicitly define the type of the file if it can't be inferred from the file extension.")
 	ap.SupportsString(delimParam, "", "delimiter", "Specify a delimeter for a csv style file with a non-comma delimiter.")
+	ap.SupportsString(sourceTableParam, "", "source_table", "The table of a sqlite database to import. Defaults to the name of the table being imported to.")
 	return ap
 }
 
@@ -415,6 +544,59 @@ func importStatsCB(stats types.AppliedEditStats) {
 	displayStrLen = cli.DeleteAndPrint(displayStrLen, displayStr)
 }
 
+// bulkImportProgress displays the progress of a parallel import, along with its throughput and memory use
+type bulkImportProgress struct {
+	start    time.Time
+	rows     int64
+	peakHeap uint64
+}
+
+func newBulkImportProgress() *bulkImportProgress {
+	return &bulkImportProgress{start: time.Now()}
+}
+
+func (p *bulkImportProgress) statsCB(stats types.AppliedEditStats) {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+	if memStats.HeapAlloc > p.peakHeap {
+		p.peakHeap = memStats.HeapAlloc
+	}
+
+	p.rows = int64(stats.Additions)
+	displayStr := fmt.Sprintf("Rows Processed: %d, Rows/s: %.0f, Heap: %s", p.rows, p.rowsPerSec(), humanize.Bytes(memStats.HeapAlloc))
+	displayStrLen = cli.DeleteAndPrint(displayStrLen, displayStr)
+}
+
+func (p *bulkImportProgress) rowsPerSec() float64 {
+	secs := time.Since(p.start).Seconds()
+	if secs <= 0 {
+		return 0
+	}
+	return float64(p.rows) / secs
+}
+
+func (p *bulkImportProgress) printSummary() {
+	cli.PrintErrln()
+	cli.PrintErrln(fmt.Sprintf("Imported %d rows in %s (%.0f rows/s), peak heap use %s", p.rows, time.Since(p.start).Round(time.Millisecond), p.rowsPerSec(), humanize.Bytes(p.peakHeap)))
+}
+
+// printBadRowReport prints the number of rows of |report| that failed to import with each class of error
+func printBadRowReport(report *mvdata.BadRowReport, badRowsFile string) {
+	if report == nil {
+		return
+	}
+
+	for _, class := range mvdata.BadRowClasses {
+		if count := report.Counts[class]; count > 0 {
+			cli.PrintErrln(color.YellowString("    %s: %d", class, count))
+		}
+	}
+
+	if badRowsFile != "" {
+		cli.PrintErrln(color.YellowString("The rows that failed to import were written to %s", badRowsFile))
+	}
+}
+
 func newImportDataMover(ctx context.Context, root *doltdb.RootValue, dEnv *env.DoltEnv, impOpts *importOptions, statsCB noms.StatsCB) (*mvdata.DataMover, *mvdata.DataMoverCreationError) {
 	var err error
 
@@ -442,9 +624,22 @@ func newImportDataMover(ctx context.Context, root *doltdb.RootValue, dEnv *env.D
 		}
 	}()
 
+	transforms := pipeline.NewTransformCollection()
+	mapSrcSch := rd.GetSchema()
+	if !impOpts.exprMapping.IsEmpty() {
+		exprTransform, err := mvdata.NewExprMappingTransform(ctx, root.VRW(), rd.GetSchema(), impOpts.nameMapper, impOpts.exprMapping)
+		if err != nil {
+			return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.MappingErr, Cause: err}
+		}
+
+		// the computed fields are mapped to the output schema along with the fields of the source
+		transforms.AppendTransforms(pipeline.NewNamedTransform("Mapping expressions", exprTransform.TransformRow))
+		mapSrcSch = exprTransform.OutSch
+	}
+
 	err = wrSch.GetPKCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		preImage := impOpts.nameMapper.PreImage(col.Name)
-		_, found := rd.GetSchema().GetAllCols().GetByName(preImage)
+		_, found := mapSrcSch.GetAllCols().GetByName(preImage)
 		if !found {
 			err = fmt.Errorf("input primary keys do not match primary keys of existing table")
 		}
@@ -455,16 +650,25 @@ func newImportDataMover(ctx context.Context, root *doltdb.RootValue, dEnv *env.D
 		return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.SchemaErr, Cause: err}
 	}
 
-	transforms, err := mvdata.NameMapTransform(ctx, root.VRW(), rd.GetSchema(), wrSch, impOpts.nameMapper)
+	if dateTransform := mvdata.NewDateLayoutTransform(mapSrcSch, wrSch, impOpts.nameMapper, impOpts.dateLayouts); dateTransform != nil {
+		transforms.AppendTransforms(pipeline.NewNamedTransform("Reformatting dates", dateTransform.TransformRow))
+	}
+
+	nameMapTransforms, err := mvdata.NameMapTransform(ctx, root.VRW(), mapSrcSch, wrSch, impOpts.nameMapper)
 
 	if err != nil {
 		return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.CreateMapperErr, Cause: err}
 	}
+	transforms.AppendTransforms(nameMapTransforms.Transforms...)
 
 	var wr table.TableWriteCloser
 	switch impOpts.operation {
 	case CreateOp:
-		wr, err = impOpts.dest.NewCreatingWriter(ctx, impOpts, dEnv, root, srcIsSorted, wrSch, statsCB, true)
+		if impOpts.workers > 0 && !schema.IsKeyless(wrSch) {
+			wr, err = impOpts.dest.NewBulkCreatingWriter(ctx, root, wrSch, impOpts.workers, statsCB)
+		} else {
+			wr, err = impOpts.dest.NewCreatingWriter(ctx, impOpts, dEnv, root, srcIsSorted, wrSch, statsCB, true)
+		}
 	case ReplaceOp:
 		wr, err = impOpts.dest.NewReplacingWriter(ctx, impOpts, dEnv, root, srcIsSorted, wrSch, statsCB, true)
 	case UpdateOp:
@@ -478,11 +682,36 @@ func newImportDataMover(ctx context.Context, root *doltdb.RootValue, dEnv *env.D
 	}
 
 	imp := &mvdata.DataMover{Rd: rd, Transforms: transforms, Wr: wr, ContOnErr: impOpts.contOnErr}
+	if _, ok := wr.(mvdata.SortedRunsWriter); ok {
+		imp.Workers = impOpts.workers
+	}
+
+	if impOpts.contOnErr {
+		var badRowWr *mvdata.BadRowWriter
+		if impOpts.badRowsFile != "" {
+			badRowWr, err = mvdata.NewBadRowWriter(root.VRW().Format(), impOpts.badRowsFile, dEnv.FS, rd.GetSchema())
+			if err != nil {
+				return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.CreateWriterErr, Cause: err}
+			}
+		}
+
+		imp.BadRows = mvdata.NewBadRowReport(badRowWr)
+	}
+
 	rd = nil
 
 	return imp, nil
 }
 
+// mapColNames returns the names that |nameMapper| renames the columns |colNames| to.
+func mapColNames(nameMapper rowconv.NameMapper, colNames []string) []string {
+	mapped := make([]string, len(colNames))
+	for i, name := range colNames {
+		mapped[i] = nameMapper.Map(name)
+	}
+	return mapped
+}
+
 func getImportSchema(ctx context.Context, root *doltdb.RootValue, fs filesys.Filesys, impOpts *importOptions) (schema.Schema, *mvdata.DataMoverCreationError) {
 	if impOpts.schFile != "" {
 		tn, out, err := mvdata.SchAndTableNameFromFile(ctx, impOpts.schFile, fs, root)
@@ -514,11 +743,55 @@ func getImportSchema(ctx context.Context, root *doltdb.RootValue, fs filesys.Fil
 			return rd.GetSchema(), nil
 		}
 
-		outSch, err := mvdata.InferSchema(ctx, root, rd, impOpts.tableName, impOpts.primaryKeys, impOpts)
+		var exprTransform *mvdata.ExprMappingTransform
+		if !impOpts.exprMapping.IsEmpty() {
+			exprTransform, err = mvdata.NewExprMappingTransform(ctx, root.VRW(), rd.GetSchema(), impOpts.nameMapper, impOpts.exprMapping)
+			if err != nil {
+				return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.MappingErr, Cause: err}
+			}
+		}
+
+		pks := impOpts.primaryKeys
+		var cols *schema.ColCollection
+		if impOpts.srcIsParquet() || impOpts.srcIsSqlite() {
+			// parquet files and sqlite databases are typed, so the schema is read from the source rather than inferred
+			// from its rows. Its columns are renamed by the mapping file, as inferred columns are.
+			if len(pks) == 0 && impOpts.srcIsSqlite() {
+				pks = mapColNames(impOpts.nameMapper, rd.GetSchema().GetPKCols().GetColumnNames())
+			}
+			cols = schema.MapColCollection(rd.GetSchema().GetAllCols(), func(col schema.Column) schema.Column {
+				col.Name = impOpts.nameMapper.Map(col.Name)
+				return col
+			})
+		} else {
+			cols, err = actions.InferColumnTypesFromTableReader(ctx, root, rd, impOpts)
+			if err != nil {
+				return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.SchemaErr, Cause: err}
+			}
+		}
+
+		if exprTransform != nil {
+			// the types of the computed fields are the types of their expressions
+			cols = exprTransform.WithComputedCols(cols)
+		}
+
+		outSch, err := mvdata.SchemaFromInferredCols(ctx, root, impOpts.tableName, cols, pks)
 		if err != nil {
 			return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.SchemaErr, Cause: err}
 		}
 
+		if impOpts.srcIsSqlite() {
+			// the indexes of sqlite tables are kept, on their columns as renamed by the mapping file
+			for _, index := range rd.GetSchema().Indexes().AllIndexes() {
+				props := schema.IndexProperties{IsUnique: index.IsUnique(), IsUserDefined: index.IsUserDefined(), Comment: index.Comment()}
+				_, err = outSch.Indexes().AddIndexByColNames(index.Name(), mapColNames(impOpts.nameMapper, index.ColumnNames()), props)
+				if err != nil {
+					err = fmt.Errorf("failed to import index '%s': %v", index.Name(), err)
+					return nil, &mvdata.DataMoverCreationError{ErrType: mvdata.SchemaErr, Cause: err}
+				}
+			}
+		}
+
 		return outSch, nil
 	}
 
@@ -0,0 +1,187 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tblcmds
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/cmd/dolt/commands/schcmds"
+	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
+	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/mvdata"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/sqlite"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+var importSqliteDocs = cli.CommandDocumentationContent{
+	ShortDesc: `Imports every table of a SQLite database`,
+	LongDesc: `Creates a table for each table of the SQLite database {{.LessThan}}file{{.GreaterThan}}, and imports the table's rows into it. The new tables keep the primary keys and indexes of the SQLite tables, and their column types follow the type affinities of the SQLite columns. SQLite tables without a primary key are imported as keyless tables.
+
+If any of the tables already exist the import is aborted before any tables are imported, unless the {{.EmphasisLeft}}--force | -f{{.EmphasisRight}} flag is provided, which overwrites the existing tables.
+
+During import, if there is an error importing any row, the import will be aborted by default. Use the {{.EmphasisLeft}}--continue{{.EmphasisRight}} flag to continue importing when an error is encountered.
+
+To import a single table of a SQLite database use {{.EmphasisLeft}}dolt table import --source-table{{.EmphasisRight}}.`,
+	Synopsis: []string{
+		"[-f] [--continue] {{.LessThan}}file{{.GreaterThan}}",
+	},
+}
+
+type ImportSqliteCmd struct{}
+
+// Name is returns the name of the Dolt cli command. This is what is used on the command line to invoke the command
+func (cmd ImportSqliteCmd) Name() string {
+	return "import-sqlite"
+}
+
+// Description returns a description of the command
+func (cmd ImportSqliteCmd) Description() string {
+	return "Creates tables from every table of a SQLite database."
+}
+
+// CreateMarkdown creates a markdown file containing the helptext for the command at the given path
+func (cmd ImportSqliteCmd) CreateMarkdown(fs filesys.Filesys, path, commandStr string) error {
+	ap := cmd.createArgParser()
+	return commands.CreateMarkdown(fs, path, cli.GetCommandDocumentation(commandStr, importSqliteDocs, ap))
+}
+
+func (cmd ImportSqliteCmd) createArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParser()
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{fileParam, "The SQLite database being imported."})
+	ap.SupportsFlag(forceParam, "f", "Overwrite any existing tables with the same names as the tables being imported.")
+	ap.SupportsFlag(contOnErrParam, "", "Continue importing when row import errors are encountered.")
+	return ap
+}
+
+// EventType returns the type of the event to log
+func (cmd ImportSqliteCmd) EventType() eventsapi.ClientEventType {
+	return eventsapi.ClientEventType_TABLE_IMPORT
+}
+
+// Exec executes the command
+func (cmd ImportSqliteCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) int {
+	ap := cmd.createArgParser()
+	help, usage := cli.HelpAndUsagePrinters(cli.GetCommandDocumentation(commandStr, importSqliteDocs, ap))
+	apr := cli.ParseArgs(ap, args, help)
+
+	if apr.NArg() != 1 {
+		usage()
+		return 1
+	}
+
+	if !cli.CheckEnvIsValid(dEnv) {
+		return 2
+	}
+
+	dEnv, err := commands.MaybeMigrateEnv(ctx, dEnv)
+	if err != nil {
+		verr := errhand.BuildDError("could not load manifest for gc").AddCause(err).Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	path := apr.Arg(0)
+	tableNames, err := sqlite.TableNames(ctx, path, dEnv.FS)
+	if err != nil {
+		verr := errhand.BuildDError("error: failed to read the tables of '%s'", path).AddCause(err).Build()
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	if len(tableNames) == 0 {
+		cli.Println("The SQLite database has no tables to import.")
+		return 0
+	}
+
+	impOptsByTable, verr := sqliteImportOptions(ctx, dEnv, path, tableNames, apr.Contains(forceParam), apr.Contains(contOnErrParam))
+	if verr != nil {
+		return commands.HandleVErrAndExitCode(verr, usage)
+	}
+
+	for i, tableName := range tableNames {
+		impOpts := impOptsByTable[i]
+		cli.Println(color.CyanString("Importing table %s", tableName))
+
+		root, err := dEnv.WorkingRoot(ctx)
+		if err != nil {
+			verr = errhand.BuildDError("Unable to get the working root value for this data repository.").AddCause(err).Build()
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+
+		mover, nDMErr := newImportDataMover(ctx, root, dEnv, impOpts, importStatsCB)
+		if nDMErr != nil {
+			verr = newDataMoverErrToVerr(impOpts, nDMErr)
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+
+		skipped, verr := mvdata.MoveData(ctx, dEnv, mover, impOpts)
+		cli.Println()
+		displayStrLen = 0
+
+		if skipped > 0 {
+			cli.PrintErrln(color.YellowString("Lines skipped: %d", skipped))
+			printBadRowReport(mover.BadRows, "")
+		}
+		if verr != nil {
+			return commands.HandleVErrAndExitCode(verr, usage)
+		}
+	}
+
+	cli.PrintErrln(color.CyanString("Import completed successfully."))
+	return 0
+}
+
+// sqliteImportOptions returns the options for importing each of the tables |tableNames| of the SQLite database at
+// |path|. The tables are checked before any of them are imported, so that either all of the tables are imported or
+// none are.
+func sqliteImportOptions(ctx context.Context, dEnv *env.DoltEnv, path string, tableNames []string, force, contOnErr bool) ([]*importOptions, errhand.VerboseError) {
+	root, err := dEnv.WorkingRoot(ctx)
+	if err != nil {
+		return nil, errhand.BuildDError("Unable to get the working root value for this data repository.").AddCause(err).Build()
+	}
+
+	impOptsByTable := make([]*importOptions, len(tableNames))
+	for i, tableName := range tableNames {
+		if verr := schcmds.ValidateTableNameForCreate(tableName); verr != nil {
+			return nil, verr
+		}
+
+		impOptsByTable[i] = &importOptions{
+			operation:  CreateOp,
+			tableName:  tableName,
+			contOnErr:  contOnErr,
+			force:      force,
+			nameMapper: make(rowconv.NameMapper),
+			src:        mvdata.FileDataLocation{Path: path, Format: mvdata.SqliteFile},
+			dest:       mvdata.TableDataLocation{Name: tableName},
+			srcOptions: mvdata.SqliteOptions{TableName: tableName},
+		}
+
+		exists, err := impOptsByTable[i].checkOverwrite(ctx, root, dEnv.FS)
+		if err != nil {
+			return nil, errhand.VerboseErrorFromError(err)
+		}
+		if exists {
+			return nil, errhand.BuildDError("%s already exists. Use -f to overwrite.", tableName).Build()
+		}
+	}
+
+	return impOptsByTable, nil
+}
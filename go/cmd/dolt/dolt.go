@@ -87,6 +87,8 @@ var doltCommand = cli.NewSubCommandHandler("dolt", "it's git for data", []cli.Co
 	commands.GarbageCollectionCmd{},
 	commands.FilterBranchCmd{},
 	commands.VerifyConstraintsCmd{},
+	commands.LoadDumpCmd{},
+	tblcmds.ImportSqliteCmd{},
 })
 
 func init() {
@@ -306,6 +308,7 @@ func commandNeedsMigrationCheck(args []string) bool {
 		commands.CloneCmd{},
 		schcmds.ImportCmd{},
 		tblcmds.ImportCmd{},
+		tblcmds.ImportSqliteCmd{},
 		tblcmds.RmCmd{},
 		tblcmds.MvCmd{},
 		tblcmds.CpCmd{},
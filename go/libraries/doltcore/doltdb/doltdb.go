@@ -303,27 +303,7 @@ func (ddb *DoltDB) Resolve(ctx context.Context, cs *CommitSpec, cwb ref.DoltRef)
 	case hashCommitSpec:
 		commitSt, err = getCommitStForHash(ctx, ddb.db, cs.baseSpec)
 	case refCommitSpec:
-		// For a ref in a CommitSpec, we have the following behavior.
-		// If it starts with `refs/`, we look for an exact match before
-		// we try any suffix matches. After that, we try a match on the
-		// user supplied input, with the following four prefixes, in
-		// order: `refs/`, `refs/heads/`, `refs/tags/`, `refs/remotes/`.
-		candidates := []string{
-			"refs/" + cs.baseSpec,
-			"refs/heads/" + cs.baseSpec,
-			"refs/tags/" + cs.baseSpec,
-			"refs/remotes/" + cs.baseSpec,
-		}
-		if strings.HasPrefix(cs.baseSpec, "refs/") {
-			candidates = []string{
-				cs.baseSpec,
-				"refs/" + cs.baseSpec,
-				"refs/heads/" + cs.baseSpec,
-				"refs/tags/" + cs.baseSpec,
-				"refs/remotes/" + cs.baseSpec,
-			}
-		}
-		for _, candidate := range candidates {
+		for _, candidate := range refCandidates(cs.baseSpec) {
 			commitSt, err = getCommitStForRefStr(ctx, ddb.db, candidate)
 			if err == nil {
 				break
@@ -333,6 +313,9 @@ func (ddb *DoltDB) Resolve(ctx context.Context, cs *CommitSpec, cwb ref.DoltRef)
 			}
 		}
 	case headCommitSpec:
+		if cwb == nil {
+			return nil, ErrBranchNotFound
+		}
 		commitSt, err = getCommitStForRefStr(ctx, ddb.db, cwb.String())
 	default:
 		panic("unrecognized commit spec csType: " + cs.csType)
@@ -351,6 +334,49 @@ func (ddb *DoltDB) Resolve(ctx context.Context, cs *CommitSpec, cwb ref.DoltRef)
 	return NewCommit(ddb.db, commitSt), nil
 }
 
+// refCandidates returns the refs that the ref |baseSpec| of a CommitSpec may name, in the order they're tried. If it
+// starts with `refs/`, we look for an exact match before we try any suffix matches. After that, we try a match on the
+// user supplied input, with the following four prefixes, in order: `refs/`, `refs/heads/`, `refs/tags/`,
+// `refs/remotes/`.
+func refCandidates(baseSpec string) []string {
+	candidates := []string{
+		"refs/" + baseSpec,
+		"refs/heads/" + baseSpec,
+		"refs/tags/" + baseSpec,
+		"refs/remotes/" + baseSpec,
+	}
+	if strings.HasPrefix(baseSpec, "refs/") {
+		candidates = append([]string{baseSpec}, candidates...)
+	}
+	return candidates
+}
+
+// ResolveCommitRef returns the ref that the base of |cs| resolves to, which is |cwb| if the CommitSpec is HEAD. Nil is
+// returned for CommitSpecs of commit hashes, which don't resolve through a ref.
+func (ddb *DoltDB) ResolveCommitRef(ctx context.Context, cs *CommitSpec, cwb ref.DoltRef) (ref.DoltRef, error) {
+	switch cs.csType {
+	case hashCommitSpec:
+		return nil, nil
+	case headCommitSpec:
+		if cwb == nil {
+			return nil, ErrBranchNotFound
+		}
+		return cwb, nil
+	}
+
+	for _, candidate := range refCandidates(cs.baseSpec) {
+		_, err := getCommitStForRefStr(ctx, ddb.db, candidate)
+		if err == ErrBranchNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		return ref.Parse(candidate)
+	}
+
+	return nil, ErrBranchNotFound
+}
+
 // ResolveRef takes a DoltRef and returns a Commit, or an error if the commit cannot be found.
 func (ddb *DoltDB) ResolveRef(ctx context.Context, ref ref.DoltRef) (*Commit, error) {
 	commitSt, err := getCommitStForRefStr(ctx, ddb.db, ref.String())
@@ -646,6 +672,16 @@ func (ddb *DoltDB) Format() *types.NomsBinFormat {
 	return ddb.db.Format()
 }
 
+// Rebase brings this DoltDB's view of its refs up to date with changes written by other processes or DoltDB instances.
+func (ddb *DoltDB) Rebase(ctx context.Context) error {
+	return ddb.db.Rebase(ctx)
+}
+
+// Stats returns the statistics of the underlying chunk store, such as nbs.Stats for noms block stores.
+func (ddb *DoltDB) Stats() interface{} {
+	return ddb.db.Stats()
+}
+
 func WriteValAndGetRef(ctx context.Context, vrw types.ValueReadWriter, val types.Value) (types.Ref, error) {
 	valRef, err := types.NewRef(val, vrw.Format())
 
@@ -787,9 +823,15 @@ func (ddb *DoltDB) NewBranchAtCommit(ctx context.Context, dref ref.DoltRef, comm
 	return err
 }
 
-// DeleteBranch deletes the branch given, returning an error if it doesn't exist.
+// DeleteBranch deletes the branch given, returning an error if it doesn't exist. The working set persisted for the
+// branch by SetBranchWorkingSet is deleted along with it.
 func (ddb *DoltDB) DeleteBranch(ctx context.Context, branch ref.DoltRef) error {
-	return ddb.deleteRef(ctx, branch)
+	err := ddb.deleteRef(ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	return ddb.DeleteBranchWorkingSet(ctx, branch)
 }
 
 func (ddb *DoltDB) deleteRef(ctx context.Context, dref ref.DoltRef) error {
@@ -884,6 +926,101 @@ func (ddb *DoltDB) DeleteWorkspace(ctx context.Context, workRef ref.DoltRef) err
 	return err
 }
 
+// branchWorkingSetRefs returns the internal refs that hold the working and staged roots of |branch|.
+func branchWorkingSetRefs(branch ref.DoltRef) (ref.DoltRef, ref.DoltRef) {
+	return ref.NewInternalRef("working/" + branch.GetPath()), ref.NewInternalRef("staged/" + branch.GetPath())
+}
+
+// SetBranchWorkingSet persists |working| and |staged| as the working and staged roots of |branch|, for branches whose
+// working set isn't kept in the repo state because they aren't checked out. Each root is stored as a dangling commit
+// whose parent is |head|, the commit the branch pointed at when the roots were written, so that the roots are kept by
+// garbage collection, and so that their changes can be merged onto the branch once it moves.
+func (ddb *DoltDB) SetBranchWorkingSet(ctx context.Context, branch ref.DoltRef, head *Commit, working, staged hash.Hash) error {
+	meta, err := head.GetCommitMeta()
+	if err != nil {
+		return err
+	}
+
+	meta, err = NewCommitMeta(meta.Name, meta.Email, "working set of "+branch.GetPath())
+	if err != nil {
+		return err
+	}
+
+	workingRef, stagedRef := branchWorkingSetRefs(branch)
+	for _, r := range []struct {
+		dref ref.DoltRef
+		h    hash.Hash
+	}{{workingRef, working}, {stagedRef, staged}} {
+		cm, err := ddb.CommitDanglingWithParentCommits(ctx, r.h, []*Commit{head}, meta)
+		if err != nil {
+			return err
+		}
+
+		err = ddb.SetHeadToCommit(ctx, r.dref, cm)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BranchRoot is a working or staged root persisted for a branch by SetBranchWorkingSet.
+type BranchRoot struct {
+	// Root is the hash of the root value.
+	Root hash.Hash
+	// Base is the commit that the branch pointed at when the root was written. The root's changes are relative to it.
+	Base *Commit
+}
+
+// GetBranchWorkingSet returns the working and staged roots persisted for |branch| by SetBranchWorkingSet. Returns false
+// if none were persisted. The branch may have moved since the roots were written, in which case their changes need to
+// be merged onto its head, see BranchRoot.Base.
+func (ddb *DoltDB) GetBranchWorkingSet(ctx context.Context, branch ref.DoltRef) (BranchRoot, BranchRoot, bool, error) {
+	workingRef, stagedRef := branchWorkingSetRefs(branch)
+	var roots [2]BranchRoot
+	for i, dref := range []ref.DoltRef{workingRef, stagedRef} {
+		cm, err := ddb.ResolveRef(ctx, dref)
+		if err == ErrBranchNotFound {
+			return BranchRoot{}, BranchRoot{}, false, nil
+		} else if err != nil {
+			return BranchRoot{}, BranchRoot{}, false, err
+		}
+
+		base, err := ddb.ResolveParent(ctx, cm, 0)
+		if err != nil {
+			return BranchRoot{}, BranchRoot{}, false, err
+		}
+
+		root, err := cm.GetRootValue()
+		if err != nil {
+			return BranchRoot{}, BranchRoot{}, false, err
+		}
+
+		rootHash, err := root.HashOf()
+		if err != nil {
+			return BranchRoot{}, BranchRoot{}, false, err
+		}
+
+		roots[i] = BranchRoot{Root: rootHash, Base: base}
+	}
+
+	return roots[0], roots[1], true, nil
+}
+
+// DeleteBranchWorkingSet deletes the working set persisted for |branch| by SetBranchWorkingSet, if there is one.
+func (ddb *DoltDB) DeleteBranchWorkingSet(ctx context.Context, branch ref.DoltRef) error {
+	workingRef, stagedRef := branchWorkingSetRefs(branch)
+	for _, dref := range []ref.DoltRef{workingRef, stagedRef} {
+		err := ddb.deleteRef(ctx, dref)
+		if err != nil && err != ErrBranchNotFound {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // GC performs garbage collection on this ddb. Values passed in |uncommitedVals| will be temporarily saved during gc.
 func (ddb *DoltDB) GC(ctx context.Context, uncommitedVals ...hash.Hash) error {
 	collector, ok := ddb.db.(datas.GarbageCollector)
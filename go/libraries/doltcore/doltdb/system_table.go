@@ -135,6 +135,7 @@ var generatedSystemTables = []string{
 	CommitsTableName,
 	CommitAncestorsTableName,
 	StatusTableName,
+	ReplicationStatusTableName,
 }
 
 var generatedSystemTablePrefixes = []string{
@@ -218,4 +219,7 @@ const (
 
 	// StatusTableName is the status system table name.
 	StatusTableName = "dolt_status"
+
+	// ReplicationStatusTableName is the replication status system table name.
+	ReplicationStatusTableName = "dolt_replication_status"
 )
@@ -0,0 +1,148 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+var ErrRemoteHasNoData = errors.New("remote at that url contains no Dolt data")
+
+// CloneRemote clones |srcDB|, the database of the remote |remoteName|, into the repository of |dEnv|, which must have
+// been initialized without data. A remote branch is created for each branch of the remote, and only |branch| is kept
+// as a local branch and checked out. If |branch| is empty, master is checked out, or the first branch if there's no
+// master branch. A remote without any branches is initialized as a new repository. The progress of the clone is sent
+// to |eventCh|.
+func CloneRemote(ctx context.Context, srcDB *doltdb.DoltDB, remoteName, branch string, dEnv *env.DoltEnv, eventCh chan<- datas.TableFileEvent) error {
+	err := Clone(ctx, srcDB, dEnv.DoltDB, eventCh)
+	if err == datas.ErrNoData {
+		return ErrRemoteHasNoData
+	} else if err != nil {
+		return err
+	}
+
+	branches, err := dEnv.DoltDB.GetBranches(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list branches: %w", err)
+	}
+
+	if branch == "" {
+		for _, brnch := range branches {
+			branch = brnch.GetPath()
+			if branch == doltdb.MasterBranch {
+				break
+			}
+		}
+	}
+
+	// If we couldn't find a branch but the repo cloned successfully, it's empty. Initialize it instead of pulling from
+	// the remote.
+	performPull := true
+	if branch == "" {
+		err = InitEmptyClonedRepo(ctx, dEnv)
+		if err != nil {
+			return err
+		}
+
+		branch = doltdb.MasterBranch
+		performPull = false
+	}
+
+	cs, _ := doltdb.NewCommitSpec(branch)
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+	if err != nil {
+		return fmt.Errorf("could not get %s: %w", branch, err)
+	}
+
+	rootVal, err := cm.GetRootValue()
+	if err != nil {
+		return fmt.Errorf("could not get the root value of %s: %w", branch, err)
+	}
+
+	// After Clone, we have repository with a local branch for every branch in the remote. What we want is a remote
+	// branch ref for every branch in the remote. We iterate through local branches and create remote refs
+	// corresponding to each of them. We delete all of the local branches except for the one corresponding to |branch|.
+	for _, brnch := range branches {
+		cs, _ := doltdb.NewCommitSpec(brnch.GetPath())
+		cm, err := dEnv.DoltDB.Resolve(ctx, cs, nil)
+		if err != nil {
+			return fmt.Errorf("could not resolve branch ref at %s: %w", brnch.String(), err)
+		}
+
+		remoteRef := ref.NewRemoteRef(remoteName, brnch.GetPath())
+		err = dEnv.DoltDB.SetHeadToCommit(ctx, remoteRef, cm)
+		if err != nil {
+			return fmt.Errorf("could not create remote ref at %s: %w", remoteRef.String(), err)
+		}
+
+		if brnch.GetPath() != branch {
+			err := dEnv.DoltDB.DeleteBranch(ctx, brnch)
+			if err != nil {
+				return fmt.Errorf("could not delete local branch %s after clone: %w", brnch.String(), err)
+			}
+		}
+	}
+
+	if performPull {
+		err = SaveDocsFromRoot(ctx, rootVal, dEnv)
+		if err != nil {
+			return fmt.Errorf("failed to update docs on the filesystem: %w", err)
+		}
+	}
+
+	h, err := dEnv.DoltDB.WriteRootValue(ctx, rootVal)
+	if err != nil {
+		return fmt.Errorf("could not write root value: %w", err)
+	}
+
+	dEnv.RepoState.Head = ref.MarshalableRef{Ref: ref.NewBranchRef(branch)}
+	dEnv.RepoState.Staged = h.String()
+	dEnv.RepoState.Working = h.String()
+
+	err = dEnv.RepoState.Save(dEnv.FS)
+	if err != nil {
+		return fmt.Errorf("failed to write repo state: %w", err)
+	}
+
+	return nil
+}
+
+// InitEmptyClonedRepo inits an empty, newly cloned repo. This would be unnecessary if we properly initialized the
+// storage for a repository when we created it on dolthub. If we do that, this code can be removed.
+func InitEmptyClonedRepo(ctx context.Context, dEnv *env.DoltEnv) error {
+	name := dEnv.Config.GetStringOrDefault(env.UserNameKey, "")
+	email := dEnv.Config.GetStringOrDefault(env.UserEmailKey, "")
+
+	if *name == "" {
+		return fmt.Errorf("could not determine user name. run dolt config --global --add %s", env.UserNameKey)
+	} else if *email == "" {
+		return fmt.Errorf("could not determine email. run dolt config --global --add %s", env.UserEmailKey)
+	}
+
+	err := dEnv.InitDBWithTime(ctx, types.Format_Default, *name, *email, doltdb.CommitNowFunc())
+	if err != nil {
+		return fmt.Errorf("could not initialize repository: %w", err)
+	}
+
+	return nil
+}
@@ -16,14 +16,19 @@ package actions
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/google/uuid"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
@@ -40,6 +45,34 @@ const (
 	minInt24  = -1 << 23
 )
 
+const (
+	// enumMinRows is the number of values a column must have before it's inferred to be an enum
+	enumMinRows = 20
+	// enumMaxValues is the largest number of distinct values of a column inferred to be an enum
+	enumMaxValues = 16
+	// enumMinRepeats is the average number of times each value of a column inferred to be an enum must appear
+	enumMinRepeats = 4
+	// decimalMinScale is the fewest digits after the decimal point of a column inferred to be a decimal. Numbers with
+	// fewer fractional digits are more likely to be measurements than fixed precision values.
+	decimalMinScale = 2
+)
+
+// CommonDateLayouts are layouts, in the format of the time package, of dates, times and datetimes that are common in
+// files but that aren't understood by SQL. They're tried after the formats which SQL understands. Table imports
+// rewrite the values in these layouts in the formats SQL understands, see mvdata.DateLayoutTransform.
+var CommonDateLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02",
+	"2006/01/02 15:04:05",
+	"1/2/2006",
+	"1/2/2006 15:04:05",
+	"Jan 2 2006",
+	"2 Jan 2006",
+	"3:04 PM",
+	"3:04:05 PM",
+}
+
 // InferenceArgs are arguments that can be passed to the schema inferrer to modify it's inference behavior.
 type InferenceArgs interface {
 	// ColNameMapper allows columns named X in the schema to be named Y in the inferred schema.
@@ -51,10 +84,31 @@ type InferenceArgs interface {
 	// a fractional component greater than or equal to 0.001 will be treated as a float (1.0 would be an int, 1.0009 would
 	// be an int, 1.001 would be a float, 1.1 would be a float, etc)
 	FloatThreshold() float64
+	// SampleRows is the number of rows that types are inferred from. If SampleRows is 0 then the types are inferred
+	// from all of the rows.
+	SampleRows() int
+	// DateLayouts are layouts, in the format of the time package, of the dates, times and datetimes which are
+	// recognized in addition to the formats that SQL understands.
+	DateLayouts() []string
+}
+
+// InferenceReport explains the column types inferred from a table reader
+type InferenceReport struct {
+	// RowsSampled is the number of rows that the types were inferred from
+	RowsSampled int
+	// Reasons holds the reason each column was given its type, keyed by column name
+	Reasons map[string]string
 }
 
 // InferColumnTypesFromTableReader will infer a data types from a table reader.
 func InferColumnTypesFromTableReader(ctx context.Context, root *doltdb.RootValue, rd table.TableReadCloser, args InferenceArgs) (*schema.ColCollection, error) {
+	cols, _, err := InferColumnTypesWithReport(ctx, root, rd, args)
+	return cols, err
+}
+
+// InferColumnTypesWithReport infers data types from a table reader like InferColumnTypesFromTableReader, and also
+// returns a report of why each column was given its type.
+func InferColumnTypesWithReport(ctx context.Context, root *doltdb.RootValue, rd table.TableReadCloser, args InferenceArgs) (*schema.ColCollection, *InferenceReport, error) {
 	inferrer := newInferrer(rd.GetSchema(), args)
 
 	var rowFailure *pipeline.TransformRowFailure
@@ -63,37 +117,73 @@ func InferColumnTypesFromTableReader(ctx context.Context, root *doltdb.RootValue
 		return false
 	}
 
-	rdProcFunc := pipeline.ProcFuncForReader(ctx, rd)
-	p := pipeline.NewAsyncPipeline(rdProcFunc, inferrer.sinkRow, nil, badRow)
+	srcFunc := pipeline.SourceFuncForReader(ctx, rd)
+	if sampleRows := args.SampleRows(); sampleRows > 0 {
+		srcFunc = limitSourceFunc(srcFunc, sampleRows)
+	}
+
+	p := pipeline.NewAsyncPipeline(pipeline.ProcFuncForSourceFunc(srcFunc), inferrer.sinkRow, nil, badRow)
 	p.Start()
 
 	err := p.Wait()
 
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if rowFailure != nil {
-		return nil, rowFailure
+		return nil, nil, rowFailure
 	}
 
 	return inferrer.inferColumnTypes(ctx, root)
 }
 
+// limitSourceFunc returns a SourceFunc which returns the first |limit| rows of |srcFunc|
+func limitSourceFunc(srcFunc pipeline.SourceFunc, limit int) pipeline.SourceFunc {
+	read := 0
+	return func() (row.Row, pipeline.ImmutableProperties, error) {
+		if read >= limit {
+			return nil, pipeline.NoProps, io.EOF
+		}
+		read++
+		return srcFunc()
+	}
+}
+
 type inferrer struct {
 	readerSch      schema.Schema
 	inferSets      map[uint64]typeInfoSet
 	nullable       *set.Uint64Set
 	mapper         rowconv.NameMapper
 	floatThreshold float64
+	dateLayouts    []string
 
-	//inferArgs *InferenceArgs
+	rowCount int
+	stats    map[uint64]*colStats
+}
+
+// colStats are the statistics of the values of a column that are needed to infer the types which can't be inferred
+// from each value on its own
+type colStats struct {
+	// values is the number of non-empty values
+	values int
+	// distinct holds the distinct values of the column. It's nil once the column can't be an enum.
+	distinct map[string]struct{}
+	// scale is the number of digits after the decimal point shared by every number with a fractional part. It's -1
+	// before the first such number, and -2 once two numbers have different scales.
+	scale int
+	// intDigits is the largest number of digits before the decimal point of a number
+	intDigits int
+	// layouts holds the DateLayouts that the values of the column were parsed with
+	layouts *set.StrSet
 }
 
 func newInferrer(readerSch schema.Schema, args InferenceArgs) *inferrer {
 	inferSets := make(map[uint64]typeInfoSet, readerSch.GetAllCols().Size())
+	stats := make(map[uint64]*colStats, readerSch.GetAllCols().Size())
 	_ = readerSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
 		inferSets[tag] = make(typeInfoSet)
+		stats[tag] = &colStats{distinct: make(map[string]struct{}), scale: -1, layouts: set.NewStrSet(nil)}
 		return false, nil
 	})
 
@@ -103,22 +193,33 @@ func newInferrer(readerSch schema.Schema, args InferenceArgs) *inferrer {
 		nullable:       set.NewUint64Set(nil),
 		mapper:         args.ColNameMapper(),
 		floatThreshold: args.FloatThreshold(),
+		dateLayouts:    args.DateLayouts(),
+		stats:          stats,
 	}
 }
 
 // inferColumnTypes returns TableReader's columns with updated TypeInfo and columns names
-func (inf *inferrer) inferColumnTypes(ctx context.Context, root *doltdb.RootValue) (*schema.ColCollection, error) {
-
-	inferredTypes := make(map[uint64]typeinfo.TypeInfo)
-	for tag, ts := range inf.inferSets {
-		inferredTypes[tag] = findCommonType(ts)
-	}
+func (inf *inferrer) inferColumnTypes(ctx context.Context, root *doltdb.RootValue) (*schema.ColCollection, *InferenceReport, error) {
+	report := &InferenceReport{RowsSampled: inf.rowCount, Reasons: make(map[string]string)}
 
 	var cols []schema.Column
-	_ = inf.readerSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+	err := inf.readerSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		stats := inf.stats[tag]
+		commonType := findCommonType(inf.inferSets[tag])
+
+		ti := commonType
+		if ti == typeinfo.StringDefaultType {
+			ti, err = stats.enumType()
+		} else if ti == typeinfo.Float32Type || ti == typeinfo.Float64Type {
+			ti, err = stats.decimalType(ti)
+		}
+		if err != nil {
+			return true, err
+		}
+
 		col.Name = inf.mapper.Map(col.Name)
-		col.Kind = inferredTypes[tag].NomsKind()
-		col.TypeInfo = inferredTypes[tag]
+		col.Kind = ti.NomsKind()
+		col.TypeInfo = ti
 		col.Tag = schema.ReservedTagMin + tag
 
 		col.Constraints = []schema.ColConstraint{schema.NotNullConstraint{}}
@@ -126,15 +227,22 @@ func (inf *inferrer) inferColumnTypes(ctx context.Context, root *doltdb.RootValu
 			col.Constraints = []schema.ColConstraint(nil)
 		}
 
+		report.Reasons[col.Name] = inf.reason(tag, commonType, ti)
+
 		cols = append(cols, col)
 		return false, nil
 	})
 
-	return schema.NewColCollection(cols...), nil
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return schema.NewColCollection(cols...), report, nil
 }
 
 func (inf *inferrer) sinkRow(p *pipeline.Pipeline, ch <-chan pipeline.RowWithProps, badRowChan chan<- *pipeline.TransformRowFailure) {
 	for r := range ch {
+		inf.rowCount++
 		_, _ = r.Row.IterSchema(inf.readerSch, func(tag uint64, val types.Value) (stop bool, err error) {
 			if val == nil {
 				inf.nullable.Add(tag)
@@ -142,12 +250,224 @@ func (inf *inferrer) sinkRow(p *pipeline.Pipeline, ch <-chan pipeline.RowWithPro
 			}
 			strVal := string(val.(types.String))
 			typeInfo := leastPermissiveType(strVal, inf.floatThreshold)
+
+			stats := inf.stats[tag]
+			if typeInfo == typeinfo.StringDefaultType && len(inf.dateLayouts) > 0 {
+				if chronoType, layout := chronoTypeForLayouts(strings.TrimSpace(strVal), inf.dateLayouts); chronoType != typeinfo.UnknownType {
+					typeInfo = chronoType
+					stats.layouts.Add(layout)
+				}
+			}
+
 			inf.inferSets[tag][typeInfo] = struct{}{}
+			stats.add(strVal, typeInfo, inf.floatThreshold)
 			return false, nil
 		})
 	}
 }
 
+// add updates the statistics of a column with one of its values, whose least permissive type is |ti|
+func (cs *colStats) add(strVal string, ti typeinfo.TypeInfo, floatThreshold float64) {
+	if ti == typeinfo.UnknownType {
+		// an empty string can't be an enum value
+		cs.distinct = nil
+		return
+	}
+
+	cs.values++
+
+	if cs.distinct != nil {
+		if strVal != strings.TrimSpace(strVal) {
+			// enum values are stored without their trailing spaces
+			cs.distinct = nil
+		} else {
+			cs.distinct[strVal] = struct{}{}
+			if len(cs.distinct) > enumMaxValues {
+				cs.distinct = nil
+			}
+		}
+	}
+
+	switch ti {
+	case typeinfo.Uint32Type, typeinfo.Uint64Type, typeinfo.Int32Type, typeinfo.Int64Type:
+		cs.addDigits(strings.TrimSpace(strVal), 0)
+	case typeinfo.Float32Type, typeinfo.Float64Type:
+		// numbers with fractional parts smaller than the float threshold are ints, so they have no fixed precision
+		if floatThreshold != 0.0 {
+			cs.scale = -2
+			return
+		}
+		parts := strings.Split(strings.TrimSpace(strVal), ".")
+		if len(parts) != 2 || !isDigits(parts[1]) {
+			cs.scale = -2
+			return
+		}
+		cs.addDigits(parts[0], len(parts[1]))
+	}
+}
+
+func (cs *colStats) addDigits(intPart string, scale int) {
+	intPart = strings.TrimLeft(intPart, "+-")
+	if len(intPart) > cs.intDigits {
+		cs.intDigits = len(intPart)
+	}
+
+	if scale == 0 || cs.scale == -2 {
+		return
+	}
+
+	if cs.scale == -1 {
+		cs.scale = scale
+	} else if cs.scale != scale {
+		cs.scale = -2
+	}
+}
+
+func isDigits(str string) bool {
+	for _, c := range str {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return len(str) > 0
+}
+
+// isEnum returns whether a column of strings has few enough distinct values, repeated often enough, to be an enum
+func (cs *colStats) isEnum() bool {
+	return cs.distinct != nil && cs.values >= enumMinRows && len(cs.distinct)*enumMinRepeats <= cs.values
+}
+
+// enumType returns an enum type of the distinct values of a column of strings, or the string type if the column
+// isn't an enum
+func (cs *colStats) enumType() (typeinfo.TypeInfo, error) {
+	if !cs.isEnum() {
+		return typeinfo.StringDefaultType, nil
+	}
+
+	vals := make([]string, 0, len(cs.distinct))
+	for val := range cs.distinct {
+		vals = append(vals, val)
+	}
+	sort.Strings(vals)
+
+	enumType, err := sql.CreateEnumType(vals, sql.Collation_Default)
+	if err != nil {
+		// some strings, such as values which are the same under the enum's collation, can't be enum values
+		return typeinfo.StringDefaultType, nil
+	}
+
+	return typeinfo.FromSqlType(enumType)
+}
+
+// isDecimal returns whether every number of a column of floats has the same number of digits after the decimal point
+func (cs *colStats) isDecimal() bool {
+	return cs.scale >= decimalMinScale && cs.scale <= sql.DecimalTypeMaxScale && cs.intDigits+cs.scale <= sql.DecimalTypeMaxPrecision
+}
+
+// decimalType returns a decimal type that fits each of the numbers of a column of floats, or |floatType| if the
+// numbers don't have a fixed precision
+func (cs *colStats) decimalType(floatType typeinfo.TypeInfo) (typeinfo.TypeInfo, error) {
+	if !cs.isDecimal() {
+		return floatType, nil
+	}
+
+	intDigits := cs.intDigits
+	if intDigits == 0 {
+		intDigits = 1
+	}
+
+	decimalType, err := sql.CreateDecimalType(uint8(intDigits+cs.scale), uint8(cs.scale))
+	if err != nil {
+		return nil, err
+	}
+
+	return typeinfo.FromSqlType(decimalType)
+}
+
+// reason explains why the column with the tag |tag| was given the type |ti|. |commonType| is the common type of all
+// of the column's values, before enums and decimals were inferred.
+func (inf *inferrer) reason(tag uint64, commonType, ti typeinfo.TypeInfo) string {
+	stats := inf.stats[tag]
+
+	var reason string
+	switch {
+	case stats.values == 0:
+		reason = "every value is empty"
+	case ti.GetTypeIdentifier() == typeinfo.EnumTypeIdentifier:
+		reason = fmt.Sprintf("%d distinct values in %d rows", len(stats.distinct), stats.values)
+	case ti.GetTypeIdentifier() == typeinfo.DecimalTypeIdentifier:
+		reason = fmt.Sprintf("every number has %d digits after the decimal point", stats.scale)
+	case commonType == typeinfo.StringDefaultType:
+		reason = inf.stringReason(tag)
+	case commonType == typeinfo.Float32Type || commonType == typeinfo.Float64Type:
+		reason = "numbers with fractional parts"
+		if stats.scale == -2 {
+			reason += ", which don't all have the same number of digits after the decimal point"
+		} else if stats.scale >= 0 && stats.scale < decimalMinScale {
+			reason += fmt.Sprintf(", with %d digit after the decimal point", stats.scale)
+		}
+	case commonType == typeinfo.Int32Type || commonType == typeinfo.Int64Type:
+		reason = "integers, some of which are negative"
+	case commonType == typeinfo.Uint32Type || commonType == typeinfo.Uint64Type:
+		reason = "integers, none of which are negative"
+	case commonType == typeinfo.BoolType:
+		reason = "every value is true or false"
+	case commonType == typeinfo.UuidType:
+		reason = "every value is a uuid"
+	case commonType == typeinfo.DateType:
+		reason = "every value is a date"
+	case commonType == typeinfo.TimeType:
+		reason = "every value is a time"
+	case len(inf.inferSets[tag]) == 1:
+		reason = "every value is a datetime"
+	default:
+		reason = "values are dates, times and datetimes"
+	}
+
+	if stats.layouts.Size() == 1 {
+		reason += fmt.Sprintf(", in the layout %s", stats.layouts.AsSlice()[0])
+	} else if stats.layouts.Size() > 1 {
+		layouts := stats.layouts.AsSlice()
+		sort.Strings(layouts)
+		reason += fmt.Sprintf(", in the layouts %s", strings.Join(layouts, ", "))
+	}
+
+	if inf.nullable.Contains(tag) {
+		reason += "; some values are empty, so it's nullable"
+	}
+
+	return reason
+}
+
+// stringReason explains why a column was given the string type
+func (inf *inferrer) stringReason(tag uint64) string {
+	var kinds []string
+	for ti := range inf.inferSets[tag] {
+		if ti != typeinfo.UnknownType && ti != typeinfo.StringDefaultType {
+			kinds = append(kinds, ti.String())
+		}
+	}
+
+	if len(kinds) > 0 && len(kinds) < len(inf.inferSets[tag]) {
+		sort.Strings(kinds)
+		return fmt.Sprintf("some values are text, and others are %s", strings.Join(kinds, ", "))
+	} else if len(kinds) > 0 {
+		sort.Strings(kinds)
+		return fmt.Sprintf("values are of types which have no common type: %s", strings.Join(kinds, ", "))
+	}
+
+	stats := inf.stats[tag]
+	if stats.distinct == nil {
+		return "text with too many distinct values to be an enum"
+	} else if stats.values < enumMinRows {
+		return fmt.Sprintf("text, with too few values to tell whether it's an enum (%d of %d)", stats.values, enumMinRows)
+	} else if len(stats.distinct)*enumMinRepeats > stats.values {
+		return "text with too many distinct values to be an enum"
+	}
+
+	return "text with values which can't be the values of an enum"
+}
+
 func leastPermissiveType(strVal string, floatThreshold float64) typeinfo.TypeInfo {
 	if len(strVal) == 0 {
 		return typeinfo.UnknownType
@@ -254,6 +574,30 @@ func leastPermissiveChronoType(strVal string) typeinfo.TypeInfo {
 	return typeinfo.DatetimeType
 }
 
+// chronoTypeForLayouts returns the type of a string which can be parsed with one of |layouts|, and the layout that
+// it was parsed with. If it can't be parsed, typeinfo.UnknownType is returned.
+func chronoTypeForLayouts(strVal string, layouts []string) (typeinfo.TypeInfo, string) {
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, strVal)
+		if err != nil {
+			continue
+		}
+
+		hasDate := t.Year() != 0
+		hasTime := t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 || t.Nanosecond() != 0
+		switch {
+		case !hasDate:
+			return typeinfo.TimeType, layout
+		case !hasTime:
+			return typeinfo.DateType, layout
+		default:
+			return typeinfo.DatetimeType, layout
+		}
+	}
+
+	return typeinfo.UnknownType, ""
+}
+
 func chronoTypes() []typeinfo.TypeInfo {
 	return []typeinfo.TypeInfo{
 		// chrono types YEAR, DATE, and TIME can also be parsed as DATETIME
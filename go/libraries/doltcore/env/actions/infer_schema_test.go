@@ -17,11 +17,14 @@ package actions
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -119,6 +122,30 @@ func TestLeasPermissiveChronoType(t *testing.T) {
 	}
 }
 
+func TestChronoTypeForLayouts(t *testing.T) {
+	tests := []struct {
+		name      string
+		valStr    string
+		expType   typeinfo.TypeInfo
+		expLayout string
+	}{
+		{"us date", "3/15/2021", typeinfo.DateType, "1/2/2006"},
+		{"slash date", "2021/03/15", typeinfo.DateType, "2006/01/02"},
+		{"iso datetime without zone", "2021-03-15T10:04:05", typeinfo.DatetimeType, "2006-01-02T15:04:05"},
+		{"iso datetime with fractional seconds", "2021-03-15T10:04:05.123", typeinfo.DatetimeType, "2006-01-02T15:04:05"},
+		{"twelve hour time", "10:30 PM", typeinfo.TimeType, "3:04 PM"},
+		{"no layout", "15th of March", typeinfo.UnknownType, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actualType, layout := chronoTypeForLayouts(test.valStr, CommonDateLayouts)
+			assert.Equal(t, test.expType, actualType, "val: %s, expected: %v, actual: %v", test.valStr, test.expType, actualType)
+			assert.Equal(t, test.expLayout, layout)
+		})
+	}
+}
+
 type commonTypeTest struct {
 	name     string
 	inferSet typeInfoSet
@@ -355,11 +382,39 @@ var floatsWithTinyFractionalPortion = `uuid,float
 00000000-0000-0000-0000-000000000001,-1.0005
 00000000-0000-0000-0000-000000000002,1.0001`
 
+// csvWithRows returns a csv with the header |header|, and a row for each line returned by |line|
+func csvWithRows(header string, rows int, line func(i int) string) string {
+	csvStr := header
+	for i := 0; i < rows; i++ {
+		csvStr += "\n" + line(i)
+	}
+	return csvStr
+}
+
+var enumsAndDecimals = csvWithRows("id,color,price,measure,name", 40, func(i int) string {
+	colors := []string{"red", "green", "blue"}
+	return fmt.Sprintf("%d,%s,%d.%02d,%d.%d,name%d", i, colors[i%3], i*3, i, i, i%10, i)
+})
+
+var otherDateLayouts = `id,us_date,iso_datetime,clock
+1,3/15/2021,2021-03-15T10:04:05,10:30 PM
+2,12/1/2020,2020-12-01T23:59:59,9:15 AM
+3,1/31/1999,1999-01-31T00:00:01,12:00 PM`
+
+var laterRowsAreText = csvWithRows("id,num", 10, func(i int) string {
+	if i < 5 {
+		return fmt.Sprintf("%d,%d", i, i)
+	}
+	return fmt.Sprintf("%d,text%d", i, i)
+})
+
 var identityMapper = make(rowconv.NameMapper)
 
 type testInferenceArgs struct {
 	ColMapper      rowconv.NameMapper
 	floatThreshold float64
+	sampleRows     int
+	dateLayouts    []string
 }
 
 func (tia testInferenceArgs) ColNameMapper() rowconv.NameMapper {
@@ -370,6 +425,14 @@ func (tia testInferenceArgs) FloatThreshold() float64 {
 	return tia.floatThreshold
 }
 
+func (tia testInferenceArgs) SampleRows() int {
+	return tia.sampleRows
+}
+
+func (tia testInferenceArgs) DateLayouts() []string {
+	return tia.dateLayouts
+}
+
 func TestInferSchema(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -462,6 +525,114 @@ func TestInferSchema(t *testing.T) {
 		},
 	}
 
+	colorEnum, err := typeinfo.FromSqlType(sql.MustCreateEnumType([]string{"blue", "green", "red"}, sql.Collation_Default))
+	require.NoError(t, err)
+	priceDecimal, err := typeinfo.FromSqlType(sql.MustCreateDecimalType(5, 2))
+	require.NoError(t, err)
+	sampledPriceDecimal, err := typeinfo.FromSqlType(sql.MustCreateDecimalType(4, 2))
+	require.NoError(t, err)
+
+	enumAndDecimalTests := []struct {
+		name         string
+		csvContents  string
+		infArgs      InferenceArgs
+		expTypes     map[string]typeinfo.TypeInfo
+		nullableCols *set.StrSet
+	}{
+		{
+			"enums and decimals",
+			enumsAndDecimals,
+			testInferenceArgs{
+				ColMapper:      identityMapper,
+				floatThreshold: 0,
+			},
+			map[string]typeinfo.TypeInfo{
+				"id":      typeinfo.Uint32Type,
+				"color":   colorEnum,
+				"price":   priceDecimal,
+				"measure": typeinfo.Float32Type,
+				"name":    typeinfo.StringDefaultType,
+			},
+			nil,
+		},
+		{
+			"enums with too few sampled rows",
+			enumsAndDecimals,
+			testInferenceArgs{
+				ColMapper:      identityMapper,
+				floatThreshold: 0,
+				sampleRows:     10,
+			},
+			map[string]typeinfo.TypeInfo{
+				"id":      typeinfo.Uint32Type,
+				"color":   typeinfo.StringDefaultType,
+				"price":   sampledPriceDecimal,
+				"measure": typeinfo.Float32Type,
+				"name":    typeinfo.StringDefaultType,
+			},
+			nil,
+		},
+		{
+			"decimals with a float threshold",
+			enumsAndDecimals,
+			testInferenceArgs{
+				ColMapper:      identityMapper,
+				floatThreshold: 0.001,
+			},
+			map[string]typeinfo.TypeInfo{
+				"id":      typeinfo.Uint32Type,
+				"color":   colorEnum,
+				"price":   typeinfo.Float32Type,
+				"measure": typeinfo.Float32Type,
+				"name":    typeinfo.StringDefaultType,
+			},
+			nil,
+		},
+		{
+			"other date layouts",
+			otherDateLayouts,
+			testInferenceArgs{
+				ColMapper:   identityMapper,
+				dateLayouts: CommonDateLayouts,
+			},
+			map[string]typeinfo.TypeInfo{
+				"id":           typeinfo.Uint32Type,
+				"us_date":      typeinfo.DateType,
+				"iso_datetime": typeinfo.DatetimeType,
+				"clock":        typeinfo.TimeType,
+			},
+			nil,
+		},
+		{
+			"other date layouts without date layouts",
+			otherDateLayouts,
+			testInferenceArgs{
+				ColMapper: identityMapper,
+			},
+			map[string]typeinfo.TypeInfo{
+				"id":           typeinfo.Uint32Type,
+				"us_date":      typeinfo.StringDefaultType,
+				"iso_datetime": typeinfo.StringDefaultType,
+				"clock":        typeinfo.StringDefaultType,
+			},
+			nil,
+		},
+		{
+			"only the sampled rows",
+			laterRowsAreText,
+			testInferenceArgs{
+				ColMapper:  identityMapper,
+				sampleRows: 5,
+			},
+			map[string]typeinfo.TypeInfo{
+				"id":  typeinfo.Uint32Type,
+				"num": typeinfo.Uint32Type,
+			},
+			nil,
+		},
+	}
+	tests = append(tests, enumAndDecimalTests...)
+
 	const importFilePath = "/Users/home/datasets/test/import_file.csv"
 
 	for _, test := range tests {
@@ -509,3 +680,26 @@ func TestInferSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestInferenceReport(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	csvStr := enumsAndDecimals + "\n40,red,1.00,,name40"
+	rd, err := csv.NewCSVReader(types.Format_Default, ioutil.NopCloser(strings.NewReader(csvStr)), csv.NewCSVInfo())
+	require.NoError(t, err)
+
+	_, report, err := InferColumnTypesWithReport(ctx, root, rd, testInferenceArgs{ColMapper: identityMapper})
+	require.NoError(t, err)
+
+	assert.Equal(t, 41, report.RowsSampled)
+	assert.Equal(t, map[string]string{
+		"id":      "integers, none of which are negative",
+		"color":   "3 distinct values in 41 rows",
+		"price":   "every number has 2 digits after the decimal point",
+		"measure": "numbers with fractional parts, with 1 digit after the decimal point; some values are empty, so it's nullable",
+		"name":    "text with too many distinct values to be an enum",
+	}, report.Reasons)
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/libraries/utils/valutil"
 	"github.com/dolthub/dolt/go/store/atomicerr"
@@ -36,16 +37,31 @@ import (
 var ErrFastForward = errors.New("fast forward")
 var ErrSameTblAddedTwice = errors.New("table with same name added in 2 commits can't be merged")
 
+// CheckEvaluator creates the RowCheckers that merged rows are checked with. Check constraints are SQL expressions,
+// which are evaluated by the caller of a merge rather than by the merge itself.
+type CheckEvaluator interface {
+	// NewRowChecker returns a RowChecker for the enforced check constraints of the table |tblName| with the schema |sch|.
+	NewRowChecker(tblName string, sch schema.Schema) (RowChecker, error)
+}
+
+// RowChecker checks rows against the check constraints of a table.
+type RowChecker interface {
+	// ViolatesChecks returns whether |r| violates any of the check constraints of the table.
+	ViolatesChecks(ctx context.Context, r row.Row) (bool, error)
+}
+
 type Merger struct {
 	root      *doltdb.RootValue
 	mergeRoot *doltdb.RootValue
 	ancRoot   *doltdb.RootValue
 	vrw       types.ValueReadWriter
+	checks    CheckEvaluator
 }
 
-// NewMerger creates a new merger utility object.
-func NewMerger(ctx context.Context, root, mergeRoot, ancRoot *doltdb.RootValue, vrw types.ValueReadWriter) *Merger {
-	return &Merger{root, mergeRoot, ancRoot, vrw}
+// NewMerger creates a new merger utility object. Merged rows are checked against the check constraints of their
+// tables with |checks|, unless it's nil.
+func NewMerger(ctx context.Context, root, mergeRoot, ancRoot *doltdb.RootValue, vrw types.ValueReadWriter, checks CheckEvaluator) *Merger {
+	return &Merger{root, mergeRoot, ancRoot, vrw, checks}
 }
 
 // MergeTable merges schema and table data for the table tblName.
@@ -223,9 +239,67 @@ func (merger *Merger) MergeTable(ctx context.Context, tblName string, sess *edit
 		return nil, nil, err
 	}
 
+	if merger.checks != nil && postMergeSchema.Checks().Count() > 0 {
+		resultRows, err := resultTbl.GetRowData(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		checker, err := merger.checks.NewRowChecker(tblName, postMergeSchema)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		stats.CheckViolations, err = countCheckViolations(ctx, checker, postMergeSchema, rows, resultRows)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return resultTbl, stats, nil
 }
 
+// countCheckViolations returns the number of rows that the merge added to or changed in |rows| that |checker| finds
+// in violation of the check constraints of their table. Both sides of a merge can satisfy a check while the rows that
+// combine them don't, so merged rows are checked again.
+func countCheckViolations(ctx context.Context, checker RowChecker, sch schema.Schema, rows, resultRows types.Map) (int, error) {
+	changeChan := make(chan types.ValueChanged, 32)
+
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		defer close(changeChan)
+		return resultRows.Diff(ctx, rows, changeChan)
+	})
+
+	var violations int
+	eg.Go(func() error {
+		for change := range changeChan {
+			if change.ChangeType == types.DiffChangeRemoved {
+				continue
+			}
+
+			r, err := row.FromNoms(sch, change.Key.(types.Tuple), change.NewValue.(types.Tuple))
+			if err != nil {
+				return err
+			}
+
+			violated, err := checker.ViolatesChecks(ctx, r)
+			if err != nil {
+				return err
+			} else if violated {
+				violations++
+			}
+		}
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil {
+		return 0, err
+	}
+
+	return violations, nil
+}
+
 func calcTableMergeStats(ctx context.Context, tbl *doltdb.Table, mergeTbl *doltdb.Table) (MergeStats, error) {
 	rows, err := tbl.GetRowData(ctx)
 
@@ -640,6 +714,9 @@ func pkRowMerge(ctx context.Context, nbf *types.NomsBinFormat, sch schema.Schema
 			mergeModified := !valutil.NilSafeEqCheck(mergeVal, baseVal)
 			switch {
 			case modified && mergeModified:
+				if col, ok := sch.GetNonPKCols().GetByTag(tag); ok && col.TypeInfo.GetTypeIdentifier() == typeinfo.JSONTypeIdentifier {
+					return mergeJSONValues(baseVal, val, mergeVal)
+				}
 				return nil, true
 			case modified:
 				return val, false
@@ -720,7 +797,8 @@ func mergeAutoIncrementValues(ctx context.Context, tbl, otherTbl, resultTbl *dol
 	return resultTbl.SetAutoIncrementValue(autoVal)
 }
 
-func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit) (*doltdb.RootValue, map[string]*MergeStats, error) {
+// MergeCommits merges |mergeCommit| into |commit|. Merged rows are checked with |checks| if it's not nil.
+func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit, checks CheckEvaluator) (*doltdb.RootValue, map[string]*MergeStats, error) {
 	ancCommit, err := doltdb.GetCommitAncestor(ctx, commit, mergeCommit)
 
 	if err != nil {
@@ -745,11 +823,13 @@ func MergeCommits(ctx context.Context, commit, mergeCommit *doltdb.Commit) (*dol
 		return nil, nil, err
 	}
 
-	return MergeRoots(ctx, ourRoot, theirRoot, ancRoot)
+	return MergeRoots(ctx, ourRoot, theirRoot, ancRoot, checks)
 }
 
-func MergeRoots(ctx context.Context, ourRoot, theirRoot, ancRoot *doltdb.RootValue) (*doltdb.RootValue, map[string]*MergeStats, error) {
-	merger := NewMerger(ctx, ourRoot, theirRoot, ancRoot, ourRoot.VRW())
+// MergeRoots merges |theirRoot| into |ourRoot|, with |ancRoot| as their common ancestor. Merged rows are checked with
+// |checks| if it's not nil.
+func MergeRoots(ctx context.Context, ourRoot, theirRoot, ancRoot *doltdb.RootValue, checks CheckEvaluator) (*doltdb.RootValue, map[string]*MergeStats, error) {
+	merger := NewMerger(ctx, ourRoot, theirRoot, ancRoot, ourRoot.VRW(), checks)
 
 	tblNames, err := doltdb.UnionTableNames(ctx, ourRoot, theirRoot)
 
@@ -0,0 +1,123 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"reflect"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// jsonMissing stands in for an object member that doesn't exist in one of the documents being merged
+type jsonMissing struct{}
+
+// mergeJSONValues merges two JSON documents that were both modified from a common ancestor. Changes to different
+// members of an object are merged recursively, so that only changes to the same member conflict. Returns true if the
+// documents cannot be merged.
+func mergeJSONValues(base, ours, theirs types.Value) (types.Value, bool) {
+	baseStr, ok := base.(types.String)
+	if !ok {
+		return nil, true
+	}
+	oursStr, ok := ours.(types.String)
+	if !ok {
+		return nil, true
+	}
+	theirsStr, ok := theirs.(types.String)
+	if !ok {
+		return nil, true
+	}
+
+	baseDoc, err := typeinfo.UnmarshalJSONValue(baseStr)
+	if err != nil {
+		return nil, true
+	}
+	oursDoc, err := typeinfo.UnmarshalJSONValue(oursStr)
+	if err != nil {
+		return nil, true
+	}
+	theirsDoc, err := typeinfo.UnmarshalJSONValue(theirsStr)
+	if err != nil {
+		return nil, true
+	}
+
+	merged, isConflict := mergeJSONDocs(baseDoc, oursDoc, theirsDoc)
+	if isConflict {
+		return nil, true
+	}
+
+	mergedVal, err := typeinfo.MarshalJSONValue(merged)
+	if err != nil {
+		return nil, true
+	}
+
+	return mergedVal, false
+}
+
+func mergeJSONDocs(base, ours, theirs interface{}) (interface{}, bool) {
+	switch {
+	case reflect.DeepEqual(ours, theirs):
+		return ours, false
+	case reflect.DeepEqual(ours, base):
+		return theirs, false
+	case reflect.DeepEqual(theirs, base):
+		return ours, false
+	}
+
+	baseObj, ok := base.(map[string]interface{})
+	if !ok {
+		return nil, true
+	}
+	oursObj, ok := ours.(map[string]interface{})
+	if !ok {
+		return nil, true
+	}
+	theirsObj, ok := theirs.(map[string]interface{})
+	if !ok {
+		return nil, true
+	}
+
+	merged := make(map[string]interface{})
+	for _, obj := range []map[string]interface{}{baseObj, oursObj, theirsObj} {
+		for key := range obj {
+			if _, ok := merged[key]; ok {
+				continue
+			}
+
+			val, isConflict := mergeJSONDocs(jsonMember(baseObj, key), jsonMember(oursObj, key), jsonMember(theirsObj, key))
+			if isConflict {
+				return nil, true
+			}
+
+			merged[key] = val
+		}
+	}
+
+	for key, val := range merged {
+		if _, ok := val.(jsonMissing); ok {
+			delete(merged, key)
+		}
+	}
+
+	return merged, false
+}
+
+func jsonMember(obj map[string]interface{}, key string) interface{} {
+	if val, ok := obj[key]; ok {
+		return val
+	}
+	return jsonMissing{}
+}
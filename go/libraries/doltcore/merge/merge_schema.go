@@ -31,15 +31,16 @@ const (
 )
 
 type SchemaConflict struct {
-	TableName    string
-	ColConflicts []ColConflict
-	IdxConflicts []IdxConflict
+	TableName      string
+	ColConflicts   []ColConflict
+	IdxConflicts   []IdxConflict
+	CheckConflicts []CheckConflict
 }
 
 var EmptySchConflicts = SchemaConflict{}
 
 func (sc SchemaConflict) Count() int {
-	return len(sc.ColConflicts) + len(sc.IdxConflicts)
+	return len(sc.ColConflicts) + len(sc.IdxConflicts) + len(sc.CheckConflicts)
 }
 
 func (sc SchemaConflict) AsError() error {
@@ -51,6 +52,9 @@ func (sc SchemaConflict) AsError() error {
 	for _, c := range sc.IdxConflicts {
 		b.WriteString(fmt.Sprintf("\t%s\n", c.String()))
 	}
+	for _, c := range sc.CheckConflicts {
+		b.WriteString(fmt.Sprintf("\t%s\n", c.String()))
+	}
 	return fmt.Errorf(b.String())
 }
 
@@ -78,6 +82,15 @@ func (c IdxConflict) String() string {
 	return ""
 }
 
+type CheckConflict struct {
+	Kind         conflictKind
+	Ours, Theirs schema.Check
+}
+
+func (c CheckConflict) String() string {
+	return fmt.Sprintf("different definitions for our check constraint %s and their check constraint %s", c.Ours.Name(), c.Theirs.Name())
+}
+
 type FKConflict struct {
 	Kind         conflictKind
 	Ours, Theirs doltdb.ForeignKey
@@ -115,6 +128,11 @@ func SchemaMerge(ourSch, theirSch, ancSch schema.Schema, tblName string) (sch sc
 		return false, nil
 	})
 
+	sc.CheckConflicts = mergeChecks(sch.Checks(), ourSch.Checks(), theirSch.Checks(), ancSch.Checks())
+	if len(sc.CheckConflicts) > 0 {
+		return nil, sc, nil
+	}
+
 	return sch, sc, nil
 }
 
@@ -317,6 +335,51 @@ func mergeIndexes(mergedCC *schema.ColCollection, ourSch, theirSch, ancSch schem
 	return merged, conflicts
 }
 
+// mergeChecks adds the check constraints of a three-way merge of ours, theirs and anc to merged. Check constraints are
+// matched by name, and a constraint that was dropped on either branch is dropped from the result.
+func mergeChecks(merged, ours, theirs, anc schema.CheckCollection) (conflicts []CheckConflict) {
+	for _, ourCheck := range ours.AllChecks() {
+		theirCheck, inTheirs := theirs.GetByNameCaseInsensitive(ourCheck.Name())
+		ancCheck, inAnc := anc.GetByNameCaseInsensitive(ourCheck.Name())
+
+		switch {
+		case inTheirs && checksAreEqual(ourCheck, theirCheck):
+		case inTheirs && inAnc && checksAreEqual(ourCheck, ancCheck):
+			// modified on their branch only
+			ourCheck = theirCheck
+		case inTheirs && inAnc && checksAreEqual(theirCheck, ancCheck):
+			// modified on our branch only
+		case inTheirs:
+			conflicts = append(conflicts, CheckConflict{
+				Kind:   NameCollision,
+				Ours:   ourCheck,
+				Theirs: theirCheck,
+			})
+			continue
+		case inAnc:
+			// dropped on their branch
+			continue
+		}
+
+		_, _ = merged.AddCheck(ourCheck.Name(), ourCheck.Expression(), ourCheck.Enforced())
+	}
+
+	for _, theirCheck := range theirs.AllChecks() {
+		_, inOurs := ours.GetByNameCaseInsensitive(theirCheck.Name())
+		_, inAnc := anc.GetByNameCaseInsensitive(theirCheck.Name())
+		if !inOurs && !inAnc {
+			// added on their branch
+			_, _ = merged.AddCheck(theirCheck.Name(), theirCheck.Expression(), theirCheck.Enforced())
+		}
+	}
+
+	return conflicts
+}
+
+func checksAreEqual(c1, c2 schema.Check) bool {
+	return c1.Expression() == c2.Expression() && c1.Enforced() == c2.Enforced()
+}
+
 func indexesInCommon(mergedCC *schema.ColCollection, ours, theirs, anc schema.IndexCollection) (common schema.IndexCollection, conflicts []IdxConflict) {
 	common = schema.NewIndexCollection(mergedCC)
 	_ = ours.Iter(func(ourIdx schema.Index) (stop bool, err error) {
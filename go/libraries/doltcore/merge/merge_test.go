@@ -25,6 +25,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
@@ -418,7 +419,7 @@ func TestMergeCommits(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, ff)
 
-	merger := NewMerger(context.Background(), root, mergeRoot, ancRoot, vrw)
+	merger := NewMerger(context.Background(), root, mergeRoot, ancRoot, vrw, nil)
 	tableEditSession := editor.CreateTableEditSession(root, editor.TableEditSessionProps{})
 	merged, stats, err := merger.MergeTable(context.Background(), tableName, tableEditSession)
 
@@ -466,3 +467,163 @@ func TestMergeCommits(t *testing.T) {
 		assert.Fail(t, "%v and %v do not equal", h, eh)
 	}
 }
+
+func TestMergeJSONValues(t *testing.T) {
+	tests := []struct {
+		name           string
+		base           types.Value
+		ours           types.Value
+		theirs         types.Value
+		expected       types.Value
+		expectConflict bool
+	}{
+		{
+			"changes to different members",
+			types.String(`{"a":1,"b":{"c":2,"d":3}}`),
+			types.String(`{"a":10,"b":{"c":2,"d":3}}`),
+			types.String(`{"a":1,"b":{"c":2,"d":30},"e":4}`),
+			types.String(`{"a":10,"b":{"c":2,"d":30},"e":4}`),
+			false,
+		},
+		{
+			"member removed on one side",
+			types.String(`{"a":1,"b":2}`),
+			types.String(`{"a":1}`),
+			types.String(`{"a":1,"b":2,"c":3}`),
+			types.String(`{"a":1,"c":3}`),
+			false,
+		},
+		{
+			"changes to the same member",
+			types.String(`{"a":1,"b":2}`),
+			types.String(`{"a":10,"b":2}`),
+			types.String(`{"a":20,"b":2}`),
+			nil,
+			true,
+		},
+		{
+			"changes to an array",
+			types.String(`[1,2]`),
+			types.String(`[1,2,3]`),
+			types.String(`[0,1,2]`),
+			nil,
+			true,
+		},
+		{
+			"document removed on one side",
+			types.String(`{"a":1}`),
+			types.NullValue,
+			types.String(`{"a":2}`),
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged, isConflict := mergeJSONValues(test.base, test.ours, test.theirs)
+			assert.Equal(t, test.expectConflict, isConflict)
+			assert.Equal(t, test.expected, merged)
+		})
+	}
+}
+
+func TestMergeChecks(t *testing.T) {
+	newChecks := func(namesAndExprs ...string) schema.CheckCollection {
+		checks := schema.NewCheckCollection()
+		for i := 0; i < len(namesAndExprs); i += 2 {
+			_, err := checks.AddCheck(namesAndExprs[i], namesAndExprs[i+1], true)
+			require.NoError(t, err)
+		}
+		return checks
+	}
+
+	tests := []struct {
+		name              string
+		ours, theirs, anc schema.CheckCollection
+		expected          schema.CheckCollection
+		conflicts         int
+	}{
+		{
+			"unchanged",
+			newChecks("a", "x > 0"), newChecks("a", "x > 0"), newChecks("a", "x > 0"),
+			newChecks("a", "x > 0"), 0,
+		},
+		{
+			"added on both branches",
+			newChecks("a", "x > 0", "b", "y > 0"), newChecks("a", "x > 0", "c", "z > 0"), newChecks("a", "x > 0"),
+			newChecks("a", "x > 0", "b", "y > 0", "c", "z > 0"), 0,
+		},
+		{
+			"dropped on their branch",
+			newChecks("a", "x > 0", "b", "y > 0"), newChecks("b", "y > 0"), newChecks("a", "x > 0", "b", "y > 0"),
+			newChecks("b", "y > 0"), 0,
+		},
+		{
+			"modified on their branch",
+			newChecks("a", "x > 0"), newChecks("a", "x > 1"), newChecks("a", "x > 0"),
+			newChecks("a", "x > 1"), 0,
+		},
+		{
+			"modified on both branches",
+			newChecks("a", "x > 2"), newChecks("a", "x > 1"), newChecks("a", "x > 0"),
+			nil, 1,
+		},
+		{
+			"added on both branches with different expressions",
+			newChecks("a", "x > 2"), newChecks("a", "x > 1"), newChecks(),
+			nil, 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := schema.NewCheckCollection()
+			conflicts := mergeChecks(merged, test.ours, test.theirs, test.anc)
+			assert.Len(t, conflicts, test.conflicts)
+			if test.expected != nil {
+				assert.True(t, test.expected.Equals(merged))
+			}
+		})
+	}
+}
+
+func TestCountCheckViolations(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	sch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true, schema.NotNullConstraint{}),
+		schema.NewColumn("x", 1, types.IntKind, false),
+		schema.NewColumn("y", 2, types.IntKind, false),
+	))
+	_, err := sch.Checks().AddCheck("chk_sum", "x + y < 10", true)
+	require.NoError(t, err)
+
+	newRows := func(vals ...int64) types.Map {
+		var kvs []types.Value
+		for i := 0; i < len(vals); i += 3 {
+			k := mustTuple(types.NewTuple(vrw.Format(), types.Uint(0), types.Int(vals[i])))
+			v := mustTuple(types.NewTuple(vrw.Format(), types.Uint(1), types.Int(vals[i+1]), types.Uint(2), types.Int(vals[i+2])))
+			kvs = append(kvs, k, v)
+		}
+		m, err := types.NewMap(ctx, vrw, kvs...)
+		require.NoError(t, err)
+		return m
+	}
+
+	// row 1 was already in violation before the merge, so only the merged rows 2 and 3 are counted
+	rows := newRows(1, 9, 9, 2, 1, 1)
+	resultRows := newRows(1, 9, 9, 2, 5, 5, 3, 9, 1, 4, 1, 1)
+	violations, err := countCheckViolations(ctx, sumChecker{}, sch, rows, resultRows)
+	require.NoError(t, err)
+	assert.Equal(t, 2, violations)
+}
+
+// sumChecker is a RowChecker for the check x + y < 10 of the schema in TestCountCheckViolations
+type sumChecker struct{}
+
+func (sumChecker) ViolatesChecks(ctx context.Context, r row.Row) (bool, error) {
+	x, _ := r.GetColVal(1)
+	y, _ := r.GetColVal(2)
+	return int64(x.(types.Int))+int64(y.(types.Int)) >= 10, nil
+}
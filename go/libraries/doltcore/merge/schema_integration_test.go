@@ -566,7 +566,7 @@ func testMergeForeignKeys(t *testing.T, test mergeForeignKeyTest) {
 	otherRoot, err := dEnv.WorkingRoot(ctx)
 	require.NoError(t, err)
 
-	mergedRoot, _, err := merge.MergeRoots(ctx, masterRoot, otherRoot, ancRoot)
+	mergedRoot, _, err := merge.MergeRoots(ctx, masterRoot, otherRoot, ancRoot, nil)
 	assert.NoError(t, err)
 
 	fkc, err := mergedRoot.GetForeignKeyCollection(ctx)
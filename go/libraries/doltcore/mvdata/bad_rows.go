@@ -0,0 +1,220 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// BadRowClass is the class of error that caused a row to fail to be moved
+type BadRowClass string
+
+const (
+	TypeConversionBadRow BadRowClass = "type conversion"
+	NullValueBadRow      BadRowClass = "null in a non-null column"
+	DuplicateKeyBadRow   BadRowClass = "duplicate key"
+	ForeignKeyBadRow     BadRowClass = "foreign key violation"
+	ConstraintBadRow     BadRowClass = "constraint failure"
+	MalformedBadRow      BadRowClass = "malformed input"
+	OtherBadRow          BadRowClass = "other"
+)
+
+// BadRowClasses are all of the classes of bad rows, in the order they're reported
+var BadRowClasses = []BadRowClass{
+	TypeConversionBadRow,
+	NullValueBadRow,
+	DuplicateKeyBadRow,
+	ForeignKeyBadRow,
+	ConstraintBadRow,
+	MalformedBadRow,
+	OtherBadRow,
+}
+
+// ClassifyBadRow returns the class of the error that caused the row of |trf| to fail to be moved.
+func ClassifyBadRow(trf *pipeline.TransformRowFailure) BadRowClass {
+	details := strings.ToLower(trf.Details)
+
+	switch {
+	case strings.Contains(details, "duplicate primary key") || strings.Contains(details, "duplicate unique key"):
+		return DuplicateKeyBadRow
+	case strings.Contains(details, "foreign key"):
+		return ForeignKeyBadRow
+	case strings.Contains(details, "not null constraint") || strings.Contains(details, "non-nullable"):
+		return NullValueBadRow
+	case strings.Contains(details, "constraint"):
+		return ConstraintBadRow
+	case trf.TransformName == "reader":
+		return MalformedBadRow
+	case trf.TransformName != "writer":
+		// the transforms of a move convert the values of the rows being moved to the types of the destination
+		return TypeConversionBadRow
+	}
+
+	return OtherBadRow
+}
+
+// BadRowReport records the rows that fail to be moved when a DataMover continues on errors. It counts the rows by the
+// class of their error, and writes them to a file if a BadRowWriter is given.
+type BadRowReport struct {
+	Counts map[BadRowClass]int64
+	wr     *BadRowWriter
+}
+
+// NewBadRowReport returns a BadRowReport which writes the bad rows to |wr|, which may be nil.
+func NewBadRowReport(wr *BadRowWriter) *BadRowReport {
+	return &BadRowReport{Counts: make(map[BadRowClass]int64), wr: wr}
+}
+
+// WritesRows returns true if the report writes the bad rows to a file
+func (rep *BadRowReport) WritesRows() bool {
+	return rep.wr != nil
+}
+
+// Add records the bad row of |trf|
+func (rep *BadRowReport) Add(ctx context.Context, trf *pipeline.TransformRowFailure) error {
+	rep.Counts[ClassifyBadRow(trf)]++
+
+	if rep.wr != nil {
+		return rep.wr.WriteBadRow(ctx, trf)
+	}
+
+	return nil
+}
+
+// Close closes the file the bad rows are written to
+func (rep *BadRowReport) Close(ctx context.Context) error {
+	if rep.wr != nil {
+		return rep.wr.Close(ctx)
+	}
+
+	return nil
+}
+
+// BadRowWriter writes the input records of rows that fail to be moved to a csv or jsonl file. Each record is written
+// unchanged, along with the line of the input that it was read from and the error that caused it to fail.
+type BadRowWriter struct {
+	wr      table.TableWriteCloser
+	sch     schema.Schema
+	nbf     *types.NomsBinFormat
+	srcTags map[uint64]uint64
+	lineTag uint64
+	errTag  uint64
+}
+
+// NewBadRowWriter creates the file at |path| in |fs|, and returns a BadRowWriter which writes the bad records of the
+// rows of |srcSch| to it. The format of the file is given by its extension, and must be csv or jsonl.
+func NewBadRowWriter(nbf *types.NomsBinFormat, path string, fs filesys.WritableFS, srcSch schema.Schema) (*BadRowWriter, error) {
+	srcCols := srcSch.GetAllCols()
+	lineCol := schema.NewColumn(uniqueColName("line", srcCols), 0, types.IntKind, false)
+	errCol := schema.NewColumn(uniqueColName("error", srcCols), 1, types.StringKind, false)
+
+	cols := []schema.Column{lineCol, errCol}
+	srcTags := make(map[uint64]uint64, srcCols.Size())
+	_ = srcCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		newTag := uint64(len(cols))
+		srcTags[tag] = newTag
+
+		col.Tag = newTag
+		cols = append(cols, col)
+		return false, nil
+	})
+
+	sch := schema.UnkeyedSchemaFromCols(schema.NewColCollection(cols...))
+
+	var wr table.TableWriteCloser
+	var err error
+	switch NewDataLocation(path, "").(FileDataLocation).Format {
+	case CsvFile:
+		wr, err = csv.OpenCSVWriter(path, fs, sch, csv.NewCSVInfo())
+	case JsonlFile:
+		wr, err = json.OpenJSONLWriter(path, fs, sch)
+	default:
+		return nil, fmt.Errorf("the bad rows file '%s' must be a .csv or .jsonl file", path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &BadRowWriter{wr: wr, sch: sch, nbf: nbf, srcTags: srcTags, lineTag: lineCol.Tag, errTag: errCol.Tag}, nil
+}
+
+// uniqueColName returns |name|, prefixed with underscores until it doesn't match the name of one of |cols|
+func uniqueColName(name string, cols *schema.ColCollection) string {
+	for {
+		if _, ok := cols.GetByNameCaseInsensitive(name); !ok {
+			return name
+		}
+		name = "_" + name
+	}
+}
+
+// WriteBadRow writes the input record of the row of |trf|. The record is the row as it was read, which is held by the
+// pipeline.SourceRowProp property of the row. Records which couldn't be read are written with only their line number
+// and error.
+func (w *BadRowWriter) WriteBadRow(ctx context.Context, trf *pipeline.TransformRowFailure) error {
+	taggedVals := row.TaggedValues{w.errTag: types.String(badRowError(trf))}
+
+	if trf.Props != nil {
+		if lineNum, ok := trf.Props.Get(pipeline.LineNumProp); ok {
+			taggedVals[w.lineTag] = types.Int(lineNum.(int))
+		}
+
+		if srcRow, ok := trf.Props.Get(pipeline.SourceRowProp); ok && srcRow != nil {
+			_, err := srcRow.(row.Row).IterCols(func(tag uint64, val types.Value) (stop bool, err error) {
+				if newTag, ok := w.srcTags[tag]; ok && !types.IsNull(val) {
+					taggedVals[newTag] = val
+				}
+				return false, nil
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	r, err := row.New(w.nbf, w.sch, taggedVals)
+	if err != nil {
+		return err
+	}
+
+	return w.wr.WriteRow(ctx, r)
+}
+
+// badRowError returns the description of the error of |trf| written to the bad rows file
+func badRowError(trf *pipeline.TransformRowFailure) string {
+	details := strings.Join(strings.Fields(trf.Details), " ")
+	if trf.TransformName == "reader" || trf.TransformName == "writer" {
+		return details
+	}
+	return trf.TransformName + ": " + details
+}
+
+// Close closes the bad rows file
+func (w *BadRowWriter) Close(ctx context.Context) error {
+	return w.wr.Close(ctx)
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestClassifyBadRow(t *testing.T) {
+	tests := []struct {
+		transformName string
+		details       string
+		expected      BadRowClass
+	}{
+		{"Mapping transform", `unable to cast "abc" of type string to int64`, TypeConversionBadRow},
+		{"Mapping transform", "invalid column: name (Not null constraint)", NullValueBadRow},
+		{"Mapping transform", "invalid column: name (Positive constraint)", ConstraintBadRow},
+		{"writer", "duplicate primary key given: (1)", DuplicateKeyBadRow},
+		{"writer", "foreign key violation on `child`.`fk`: `(1)`", ForeignKeyBadRow},
+		{"reader", "csv reader's schema expects 3 fields, but line only has 2 values.", MalformedBadRow},
+		{"writer", "something else", OtherBadRow},
+	}
+
+	for _, test := range tests {
+		t.Run(test.details, func(t *testing.T) {
+			trf := &pipeline.TransformRowFailure{TransformName: test.transformName, Details: test.details}
+			assert.Equal(t, test.expected, ClassifyBadRow(trf))
+		})
+	}
+}
+
+func TestBadRowReport(t *testing.T) {
+	ctx := context.Background()
+	fs := filesys.EmptyInMemFS("/")
+
+	_, srcSch := untyped.NewUntypedSchema("id", "error")
+	srcRow, err := untyped.NewRowFromStrings(types.Format_Default, srcSch, []string{"1", "oops"})
+	require.NoError(t, err)
+
+	wr, err := NewBadRowWriter(types.Format_Default, "bad.csv", fs, srcSch)
+	require.NoError(t, err)
+	report := NewBadRowReport(wr)
+	assert.True(t, report.WritesRows())
+
+	srcProps := pipeline.NoProps.Set(map[string]interface{}{pipeline.SourceRowProp: srcRow, pipeline.LineNumProp: 2})
+	require.NoError(t, report.Add(ctx, &pipeline.TransformRowFailure{Row: srcRow, TransformName: "Mapping transform", Details: "unable to cast\n\"oops\"", Props: srcProps}))
+
+	readerProps := pipeline.NoProps.Set(map[string]interface{}{pipeline.SourceRowProp: nil, pipeline.LineNumProp: 3})
+	require.NoError(t, report.Add(ctx, &pipeline.TransformRowFailure{TransformName: "reader", Details: "bad line", Props: readerProps}))
+	require.NoError(t, report.Add(ctx, &pipeline.TransformRowFailure{TransformName: "reader", Details: "bad line", Props: pipeline.NoProps}))
+	require.NoError(t, report.Close(ctx))
+
+	assert.Equal(t, map[BadRowClass]int64{TypeConversionBadRow: 1, MalformedBadRow: 2}, report.Counts)
+
+	data, err := fs.ReadFile("bad.csv")
+	require.NoError(t, err)
+	expected := "line,_error,id,error\n" +
+		"2,\"Mapping transform: unable to cast \"\"oops\"\"\",1,oops\n" +
+		"3,bad line,,\n" +
+		",bad line,,\n"
+	assert.Equal(t, expected, string(data))
+
+	_, err = NewBadRowWriter(types.Format_Default, "bad.txt", fs, srcSch)
+	assert.Error(t, err)
+}
@@ -0,0 +1,297 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/dolthub/dolt/go/store/types"
+	"github.com/dolthub/dolt/go/store/types/edits"
+)
+
+// bulkSortedRunSize is the number of rows in each of the sorted runs built by a bulk table writer
+const bulkSortedRunSize = 64 * 1024
+
+// SortedRunsWriter is a TableWriteCloser which collects the rows written to it in sorted runs, and builds the table
+// from them once all of the rows are written. Rows with duplicate primary keys can't be found until the runs are
+// merged, so MergeRuns reports them to |badRowCB|. If |badRowCB| returns true, the TransformRowFailure of the
+// duplicate row is returned.
+type SortedRunsWriter interface {
+	table.TableWriteCloser
+	// WriteRowWithProps writes a row like WriteRow, and keeps its properties until the runs are merged, so that a row
+	// with a duplicate primary key is reported with the properties it was read with.
+	WriteRowWithProps(ctx context.Context, r row.Row, props pipeline.ReadableMap) error
+	MergeRuns(ctx context.Context, badRowCB pipeline.BadRowCallback) error
+}
+
+// propsTuple is the value tuple of a row written with WriteRowWithProps, and the properties of the row. It's sorted
+// with the row's key, and unwrapped when the runs are merged.
+type propsTuple struct {
+	types.Tuple
+	props pipeline.ReadableMap
+}
+
+// bulkTableWriter is a SortedRunsWriter which creates a new table. It is safe for concurrent use, and encodes each row
+// on the goroutine which writes it. The sorted runs are merged and streamed into the new table's row data with a
+// noms.NomsMapCreator, which builds the map without any of the reads of a table editor.
+type bulkTableWriter struct {
+	root      *doltdb.RootValue
+	tableName string
+	sch       schema.Schema
+
+	mu       sync.Mutex
+	edits    *edits.AsyncSortedEdits
+	merged   bool
+	mergeErr error
+	rowData  *types.Map
+
+	statsCB noms.StatsCB
+	stats   types.AppliedEditStats
+	statOps int64
+}
+
+var _ DataMoverCloser = (*bulkTableWriter)(nil)
+var _ SortedRunsWriter = (*bulkTableWriter)(nil)
+
+// NewBulkCreatingWriter returns a TableWriteCloser which creates a new table, or overwrites an existing table, like
+// NewCreatingWriter. Its rows may be written by |workers| goroutines at once, and they are sorted in the background as
+// they're written. The schema must have a primary key.
+func (dl TableDataLocation) NewBulkCreatingWriter(_ context.Context, root *doltdb.RootValue, outSch schema.Schema, workers int, statsCB noms.StatsCB) (table.TableWriteCloser, error) {
+	if schema.IsKeyless(outSch) {
+		return nil, ErrNoPK
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	return &bulkTableWriter{
+		root:      root,
+		tableName: dl.Name,
+		sch:       outSch,
+		edits:     edits.NewAsyncSortedEdits(root.VRW().Format(), bulkSortedRunSize, workers, workers),
+		statsCB:   statsCB,
+	}, nil
+}
+
+// GetSchema implements TableWriteCloser
+func (bw *bulkTableWriter) GetSchema() schema.Schema {
+	return bw.sch
+}
+
+// WriteRow implements TableWriteCloser
+func (bw *bulkTableWriter) WriteRow(ctx context.Context, r row.Row) error {
+	return bw.WriteRowWithProps(ctx, r, nil)
+}
+
+// WriteRowWithProps implements SortedRunsWriter
+func (bw *bulkTableWriter) WriteRowWithProps(ctx context.Context, r row.Row, props pipeline.ReadableMap) error {
+	key, err := r.NomsMapKey(bw.sch).Value(ctx)
+	if err != nil {
+		return err
+	}
+
+	val, err := r.NomsMapValue(bw.sch).Value(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the runs are held in memory until all of the rows are written, so the tuples are kept as small as they can be
+	key = key.(types.Tuple).Compact()
+	val = val.(types.Tuple).Compact()
+
+	var sortedVal types.Valuable = val
+	if props != nil {
+		sortedVal = propsTuple{val.(types.Tuple), props}
+	}
+
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	if bw.merged {
+		return errors.New("writing to a table after its rows were merged")
+	}
+
+	bw.edits.AddEdit(key.(types.Tuple), sortedVal)
+
+	bw.stats.Additions++
+	bw.statOps++
+	if bw.statsCB != nil && bw.statOps >= tableWriterStatUpdateRate {
+		bw.statOps = 0
+		bw.statsCB(bw.stats)
+	}
+
+	return nil
+}
+
+// MergeRuns implements SortedRunsWriter
+func (bw *bulkTableWriter) MergeRuns(ctx context.Context, badRowCB pipeline.BadRowCallback) error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	return bw.mergeRuns(ctx, badRowCB)
+}
+
+// mergeRuns merges the sorted runs the first time it's called, and returns the error of the merge on later calls
+func (bw *bulkTableWriter) mergeRuns(ctx context.Context, badRowCB pipeline.BadRowCallback) error {
+	if !bw.merged {
+		bw.merged = true
+		bw.mergeErr = bw.buildRowData(ctx, badRowCB)
+	}
+
+	return bw.mergeErr
+}
+
+func (bw *bulkTableWriter) buildRowData(ctx context.Context, badRowCB pipeline.BadRowCallback) error {
+	ep, err := bw.edits.FinishedEditing()
+	if err != nil {
+		return err
+	}
+
+	nmc := noms.NewNomsMapCreator(ctx, bw.root.VRW(), bw.sch)
+
+	var lastKey types.Value
+	for {
+		kvp, err := ep.Next()
+		if err != nil {
+			_ = nmc.Close(ctx)
+			return err
+		} else if kvp == nil {
+			break
+		}
+
+		key := kvp.Key.(types.Tuple)
+		val, props := unwrapSortedVal(kvp.Val)
+		if lastKey != nil && lastKey.Equals(key) {
+			if trf, err := bw.duplicateFailure(ctx, key, val, props); err != nil {
+				_ = nmc.Close(ctx)
+				return err
+			} else if badRowCB == nil || badRowCB(trf) {
+				_ = nmc.Close(ctx)
+				return trf
+			}
+
+			bw.stats.Additions--
+			continue
+		}
+
+		if err = nmc.WriteKV(ctx, key, val); err != nil {
+			_ = nmc.Close(ctx)
+			return err
+		}
+		lastKey = key
+	}
+
+	if err = nmc.Close(ctx); err != nil {
+		return err
+	}
+
+	m := nmc.GetMap()
+	bw.rowData = &m
+
+	return nil
+}
+
+// unwrapSortedVal returns the value tuple of a row in the sorted runs, and the properties it was written with, which
+// are nil if it was written without them
+func unwrapSortedVal(v types.Valuable) (types.Tuple, pipeline.ReadableMap) {
+	if pt, ok := v.(propsTuple); ok {
+		return pt.Tuple, pt.props
+	}
+	return v.(types.Tuple), nil
+}
+
+// duplicateFailure returns the TransformRowFailure for a row whose primary key was already written. |props| are the
+// properties the row was written with, and may be nil.
+func (bw *bulkTableWriter) duplicateFailure(ctx context.Context, key, val types.Tuple, props pipeline.ReadableMap) (*pipeline.TransformRowFailure, error) {
+	r, err := row.FromNoms(bw.sch, key, val)
+	if err != nil {
+		return nil, err
+	}
+
+	if props == nil {
+		props = pipeline.NoProps
+	}
+
+	keyStr, err := editor.FormatKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pipeline.TransformRowFailure{
+		Row:           r,
+		TransformName: "writer",
+		Details:       sql.ErrPrimaryKeyViolation.New(keyStr).Error(),
+		Props:         props,
+	}, nil
+}
+
+// Flush implements DataMoverCloser. It creates the table with the merged rows, and builds its indexes.
+func (bw *bulkTableWriter) Flush(ctx context.Context) (*doltdb.RootValue, error) {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	if bw.rowData == nil {
+		return nil, errors.New("flushing a table before its rows were merged")
+	}
+
+	root, err := bw.root.CreateEmptyTable(ctx, bw.tableName, bw.sch)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, _, err := root.GetTable(ctx, bw.tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err = tbl.UpdateRows(ctx, *bw.rowData)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err = editor.RebuildAllIndexes(ctx, tbl)
+	if err != nil {
+		return nil, err
+	}
+
+	return root.PutTable(ctx, bw.tableName, tbl)
+}
+
+// Close implements TableWriteCloser. If the runs weren't merged by MergeRuns they are merged now, and any duplicate
+// primary key is an error.
+func (bw *bulkTableWriter) Close(ctx context.Context) error {
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+
+	err := bw.mergeRuns(ctx, nil)
+
+	if bw.statsCB != nil {
+		bw.statsCB(bw.stats)
+	}
+
+	return err
+}
@@ -0,0 +1,172 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func bulkTestRows(t *testing.T, n int) []row.Row {
+	var rows []row.Row
+	for i := n - 1; i >= 0; i-- {
+		r, err := row.New(types.Format_7_18, fakeSchema, row.TaggedValues{
+			0: types.String(fmt.Sprintf("key%06d", i)),
+			1: types.String(fmt.Sprintf("%d", i)),
+		})
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+func TestBulkCreatingWriter(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	const numRows = 10000
+	rows := bulkTestRows(t, numRows)
+	// a row with the same key as another is a bad row
+	rows = append(rows, rows[42])
+
+	tests := []struct {
+		name      string
+		contOnErr bool
+		expectErr bool
+	}{
+		{"continue on duplicates", true, false},
+		{"fail on duplicates", false, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			loc := TableDataLocation{Name: testTableName}
+			wr, err := loc.NewBulkCreatingWriter(ctx, root, fakeSchema, 4, nil)
+			require.NoError(t, err)
+
+			report := NewBadRowReport(nil)
+			mover := &DataMover{
+				Rd:         table.NewInMemTableReader(table.NewInMemTableWithData(fakeSchema, rows)),
+				Transforms: pipeline.NewTransformCollection(),
+				Wr:         wr,
+				ContOnErr:  test.contOnErr,
+				BadRows:    report,
+				Workers:    4,
+			}
+
+			badCount, err := mover.Move(ctx)
+			if test.expectErr {
+				require.Error(t, err)
+				assert.True(t, pipeline.IsTransformFailure(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(1), badCount)
+			assert.Equal(t, int64(1), report.Counts[DuplicateKeyBadRow])
+
+			newRoot, err := wr.(DataMoverCloser).Flush(ctx)
+			require.NoError(t, err)
+
+			tbl, ok, err := newRoot.GetTable(ctx, testTableName)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			rowData, err := tbl.GetRowData(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(numRows), rowData.Len())
+
+			for _, r := range rows[:numRows] {
+				key, err := r.NomsMapKey(fakeSchema).Value(ctx)
+				require.NoError(t, err)
+				_, ok, err := rowData.MaybeGet(ctx, key)
+				require.NoError(t, err)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
+func TestBulkCreatingWriterBadRowRecords(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	const numRows = 1000
+	rows := bulkTestRows(t, numRows)
+	rows = append(rows, rows[42])
+
+	loc := TableDataLocation{Name: testTableName}
+	wr, err := loc.NewBulkCreatingWriter(ctx, root, fakeSchema, 4, nil)
+	require.NoError(t, err)
+
+	fs := filesys.EmptyInMemFS("/")
+	badRowWr, err := NewBadRowWriter(types.Format_7_18, "/bad.csv", fs, fakeSchema)
+	require.NoError(t, err)
+
+	mover := &DataMover{
+		Rd:         table.NewInMemTableReader(table.NewInMemTableWithData(fakeSchema, rows)),
+		Transforms: pipeline.NewTransformCollection(),
+		Wr:         wr,
+		ContOnErr:  true,
+		BadRows:    NewBadRowReport(badRowWr),
+		Workers:    4,
+	}
+
+	badCount, err := mover.Move(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), badCount)
+
+	data, err := fs.ReadFile("/bad.csv")
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	// either of the rows with the duplicate key may be the one reported, but it's reported with its line and record
+	fields := strings.Split(lines[1], ",")
+	assert.Contains(t, []string{"43", fmt.Sprint(numRows + 1)}, fields[0])
+	assert.Contains(t, lines[1], "key000957")
+}
+
+func TestBulkCreatingWriterRequiresPK(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(ctx)
+	require.NoError(t, err)
+
+	keylessSch, err := schema.SchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("a", 0, types.StringKind, false),
+		schema.NewColumn("b", 1, types.StringKind, false),
+	))
+	require.NoError(t, err)
+
+	_, err = TableDataLocation{Name: testTableName}.NewBulkCreatingWriter(ctx, root, keylessSch, 4, nil)
+	assert.Equal(t, ErrNoPK, err)
+}
@@ -63,6 +63,15 @@ const (
 
 	// SqlFile is the format of a data location that is a .sql file
 	SqlFile DataFormat = ".sql"
+
+	// JsonlFile is the format of a data location that is a newline delimited json file
+	JsonlFile DataFormat = ".jsonl"
+
+	// ParquetFile is the format of a data location that is a .parquet file
+	ParquetFile DataFormat = ".parquet"
+
+	// SqliteFile is the format of a data location that is a SQLite database file
+	SqliteFile DataFormat = ".sqlite"
 )
 
 // ReadableStr returns a human readable string for a DataFormat
@@ -80,6 +89,12 @@ func (df DataFormat) ReadableStr() string {
 		return "json file"
 	case SqlFile:
 		return "sql file"
+	case JsonlFile:
+		return "jsonl file"
+	case ParquetFile:
+		return "parquet file"
+	case SqliteFile:
+		return "sqlite file"
 	default:
 		return "invalid"
 	}
@@ -132,6 +147,12 @@ func NewDataLocation(path, fileFmtStr string) DataLocation {
 				dataFmt = JsonFile
 			case string(SqlFile):
 				dataFmt = SqlFile
+			case string(JsonlFile), ".ndjson":
+				dataFmt = JsonlFile
+			case string(ParquetFile):
+				dataFmt = ParquetFile
+			case string(SqliteFile), ".sqlite3", ".db":
+				dataFmt = SqliteFile
 			}
 		}
 	}
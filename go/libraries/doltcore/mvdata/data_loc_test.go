@@ -29,6 +29,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/parquet"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
 	"github.com/dolthub/dolt/go/store/types"
@@ -82,6 +83,11 @@ func TestBasics(t *testing.T) {
 		{NewDataLocation("file.csv", ""), CsvFile.ReadableStr() + ":file.csv", true},
 		{NewDataLocation("file.psv", ""), PsvFile.ReadableStr() + ":file.psv", true},
 		{NewDataLocation("file.json", ""), JsonFile.ReadableStr() + ":file.json", true},
+		{NewDataLocation("file.jsonl", ""), JsonlFile.ReadableStr() + ":file.jsonl", true},
+		{NewDataLocation("file.ndjson", ""), JsonlFile.ReadableStr() + ":file.ndjson", true},
+		{NewDataLocation("file.parquet", ""), ParquetFile.ReadableStr() + ":file.parquet", true},
+		{NewDataLocation("file.sqlite", ""), SqliteFile.ReadableStr() + ":file.sqlite", true},
+		{NewDataLocation("file.db", ""), SqliteFile.ReadableStr() + ":file.db", true},
 		//{NewDataLocation("file.nbf", ""), NbfFile, "file.nbf", true},
 	}
 
@@ -184,6 +190,8 @@ func TestCreateRdWr(t *testing.T) {
 		{NewDataLocation("file.csv", ""), reflect.TypeOf((*csv.CSVReader)(nil)).Elem(), reflect.TypeOf((*csv.CSVWriter)(nil)).Elem()},
 		{NewDataLocation("file.psv", ""), reflect.TypeOf((*csv.CSVReader)(nil)).Elem(), reflect.TypeOf((*csv.CSVWriter)(nil)).Elem()},
 		{NewDataLocation("file.json", ""), reflect.TypeOf((*json.JSONReader)(nil)).Elem(), reflect.TypeOf((*json.JSONWriter)(nil)).Elem()},
+		{NewDataLocation("file.jsonl", ""), reflect.TypeOf((*json.JSONLReader)(nil)).Elem(), reflect.TypeOf((*json.JSONLWriter)(nil)).Elem()},
+		{NewDataLocation("file.parquet", ""), reflect.TypeOf((*parquet.ParquetReader)(nil)).Elem(), reflect.TypeOf((*parquet.ParquetWriter)(nil)).Elem()},
 		//{NewDataLocation("file.nbf", ""), reflect.TypeOf((*nbf.NBFReader)(nil)).Elem(), reflect.TypeOf((*nbf.NBFWriter)(nil)).Elem()},
 	}
 
@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"sync"
 	"sync/atomic"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
@@ -48,12 +50,25 @@ type JSONOptions struct {
 	SchFile   string
 }
 
+type SqliteOptions struct {
+	TableName string
+}
+
 type DataMoverOptions interface {
 	WritesToTable() bool
 	SrcName() string
 	DestName() string
 }
 
+// CommitDataMoverOptions are DataMoverOptions which know the commit that the data being moved is read at. Writers
+// of files with document properties record the commit in them.
+type CommitDataMoverOptions interface {
+	DataMoverOptions
+	// SrcCommit returns the hash of the commit the data is read at, and whether the data includes uncommitted changes
+	// made on top of it.
+	SrcCommit() (commitHash string, uncommitted bool)
+}
+
 type DataMoverCloser interface {
 	table.TableWriteCloser
 	Flush(context.Context) (*doltdb.RootValue, error)
@@ -64,6 +79,14 @@ type DataMover struct {
 	Transforms *pipeline.TransformCollection
 	Wr         table.TableWriteCloser
 	ContOnErr  bool
+
+	// BadRows records the rows that fail to be moved when ContOnErr is true. It may be nil.
+	BadRows *BadRowReport
+
+	// Workers is the number of pipelines which transform and write rows at the same time. The rows are read by one
+	// goroutine at a time and handed out to the pipelines. If Workers is greater than one, Wr must be safe for
+	// concurrent use, and the rows may be written in any order.
+	Workers int
 }
 
 type DataMoverCreationErrType string
@@ -112,26 +135,68 @@ func (imp *DataMover) Move(ctx context.Context) (badRowCount int64, err error) {
 		}
 	}()
 
+	if imp.BadRows != nil {
+		defer func() {
+			closeErr := imp.BadRows.Close(ctx)
+			if err == nil {
+				err = closeErr
+			}
+		}()
+	}
+
 	var badCount int64
 	var rowErr error
+	var stopped int32
+	var badRowMu sync.Mutex
 	badRowCB := func(trf *pipeline.TransformRowFailure) (quit bool) {
+		badRowMu.Lock()
+		defer badRowMu.Unlock()
+
 		if !imp.ContOnErr {
 			rowErr = trf
+			atomic.StoreInt32(&stopped, 1)
 			return true
 		}
 
 		atomic.AddInt64(&badCount, 1)
+
+		if imp.BadRows != nil {
+			if err := imp.BadRows.Add(ctx, trf); err != nil {
+				rowErr = fmt.Errorf("failed to record bad row: %w", err)
+				atomic.StoreInt32(&stopped, 1)
+				return true
+			}
+		}
+
 		return false
 	}
 
-	p := pipeline.NewAsyncPipeline(
-		pipeline.ProcFuncForReader(ctx, imp.Rd),
-		pipeline.ProcFuncForWriter(ctx, imp.Wr),
-		imp.Transforms,
-		badRowCB)
-	p.Start()
+	srcFunc := pipeline.SourceFuncForReader(ctx, imp.Rd)
+	sinkFunc := pipeline.ProcFuncForWriter(ctx, imp.Wr)
+	if imp.BadRows != nil && imp.BadRows.WritesRows() {
+		// the rows are traced back to their input records, which are written to the bad rows file
+		srcFunc = pipeline.SourceFuncForSourceRows(ctx, imp.Rd)
+
+		if srw, ok := imp.Wr.(SortedRunsWriter); ok {
+			// rows with duplicate keys aren't found until the runs are merged, so they're written with their records
+			sinkFunc = pipeline.ProcFuncForSinkFunc(func(r row.Row, props pipeline.ReadableMap) error {
+				return srw.WriteRowWithProps(ctx, r, props)
+			})
+		}
+	}
 
-	err = p.Wait()
+	if imp.Workers <= 1 {
+		p := pipeline.NewAsyncPipeline(
+			pipeline.ProcFuncForSourceFunc(srcFunc),
+			sinkFunc,
+			imp.Transforms,
+			badRowCB)
+		p.Start()
+
+		err = p.Wait()
+	} else {
+		err = imp.moveConcurrently(ctx, srcFunc, sinkFunc, badRowCB, &stopped)
+	}
 
 	if err != nil {
 		return 0, err
@@ -141,9 +206,68 @@ func (imp *DataMover) Move(ctx context.Context) (badRowCount int64, err error) {
 		return 0, rowErr
 	}
 
+	if srw, ok := imp.Wr.(SortedRunsWriter); ok {
+		// duplicate keys are found as the runs are merged, and are bad rows like any others
+		err = srw.MergeRuns(ctx, badRowCB)
+
+		if rowErr != nil {
+			return 0, rowErr
+		} else if err != nil {
+			return 0, err
+		}
+	}
+
 	return badCount, nil
 }
 
+// moveConcurrently moves the rows of |srcFunc| to |sinkFunc| with a pipeline for each of the mover's workers. Reading
+// stops once |stopped| is set, or any of the pipelines fails.
+func (imp *DataMover) moveConcurrently(ctx context.Context, srcFunc pipeline.SourceFunc, sinkFunc pipeline.OutFunc, badRowCB pipeline.BadRowCallback, stopped *int32) error {
+	var readMu sync.Mutex
+	sharedSrcFunc := func() (row.Row, pipeline.ImmutableProperties, error) {
+		readMu.Lock()
+		defer readMu.Unlock()
+
+		if atomic.LoadInt32(stopped) != 0 {
+			return nil, pipeline.NoProps, io.EOF
+		}
+
+		return srcFunc()
+	}
+
+	pipelines := make([]*pipeline.Pipeline, imp.Workers)
+	for i := range pipelines {
+		pipelines[i] = pipeline.NewAsyncPipeline(
+			pipeline.ProcFuncForSourceFunc(sharedSrcFunc),
+			sinkFunc,
+			imp.Transforms,
+			badRowCB)
+		pipelines[i].Start()
+	}
+
+	errs := make([]error, len(pipelines))
+	wg := &sync.WaitGroup{}
+	for i, p := range pipelines {
+		wg.Add(1)
+		go func(i int, p *pipeline.Pipeline) {
+			defer wg.Done()
+			errs[i] = p.Wait()
+			if errs[i] != nil {
+				atomic.StoreInt32(stopped, 1)
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func MoveDataToRoot(ctx context.Context, mover *DataMover, mvOpts DataMoverOptions, root *doltdb.RootValue, updateRoot func(c context.Context, r *doltdb.RootValue) error) (*doltdb.RootValue, int64, errhand.VerboseError) {
 	var badCount int64
 	var err error
@@ -251,13 +375,18 @@ func SchAndTableNameFromFile(ctx context.Context, path string, fs filesys.Readab
 }
 
 func InferSchema(ctx context.Context, root *doltdb.RootValue, rd table.TableReadCloser, tableName string, pks []string, args actions.InferenceArgs) (schema.Schema, error) {
-	var err error
-
 	infCols, err := actions.InferColumnTypesFromTableReader(ctx, root, rd, args)
 	if err != nil {
 		return nil, err
 	}
 
+	return SchemaFromInferredCols(ctx, root, tableName, infCols, pks)
+}
+
+// SchemaFromInferredCols returns the schema of a new table named |tableName| with the columns |infCols|, whose
+// types were inferred from the data being imported. The columns named by |pks| make up the primary key, and new tags
+// are generated for all of the columns.
+func SchemaFromInferredCols(ctx context.Context, root *doltdb.RootValue, tableName string, infCols *schema.ColCollection, pks []string) (schema.Schema, error) {
 	pkSet := set.NewStrSet(pks)
 	newCols := schema.MapColCollection(infCols, func(col schema.Column) schema.Column {
 		col.IsPartOfPK = pkSet.Contains(col.Name)
@@ -284,7 +413,7 @@ func InferSchema(ctx context.Context, root *doltdb.RootValue, rd table.TableRead
 		}
 	}
 
-	newCols, err = root.GenerateTagsForNewColColl(ctx, tableName, newCols)
+	newCols, err := root.GenerateTagsForNewColColl(ctx, tableName, newCols)
 	if err != nil {
 		return nil, errhand.BuildDError("failed to generate new schema").AddCause(err).Build()
 	}
@@ -0,0 +1,121 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// DateLayoutTransform rewrites the dates, times and datetimes of the rows being moved which are in layouts that SQL
+// doesn't understand, such as 1/2/2006, in the formats that SQL understands. Only the fields which are moved to
+// date, time, datetime and timestamp columns are rewritten.
+type DateLayoutTransform struct {
+	inSch   schema.Schema
+	layouts []string
+	// formats holds the format that each rewritten field is written in, keyed by the tag of the field
+	formats map[uint64]string
+	// outTypes holds the type of the column that each rewritten field is moved to, keyed by the tag of the field
+	outTypes map[uint64]typeinfo.TypeInfo
+}
+
+// NewDateLayoutTransform returns a DateLayoutTransform of the rows of |inSch|, whose fields are renamed by
+// |nameMapper| to the columns of |outSch|, which parses the fields with |layouts|. It returns nil if none of the
+// fields are moved to date, time, datetime or timestamp columns, or if there are no layouts.
+func NewDateLayoutTransform(inSch, outSch schema.Schema, nameMapper rowconv.NameMapper, layouts []string) *DateLayoutTransform {
+	if len(layouts) == 0 {
+		return nil
+	}
+
+	t := &DateLayoutTransform{
+		inSch:    inSch,
+		layouts:  layouts,
+		formats:  make(map[uint64]string),
+		outTypes: make(map[uint64]typeinfo.TypeInfo),
+	}
+	_ = inSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		outCol, ok := outSch.GetAllCols().GetByName(nameMapper.Map(col.Name))
+		if !ok {
+			return false, nil
+		}
+
+		switch outCol.TypeInfo.ToSqlType().Type() {
+		case sqltypes.Date:
+			t.formats[tag] = "2006-01-02"
+		case sqltypes.Time:
+			t.formats[tag] = "15:04:05.999999"
+		case sqltypes.Datetime, sqltypes.Timestamp:
+			t.formats[tag] = "2006-01-02 15:04:05.999999"
+		default:
+			return false, nil
+		}
+		t.outTypes[tag] = outCol.TypeInfo
+		return false, nil
+	})
+
+	if len(t.formats) == 0 {
+		return nil
+	}
+
+	return t
+}
+
+// TransformRow is a pipeline.TransformRowFunc which rewrites the dates, times and datetimes of |inRow| that are in
+// one of the layouts of the transform. Fields which SQL already understands, or which aren't in any of the layouts,
+// are left as they are.
+func (t *DateLayoutTransform) TransformRow(inRow row.Row, props pipeline.ReadableMap) ([]*pipeline.TransformedRowResult, string) {
+	outRow := inRow
+	for tag, format := range t.formats {
+		val, ok := inRow.GetColVal(tag)
+		if !ok {
+			continue
+		}
+
+		str, ok := val.(types.String)
+		if !ok {
+			continue
+		}
+
+		strVal := string(str)
+		if _, err := t.outTypes[tag].ParseValue(context.Background(), nil, &strVal); err == nil {
+			continue
+		}
+
+		for _, layout := range t.layouts {
+			parsed, err := time.Parse(layout, strings.TrimSpace(strVal))
+			if err != nil {
+				continue
+			}
+
+			outRow, err = outRow.SetColVal(tag, types.String(parsed.Format(format)), t.inSch)
+			if err != nil {
+				return nil, err.Error()
+			}
+			break
+		}
+	}
+
+	return []*pipeline.TransformedRowResult{{RowData: outRow}}, ""
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestDateLayoutTransform(t *testing.T) {
+	vrw := types.NewMemoryValueStore()
+	layouts := []string{"1/2/2006", "2006-01-02T15:04:05", "3:04 PM"}
+
+	_, inSch := untyped.NewUntypedSchema("pk", "signup", "seen", "clock", "name")
+	outSch := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.StringKind, true),
+		mustColumn(t, "joined", 1, typeinfo.DateType),
+		mustColumn(t, "seen", 2, typeinfo.DatetimeType),
+		mustColumn(t, "clock", 3, typeinfo.TimeType),
+		mustColumn(t, "name", 4, typeinfo.StringDefaultType),
+	))
+	nameMapper := rowconv.NameMapper{"signup": "joined"}
+
+	assert.Nil(t, NewDateLayoutTransform(inSch, outSch, nameMapper, nil))
+	transform := NewDateLayoutTransform(inSch, outSch, nameMapper, layouts)
+	require.NotNil(t, transform)
+
+	tests := []struct {
+		name     string
+		in       []string
+		expected []string
+	}{
+		{
+			name:     "common layouts",
+			in:       []string{"1", "12/31/2020", "2021-01-10T10:00:00", "3:30 PM", "1/2/2006"},
+			expected: []string{"1", "2020-12-31", "2021-01-10 10:00:00", "15:30:00", "1/2/2006"},
+		},
+		{
+			name:     "understood by SQL",
+			in:       []string{"2", "2020-12-31", "2021-01-10 10:00:00", "15:30:00", "x"},
+			expected: []string{"2", "2020-12-31", "2021-01-10 10:00:00", "15:30:00", "x"},
+		},
+		{
+			name:     "unknown layouts",
+			in:       []string{"3", "31.12.2020", "yesterday", "noon", "x"},
+			expected: []string{"3", "31.12.2020", "yesterday", "noon", "x"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inRow, err := untyped.NewRowFromStrings(vrw.Format(), inSch, test.in)
+			require.NoError(t, err)
+
+			results, badRowDetails := transform.TransformRow(inRow, nil)
+			require.Empty(t, badRowDetails)
+			require.Len(t, results, 1)
+
+			for i, expectedStr := range test.expected {
+				val, ok := results[0].RowData.GetColVal(uint64(i))
+				require.True(t, ok)
+				assert.Equal(t, types.String(expectedStr), val)
+			}
+		})
+	}
+}
+
+func mustColumn(t *testing.T, name string, tag uint64, ti typeinfo.TypeInfo) schema.Column {
+	col, err := schema.NewColumnWithTypeInfo(name, tag, ti, false, "", false, "")
+	require.NoError(t, err)
+	return col
+}
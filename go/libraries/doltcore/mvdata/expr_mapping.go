@@ -0,0 +1,279 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/pipeline"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// mappingFunctions are the functions that can be called by the expressions of a mapping file
+var mappingFunctions = func() sql.FunctionRegistry {
+	registry := sql.NewFunctionRegistry()
+	registry.MustRegister(function.Defaults...)
+	return registry
+}()
+
+// computedCol is a field computed by an expression of a mapping file
+type computedCol struct {
+	col  schema.Column
+	expr sql.Expression
+}
+
+// ExprMappingTransform evaluates the SQL expressions of a rowconv.ExprMapping against the rows being moved. The fields
+// computed by the expressions are added to each row, and rows which don't match the mapping's filter are skipped.
+type ExprMappingTransform struct {
+	// OutSch is the schema of the rows output by the transform. It has the columns of the input schema, followed by a
+	// column for each computed field. Input columns which are renamed to a computed field are left out, as the computed
+	// field replaces them.
+	OutSch schema.Schema
+
+	vrw      types.ValueReadWriter
+	sqlCtx   *sql.Context
+	inCols   []schema.Column
+	keptTags []uint64
+	computed []computedCol
+	where    sql.Expression
+}
+
+// NewExprMappingTransform returns an ExprMappingTransform of the rows of |inSch|, whose fields are renamed by
+// |nameMapper|. The fields of |inSch| are referenced by name in the expressions of |mapping|.
+func NewExprMappingTransform(ctx context.Context, vrw types.ValueReadWriter, inSch schema.Schema, nameMapper rowconv.NameMapper, mapping rowconv.ExprMapping) (*ExprMappingTransform, error) {
+	sqlCtx := sql.NewContext(ctx)
+
+	var inCols []schema.Column
+	var maxTag uint64
+	_ = inSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		inCols = append(inCols, col)
+		if tag > maxTag {
+			maxTag = tag
+		}
+		return false, nil
+	})
+
+	names := make([]string, 0, len(mapping.Exprs))
+	for name := range mapping.Exprs {
+		if _, ok := nameMapper[name]; ok {
+			return nil, fmt.Errorf("mapping file both renames and computes the field '%s'", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := &ExprMappingTransform{vrw: vrw, sqlCtx: sqlCtx, inCols: inCols}
+	for i, name := range names {
+		expr, err := parseMappingExpr(sqlCtx, mapping.Exprs[name], inCols)
+		if err != nil {
+			return nil, err
+		}
+
+		ti := computedTypeInfo(expr.Type())
+		col, err := schema.NewColumnWithTypeInfo(name, maxTag+1+uint64(i), ti, false, "", false, "")
+		if err != nil {
+			return nil, err
+		}
+		t.computed = append(t.computed, computedCol{col: col, expr: expr})
+	}
+
+	if mapping.Where != "" {
+		where, err := parseMappingExpr(sqlCtx, mapping.Where, inCols)
+		if err != nil {
+			return nil, err
+		}
+		t.where = where
+	}
+
+	var outCols []schema.Column
+	for _, col := range inCols {
+		if _, ok := mapping.Exprs[nameMapper.Map(col.Name)]; ok {
+			continue
+		}
+		col.IsPartOfPK = false
+		outCols = append(outCols, col)
+		t.keptTags = append(t.keptTags, col.Tag)
+	}
+	for _, cc := range t.computed {
+		outCols = append(outCols, cc.col)
+	}
+
+	t.OutSch = schema.UnkeyedSchemaFromCols(schema.NewColCollection(outCols...))
+
+	return t, nil
+}
+
+// parseMappingExpr parses the SQL expression |exprStr|, resolving its columns to the fields of |inCols|.
+func parseMappingExpr(ctx *sql.Context, exprStr string, inCols []schema.Column) (sql.Expression, error) {
+	node, err := parse.Parse(ctx, "SELECT "+exprStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mapping expression '%s': %w", exprStr, err)
+	}
+
+	proj, ok := node.(*plan.Project)
+	if !ok || len(proj.Projections) != 1 {
+		return nil, fmt.Errorf("invalid mapping expression '%s'", exprStr)
+	}
+
+	expr, err := expression.TransformUp(proj.Projections[0], func(e sql.Expression) (sql.Expression, error) {
+		switch e := e.(type) {
+		case *expression.UnresolvedColumn:
+			for i, col := range inCols {
+				if strings.EqualFold(col.Name, e.Name()) {
+					return expression.NewGetField(i, col.TypeInfo.ToSqlType(), col.Name, col.IsNullable()), nil
+				}
+			}
+			return nil, fmt.Errorf("unknown field '%s' in mapping expression '%s'", e.Name(), exprStr)
+		case *expression.UnresolvedFunction:
+			fn, err := mappingFunctions.Function(strings.ToLower(e.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("%w in mapping expression '%s'", err, exprStr)
+			}
+			return fn.NewInstance(e.Arguments)
+		default:
+			return e, nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if alias, ok := expr.(*expression.Alias); ok {
+		expr = alias.Child
+	}
+
+	if !expr.Resolved() {
+		return nil, fmt.Errorf("mapping expression '%s' is not supported", exprStr)
+	}
+
+	return expr, nil
+}
+
+// computedTypeInfo returns the type of a field computed by an expression of type |sqlType|. Integers and floats are
+// widened, so that the types of the fields don't depend on the widths of the literals in the expressions.
+func computedTypeInfo(sqlType sql.Type) typeinfo.TypeInfo {
+	switch {
+	case sql.IsSigned(sqlType):
+		return typeinfo.Int64Type
+	case sql.IsUnsigned(sqlType):
+		return typeinfo.Uint64Type
+	case sql.IsFloat(sqlType):
+		return typeinfo.Float64Type
+	case sqlType == sql.Null:
+		return typeinfo.StringDefaultType
+	}
+
+	ti, err := typeinfo.FromSqlType(sqlType)
+	if err != nil {
+		return typeinfo.StringDefaultType
+	}
+	return ti
+}
+
+// WithComputedCols returns |cols| with the columns of the computed fields. The columns of |cols| that have the same
+// names as computed fields are replaced.
+func (t *ExprMappingTransform) WithComputedCols(cols *schema.ColCollection) *schema.ColCollection {
+	isComputed := make(map[string]bool, len(t.computed))
+	for _, cc := range t.computed {
+		isComputed[cc.col.Name] = true
+	}
+
+	var newCols []schema.Column
+	var maxTag uint64
+	_ = cols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if tag > maxTag {
+			maxTag = tag
+		}
+		if !isComputed[col.Name] {
+			newCols = append(newCols, col)
+		}
+		return false, nil
+	})
+
+	for i, cc := range t.computed {
+		col := cc.col
+		col.Tag = maxTag + 1 + uint64(i)
+		newCols = append(newCols, col)
+	}
+
+	return schema.NewColCollection(newCols...)
+}
+
+// TransformRow is a pipeline.TransformRowFunc which computes the fields of |inRow|, and skips it if it doesn't match
+// the filter.
+func (t *ExprMappingTransform) TransformRow(inRow row.Row, props pipeline.ReadableMap) ([]*pipeline.TransformedRowResult, string) {
+	sqlRow := make(sql.Row, len(t.inCols))
+	for i, col := range t.inCols {
+		if val, ok := inRow.GetColVal(col.Tag); ok && !types.IsNull(val) {
+			sqlVal, err := col.TypeInfo.ConvertNomsValueToValue(val)
+			if err != nil {
+				return nil, fmt.Sprintf("field %s: %v", col.Name, err)
+			}
+			sqlRow[i] = sqlVal
+		}
+	}
+
+	if t.where != nil {
+		matches, err := sql.EvaluateCondition(t.sqlCtx, t.where, sqlRow)
+		if err != nil {
+			return nil, fmt.Sprintf("filter: %v", err)
+		} else if !matches {
+			return nil, ""
+		}
+	}
+
+	taggedVals := make(row.TaggedValues, len(t.keptTags)+len(t.computed))
+	for _, tag := range t.keptTags {
+		if val, ok := inRow.GetColVal(tag); ok {
+			taggedVals[tag] = val
+		}
+	}
+
+	for _, cc := range t.computed {
+		sqlVal, err := cc.expr.Eval(t.sqlCtx, sqlRow)
+		if err != nil {
+			return nil, fmt.Sprintf("field %s: %v", cc.col.Name, err)
+		}
+		if sqlVal == nil {
+			continue
+		}
+
+		val, err := cc.col.TypeInfo.ConvertValueToNomsValue(t.sqlCtx, t.vrw, sqlVal)
+		if err != nil {
+			return nil, fmt.Sprintf("field %s: %v", cc.col.Name, err)
+		}
+		taggedVals[cc.col.Tag] = val
+	}
+
+	outRow, err := row.New(t.vrw.Format(), t.OutSch, taggedVals)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	return []*pipeline.TransformedRowResult{{RowData: outRow}}, ""
+}
@@ -0,0 +1,131 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/rowconv"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestExprMappingTransform(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	_, inSch := untyped.NewUntypedSchema("full_name", "age", "email")
+	nameMapper := rowconv.NameMapper{"email": "mail"}
+	mapping := rowconv.ExprMapping{
+		Exprs: map[string]string{
+			"first_name": "SUBSTRING_INDEX(full_name, ' ', 1)",
+			"country":    "'US'",
+			"age":        "age + 1",
+		},
+		Where: "age >= 18",
+	}
+
+	transform, err := NewExprMappingTransform(ctx, vrw, inSch, nameMapper, mapping)
+	require.NoError(t, err)
+
+	outCols := transform.OutSch.GetAllCols()
+	assert.Equal(t, []string{"full_name", "email", "age", "country", "first_name"}, outCols.GetColumnNames())
+	age, _ := outCols.GetByName("age")
+	assert.True(t, typeinfo.Float64Type.Equals(age.TypeInfo))
+	country, _ := outCols.GetByName("country")
+	assert.Equal(t, sql.LongText, country.TypeInfo.ToSqlType())
+
+	adult, err := untyped.NewRowFromStrings(vrw.Format(), inSch, []string{"Tim Sehn", "40", "tim@example.com"})
+	require.NoError(t, err)
+	results, badRowDetails := transform.TransformRow(adult, nil)
+	require.Empty(t, badRowDetails)
+	require.Len(t, results, 1)
+
+	expected := map[string]string{
+		"full_name":  "Tim Sehn",
+		"email":      "tim@example.com",
+		"age":        "41",
+		"country":    "US",
+		"first_name": "Tim",
+	}
+	for name, expectedStr := range expected {
+		col, _ := outCols.GetByName(name)
+		val, ok := results[0].RowData.GetColVal(col.Tag)
+		require.True(t, ok, name)
+		str, err := col.TypeInfo.FormatValue(val)
+		require.NoError(t, err)
+		assert.Equal(t, expectedStr, *str, name)
+	}
+
+	minor, err := untyped.NewRowFromStrings(vrw.Format(), inSch, []string{"Aaron Son", "17", "aaron@example.com"})
+	require.NoError(t, err)
+	results, badRowDetails = transform.TransformRow(minor, nil)
+	assert.Empty(t, badRowDetails)
+	assert.Empty(t, results)
+}
+
+func TestExprMappingTransformErrors(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	_, inSch := untyped.NewUntypedSchema("a", "b")
+
+	tests := []struct {
+		name       string
+		nameMapper rowconv.NameMapper
+		mapping    rowconv.ExprMapping
+	}{
+		{"unknown field", nil, rowconv.ExprMapping{Exprs: map[string]string{"c": "UPPER(z)"}}},
+		{"unknown function", nil, rowconv.ExprMapping{Exprs: map[string]string{"c": "NOPE(a)"}}},
+		{"invalid syntax", nil, rowconv.ExprMapping{Exprs: map[string]string{"c": "a +"}}},
+		{"invalid filter", nil, rowconv.ExprMapping{Where: "z > 1"}},
+		{"renamed and computed", rowconv.NameMapper{"c": "d"}, rowconv.ExprMapping{Exprs: map[string]string{"c": "a"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewExprMappingTransform(ctx, vrw, inSch, test.nameMapper, test.mapping)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExprMappingTransformWithComputedCols(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	_, inSch := untyped.NewUntypedSchema("a", "b")
+
+	transform, err := NewExprMappingTransform(ctx, vrw, inSch, nil, rowconv.ExprMapping{Exprs: map[string]string{"b": "LENGTH(a)", "c": "'x'"}})
+	require.NoError(t, err)
+
+	inferred := schema.NewColCollection(
+		schema.NewColumn("a", 5, types.StringKind, false),
+		schema.NewColumn("b", 6, types.StringKind, false),
+	)
+	cols := transform.WithComputedCols(inferred)
+	assert.Equal(t, []string{"a", "b", "c"}, cols.GetColumnNames())
+
+	b, _ := cols.GetByName("b")
+	assert.Equal(t, uint64(7), b.Tag)
+	assert.True(t, typeinfo.Int64Type.Equals(b.TypeInfo))
+	c, _ := cols.GetByName("c")
+	assert.Equal(t, uint64(8), c.Tag)
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/parquet"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/sqlite"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/sqlexport"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/xlsx"
@@ -47,6 +49,12 @@ func DFFromString(dfStr string) DataFormat {
 		return JsonFile
 	case "sql", ".sql":
 		return SqlFile
+	case "jsonl", ".jsonl", "ndjson", ".ndjson":
+		return JsonlFile
+	case "parquet", ".parquet":
+		return ParquetFile
+	case "sqlite", ".sqlite", "sqlite3", ".sqlite3", ".db":
+		return SqliteFile
 	default:
 		return InvalidDataFormat
 	}
@@ -108,41 +116,72 @@ func (dl FileDataLocation) NewReader(ctx context.Context, root *doltdb.RootValue
 		return rd, false, err
 
 	case JsonFile:
-		var sch schema.Schema
-		jsonOpts, _ := opts.(JSONOptions)
-		if jsonOpts.SchFile != "" {
-			tn, s, err := SchAndTableNameFromFile(ctx, jsonOpts.SchFile, fs, root)
-			if err != nil {
-				return nil, false, err
-			}
-			if tn != jsonOpts.TableName {
-				return nil, false, fmt.Errorf("table name '%s' from schema file %s does not match table arg '%s'", tn, jsonOpts.SchFile, jsonOpts.TableName)
-			}
-			sch = s
-		} else {
-			if opts == nil {
-				return nil, false, errors.New("Unable to determine table name on JSON import")
-			}
-			tbl, exists, err := root.GetTable(context.TODO(), jsonOpts.TableName)
-			if !exists {
-				return nil, false, errors.New(fmt.Sprintf("The following table could not be found:\n%v", jsonOpts.TableName))
-			}
-			if err != nil {
-				return nil, false, errors.New(fmt.Sprintf("An error occurred attempting to read the table:\n%v", err.Error()))
-			}
-			sch, err = tbl.GetSchema(context.TODO())
-			if err != nil {
-				return nil, false, errors.New(fmt.Sprintf("An error occurred attempting to read the table schema:\n%v", err.Error()))
-			}
+		sch, err := jsonSchema(ctx, root, fs, opts)
+		if err != nil {
+			return nil, false, err
 		}
 
 		rd, err := json.OpenJSONReader(root.VRW(), dl.Path, fs, sch)
 		return rd, false, err
+
+	case JsonlFile:
+		sch, err := jsonSchema(ctx, root, fs, opts)
+		if err != nil {
+			return nil, false, err
+		}
+
+		rd, err := json.OpenJSONLReader(root.VRW(), dl.Path, fs, sch)
+		return rd, false, err
+
+	case ParquetFile:
+		rd, err := parquet.OpenParquetReader(root.VRW(), dl.Path, fs)
+		return rd, false, err
+
+	case SqliteFile:
+		sqliteOpts, _ := opts.(SqliteOptions)
+		if sqliteOpts.TableName == "" {
+			return nil, false, errors.New("the table to read from the sqlite database was not given")
+		}
+
+		rd, err := sqlite.OpenSQLiteReader(ctx, root.VRW(), dl.Path, fs, sqliteOpts.TableName)
+		return rd, false, err
 	}
 
 	return nil, false, errors.New("unsupported format")
 }
 
+// jsonSchema returns the schema of the rows of a json or jsonl file, which is read from the schema file given by the
+// JSONOptions |opts|, or from the table being imported into.
+func jsonSchema(ctx context.Context, root *doltdb.RootValue, fs filesys.ReadableFS, opts interface{}) (schema.Schema, error) {
+	jsonOpts, _ := opts.(JSONOptions)
+	if jsonOpts.SchFile != "" {
+		tn, s, err := SchAndTableNameFromFile(ctx, jsonOpts.SchFile, fs, root)
+		if err != nil {
+			return nil, err
+		}
+		if tn != jsonOpts.TableName {
+			return nil, fmt.Errorf("table name '%s' from schema file %s does not match table arg '%s'", tn, jsonOpts.SchFile, jsonOpts.TableName)
+		}
+		return s, nil
+	}
+
+	if opts == nil {
+		return nil, errors.New("Unable to determine table name on JSON import")
+	}
+	tbl, exists, err := root.GetTable(context.TODO(), jsonOpts.TableName)
+	if !exists {
+		return nil, errors.New(fmt.Sprintf("The following table could not be found:\n%v", jsonOpts.TableName))
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("An error occurred attempting to read the table:\n%v", err.Error()))
+	}
+	sch, err := tbl.GetSchema(context.TODO())
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("An error occurred attempting to read the table schema:\n%v", err.Error()))
+	}
+	return sch, nil
+}
+
 // NewCreatingWriter will create a TableWriteCloser for a DataLocation that will create a new table, or overwrite
 // an existing table.
 func (dl FileDataLocation) NewCreatingWriter(ctx context.Context, mvOpts DataMoverOptions, dEnv *env.DoltEnv, root *doltdb.RootValue, _ bool, outSch schema.Schema, _ noms.StatsCB, _ bool) (table.TableWriteCloser, error) {
@@ -152,11 +191,22 @@ func (dl FileDataLocation) NewCreatingWriter(ctx context.Context, mvOpts DataMov
 	case PsvFile:
 		return csv.OpenCSVWriter(dl.Path, dEnv.FS, outSch, csv.NewCSVInfo().SetDelim("|"))
 	case XlsxFile:
-		panic("writing to xlsx files is not supported yet")
+		wr, err := xlsx.OpenXLSXWriter(dl.Path, dEnv.FS, mvOpts.SrcName(), outSch)
+		if err != nil {
+			return nil, err
+		}
+		wr.Workbook().SetProperties(XlsxDocProperties(mvOpts))
+		return wr, nil
 	case JsonFile:
 		return json.OpenJSONWriter(dl.Path, dEnv.FS, outSch)
+	case JsonlFile:
+		return json.OpenJSONLWriter(dl.Path, dEnv.FS, outSch)
 	case SqlFile:
 		return sqlexport.OpenSQLExportWriter(ctx, dl.Path, dEnv.FS, root, mvOpts.SrcName(), outSch)
+	case ParquetFile:
+		return parquet.OpenParquetWriter(dl.Path, dEnv.FS, outSch)
+	case SqliteFile:
+		return nil, errors.New("writing to sqlite files is not supported")
 	}
 
 	panic("Invalid Data Format." + string(dl.Format))
@@ -168,6 +218,26 @@ func (dl FileDataLocation) NewUpdatingWriter(_ context.Context, _ DataMoverOptio
 	panic("Updating of files is not supported")
 }
 
+// XlsxDocProperties returns the document properties of an xlsx workbook written by a move with the options
+// |mvOpts|. The commit the data was read at is recorded if |mvOpts| is a CommitDataMoverOptions.
+func XlsxDocProperties(mvOpts DataMoverOptions) xlsx.DocProperties {
+	cmOpts, ok := mvOpts.(CommitDataMoverOptions)
+	if !ok {
+		return xlsx.DocProperties{}
+	}
+
+	commitHash, uncommitted := cmOpts.SrcCommit()
+	if commitHash == "" {
+		return xlsx.DocProperties{}
+	}
+
+	desc := fmt.Sprintf("Exported from dolt commit %s", commitHash)
+	if uncommitted {
+		desc = fmt.Sprintf("Exported from a dolt working set with uncommitted changes on top of commit %s", commitHash)
+	}
+	return xlsx.DocProperties{Description: desc, CommitHash: commitHash}
+}
+
 // NewReplacingWriter will create a TableWriteCloser for a DataLocation that will overwrite an existing table while
 // preserving schema
 func (dl FileDataLocation) NewReplacingWriter(_ context.Context, _ DataMoverOptions, _ *env.DoltEnv, _ *doltdb.RootValue, _ bool, _ schema.Schema, _ noms.StatsCB, _ bool) (table.TableWriteCloser, error) {
@@ -0,0 +1,69 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// QueryResultReader is a TableReadCloser which reads the result set of a SQL query. The schema of the rows has a
+// column for each column of the result set, and no primary key.
+type QueryResultReader struct {
+	sqlCtx *sql.Context
+	vrw    types.ValueReadWriter
+	iter   sql.RowIter
+	sch    schema.Schema
+}
+
+var _ table.TableReadCloser = (*QueryResultReader)(nil)
+
+// NewQueryResultReader returns a QueryResultReader of the rows of |iter|, whose schema is |sqlSch|. The reader closes
+// |iter| when it's closed.
+func NewQueryResultReader(sqlCtx *sql.Context, vrw types.ValueReadWriter, sqlSch sql.Schema, iter sql.RowIter) (*QueryResultReader, error) {
+	sch, err := sqlutil.ToDoltResultSchema(sqlSch)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryResultReader{sqlCtx: sqlCtx, vrw: vrw, iter: iter, sch: sch}, nil
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (rd *QueryResultReader) GetSchema() schema.Schema {
+	return rd.sch
+}
+
+// ReadRow reads a row from the result set. When there are no more rows io.EOF is returned.
+func (rd *QueryResultReader) ReadRow(ctx context.Context) (row.Row, error) {
+	sqlRow, err := rd.iter.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlutil.SqlRowToDoltRow(ctx, rd.vrw, sqlRow, rd.sch)
+}
+
+// Close closes the iterator of the result set
+func (rd *QueryResultReader) Close(ctx context.Context) error {
+	return rd.iter.Close(rd.sqlCtx)
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvdata
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestQueryResultReader(t *testing.T) {
+	ctx := context.Background()
+	sqlCtx := sql.NewContext(ctx)
+	vrw := types.NewMemoryValueStore()
+
+	sqlSch := sql.Schema{
+		{Name: "name", Type: sql.LongText, Nullable: true},
+		{Name: "total", Type: sql.Int64, Nullable: true},
+	}
+	iter := sql.RowsToRowIter(
+		sql.NewRow("tim", int64(3)),
+		sql.NewRow("aaron", nil),
+	)
+
+	rd, err := NewQueryResultReader(sqlCtx, vrw, sqlSch, iter)
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	sch := rd.GetSchema()
+	assert.Equal(t, 2, sch.GetAllCols().Size())
+	assert.Equal(t, 0, sch.GetPKCols().Size())
+
+	nameCol, ok := sch.GetAllCols().GetByName("name")
+	require.True(t, ok)
+	totalCol, ok := sch.GetAllCols().GetByName("total")
+	require.True(t, ok)
+
+	r, err := rd.ReadRow(ctx)
+	require.NoError(t, err)
+	name, _ := r.GetColVal(nameCol.Tag)
+	total, _ := r.GetColVal(totalCol.Tag)
+	assertFormatted(t, nameCol.TypeInfo, "tim", name)
+	assert.Equal(t, types.Int(3), total)
+
+	r, err = rd.ReadRow(ctx)
+	require.NoError(t, err)
+	name, _ = r.GetColVal(nameCol.Tag)
+	total, ok = r.GetColVal(totalCol.Tag)
+	assertFormatted(t, nameCol.TypeInfo, "aaron", name)
+	assert.True(t, !ok || types.IsNull(total))
+
+	_, err = rd.ReadRow(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func assertFormatted(t *testing.T, ti typeinfo.TypeInfo, expected string, val types.Value) {
+	str, err := ti.FormatValue(val)
+	require.NoError(t, err)
+	require.NotNil(t, str)
+	assert.Equal(t, expected, *str)
+}
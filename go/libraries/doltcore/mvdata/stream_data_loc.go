@@ -25,6 +25,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/json"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/typed/noms"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/untyped/csv"
 	"github.com/dolthub/dolt/go/libraries/utils/filesys"
@@ -69,6 +70,15 @@ func (dl StreamDataLocation) NewReader(ctx context.Context, root *doltdb.RootVal
 	case PsvFile:
 		rd, err := csv.NewCSVReader(root.VRW().Format(), ioutil.NopCloser(dl.Reader), csv.NewCSVInfo().SetDelim("|"))
 		return rd, false, err
+
+	case JsonlFile:
+		sch, err := jsonSchema(ctx, root, fs, opts)
+		if err != nil {
+			return nil, false, err
+		}
+
+		rd, err := json.NewJSONLReader(root.VRW(), ioutil.NopCloser(dl.Reader), sch)
+		return rd, false, err
 	}
 
 	return nil, false, errors.New(string(dl.Format) + "is an unsupported format to read from stdin")
@@ -83,6 +93,9 @@ func (dl StreamDataLocation) NewCreatingWriter(_ context.Context, _ DataMoverOpt
 
 	case PsvFile:
 		return csv.NewCSVWriter(iohelp.NopWrCloser(dl.Writer), outSch, csv.NewCSVInfo().SetDelim("|"))
+
+	case JsonlFile:
+		return json.NewJSONLWriter(iohelp.NopWrCloser(dl.Writer), outSch)
 	}
 
 	return nil, errors.New(string(dl.Format) + "is an unsupported format to write to stdout")
@@ -17,6 +17,7 @@ package mvdata
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync/atomic"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -226,7 +227,7 @@ func (te *tableEditorWriteCloser) WriteRow(ctx context.Context, r row.Row) error
 		err := te.tableEditor.InsertRow(ctx, r)
 
 		if err != nil {
-			return err
+			return badRowIfViolation(r, err)
 		}
 
 		_ = atomic.AddInt64(&te.statOps, 1)
@@ -246,7 +247,7 @@ func (te *tableEditorWriteCloser) WriteRow(ctx context.Context, r row.Row) error
 			err := te.tableEditor.InsertRow(ctx, r)
 
 			if err != nil {
-				return err
+				return badRowIfViolation(r, err)
 			}
 
 			_ = atomic.AddInt64(&te.statOps, 1)
@@ -264,7 +265,7 @@ func (te *tableEditorWriteCloser) WriteRow(ctx context.Context, r row.Row) error
 		err = te.tableEditor.UpdateRow(ctx, oldRow, r)
 
 		if err != nil {
-			return err
+			return badRowIfViolation(r, err)
 		}
 
 		_ = atomic.AddInt64(&te.statOps, 1)
@@ -273,6 +274,15 @@ func (te *tableEditorWriteCloser) WriteRow(ctx context.Context, r row.Row) error
 	}
 }
 
+// badRowIfViolation returns a bad row error for |r| if |err| is a foreign key violation, so that rows which violate
+// foreign keys can be skipped like other bad rows. Other errors are returned unchanged.
+func badRowIfViolation(r row.Row, err error) error {
+	if strings.HasPrefix(err.Error(), "foreign key violation") {
+		return table.NewBadRow(r, err.Error())
+	}
+	return err
+}
+
 func (te *tableEditorWriteCloser) GC(ctx context.Context) error {
 	if !te.useGC {
 		return nil
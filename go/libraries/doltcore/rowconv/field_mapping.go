@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
@@ -74,6 +75,45 @@ func (nm NameMapper) PreImage(str string) string {
 	return str
 }
 
+// ExprMappingPrefix begins the values of a mapping file that are SQL expressions. The key of such a value is the name
+// of the field that the expression computes.
+const ExprMappingPrefix = "="
+
+// WhereMappingKey is the key of a mapping file whose value is a SQL expression that each row must match to be mapped.
+const WhereMappingKey = "$where"
+
+// ExprMapping holds the fields that are computed by SQL expressions in a mapping file, and the expression that filters
+// the rows being mapped.
+type ExprMapping struct {
+	// Exprs are the SQL expressions of the computed fields, keyed by the names of the fields
+	Exprs map[string]string
+	// Where is the SQL expression that rows must match to be mapped, or "" if every row is mapped
+	Where string
+}
+
+// IsEmpty returns true if the mapping doesn't compute any fields or filter any rows
+func (em ExprMapping) IsEmpty() bool {
+	return len(em.Exprs) == 0 && em.Where == ""
+}
+
+// SplitExprMapping splits the entries of a mapping file that are SQL expressions from those that rename fields. It
+// returns a NameMapper with the renames, along with the ExprMapping of the expressions.
+func SplitExprMapping(nm NameMapper) (NameMapper, ExprMapping) {
+	names := make(NameMapper)
+	exprMapping := ExprMapping{Exprs: make(map[string]string)}
+	for k, v := range nm {
+		switch {
+		case k == WhereMappingKey:
+			exprMapping.Where = v
+		case strings.HasPrefix(v, ExprMappingPrefix):
+			exprMapping.Exprs[k] = strings.TrimSpace(v[len(ExprMappingPrefix):])
+		default:
+			names[k] = v
+		}
+	}
+	return names, exprMapping
+}
+
 // FieldMapping defines a mapping from columns in a source schema to columns in a dest schema.
 type FieldMapping struct {
 	// SrcSch is the source schema being mapped from.
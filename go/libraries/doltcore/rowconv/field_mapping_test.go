@@ -112,3 +112,32 @@ func TestFieldMapping(t *testing.T) {
 		}
 	}
 }
+
+func TestSplitExprMapping(t *testing.T) {
+	nm := NameMapper{
+		"a":      "b",
+		"c":      "=UPPER(a)",
+		"d":      "= 1 + 2",
+		"$where": "a > 1",
+	}
+
+	names, exprMapping := SplitExprMapping(nm)
+
+	if !reflect.DeepEqual(names, NameMapper{"a": "b"}) {
+		t.Error("unexpected renames:", names)
+	}
+
+	expectedExprs := map[string]string{"c": "UPPER(a)", "d": "1 + 2"}
+	if !reflect.DeepEqual(exprMapping.Exprs, expectedExprs) {
+		t.Error("unexpected expressions:", exprMapping.Exprs)
+	}
+
+	if exprMapping.Where != "a > 1" {
+		t.Error("unexpected filter:", exprMapping.Where)
+	}
+
+	_, exprMapping = SplitExprMapping(NameMapper{"a": "b"})
+	if !exprMapping.IsEmpty() {
+		t.Error("expected an empty expression mapping")
+	}
+}
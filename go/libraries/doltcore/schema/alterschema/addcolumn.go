@@ -120,6 +120,7 @@ func addColumnToSchema(sch schema.Schema, tag uint64, newColName string, typeInf
 		return nil, err
 	}
 	newSch.Indexes().AddIndex(sch.Indexes().AllIndexes()...)
+	schema.CopyChecks(sch, newSch)
 
 	return newSch, nil
 }
@@ -91,6 +91,7 @@ func DropColumn(ctx context.Context, tbl *doltdb.Table, colName string, foreignK
 		return nil, err
 	}
 	newSch.Indexes().AddIndex(sch.Indexes().AllIndexes()...)
+	schema.CopyChecks(sch, newSch)
 
 	return tbl.UpdateSchema(ctx, newSch)
 }
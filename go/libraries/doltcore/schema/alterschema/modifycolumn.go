@@ -332,5 +332,6 @@ func replaceColumnInSchema(sch schema.Schema, oldCol schema.Column, newCol schem
 			return nil, err
 		}
 	}
+	schema.CopyChecks(sch, newSch)
 	return newSch, nil
 }
@@ -57,7 +57,9 @@ func TestModifyColumn(t *testing.T) {
 		order          *ColumnOrder
 		expectedSchema schema.Schema
 		expectedRows   []row.Row
-		expectedErr    string
+		// blobTags are the columns whose values are stored in blobs, which are compared to expectedRows as strings
+		blobTags    []uint64
+		expectedErr string
 	}{
 		{
 			name:           "column rename",
@@ -163,6 +165,7 @@ func TestModifyColumn(t *testing.T) {
 			newColumn:      newNameColSameTag,
 			expectedSchema: alteredTypeSch2,
 			expectedRows:   dtestutils.TypedRows,
+			blobTags:       []uint64{dtestutils.NameTag},
 		},
 	}
 
@@ -203,6 +206,10 @@ func TestModifyColumn(t *testing.T) {
 					return false, err
 				}
 
+				for _, tag := range tt.blobTags {
+					tpl = blobToString(t, tt.expectedSchema, tpl, tag)
+				}
+
 				foundRows = append(foundRows, tpl)
 				return false, nil
 			})
@@ -220,3 +227,19 @@ func TestModifyColumn(t *testing.T) {
 		})
 	}
 }
+
+// blobToString returns |r| with the value of the column |tag|, which must be stored in a blob, replaced by its string.
+func blobToString(t *testing.T, sch schema.Schema, r row.Row, tag uint64) row.Row {
+	col, ok := sch.GetAllCols().GetByTag(tag)
+	require.True(t, ok)
+	val, ok := r.GetColVal(tag)
+	require.True(t, ok)
+	blob, ok := val.(types.Blob)
+	require.True(t, ok, "expected a blob, got %v", val.Kind())
+
+	str, err := col.TypeInfo.FormatValue(blob)
+	require.NoError(t, err)
+	r, err = r.SetColVal(tag, types.String(*str), sch)
+	require.NoError(t, err)
+	return r
+}
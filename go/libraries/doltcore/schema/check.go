@@ -0,0 +1,158 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Check is a table-level CHECK constraint. Its expression is stored as SQL text, and rows for which it evaluates to
+// false violate the constraint.
+type Check interface {
+	// Name returns the name of the check constraint.
+	Name() string
+	// Expression returns the SQL expression of the check constraint.
+	Expression() string
+	// Enforced returns whether rows that are written to the table are checked against the constraint.
+	Enforced() bool
+}
+
+type CheckCollection interface {
+	// AddCheck adds a check constraint with the given name and expression.
+	AddCheck(name, expression string, enforced bool) (Check, error)
+	// AllChecks returns all of the check constraints in the order that they were added.
+	AllChecks() []Check
+	// Count returns the number of check constraints in this collection.
+	Count() int
+	// DropCheck removes the check constraint with the given case-insensitive name.
+	DropCheck(name string) error
+	// Equals returns whether this check collection has the same checks, in the same order, as another.
+	Equals(other CheckCollection) bool
+	// GetByNameCaseInsensitive returns the check constraint with a matching case-insensitive name, the bool return
+	// value indicates if a match was found.
+	GetByNameCaseInsensitive(name string) (Check, bool)
+}
+
+type check struct {
+	name       string
+	expression string
+	enforced   bool
+}
+
+var _ Check = check{}
+
+// Name implements Check.
+func (c check) Name() string {
+	return c.name
+}
+
+// Expression implements Check.
+func (c check) Expression() string {
+	return c.expression
+}
+
+// Enforced implements Check.
+func (c check) Enforced() bool {
+	return c.enforced
+}
+
+type checkCollection struct {
+	checks []check
+}
+
+var _ CheckCollection = (*checkCollection)(nil)
+
+func NewCheckCollection() CheckCollection {
+	return &checkCollection{}
+}
+
+// AddCheck implements CheckCollection.
+func (cc *checkCollection) AddCheck(name, expression string, enforced bool) (Check, error) {
+	if len(name) == 0 {
+		return nil, fmt.Errorf("check constraints must have a name")
+	}
+	if len(strings.TrimSpace(expression)) == 0 {
+		return nil, fmt.Errorf("check constraint `%s` has an empty expression", name)
+	}
+	if _, ok := cc.GetByNameCaseInsensitive(name); ok {
+		return nil, fmt.Errorf("`%s` already exists as a check constraint for this table", name)
+	}
+
+	c := check{name: name, expression: expression, enforced: enforced}
+	cc.checks = append(cc.checks, c)
+	return c, nil
+}
+
+// AllChecks implements CheckCollection.
+func (cc *checkCollection) AllChecks() []Check {
+	checks := make([]Check, len(cc.checks))
+	for i, c := range cc.checks {
+		checks[i] = c
+	}
+	return checks
+}
+
+// Count implements CheckCollection.
+func (cc *checkCollection) Count() int {
+	return len(cc.checks)
+}
+
+// DropCheck implements CheckCollection.
+func (cc *checkCollection) DropCheck(name string) error {
+	for i, c := range cc.checks {
+		if strings.EqualFold(c.name, name) {
+			cc.checks = append(cc.checks[:i:i], cc.checks[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("`%s` does not exist as a check constraint for this table", name)
+}
+
+// Equals implements CheckCollection.
+func (cc *checkCollection) Equals(other CheckCollection) bool {
+	otherChecks := other.AllChecks()
+	if len(cc.checks) != len(otherChecks) {
+		return false
+	}
+	for i, c := range cc.checks {
+		if c.name != otherChecks[i].Name() ||
+			c.expression != otherChecks[i].Expression() ||
+			c.enforced != otherChecks[i].Enforced() {
+			return false
+		}
+	}
+	return true
+}
+
+// GetByNameCaseInsensitive implements CheckCollection.
+func (cc *checkCollection) GetByNameCaseInsensitive(name string) (Check, bool) {
+	for _, c := range cc.checks {
+		if strings.EqualFold(c.name, name) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// CopyChecks adds the check constraints of |from| to |to|, skipping any whose names are already in use.
+func CopyChecks(from, to Schema) {
+	for _, c := range from.Checks().AllChecks() {
+		if _, ok := to.Checks().GetByNameCaseInsensitive(c.Name()); ok {
+			continue
+		}
+		_, _ = to.Checks().AddCheck(c.Name(), c.Expression(), c.Enforced())
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckCollectionAddAndDrop(t *testing.T) {
+	checks := NewCheckCollection()
+
+	_, err := checks.AddCheck("chk_price", "price > 0", true)
+	require.NoError(t, err)
+	_, err = checks.AddCheck("chk_qty", "qty >= 0", false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, checks.Count())
+
+	_, err = checks.AddCheck("CHK_PRICE", "price > 1", true)
+	assert.Error(t, err)
+	_, err = checks.AddCheck("", "price > 1", true)
+	assert.Error(t, err)
+	_, err = checks.AddCheck("chk_empty", " ", true)
+	assert.Error(t, err)
+
+	chk, ok := checks.GetByNameCaseInsensitive("Chk_Qty")
+	require.True(t, ok)
+	assert.Equal(t, "chk_qty", chk.Name())
+	assert.Equal(t, "qty >= 0", chk.Expression())
+	assert.False(t, chk.Enforced())
+
+	require.NoError(t, checks.DropCheck("CHK_PRICE"))
+	assert.Error(t, checks.DropCheck("chk_price"))
+	assert.Equal(t, []Check{chk}, checks.AllChecks())
+}
+
+func TestCheckCollectionEquals(t *testing.T) {
+	newChecks := func(enforced bool, exprs ...string) CheckCollection {
+		checks := NewCheckCollection()
+		for i, expr := range exprs {
+			_, err := checks.AddCheck(string(rune('a'+i)), expr, enforced)
+			require.NoError(t, err)
+		}
+		return checks
+	}
+
+	assert.True(t, newChecks(true).Equals(newChecks(true)))
+	assert.True(t, newChecks(true, "x > 0", "y > 0").Equals(newChecks(true, "x > 0", "y > 0")))
+	assert.False(t, newChecks(true, "x > 0", "y > 0").Equals(newChecks(true, "y > 0", "x > 0")))
+	assert.False(t, newChecks(true, "x > 0").Equals(newChecks(false, "x > 0")))
+	assert.False(t, newChecks(true, "x > 0").Equals(newChecks(true)))
+}
@@ -146,9 +146,16 @@ type encodedIndex struct {
 	IsSystemDefined bool     `noms:"hidden,omitempty" json:"hidden,omitempty"` // Was previously named Hidden, do not change noms name
 }
 
+type encodedCheck struct {
+	Name       string `noms:"name" json:"name"`
+	Expression string `noms:"expression" json:"expression"`
+	Enforced   bool   `noms:"enforced" json:"enforced"`
+}
+
 type schemaData struct {
 	Columns         []encodedColumn `noms:"columns" json:"columns"`
 	IndexCollection []encodedIndex  `noms:"idxColl,omitempty" json:"idxColl,omitempty"`
+	CheckCollection []encodedCheck  `noms:"checks,omitempty" json:"checks,omitempty"`
 }
 
 func toSchemaData(sch schema.Schema) (schemaData, error) {
@@ -178,7 +185,16 @@ func toSchemaData(sch schema.Schema) (schemaData, error) {
 		}
 	}
 
-	return schemaData{encCols, encodedIndexes}, nil
+	encodedChecks := make([]encodedCheck, sch.Checks().Count())
+	for i, check := range sch.Checks().AllChecks() {
+		encodedChecks[i] = encodedCheck{
+			Name:       check.Name(),
+			Expression: check.Expression(),
+			Enforced:   check.Enforced(),
+		}
+	}
+
+	return schemaData{encCols, encodedIndexes, encodedChecks}, nil
 }
 
 func (sd schemaData) decodeSchema() (schema.Schema, error) {
@@ -215,6 +231,13 @@ func (sd schemaData) decodeSchema() (schema.Schema, error) {
 		}
 	}
 
+	for _, encodedCheck := range sd.CheckCollection {
+		_, err = sch.Checks().AddCheck(encodedCheck.Name, encodedCheck.Expression, encodedCheck.Enforced)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return sch, nil
 }
 
@@ -208,6 +208,8 @@ func bitTypeConverter(ctx context.Context, src *bitType, destTi TypeInfo) (tc Ty
 		} else {
 			return wrapIsValid(dest.IsValid, src, dest)
 		}
+	case *blobStringType:
+		return bitTypeConverterInterpretAsString(ctx, src, destTi)
 	case *boolType:
 		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
 	case *datetimeType:
@@ -234,6 +236,8 @@ func bitTypeConverter(ctx context.Context, src *bitType, destTi TypeInfo) (tc Ty
 		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
 	case *intType:
 		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *jsonType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
 	case *setType:
 		return wrapIsValid(dest.IsValid, src, dest)
 	case *timeType:
@@ -291,10 +295,15 @@ func bitTypeConverterInterpretAsString(ctx context.Context, src *bitType, destTi
 			bytes[i], bytes[j] = bytes[j], bytes[i]
 		}
 		s := string(bytes)
-		if dest, ok := destTi.(*varStringType); ok && dest.sqlStringType.Type() == sqltypes.Text {
-			if !utf8.ValidString(s) {
-				return nil, fmt.Errorf(`invalid %s value: "%s"`, strings.ToLower(dest.String()), s)
-			}
+		isText := false
+		switch dest := destTi.(type) {
+		case *blobStringType:
+			isText = true
+		case *varStringType:
+			isText = dest.sqlStringType.Type() == sqltypes.Text
+		}
+		if isText && !utf8.ValidString(s) {
+			return nil, fmt.Errorf(`invalid %s value: "%s"`, strings.ToLower(destTi.String()), s)
 		}
 		return destTi.ConvertValueToNomsValue(ctx, vrw, s)
 	}, true, nil
@@ -0,0 +1,244 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	blobStringTypeParam_Collate = "collate"
+	blobStringTypeParam_Length  = "length"
+)
+
+// blobStringType is the TypeInfo for the TEXT types. Like varBinaryType does for the BLOB types, it stores its values
+// in a types.Blob, so that large values are chunked separately from the row that references them. Rows only hold the
+// ref of a large value, which is all that diffs and merges need to compare, so they don't read its bytes. Reads of
+// the value itself aren't lazy: ConvertNomsValueToValue and ReadFrom read the whole blob every time a row holding it
+// is decoded.
+//
+// Index rows are ordered by the refs of blobs rather than by their contents, so indexes on TEXT columns only serve
+// equality lookups. Range lookups on them scan every row of the index, see doltIndex.orderedByValue.
+type blobStringType struct {
+	sqlStringType sql.StringType
+}
+
+var _ TypeInfo = (*blobStringType)(nil)
+
+func CreateBlobStringTypeFromParams(params map[string]string) (TypeInfo, error) {
+	var length int64
+	var collation sql.Collation
+	var err error
+	if collationStr, ok := params[blobStringTypeParam_Collate]; ok {
+		collation, err = sql.ParseCollation(nil, &collationStr, false)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf(`create blobstring type info is missing param "%v"`, blobStringTypeParam_Collate)
+	}
+	if lengthStr, ok := params[blobStringTypeParam_Length]; ok {
+		length, err = strconv.ParseInt(lengthStr, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, fmt.Errorf(`create blobstring type info is missing param "%v"`, blobStringTypeParam_Length)
+	}
+	sqlType, err := sql.CreateString(sqltypes.Text, length, collation)
+	if err != nil {
+		return nil, err
+	}
+	return &blobStringType{sqlType}, nil
+}
+
+// ConvertNomsValueToValue implements TypeInfo interface.
+func (ti *blobStringType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+	if val, ok := v.(types.Blob); ok {
+		return fromBlob(val)
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, ti.String(), v.Kind())
+}
+
+// ReadFrom reads a go value from a noms types.CodecReader directly
+func (ti *blobStringType) ReadFrom(_ *types.NomsBinFormat, reader types.CodecReader) (interface{}, error) {
+	k := reader.PeekKind()
+	switch k {
+	case types.BlobKind:
+		val, err := reader.ReadBlob()
+		if err != nil {
+			return nil, err
+		}
+		return fromBlob(val)
+	case types.NullKind:
+		_ = reader.ReadKind()
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, ti.String(), k)
+}
+
+// ConvertValueToNomsValue implements TypeInfo interface.
+func (ti *blobStringType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
+	if v == nil {
+		return types.NullValue, nil
+	}
+	strVal, err := ti.sqlStringType.Convert(v)
+	if err != nil {
+		return nil, err
+	}
+	val, ok := strVal.(string)
+	if ok {
+		return toBlob(ctx, vrw, val)
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert value "%v" of type "%T" as it is invalid`, ti.String(), v, v)
+}
+
+// Equals implements TypeInfo interface.
+func (ti *blobStringType) Equals(other TypeInfo) bool {
+	if other == nil {
+		return false
+	}
+	if ti2, ok := other.(*blobStringType); ok {
+		return ti.sqlStringType.MaxCharacterLength() == ti2.sqlStringType.MaxCharacterLength() &&
+			ti.sqlStringType.Collation() == ti2.sqlStringType.Collation()
+	}
+	return false
+}
+
+// FormatValue implements TypeInfo interface.
+func (ti *blobStringType) FormatValue(v types.Value) (*string, error) {
+	if val, ok := v.(types.Blob); ok {
+		resStr, err := fromBlob(val)
+		if err != nil {
+			return nil, err
+		}
+		return &resStr, nil
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a string`, ti.String(), v.Kind())
+}
+
+// GetTypeIdentifier implements TypeInfo interface.
+func (ti *blobStringType) GetTypeIdentifier() Identifier {
+	return BlobStringTypeIdentifier
+}
+
+// GetTypeParams implements TypeInfo interface.
+func (ti *blobStringType) GetTypeParams() map[string]string {
+	return map[string]string{
+		blobStringTypeParam_Collate: ti.sqlStringType.Collation().String(),
+		blobStringTypeParam_Length:  strconv.FormatInt(ti.sqlStringType.MaxCharacterLength(), 10),
+	}
+}
+
+// IsValid implements TypeInfo interface.
+func (ti *blobStringType) IsValid(v types.Value) bool {
+	if val, ok := v.(types.Blob); ok {
+		strLen, err := fromBlobLength(val)
+		if err != nil {
+			return false
+		}
+		if int64(strLen) <= ti.sqlStringType.MaxByteLength() {
+			return true
+		}
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return true
+	}
+	return false
+}
+
+// NomsKind implements TypeInfo interface.
+func (ti *blobStringType) NomsKind() types.NomsKind {
+	return types.BlobKind
+}
+
+// ParseValue implements TypeInfo interface.
+func (ti *blobStringType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
+	if str == nil {
+		return types.NullValue, nil
+	}
+	return ti.ConvertValueToNomsValue(ctx, vrw, *str)
+}
+
+// Promote implements TypeInfo interface.
+func (ti *blobStringType) Promote() TypeInfo {
+	return &blobStringType{ti.sqlStringType.Promote().(sql.StringType)}
+}
+
+// String implements TypeInfo interface.
+func (ti *blobStringType) String() string {
+	return fmt.Sprintf(`BlobString(%v, %v)`, ti.sqlStringType.Collation().String(), ti.sqlStringType.MaxCharacterLength())
+}
+
+// ToSqlType implements TypeInfo interface.
+func (ti *blobStringType) ToSqlType() sql.Type {
+	return ti.sqlStringType
+}
+
+// blobStringTypeConverter is an internal function for GetTypeConverter that handles the specific type as the source TypeInfo.
+func blobStringTypeConverter(ctx context.Context, src *blobStringType, destTi TypeInfo) (tc TypeConverter, needsConversion bool, err error) {
+	switch dest := destTi.(type) {
+	case *bitType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *blobStringType:
+		return wrapIsValid(dest.IsValid, src, dest)
+	case *boolType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *datetimeType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *decimalType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *enumType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *floatType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *inlineBlobType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *intType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *jsonType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *setType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *timeType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *uintType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *uuidType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *varBinaryType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *varStringType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *yearType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	default:
+		return nil, false, UnhandledTypeConversion.New(src.String(), destTi.String())
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestBlobStringRoundTrip(t *testing.T) {
+	tests := []string{
+		"",
+		"a",
+		"هذا هو بعض نماذج النص التي أستخدمها لاختبار عناصر",
+		strings.Repeat("abcdefghijklmnopqrstuvwxyz", 100000),
+	}
+
+	ti := &blobStringType{sql.CreateLongText(sql.Collation_Default)}
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("length %d", len(test)), func(t *testing.T) {
+			ctx := context.Background()
+			vrw := types.NewMemoryValueStore()
+
+			val, err := ti.ConvertValueToNomsValue(ctx, vrw, test)
+			require.NoError(t, err)
+			require.Equal(t, types.BlobKind, val.Kind())
+			assert.True(t, ti.IsValid(val))
+
+			out, err := ti.ConvertNomsValueToValue(val)
+			require.NoError(t, err)
+			assert.Equal(t, test, out)
+
+			str, err := ti.FormatValue(val)
+			require.NoError(t, err)
+			assert.Equal(t, test, *str)
+
+			parsed, err := ti.ParseValue(ctx, vrw, str)
+			require.NoError(t, err)
+			assert.True(t, val.Equals(parsed))
+
+			tup, err := types.NewTuple(vrw.Format(), val)
+			require.NoError(t, err)
+			itr, err := tup.Iterator()
+			require.NoError(t, err)
+			reader, _ := itr.CodecReader()
+			read, err := ti.ReadFrom(vrw.Format(), reader)
+			require.NoError(t, err)
+			assert.Equal(t, test, read)
+		})
+	}
+}
+
+func TestBlobStringTypeParams(t *testing.T) {
+	for _, sqlType := range []sql.StringType{
+		sql.CreateTinyText(sql.Collation_Default),
+		sql.CreateText(sql.Collation_utf8mb4_bin),
+		sql.CreateMediumText(sql.Collation_Default),
+		sql.CreateLongText(sql.Collation_Default),
+	} {
+		t.Run(sqlType.String(), func(t *testing.T) {
+			ti, err := FromSqlType(sqlType)
+			require.NoError(t, err)
+			require.Equal(t, BlobStringTypeIdentifier, ti.GetTypeIdentifier())
+			assert.Equal(t, sqlType, ti.ToSqlType())
+
+			newTi, err := FromTypeParams(ti.GetTypeIdentifier(), ti.GetTypeParams())
+			require.NoError(t, err)
+			assert.True(t, ti.Equals(newTi))
+		})
+	}
+}
+
+func TestBlobStringIsValid(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	tinyText := &blobStringType{sql.CreateTinyText(sql.Collation_Default)}
+	longText := &blobStringType{sql.CreateLongText(sql.Collation_Default)}
+
+	val, err := longText.ConvertValueToNomsValue(ctx, vrw, strings.Repeat("a", 1000))
+	require.NoError(t, err)
+	assert.True(t, longText.IsValid(val))
+	assert.False(t, tinyText.IsValid(val))
+	assert.False(t, longText.IsValid(types.String("a")))
+}
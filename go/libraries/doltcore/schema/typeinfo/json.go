@@ -0,0 +1,241 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// jsonType stores JSON documents as Noms strings in a canonical encoding: object keys are sorted, insignificant
+// whitespace is removed, and numbers are kept exactly as written. Documents that are equal therefore have equal Noms
+// values, so diffs and merges only ever see changes to the content of a document.
+type jsonType struct {
+	sqlJSONType sql.JsonType
+}
+
+var _ TypeInfo = (*jsonType)(nil)
+
+var JSONType = &jsonType{sql.JSON}
+
+// ConvertNomsValueToValue implements TypeInfo interface.
+func (ti *jsonType) ConvertNomsValueToValue(v types.Value) (interface{}, error) {
+	if val, ok := v.(types.String); ok {
+		return []byte(val), nil
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, ti.String(), v.Kind())
+}
+
+// ReadFrom reads a go value from a noms types.CodecReader directly
+func (ti *jsonType) ReadFrom(_ *types.NomsBinFormat, reader types.CodecReader) (interface{}, error) {
+	k := reader.ReadKind()
+	switch k {
+	case types.StringKind:
+		return []byte(reader.ReadString()), nil
+	case types.NullKind:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a value`, ti.String(), k)
+}
+
+// ConvertValueToNomsValue implements TypeInfo interface.
+func (ti *jsonType) ConvertValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
+	var doc string
+	var err error
+	switch val := v.(type) {
+	case nil:
+		return types.NullValue, nil
+	case string:
+		doc, err = canonicalJSON([]byte(val))
+	case []byte:
+		doc, err = canonicalJSON(val)
+	default:
+		doc, err = marshalJSON(val)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(`"%v" cannot convert value "%v" of type "%T" as it is invalid: %v`, ti.String(), v, v, err)
+	}
+	return types.String(doc), nil
+}
+
+// Equals implements TypeInfo interface.
+func (ti *jsonType) Equals(other TypeInfo) bool {
+	if other == nil {
+		return false
+	}
+	_, ok := other.(*jsonType)
+	return ok
+}
+
+// FormatValue implements TypeInfo interface.
+func (ti *jsonType) FormatValue(v types.Value) (*string, error) {
+	if val, ok := v.(types.String); ok {
+		res := string(val)
+		return &res, nil
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return nil, nil
+	}
+	return nil, fmt.Errorf(`"%v" cannot convert NomsKind "%v" to a string`, ti.String(), v.Kind())
+}
+
+// GetTypeIdentifier implements TypeInfo interface.
+func (ti *jsonType) GetTypeIdentifier() Identifier {
+	return JSONTypeIdentifier
+}
+
+// GetTypeParams implements TypeInfo interface.
+func (ti *jsonType) GetTypeParams() map[string]string {
+	return nil
+}
+
+// IsValid implements TypeInfo interface.
+func (ti *jsonType) IsValid(v types.Value) bool {
+	if val, ok := v.(types.String); ok {
+		return json.Valid([]byte(val))
+	}
+	if _, ok := v.(types.Null); ok || v == nil {
+		return true
+	}
+	return false
+}
+
+// NomsKind implements TypeInfo interface.
+func (ti *jsonType) NomsKind() types.NomsKind {
+	return types.StringKind
+}
+
+// ParseValue implements TypeInfo interface. Like ConvertValueToNomsValue and MySQL, an empty string is rejected, as it
+// isn't a JSON document; only a nil |str| is NULL.
+func (ti *jsonType) ParseValue(ctx context.Context, vrw types.ValueReadWriter, str *string) (types.Value, error) {
+	if str == nil {
+		return types.NullValue, nil
+	}
+	return ti.ConvertValueToNomsValue(ctx, vrw, *str)
+}
+
+// Promote implements TypeInfo interface.
+func (ti *jsonType) Promote() TypeInfo {
+	return ti
+}
+
+// String implements TypeInfo interface.
+func (ti *jsonType) String() string {
+	return "JSON"
+}
+
+// ToSqlType implements TypeInfo interface.
+func (ti *jsonType) ToSqlType() sql.Type {
+	return ti.sqlJSONType
+}
+
+// UnmarshalJSONValue decodes a JSON document stored by a JSON column, keeping numbers as json.Number so that they are
+// not altered when the document is encoded again.
+func UnmarshalJSONValue(v types.String) (interface{}, error) {
+	dec := json.NewDecoder(strings.NewReader(string(v)))
+	dec.UseNumber()
+
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after the end of the JSON document")
+	}
+
+	return doc, nil
+}
+
+// MarshalJSONValue encodes a decoded JSON document in the canonical form stored by JSON columns.
+func MarshalJSONValue(doc interface{}) (types.String, error) {
+	str, err := marshalJSON(doc)
+	if err != nil {
+		return "", err
+	}
+	return types.String(str), nil
+}
+
+// canonicalJSON returns the canonical encoding of the JSON document |data|.
+func canonicalJSON(data []byte) (string, error) {
+	doc, err := UnmarshalJSONValue(types.String(data))
+	if err != nil {
+		return "", err
+	}
+	return marshalJSON(doc)
+}
+
+func marshalJSON(doc interface{}) (string, error) {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// jsonTypeConverter is an internal function for GetTypeConverter that handles the specific type as the source TypeInfo.
+func jsonTypeConverter(ctx context.Context, src *jsonType, destTi TypeInfo) (tc TypeConverter, needsConversion bool, err error) {
+	switch dest := destTi.(type) {
+	case *bitType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *blobStringType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *boolType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *datetimeType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *decimalType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *enumType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *floatType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *inlineBlobType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *intType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *jsonType:
+		return wrapIsValid(dest.IsValid, src, dest)
+	case *setType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *timeType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *uintType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *uuidType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *varBinaryType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *varStringType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	case *yearType:
+		return wrapConvertValueToNomsValue(dest.ConvertValueToNomsValue)
+	default:
+		return nil, false, UnhandledTypeConversion.New(src.String(), destTi.String())
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package typeinfo
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestJSONConvertNomsValueToValue(t *testing.T) {
+	tests := []struct {
+		input  types.String
+		output []byte
+	}{
+		{
+			types.String(`null`),
+			[]byte(`null`),
+		},
+		{
+			types.String(`{"a":1,"b":[true,"c"]}`),
+			[]byte(`{"a":1,"b":[true,"c"]}`),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf(`%v %v`, JSONType.String(), test.input), func(t *testing.T) {
+			output, err := JSONType.ConvertNomsValueToValue(test.input)
+			require.NoError(t, err)
+			require.Equal(t, test.output, output)
+		})
+	}
+}
+
+func TestJSONConvertValueToNomsValue(t *testing.T) {
+	tests := []struct {
+		input       interface{}
+		output      types.String
+		expectedErr bool
+	}{
+		{
+			`{"b": [1, 2.50, "x"], "a": {"d": null, "c": false}}`,
+			types.String(`{"a":{"c":false,"d":null},"b":[1,2.50,"x"]}`),
+			false,
+		},
+		{
+			[]byte(` "a<b" `),
+			types.String(`"a<b"`),
+			false,
+		},
+		{
+			int64(7),
+			types.String(`7`),
+			false,
+		},
+		{
+			map[string]interface{}{"z": 1, "y": []interface{}{"q"}},
+			types.String(`{"y":["q"],"z":1}`),
+			false,
+		},
+		{
+			"something",
+			"",
+			true,
+		},
+		{
+			`{"a": 1} {"b": 2}`,
+			"",
+			true,
+		},
+		{
+			"",
+			"",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf(`%v %v`, JSONType.String(), test.input), func(t *testing.T) {
+			vrw := types.NewMemoryValueStore()
+			output, err := JSONType.ConvertValueToNomsValue(context.Background(), vrw, test.input)
+			if !test.expectedErr {
+				require.NoError(t, err)
+				assert.Equal(t, test.output, output)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestJSONParseValue(t *testing.T) {
+	tests := []struct {
+		input       string
+		output      types.String
+		expectedErr bool
+	}{
+		{
+			`[ {"b": 1, "a": 2} ]`,
+			types.String(`[{"a":2,"b":1}]`),
+			false,
+		},
+		{
+			`{"a": }`,
+			"",
+			true,
+		},
+		{
+			"",
+			"",
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf(`%v %v`, JSONType.String(), test.input), func(t *testing.T) {
+			vrw := types.NewMemoryValueStore()
+			output, err := JSONType.ParseValue(context.Background(), vrw, &test.input)
+			if !test.expectedErr {
+				require.NoError(t, err)
+				assert.Equal(t, test.output, output)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		output, err := JSONType.ParseValue(context.Background(), types.NewMemoryValueStore(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, types.NullValue, output)
+	})
+}
+
+// An empty string isn't a JSON document, whether it's parsed by an import or converted from a SQL value.
+func TestJSONEmptyString(t *testing.T) {
+	vrw := types.NewMemoryValueStore()
+	empty := ""
+
+	_, parseErr := JSONType.ParseValue(context.Background(), vrw, &empty)
+	_, convertErr := JSONType.ConvertValueToNomsValue(context.Background(), vrw, empty)
+	assert.Error(t, parseErr)
+	assert.Error(t, convertErr)
+}
+
+func TestJSONFromSqlType(t *testing.T) {
+	ti, err := FromSqlType(sql.JSON)
+	require.NoError(t, err)
+	assert.True(t, JSONType.Equals(ti))
+	assert.Equal(t, sql.JSON, ti.ToSqlType())
+}
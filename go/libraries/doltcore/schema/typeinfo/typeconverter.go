@@ -50,6 +50,8 @@ func GetTypeConverter(ctx context.Context, srcTi TypeInfo, destTi TypeInfo) (tc
 	switch src := srcTi.(type) {
 	case *bitType:
 		return bitTypeConverter(ctx, src, destTi)
+	case *blobStringType:
+		return blobStringTypeConverter(ctx, src, destTi)
 	case *boolType:
 		return boolTypeConverter(ctx, src, destTi)
 	case *datetimeType:
@@ -64,6 +66,8 @@ func GetTypeConverter(ctx context.Context, srcTi TypeInfo, destTi TypeInfo) (tc
 		return inlineBlobTypeConverter(ctx, src, destTi)
 	case *intType:
 		return intTypeConverter(ctx, src, destTi)
+	case *jsonType:
+		return jsonTypeConverter(ctx, src, destTi)
 	case *setType:
 		return setTypeConverter(ctx, src, destTi)
 	case *timeType:
@@ -30,6 +30,7 @@ type Identifier string
 const (
 	UnknownTypeIdentifier    Identifier = "unknown"
 	BitTypeIdentifier        Identifier = "bit"
+	BlobStringTypeIdentifier Identifier = "blobstring"
 	BoolTypeIdentifier       Identifier = "bool"
 	DatetimeTypeIdentifier   Identifier = "datetime"
 	DecimalTypeIdentifier    Identifier = "decimal"
@@ -37,6 +38,7 @@ const (
 	FloatTypeIdentifier      Identifier = "float"
 	InlineBlobTypeIdentifier Identifier = "inlineblob"
 	IntTypeIdentifier        Identifier = "int"
+	JSONTypeIdentifier       Identifier = "json"
 	SetTypeIdentifier        Identifier = "set"
 	TimeTypeIdentifier       Identifier = "time"
 	TupleTypeIdentifier      Identifier = "tuple"
@@ -50,6 +52,7 @@ const (
 var Identifiers = map[Identifier]struct{}{
 	UnknownTypeIdentifier:    {},
 	BitTypeIdentifier:        {},
+	BlobStringTypeIdentifier: {},
 	BoolTypeIdentifier:       {},
 	DatetimeTypeIdentifier:   {},
 	DecimalTypeIdentifier:    {},
@@ -57,6 +60,7 @@ var Identifiers = map[Identifier]struct{}{
 	FloatTypeIdentifier:      {},
 	InlineBlobTypeIdentifier: {},
 	IntTypeIdentifier:        {},
+	JSONTypeIdentifier:       {},
 	SetTypeIdentifier:        {},
 	TimeTypeIdentifier:       {},
 	TupleTypeIdentifier:      {},
@@ -165,7 +169,7 @@ func FromSqlType(sqlType sql.Type) (TypeInfo, error) {
 		if !ok {
 			return nil, fmt.Errorf(`expected "StringType" from SQL basetype "Text"`)
 		}
-		return &varStringType{stringType}, nil
+		return &blobStringType{stringType}, nil
 	case sqltypes.Blob:
 		stringType, ok := sqlType.(sql.StringType)
 		if !ok {
@@ -214,16 +218,33 @@ func FromSqlType(sqlType sql.Type) (TypeInfo, error) {
 			return nil, fmt.Errorf(`expected "SetTypeIdentifier" from SQL basetype "Set"`)
 		}
 		return &setType{setSQLType}, nil
+	case sqltypes.TypeJSON:
+		return JSONType, nil
 	default:
 		return nil, fmt.Errorf(`no type info can be created from SQL base type "%v"`, sqlType.String())
 	}
 }
 
+// FromSqlKeyType returns the TypeInfo for a primary key column of the given sql.Type. Rows are ordered by their keys,
+// so key values are always stored inline rather than in a types.Blob, which is ordered by its hash.
+func FromSqlKeyType(sqlType sql.Type) (TypeInfo, error) {
+	ti, err := FromSqlType(sqlType)
+	if err != nil {
+		return nil, err
+	}
+	if blobTi, ok := ti.(*blobStringType); ok {
+		return &varStringType{blobTi.sqlStringType}, nil
+	}
+	return ti, nil
+}
+
 // FromTypeParams constructs a TypeInfo from the given identifier and parameters.
 func FromTypeParams(id Identifier, params map[string]string) (TypeInfo, error) {
 	switch id {
 	case BitTypeIdentifier:
 		return CreateBitTypeFromParams(params)
+	case BlobStringTypeIdentifier:
+		return CreateBlobStringTypeFromParams(params)
 	case BoolTypeIdentifier:
 		return BoolType, nil
 	case DatetimeTypeIdentifier:
@@ -238,6 +259,8 @@ func FromTypeParams(id Identifier, params map[string]string) (TypeInfo, error) {
 		return CreateInlineBlobTypeFromParams(params)
 	case IntTypeIdentifier:
 		return CreateIntTypeFromParams(params)
+	case JSONTypeIdentifier:
+		return JSONType, nil
 	case SetTypeIdentifier:
 		return CreateSetTypeFromParams(params)
 	case TimeTypeIdentifier:
@@ -0,0 +1,365 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// ErrNoUserAccounts is returned for statements that manage user accounts in sessions without any.
+var ErrNoUserAccounts = errors.NewKind("%s is not supported without user accounts")
+
+type accountStatementKind int
+
+const (
+	createUserStatement accountStatementKind = iota
+	dropUserStatement
+	grantStatement
+	revokeStatement
+	showGrantsStatement
+)
+
+// accountStatementNames are the names of the kinds of account statements, as they're named in errors.
+var accountStatementNames = map[accountStatementKind]string{
+	createUserStatement: "CREATE USER",
+	dropUserStatement:   "DROP USER",
+	grantStatement:      "GRANT",
+	revokeStatement:     "REVOKE",
+	showGrantsStatement: "SHOW GRANTS",
+}
+
+// AccountStatement is a statement that manages user accounts: CREATE USER, DROP USER, GRANT, REVOKE or SHOW GRANTS.
+// The SQL parser doesn't support these statements, so they're found by ParseAccountStatement and run by
+// ExecAccountStatement against the PrivilegeStore of the session instead.
+type AccountStatement struct {
+	kind     accountStatementKind
+	user     string
+	password string
+	// passwordHash is set instead of password by IDENTIFIED WITH mysql_native_password AS, which gives the hash of the
+	// password rather than the password itself
+	passwordHash string
+	ifExists     bool
+	grant        Grant
+}
+
+const userPattern = `([^\s;]+)`
+const stmtEnd = `\s*;?\s*$`
+
+// passwordPattern matches a quoted string, in which quotes are escaped by doubling them or with backslashes
+const passwordPattern = `('(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*")`
+
+// authPluginPattern matches the name of an authentication plugin, which may be quoted
+const authPluginPattern = "(\\w+|'\\w+'|\"\\w+\"|`\\w+`)"
+
+var createUserRegex = regexp.MustCompile(`(?is)^\s*create\s+user\s+(if\s+not\s+exists\s+)?` + userPattern + `(?:\s+identified\s+(?:with\s+` + authPluginPattern + `\s+)?(by|as)\s+` + passwordPattern + `)?` + stmtEnd)
+var dropUserRegex = regexp.MustCompile(`(?is)^\s*drop\s+user\s+(if\s+exists\s+)?` + userPattern + stmtEnd)
+var grantRegex = regexp.MustCompile(`(?is)^\s*grant\s+(.+?)\s+on\s+(?:table\s+)?(\S+)\s+to\s+` + userPattern + stmtEnd)
+var revokeRegex = regexp.MustCompile(`(?is)^\s*revoke\s+(.+?)\s+on\s+(?:table\s+)?(\S+)\s+from\s+` + userPattern + stmtEnd)
+var showGrantsRegex = regexp.MustCompile(`(?is)^\s*show\s+grants(?:\s+for\s+` + userPattern + `)?` + stmtEnd)
+
+// nativePasswordPlugin is the only authentication plugin that accounts may be identified with
+const nativePasswordPlugin = "mysql_native_password"
+
+// privilegePermissions maps the MySQL privileges that may be granted to the permissions they give.
+var privilegePermissions = map[string]auth.Permission{
+	"SELECT":         auth.ReadPerm,
+	"ALL":            auth.AllPermissions,
+	"ALL PRIVILEGES": auth.AllPermissions,
+}
+
+// writePrivileges are the MySQL privileges that give write permission. Write permission allows all of them, so they
+// can only be granted or revoked together.
+var writePrivileges = []string{"INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "ALTER", "INDEX"}
+
+// ParseAccountStatement returns the AccountStatement of |query| if it's a statement that manages user accounts. It
+// returns nil for every other statement.
+func ParseAccountStatement(query string) (*AccountStatement, error) {
+	if m := createUserRegex.FindStringSubmatch(query); m != nil {
+		stmt := &AccountStatement{kind: createUserStatement, ifExists: m[1] != "", user: parseUserName(m[2])}
+		plugin := strings.Trim(m[3], "'\"`")
+		if plugin != "" && !strings.EqualFold(plugin, nativePasswordPlugin) {
+			return nil, fmt.Errorf("unsupported authentication plugin '%s', accounts are identified with %s", plugin, nativePasswordPlugin)
+		}
+
+		switch strings.ToLower(m[4]) {
+		case "by":
+			stmt.password = unquoteString(m[5])
+		case "as":
+			if plugin == "" {
+				return nil, fmt.Errorf("IDENTIFIED AS requires an authentication plugin, as in IDENTIFIED WITH %s AS", nativePasswordPlugin)
+			}
+			stmt.passwordHash = unquoteString(m[5])
+			if !nativePasswordRegex.MatchString(stmt.passwordHash) {
+				return nil, ErrInvalidPasswordHash.New(stmt.user)
+			}
+		}
+		return stmt, nil
+	}
+
+	if m := dropUserRegex.FindStringSubmatch(query); m != nil {
+		return &AccountStatement{kind: dropUserStatement, ifExists: m[1] != "", user: parseUserName(m[2])}, nil
+	}
+
+	kind := grantStatement
+	m := grantRegex.FindStringSubmatch(query)
+	if m == nil {
+		kind = revokeStatement
+		m = revokeRegex.FindStringSubmatch(query)
+	}
+	if m != nil {
+		grant, err := parseGrant(m[1], m[2])
+		if err != nil {
+			return nil, err
+		}
+		return &AccountStatement{kind: kind, user: parseUserName(m[3]), grant: grant}, nil
+	}
+
+	if m := showGrantsRegex.FindStringSubmatch(query); m != nil {
+		return &AccountStatement{kind: showGrantsStatement, user: parseUserName(m[1])}, nil
+	}
+
+	return nil, nil
+}
+
+// unquoteString returns the string literal |quoted| without its quotes and escapes. An empty string is returned as
+// is.
+func unquoteString(quoted string) string {
+	if len(quoted) < 2 {
+		return quoted
+	}
+
+	quote := quoted[0]
+	quoted = quoted[1 : len(quoted)-1]
+
+	sb := strings.Builder{}
+	for i := 0; i < len(quoted); i++ {
+		c := quoted[i]
+		if c == quote && i+1 < len(quoted) && quoted[i+1] == quote {
+			i++
+		} else if c == '\\' && i+1 < len(quoted) {
+			i++
+			c = quoted[i]
+			switch c {
+			case '0':
+				c = 0
+			case 'b':
+				c = '\b'
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'Z':
+				c = 26
+			case '%', '_':
+				// these are only escaped in patterns, so the backslash is kept
+				sb.WriteByte('\\')
+			}
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// parseUserName returns the name of the account |user|, which may be quoted and may be followed by a host. Accounts
+// aren't restricted by host, so the host is ignored.
+func parseUserName(user string) string {
+	if len(user) > 0 && strings.ContainsRune("'\"`", rune(user[0])) {
+		if end := strings.IndexByte(user[1:], user[0]); end >= 0 {
+			return user[1 : end+1]
+		}
+	}
+
+	if at := strings.IndexByte(user, '@'); at >= 0 {
+		return user[:at]
+	}
+	return user
+}
+
+// parseGrant returns the Grant of the privileges |privs| on |target|, which has the form `db.table` or
+// `db/branch.table`. Names containing dots or wildcards other than `*` must be quoted with backticks.
+func parseGrant(privs, target string) (Grant, error) {
+	var perms auth.Permission
+	writes := make(map[string]bool)
+	for _, priv := range strings.Split(privs, ",") {
+		priv = strings.ToUpper(strings.Join(strings.Fields(priv), " "))
+		if isWritePrivilege(priv) {
+			writes[priv] = true
+			continue
+		}
+
+		perm, ok := privilegePermissions[priv]
+		if !ok {
+			return Grant{}, fmt.Errorf("unsupported privilege '%s', supported privileges are SELECT, ALL and %s", priv, strings.Join(writePrivileges, ", "))
+		}
+		perms |= perm
+	}
+
+	if len(writes) > 0 {
+		if len(writes) != len(writePrivileges) {
+			return Grant{}, fmt.Errorf("privileges %s can only be granted or revoked together", strings.Join(writePrivileges, ", "))
+		}
+		perms |= auth.WritePerm
+	}
+
+	dot := -1
+	inQuotes := false
+	for i, r := range target {
+		if r == '`' {
+			inQuotes = !inQuotes
+		} else if r == '.' && !inQuotes {
+			dot = i
+			break
+		}
+	}
+	if dot < 0 {
+		return Grant{}, fmt.Errorf("invalid privilege target '%s', expected `database.table` or `database/branch.table`", target)
+	}
+
+	db := strings.Trim(target[:dot], "`")
+	var branch string
+	if slash := strings.IndexByte(db, '/'); slash >= 0 {
+		db, branch = db[:slash], db[slash+1:]
+	}
+
+	return Grant{
+		Database:    db,
+		Branch:      branch,
+		Table:       strings.Trim(target[dot+1:], "`"),
+		Permissions: permissionNames(perms),
+	}, nil
+}
+
+func isWritePrivilege(priv string) bool {
+	for _, p := range writePrivileges {
+		if p == priv {
+			return true
+		}
+	}
+	return false
+}
+
+// formatGrant returns |g| as the GRANT statement SHOW GRANTS displays for |user|.
+func formatGrant(user string, g Grant) string {
+	perms, _ := g.permissions()
+
+	var privs string
+	switch perms {
+	case auth.AllPermissions:
+		privs = "ALL PRIVILEGES"
+	case auth.ReadPerm:
+		privs = "SELECT"
+	default:
+		privs = strings.Join(writePrivileges, ", ")
+	}
+
+	db := normalizePattern(g.Database)
+	if branch := normalizePattern(g.Branch); branch != matchAll {
+		db += "/" + branch
+	}
+
+	return fmt.Sprintf("GRANT %s ON %s.%s TO `%s`", privs, quotePattern(db), quotePattern(normalizePattern(g.Table)), user)
+}
+
+func quotePattern(pattern string) string {
+	if pattern == matchAll {
+		return pattern
+	}
+	return "`" + pattern + "`"
+}
+
+// SetUserAccounts sets the PrivilegeStore that the statements of this session that manage user accounts are run
+// against. Sessions without one can't run them.
+func (sess *DoltSession) SetUserAccounts(accounts *PrivilegeStore) {
+	sess.accounts = accounts
+}
+
+// ExecAccountStatement runs |stmt| against the user accounts of the session of |ctx|. Only the superuser of the
+// accounts may manage them or see the grants of accounts other than its own.
+func ExecAccountStatement(ctx *sql.Context, stmt *AccountStatement) (sql.Schema, sql.RowIter, error) {
+	accounts := DSessFromSess(ctx.Session).accounts
+	if accounts == nil {
+		return nil, nil, ErrNoUserAccounts.New(accountStatementNames[stmt.kind])
+	}
+
+	user := ctx.Client().User
+	target := stmt.user
+	if stmt.kind == showGrantsStatement && target == "" {
+		target = user
+	}
+
+	if !accounts.IsSuperUser(user) && !(stmt.kind == showGrantsStatement && target == user) {
+		return nil, nil, mysql.NewSQLError(mysql.ERSpecifiedAccessDenied, mysql.SSAccessDeniedError, "Access denied; only the server user '%s' may manage user accounts", accounts.SuperUser())
+	}
+
+	var err error
+	switch stmt.kind {
+	case createUserStatement:
+		err = accounts.CreateUser(UserAccount{Name: target, Password: stmt.password, PasswordHash: stmt.passwordHash}, stmt.ifExists)
+	case dropUserStatement:
+		err = accounts.DropUser(target, stmt.ifExists)
+	case grantStatement:
+		err = accounts.GrantPrivileges(target, stmt.grant)
+	case revokeStatement:
+		err = accounts.RevokePrivileges(target, stmt.grant)
+	case showGrantsStatement:
+		return showGrants(accounts, target)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+	return sql.OkResultSchema, sql.RowsToRowIter(sql.NewRow(sql.OkResult{})), nil
+}
+
+// Schema returns the schema of the results of the statement, run by the account |user|.
+func (stmt *AccountStatement) Schema(user string) sql.Schema {
+	if stmt.kind != showGrantsStatement {
+		return sql.OkResultSchema
+	}
+	if stmt.user != "" {
+		user = stmt.user
+	}
+	return showGrantsSchema(user)
+}
+
+func showGrantsSchema(user string) sql.Schema {
+	return sql.Schema{{Name: "Grants for " + user, Type: sql.LongText}}
+}
+
+func showGrants(accounts *PrivilegeStore, user string) (sql.Schema, sql.RowIter, error) {
+	grants, ok := accounts.GrantsFor(user)
+	if !ok {
+		return nil, nil, mysql.NewSQLError(mysql.ERNonExistingGrant, mysql.SSUnknownSQLState, "There is no such grant defined for user '%s'", user)
+	}
+
+	var rows []sql.Row
+	if len(grants) == 0 {
+		rows = append(rows, sql.NewRow(fmt.Sprintf("GRANT USAGE ON *.* TO `%s`", user)))
+	}
+	for _, g := range grants {
+		rows = append(rows, sql.NewRow(formatGrant(user, g)))
+	}
+
+	return showGrantsSchema(user), sql.RowsToRowIter(rows...), nil
+}
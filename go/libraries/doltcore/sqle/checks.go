@@ -0,0 +1,379 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+)
+
+// MergeCheckEvaluator is the merge.CheckEvaluator that checks merged rows against the check constraints of their
+// tables.
+type MergeCheckEvaluator struct{}
+
+var _ merge.CheckEvaluator = MergeCheckEvaluator{}
+
+// NewRowChecker implements merge.CheckEvaluator.
+func (MergeCheckEvaluator) NewRowChecker(tblName string, sch schema.Schema) (merge.RowChecker, error) {
+	return sqlutil.NewCheckEvaluator(tblName, sch)
+}
+
+// CheckStatement is a CREATE TABLE or ALTER TABLE statement that adds or drops check constraints. The SQL parser
+// discards the CHECK clauses of these statements, so they're found by ParseCheckStatement and run by QueryWithChecks
+// instead.
+type CheckStatement struct {
+	// query is the statement itself
+	query string
+	// createQuery is the CREATE TABLE statement with its CHECK clauses removed, which the engine runs before the checks
+	// are added. It's empty for ALTER TABLE statements.
+	createQuery string
+	ifNotExists bool
+	db          string
+	table       string
+	add         []checkDefinition
+	drop        string
+	// dropConstraint is set for DROP CONSTRAINT, which drops a check only if there's one with its name
+	dropConstraint bool
+}
+
+type checkDefinition struct {
+	name       string
+	expression string
+	enforced   bool
+}
+
+type checkToken struct {
+	typ        int
+	val        string
+	start, end int
+}
+
+// ParseCheckStatement returns the CheckStatement of |query| if it's a CREATE TABLE statement with CHECK clauses, or an
+// ALTER TABLE statement that adds or drops a check constraint. It returns nil for every other statement.
+func ParseCheckStatement(query string) (*CheckStatement, error) {
+	toks := tokenizeCheckStatement(query)
+	if len(toks) < 3 || toks[1].typ != sqlparser.TABLE {
+		return nil, nil
+	}
+
+	switch toks[0].typ {
+	case sqlparser.CREATE:
+		return parseCreateTableChecks(query, toks)
+	case sqlparser.ALTER:
+		return parseAlterTableChecks(query, toks)
+	default:
+		return nil, nil
+	}
+}
+
+// tokenizeCheckStatement returns the tokens of |query| with their positions, without comments or a trailing
+// semicolon. It returns nil if the query can't be tokenized.
+func tokenizeCheckStatement(query string) []checkToken {
+	tkn := sqlparser.NewStringTokenizer(query)
+
+	var toks []checkToken
+	prevEnd := 0
+	for {
+		typ, val := tkn.Scan()
+		end := tkn.Position - 1
+		if end > len(query) {
+			end = len(query)
+		}
+
+		switch typ {
+		case 0, ';':
+			return toks
+		case sqlparser.LEX_ERROR:
+			return nil
+		case sqlparser.COMMENT:
+			prevEnd = end
+			continue
+		}
+
+		start := prevEnd
+		for start < end && strings.ContainsRune(" \t\r\n", rune(query[start])) {
+			start++
+		}
+		toks = append(toks, checkToken{typ: typ, val: string(val), start: start, end: end})
+		prevEnd = end
+	}
+}
+
+// parseTableName parses a table name, which may be qualified by its database, starting at toks[i]. It returns the
+// index of the token following the name.
+func parseTableName(toks []checkToken, i int) (db, table string, next int, ok bool) {
+	if i >= len(toks) || toks[i].val == "" {
+		return "", "", 0, false
+	}
+	if i+2 < len(toks) && toks[i+1].typ == '.' && toks[i+2].val != "" {
+		return toks[i].val, toks[i+2].val, i + 3, true
+	}
+	return "", toks[i].val, i + 1, true
+}
+
+// parseCheck parses a check clause of the form [CONSTRAINT [name]] CHECK (expr) [[NOT] ENFORCED] starting at toks[i].
+// It returns the index of the token following the clause, and false if toks[i] doesn't start a check clause.
+func parseCheck(query string, toks []checkToken, i int) (checkDefinition, int, bool, error) {
+	var chk checkDefinition
+	if i < len(toks) && toks[i].typ == sqlparser.CONSTRAINT {
+		if i+1 < len(toks) && toks[i+1].typ == sqlparser.CHECK {
+			i++
+		} else if i+2 < len(toks) && toks[i+2].typ == sqlparser.CHECK {
+			chk.name = toks[i+1].val
+			i += 2
+		} else {
+			return chk, 0, false, nil
+		}
+	}
+
+	if i+1 >= len(toks) || toks[i].typ != sqlparser.CHECK || toks[i+1].typ != '(' {
+		return chk, 0, false, nil
+	}
+
+	open := i + 1
+	depth := 0
+	for i = open; i < len(toks); i++ {
+		if toks[i].typ == '(' {
+			depth++
+		} else if toks[i].typ == ')' {
+			depth--
+			if depth == 0 {
+				break
+			}
+		}
+	}
+	if i == len(toks) {
+		return chk, 0, false, fmt.Errorf("check constraint has an unterminated expression")
+	}
+	chk.expression = strings.TrimSpace(query[toks[open].end:toks[i].start])
+	i++
+
+	chk.enforced = true
+	if i+1 < len(toks) && toks[i].typ == sqlparser.NOT && isEnforced(toks[i+1]) {
+		chk.enforced = false
+		i += 2
+	} else if i < len(toks) && isEnforced(toks[i]) {
+		i++
+	}
+
+	return chk, i, true, nil
+}
+
+// isEnforced returns whether |tok| is ENFORCED, which the tokenizer doesn't know as a keyword
+func isEnforced(tok checkToken) bool {
+	return tok.typ == sqlparser.ID && strings.EqualFold(tok.val, "enforced")
+}
+
+func parseCreateTableChecks(query string, toks []checkToken) (*CheckStatement, error) {
+	stmt := &CheckStatement{query: query}
+
+	i := 2
+	if i+2 < len(toks) && toks[i].typ == sqlparser.IF && toks[i+1].typ == sqlparser.NOT && toks[i+2].typ == sqlparser.EXISTS {
+		stmt.ifNotExists = true
+		i += 3
+	}
+
+	var ok bool
+	stmt.db, stmt.table, i, ok = parseTableName(toks, i)
+	if !ok || i >= len(toks) || toks[i].typ != '(' {
+		// CREATE TABLE ... LIKE and CREATE TABLE ... AS SELECT can't declare checks
+		return nil, nil
+	}
+
+	// the check clauses are cut out of the statement, along with the comma that separates a table constraint from the
+	// rest of the table's definitions
+	var cuts [][2]int
+	depth := 0
+	elementStart := false
+	lastComma := -1
+	for i < len(toks) {
+		tok := toks[i]
+		if depth == 1 {
+			chk, next, found, err := parseCheck(query, toks, i)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				stmt.add = append(stmt.add, chk)
+
+				start, end := tok.start, toks[next-1].end
+				if elementStart && lastComma >= 0 {
+					start = toks[lastComma].start
+				} else if elementStart && next < len(toks) && toks[next].typ == ',' {
+					end = toks[next].end
+					next++
+				}
+				cuts = append(cuts, [2]int{start, end})
+
+				elementStart = false
+				i = next
+				continue
+			}
+		}
+
+		switch tok.typ {
+		case '(':
+			depth++
+			if depth == 1 {
+				elementStart = true
+			}
+		case ')':
+			depth--
+		case ',':
+			if depth == 1 {
+				elementStart = true
+				lastComma = i
+				i++
+				continue
+			}
+		}
+		if depth == 1 && tok.typ != '(' {
+			elementStart = false
+		}
+		i++
+	}
+
+	if len(stmt.add) == 0 {
+		return nil, nil
+	}
+
+	sb := strings.Builder{}
+	pos := 0
+	for _, cut := range cuts {
+		sb.WriteString(query[pos:cut[0]])
+		pos = cut[1]
+	}
+	sb.WriteString(query[pos:])
+	stmt.createQuery = sb.String()
+
+	return stmt, nil
+}
+
+func parseAlterTableChecks(query string, toks []checkToken) (*CheckStatement, error) {
+	stmt := &CheckStatement{query: query}
+
+	db, table, i, ok := parseTableName(toks, 2)
+	if !ok || i >= len(toks) {
+		return nil, nil
+	}
+	stmt.db, stmt.table = db, table
+
+	var next int
+	switch {
+	case toks[i].typ == sqlparser.ADD:
+		chk, n, found, err := parseCheck(query, toks, i+1)
+		if err != nil || !found {
+			return nil, err
+		}
+		stmt.add = append(stmt.add, chk)
+		next = n
+	case toks[i].typ == sqlparser.DROP && i+2 < len(toks) && toks[i+1].typ == sqlparser.CHECK:
+		stmt.drop = toks[i+2].val
+		next = i + 3
+	case toks[i].typ == sqlparser.DROP && i+2 < len(toks) && toks[i+1].typ == sqlparser.CONSTRAINT:
+		stmt.drop = toks[i+2].val
+		stmt.dropConstraint = true
+		next = i + 3
+	default:
+		return nil, nil
+	}
+
+	if next < len(toks) {
+		return nil, fmt.Errorf("check constraints must be added or dropped in an ALTER TABLE statement of their own")
+	}
+
+	return stmt, nil
+}
+
+// exec runs the statement. Tables named without a database are in the current database of |ctx|. The caller checks
+// that |engine| allows writes, see QueryWithChecks.
+func (stmt *CheckStatement) exec(ctx *sql.Context, engine *sqle.Engine) error {
+	dbName := stmt.db
+	if dbName == "" {
+		dbName = ctx.GetCurrentDatabase()
+	}
+
+	created := false
+	if stmt.createQuery != "" {
+		if stmt.ifNotExists {
+			if _, err := engine.Catalog.Table(ctx, dbName, stmt.table); err == nil {
+				// the checks of an existing table are left as they are
+				return execCheckQuery(ctx, engine, stmt.createQuery)
+			}
+		}
+
+		if err := execCheckQuery(ctx, engine, stmt.createQuery); err != nil {
+			return err
+		}
+		created = true
+	}
+
+	tbl, err := engine.Catalog.Table(ctx, dbName, stmt.table)
+	if err != nil {
+		return err
+	}
+
+	t, ok := tbl.(*AlterableDoltTable)
+	if !ok {
+		// tables are read only to users without write privileges on them, and in read only databases
+		return ErrTableAccessDenied.New("ALTER", ctx.Client().User, stmt.table)
+	}
+
+	if stmt.drop != "" {
+		if _, ok := t.sch.Checks().GetByNameCaseInsensitive(stmt.drop); !ok && stmt.dropConstraint {
+			// the constraint isn't a check, so it's left to the engine
+			return execCheckQuery(ctx, engine, stmt.query)
+		}
+		return t.DropCheck(ctx, stmt.drop)
+	}
+
+	for _, chk := range stmt.add {
+		err = t.CreateCheck(ctx, chk.name, chk.expression, chk.enforced)
+		if err != nil {
+			if created {
+				_ = t.db.DropTable(ctx, t.name)
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+// execCheckQuery runs |query| with |engine|, discarding its results.
+func execCheckQuery(ctx *sql.Context, engine *sqle.Engine, query string) error {
+	_, iter, err := engine.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	for _, err = iter.Next(); err == nil; _, err = iter.Next() {
+	}
+	if err != io.EOF {
+		_ = iter.Close(ctx)
+		return err
+	}
+
+	return iter.Close(ctx)
+}
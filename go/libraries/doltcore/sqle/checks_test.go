@@ -0,0 +1,182 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+)
+
+func TestParseCheckStatement(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		expected    *CheckStatement
+		expectedErr string
+	}{
+		{
+			name:  "create table with column check",
+			query: "CREATE TABLE t (pk int primary key, x int CHECK (x > 0))",
+			expected: &CheckStatement{
+				createQuery: "CREATE TABLE t (pk int primary key, x int )",
+				table:       "t",
+				add:         []checkDefinition{{expression: "x > 0", enforced: true}},
+			},
+		},
+		{
+			name:  "create table with named table checks",
+			query: "CREATE TABLE IF NOT EXISTS db.t (pk int primary key, x int, CONSTRAINT c1 CHECK (x IN (1, 2)) NOT ENFORCED, CHECK (x <> ',)'));",
+			expected: &CheckStatement{
+				createQuery: "CREATE TABLE IF NOT EXISTS db.t (pk int primary key, x int);",
+				ifNotExists: true,
+				db:          "db",
+				table:       "t",
+				add: []checkDefinition{
+					{name: "c1", expression: "x IN (1, 2)", enforced: false},
+					{expression: "x <> ',)'", enforced: true},
+				},
+			},
+		},
+		{
+			name:  "create table with leading check",
+			query: "CREATE TABLE t (CONSTRAINT CHECK (x > 0) ENFORCED, pk int primary key, x int)",
+			expected: &CheckStatement{
+				createQuery: "CREATE TABLE t ( pk int primary key, x int)",
+				table:       "t",
+				add:         []checkDefinition{{expression: "x > 0", enforced: true}},
+			},
+		},
+		{
+			name:  "create table with versioned comment",
+			query: "CREATE TABLE t (pk int primary key, x int, CONSTRAINT `c1` CHECK ((`x` > 0)) /*!80016 NOT ENFORCED */)",
+			expected: &CheckStatement{
+				createQuery: "CREATE TABLE t (pk int primary key, x int)",
+				table:       "t",
+				add:         []checkDefinition{{name: "c1", expression: "(`x` > 0)", enforced: false}},
+			},
+		},
+		{
+			name:  "alter table add check",
+			query: "ALTER TABLE t ADD CONSTRAINT c1 CHECK (x < 10)",
+			expected: &CheckStatement{
+				table: "t",
+				add:   []checkDefinition{{name: "c1", expression: "x < 10", enforced: true}},
+			},
+		},
+		{
+			name:     "alter table drop check",
+			query:    "ALTER TABLE t DROP CHECK c1",
+			expected: &CheckStatement{table: "t", drop: "c1"},
+		},
+		{
+			name:     "alter table drop constraint",
+			query:    "alter table db.t drop constraint c1",
+			expected: &CheckStatement{db: "db", table: "t", drop: "c1", dropConstraint: true},
+		},
+		{
+			name:        "alter table with other specs",
+			query:       "ALTER TABLE t ADD CHECK (x < 10), ADD COLUMN y int",
+			expectedErr: "statement of their own",
+		},
+		{
+			name:        "unterminated check",
+			query:       "ALTER TABLE t ADD CHECK (x < (10)",
+			expectedErr: "unterminated",
+		},
+		{name: "create table without checks", query: "CREATE TABLE t (pk int primary key, checked int)"},
+		{name: "create table like", query: "CREATE TABLE t LIKE u"},
+		{name: "alter table add column", query: "ALTER TABLE t ADD COLUMN y int"},
+		{name: "alter table add foreign key", query: "ALTER TABLE t ADD CONSTRAINT fk FOREIGN KEY (x) REFERENCES u (pk)"},
+		{name: "select", query: "SELECT 'CREATE TABLE t (x int CHECK (x > 0))'"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stmt, err := ParseCheckStatement(test.query)
+			if test.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if test.expected == nil {
+				assert.Nil(t, stmt)
+				return
+			}
+			test.expected.query = test.query
+			assert.Equal(t, test.expected, stmt)
+		})
+	}
+}
+
+func TestParseShowCreateTable(t *testing.T) {
+	tests := []struct {
+		query string
+		db    string
+		table string
+		ok    bool
+	}{
+		{"SHOW CREATE TABLE t", "", "t", true},
+		{"show create table db.t", "db", "t", true},
+		{"SHOW CREATE TABLE `t`;", "", "t", true},
+		{"SHOW CREATE VIEW v", "", "", false},
+		{"SHOW TABLES", "", "", false},
+		{"SELECT 1", "", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			db, table, ok := ParseShowCreateTable(test.query)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.db, db)
+			assert.Equal(t, test.table, table)
+		})
+	}
+}
+
+func TestQueryWithChecksReadOnly(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+	root, err = ExecuteSql(dEnv, root, "CREATE TABLE t (pk int primary key, x int);")
+	require.NoError(t, err)
+
+	engine, ctx, err := NewTestEngine(context.Background(), NewDatabase("dolt", dEnv.DbData()), root)
+	require.NoError(t, err)
+	engine.Auth = auth.NewNativeSingle("", "", auth.ReadPerm)
+
+	for _, query := range []string{
+		"ALTER TABLE t ADD CONSTRAINT c1 CHECK (x > 0)",
+		"ALTER TABLE t DROP CHECK c1",
+		"CREATE TABLE u (pk int primary key, x int CHECK (x > 0))",
+	} {
+		_, _, err = QueryWithChecks(ctx, engine, query)
+		assert.True(t, auth.ErrNotAuthorized.Is(err), query)
+	}
+
+	_, iter, err := QueryWithChecks(ctx, engine, "SHOW CREATE TABLE t")
+	require.NoError(t, err)
+	rows, err := sql.RowIterToRows(ctx, iter)
+	require.NoError(t, err)
+	assert.Len(t, rows, 1)
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/parse"
 	"github.com/dolthub/go-mysql-server/sql/plan"
@@ -30,6 +31,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/alterschema"
@@ -46,6 +48,7 @@ type commitBehavior int8
 var ErrInvalidTableName = errors.NewKind("Invalid table name %s. Table names must match the regular expression " + doltdb.TableNameRegexStr)
 var ErrReservedTableName = errors.NewKind("Invalid table name %s. Table names beginning with `dolt_` are reserved for internal use")
 var ErrSystemTableAlter = errors.NewKind("Cannot alter table %s: system tables cannot be dropped or altered")
+var ErrReadOnlyDatabase = errors.NewKind("Cannot modify database %s: database is read-only")
 
 const (
 	batched commitBehavior = iota
@@ -86,6 +89,7 @@ type Database struct {
 	rsw       env.RepoStateWriter
 	drw       env.DocsReadWriter
 	batchMode commitBehavior
+	readOnly  bool
 }
 
 var _ SqlDatabase = Database{}
@@ -125,6 +129,11 @@ func (db Database) Name() string {
 	return db.name
 }
 
+// IsReadOnly returns whether the tables and schema of this database can be modified.
+func (db Database) IsReadOnly() bool {
+	return db.readOnly
+}
+
 // GetDoltDB gets the underlying DoltDB of the Database
 func (db Database) GetDoltDB() *doltdb.DoltDB {
 	return db.ddb
@@ -147,6 +156,22 @@ func (db Database) GetDocsReadWriter() env.DocsReadWriter {
 // GetTableInsensitive is used when resolving tables in queries. It returns a best-effort case-insensitive match for
 // the table name given.
 func (db Database) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Table, bool, error) {
+	tbl, ok, err := db.getTableInsensitive(ctx, tblName)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	tbl, err = db.withTablePrivileges(ctx, checkedOutBranch(db.rsr), tbl.Name(), tbl)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return tbl, true, nil
+}
+
+// getTableInsensitive returns the table with the name given from the working root, without checking the privileges of
+// the user of the session.
+func (db Database) getTableInsensitive(ctx *sql.Context, tblName string) (sql.Table, bool, error) {
 	root, err := db.GetRoot(ctx)
 
 	if err != nil {
@@ -204,6 +229,12 @@ func (db Database) GetTableInsensitiveWithRoot(ctx *sql.Context, root *doltdb.Ro
 		dt, found = dtables.NewCommitAncestorsTable(ctx, db.ddb), true
 	case doltdb.StatusTableName:
 		dt, found = dtables.NewStatusTable(ctx, db.ddb, db.rsr, db.drw), true
+	case doltdb.ReplicationStatusTableName:
+		dbName := db.name
+		if srcName, _, ok := SplitRevisionDbName(dbName); ok {
+			dbName = srcName
+		}
+		dt, found = dtables.NewReplicationStatusTable(ctx, dbName, DSessFromSess(ctx.Session).replication), true
 	}
 	if found {
 		return dt, found, nil
@@ -221,7 +252,47 @@ func (db Database) GetTableInsensitiveAsOf(ctx *sql.Context, tableName string, a
 		return nil, false, nil
 	}
 
-	return db.getTable(ctx, root, tableName)
+	tbl, ok, err := db.getTable(ctx, root, tableName)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	branch, err := db.branchAsOf(ctx, asOf)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tbl, err = db.withTablePrivileges(ctx, branch, tbl.Name(), tbl)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return tbl, true, nil
+}
+
+// branchAsOf returns the name of the branch that the tables as of the expression given are read from, which their
+// privileges are checked against. Times are looked up in the history of the checked out branch. Commit hashes, tags
+// and remote branches aren't read from a branch, and an empty string is returned for them.
+func (db Database) branchAsOf(ctx *sql.Context, asOf interface{}) (string, error) {
+	commitRef, ok := asOf.(string)
+	if !ok {
+		return checkedOutBranch(db.rsr), nil
+	}
+
+	cs, err := doltdb.NewCommitSpec(commitRef)
+	if err != nil {
+		return "", err
+	}
+
+	r, err := db.ddb.ResolveCommitRef(ctx, cs, db.rsr.CWBHeadRef())
+	if err != nil {
+		return "", err
+	}
+
+	if r == nil || r.GetType() != ref.BranchRefType {
+		return "", nil
+	}
+	return r.GetPath(), nil
 }
 
 // rootAsOf returns the root of the DB as of the expression given, which may be nil in the case that it refers to an
@@ -238,12 +309,7 @@ func (db Database) rootAsOf(ctx *sql.Context, asOf interface{}) (*doltdb.RootVal
 }
 
 func (db Database) getRootForTime(ctx *sql.Context, asOf time.Time) (*doltdb.RootValue, error) {
-	cs, err := doltdb.NewCommitSpec("HEAD")
-	if err != nil {
-		return nil, err
-	}
-
-	cm, err := db.ddb.Resolve(ctx, cs, db.rsr.CWBHeadRef())
+	cm, err := db.ddb.Resolve(ctx, db.rsr.CWBHeadSpec(), db.rsr.CWBHeadRef())
 	if err != nil {
 		return nil, err
 	}
@@ -348,7 +414,7 @@ func (db Database) getTable(ctx *sql.Context, root *doltdb.RootValue, tableName
 	var table sql.Table
 
 	readonlyTable := NewDoltTable(tableName, sch, tbl, db)
-	if doltdb.IsReadOnlySystemTable(tableName) {
+	if db.readOnly || doltdb.IsReadOnlySystemTable(tableName) {
 		table = &readonlyTable
 	} else if doltdb.HasDoltPrefix(tableName) {
 		table = &WritableDoltTable{DoltTable: readonlyTable, db: db}
@@ -462,46 +528,38 @@ func (db Database) GetRoot(ctx *sql.Context) (*doltdb.RootValue, error) {
 // basic SQL execution engine. If |newRoot|'s FeatureVersion is
 // out-of-date with the client, SetRoot will update it.
 func (db Database) SetRoot(ctx *sql.Context, newRoot *doltdb.RootValue) error {
-	h, err := newRoot.HashOf()
-
-	if err != nil {
-		return err
-	}
-
-	hashStr := h.String()
-	key := db.WorkingKey()
-
-	err = ctx.Session.Set(ctx, key, hashType, hashStr)
+	return DSessFromSess(ctx.Session).setRoot(ctx, db.name, newRoot)
+}
 
+// LoadRootFromRepoState loads the root value from the repo state's working hash, then calls SetRoot with the loaded
+// root value. The session's changes to the root are merged with concurrent changes relative to it when they're
+// committed.
+func (db Database) LoadRootFromRepoState(ctx *sql.Context) error {
+	workingHash := db.rsr.WorkingHash()
+	root, err := db.ddb.ReadRootValue(ctx, workingHash)
 	if err != nil {
 		return err
 	}
 
-	dsess := DSessFromSess(ctx.Session)
-	dsess.dbRoots[db.name] = dbRoot{hashStr, newRoot}
-
-	err = dsess.dbEditors[db.name].SetRoot(ctx, newRoot)
+	err = db.SetRoot(ctx, root)
 	if err != nil {
 		return err
 	}
 
+	DSessFromSess(ctx.Session).baseRoots[db.name] = root
 	return nil
 }
 
-// LoadRootFromRepoState loads the root value from the repo state's working hash, then calls SetRoot with the loaded
-// root value.
-func (db Database) LoadRootFromRepoState(ctx *sql.Context) error {
-	workingHash := db.rsr.WorkingHash()
-	root, err := db.ddb.ReadRootValue(ctx, workingHash)
-	if err != nil {
-		return err
+// DropTable drops the table with the name given
+func (db Database) DropTable(ctx *sql.Context, tableName string) error {
+	if db.readOnly {
+		return ErrReadOnlyDatabase.New(db.name)
 	}
 
-	return db.SetRoot(ctx, root)
-}
+	if err := db.checkTablePrivilege(ctx, "DROP", tableName, auth.WritePerm); err != nil {
+		return err
+	}
 
-// DropTable drops the table with the name given
-func (db Database) DropTable(ctx *sql.Context, tableName string) error {
 	root, err := db.GetRoot(ctx)
 
 	if err != nil {
@@ -531,6 +589,14 @@ func (db Database) DropTable(ctx *sql.Context, tableName string) error {
 
 // CreateTable creates a table with the name and schema given.
 func (db Database) CreateTable(ctx *sql.Context, tableName string, sch sql.Schema) error {
+	if db.readOnly {
+		return ErrReadOnlyDatabase.New(db.name)
+	}
+
+	if err := db.checkTablePrivilege(ctx, "CREATE", tableName, auth.WritePerm); err != nil {
+		return err
+	}
+
 	if doltdb.HasDoltPrefix(tableName) {
 		return ErrReservedTableName.New(tableName)
 	}
@@ -598,6 +664,17 @@ func (db Database) createDoltTable(ctx *sql.Context, tableName string, root *dol
 
 // RenameTable implements sql.TableRenamer
 func (db Database) RenameTable(ctx *sql.Context, oldName, newName string) error {
+	if db.readOnly {
+		return ErrReadOnlyDatabase.New(db.name)
+	}
+
+	if err := db.checkTablePrivilege(ctx, "ALTER", oldName, auth.WritePerm); err != nil {
+		return err
+	}
+	if err := db.checkTablePrivilege(ctx, "CREATE", newName, auth.WritePerm); err != nil {
+		return err
+	}
+
 	root, err := db.GetRoot(ctx)
 
 	if err != nil {
@@ -616,7 +693,7 @@ func (db Database) RenameTable(ctx *sql.Context, oldName, newName string) error
 		return ErrInvalidTableName.New(newName)
 	}
 
-	if _, ok, _ := db.GetTableInsensitive(ctx, newName); ok {
+	if _, ok, _ := db.getTableInsensitive(ctx, newName); ok {
 		return sql.ErrTableAlreadyExists.New(newName)
 	}
 
@@ -676,14 +753,14 @@ func (db Database) DropView(ctx *sql.Context, name string) error {
 
 // GetTriggers implements sql.TriggerDatabase.
 func (db Database) GetTriggers(ctx *sql.Context) ([]sql.TriggerDefinition, error) {
-	sqlTbl, ok, err := db.GetTableInsensitive(ctx, doltdb.SchemasTableName)
+	sqlTbl, ok, err := db.getTableInsensitive(ctx, doltdb.SchemasTableName)
 	if err != nil {
 		return nil, err
 	}
 	if !ok {
 		return nil, nil
 	}
-	tbl := sqlTbl.(*WritableDoltTable)
+	tbl := asDoltTable(sqlTbl)
 
 	typeCol, ok := tbl.sch.GetAllCols().GetByName(doltdb.SchemasTablesTypeCol)
 	if !ok {
@@ -749,6 +826,14 @@ func (db Database) DropTrigger(ctx *sql.Context, name string) error {
 }
 
 func (db Database) addFragToSchemasTable(ctx *sql.Context, fragType, name, definition string, existingErr error) (retErr error) {
+	if db.readOnly {
+		return ErrReadOnlyDatabase.New(db.name)
+	}
+
+	if err := db.checkTablePrivilege(ctx, "INSERT", doltdb.SchemasTableName, auth.WritePerm); err != nil {
+		return err
+	}
+
 	tbl, err := GetOrCreateDoltSchemasTable(ctx, db)
 	if err != nil {
 		return err
@@ -802,7 +887,15 @@ func (db Database) addFragToSchemasTable(ctx *sql.Context, fragType, name, defin
 }
 
 func (db Database) dropFragFromSchemasTable(ctx *sql.Context, fragType, name string, missingErr error) error {
-	stbl, found, err := db.GetTableInsensitive(ctx, doltdb.SchemasTableName)
+	if db.readOnly {
+		return ErrReadOnlyDatabase.New(db.name)
+	}
+
+	if err := db.checkTablePrivilege(ctx, "DELETE", doltdb.SchemasTableName, auth.WritePerm); err != nil {
+		return err
+	}
+
+	stbl, found, err := db.getTableInsensitive(ctx, doltdb.SchemasTableName)
 	if err != nil {
 		return err
 	}
@@ -827,6 +920,14 @@ func (db Database) dropFragFromSchemasTable(ctx *sql.Context, fragType, name str
 	return deleter.Close(ctx)
 }
 
+// asDoltTable returns the DoltTable underlying |tbl|, which is writable unless it belongs to a read-only database.
+func asDoltTable(tbl sql.Table) *DoltTable {
+	if writable, ok := tbl.(*WritableDoltTable); ok {
+		return &writable.DoltTable
+	}
+	return tbl.(*DoltTable)
+}
+
 // TableEditSession returns the TableEditSession for this database from the given context.
 func (db Database) TableEditSession(ctx *sql.Context) *editor.TableEditSession {
 	return DSessFromSess(ctx.Session).dbEditors[db.name]
@@ -846,14 +947,14 @@ func RegisterSchemaFragments(ctx *sql.Context, db Database, root *doltdb.RootVal
 		return nil
 	}
 
-	tbl := stbl.(*WritableDoltTable)
+	tbl := asDoltTable(stbl)
 	rowData, err := tbl.table.GetRowData(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	iter, err := newRowIterator(ctx, &tbl.DoltTable, nil, &doltTablePartition{rowData: rowData, end: NoUpperBound})
+	iter, err := newRowIterator(ctx, tbl, nil, &doltTablePartition{rowData: rowData, end: NoUpperBound})
 	if err != nil {
 		return err
 	}
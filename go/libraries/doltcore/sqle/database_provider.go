@@ -0,0 +1,246 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// DatabaseProvider creates, clones and drops the databases of a server while it runs. Implementations add the
+// databases they create to the server's catalog and remove the ones they drop from it. Sessions pick up the change
+// the next time they use the database, see ResolveRevisionDatabases and RemoveDroppedDatabases.
+type DatabaseProvider interface {
+	// CreateDatabase initializes a new, empty database named |name|.
+	CreateDatabase(ctx *sql.Context, name string) error
+	// CloneDatabase clones the remote at |url| into a new database named |name|.
+	CloneDatabase(ctx *sql.Context, url, name string) error
+	// DropDatabase deletes the database named |name| and its data.
+	DropDatabase(ctx *sql.Context, name string) error
+}
+
+// SetDatabaseProvider sets the DatabaseProvider that CREATE DATABASE, DROP DATABASE and DOLT_CLONE use. Sessions
+// without one can't create, clone or drop Dolt databases.
+func (sess *DoltSession) SetDatabaseProvider(provider DatabaseProvider) {
+	sess.databases = provider
+}
+
+// DatabaseProvider returns the DatabaseProvider of this session, or nil if it has none.
+func (sess *DoltSession) DatabaseProvider() DatabaseProvider {
+	return sess.databases
+}
+
+// CheckDatabasePrivilege returns an error naming |command| if the user of the session of |ctx| may not write to every
+// branch of the database |dbName|, as creating, cloning and dropping databases requires.
+func CheckDatabasePrivilege(ctx *sql.Context, dbName, command string) error {
+	privileges := DSessFromSess(ctx.Session).privileges
+	if privileges != nil && !privileges.HasTablePrivilege(ctx.Client().User, dbName, "", "", auth.WritePerm) {
+		return ErrDatabaseAccessDenied.New(command, ctx.Client().User, dbName)
+	}
+	return nil
+}
+
+// RemoveDroppedDatabases removes the databases that are no longer in |catalog| from the session of |ctx|, so that
+// sessions stop using the databases that other sessions drop.
+func RemoveDroppedDatabases(ctx *sql.Context, catalog *sql.Catalog) {
+	dsess, ok := ctx.Session.(*DoltSession)
+	if !ok {
+		return
+	}
+
+	revDbs := dsess.revisionDbs
+	if revDbs != nil {
+		revDbs.mu.Lock()
+		defer revDbs.mu.Unlock()
+	}
+
+	for dbName, dbData := range dsess.dbDatas {
+		if !catalog.HasDB(dbName) {
+			if revDbs != nil {
+				revDbs.release(catalog, dbName, dbData)
+			}
+			dsess.removeDB(dbName)
+		}
+	}
+}
+
+// removeDB forgets the database |dbName|, discarding its active transaction.
+func (sess *DoltSession) removeDB(dbName string) {
+	delete(sess.dbDatas, dbName)
+	delete(sess.dbRoots, dbName)
+	delete(sess.dbEditors, dbName)
+	delete(sess.caches, dbName)
+	delete(sess.transactions, dbName)
+	delete(sess.baseRoots, dbName)
+}
+
+// ResolveDatabaseDDL is an analyzer rule that replaces the CREATE DATABASE and DROP DATABASE nodes, which create and
+// drop in-memory databases in go-mysql-server, with nodes that create and drop Dolt databases through the
+// DatabaseProvider of the session. Sessions without a DatabaseProvider keep the go-mysql-server behavior.
+func ResolveDatabaseDDL(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope) (sql.Node, error) {
+	dsess, ok := ctx.Session.(*DoltSession)
+	if !ok || dsess.databases == nil {
+		return n, nil
+	}
+
+	switch n.(type) {
+	case *plan.CreateDB, *plan.DropDB:
+	default:
+		return n, nil
+	}
+
+	// the database name isn't exported by the go-mysql-server nodes, so it's read from the statement itself
+	stmt, err := sqlparser.Parse(ctx.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	ddl, ok := stmt.(*sqlparser.DBDDL)
+	if !ok {
+		return n, nil
+	}
+
+	switch n.(type) {
+	case *plan.CreateDB:
+		return &createDatabase{catalog: a.Catalog, name: ddl.DBName, ifNotExists: ddl.IfNotExists}, nil
+	default:
+		return &dropDatabase{catalog: a.Catalog, name: ddl.DBName, ifExists: ddl.IfExists}, nil
+	}
+}
+
+// createDatabase is the node for CREATE DATABASE.
+type createDatabase struct {
+	catalog     *sql.Catalog
+	name        string
+	ifNotExists bool
+}
+
+var _ sql.Node = (*createDatabase)(nil)
+
+// RowIter implements the sql.Node interface.
+func (c *createDatabase) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	if c.catalog.HasDB(c.name) {
+		if !c.ifNotExists {
+			return nil, sql.ErrCannotCreateDatabaseExists.New(c.name)
+		}
+
+		ctx.Session.Warn(&sql.Warning{
+			Level:   "Note",
+			Code:    mysql.ERDbCreateExists,
+			Message: fmt.Sprintf("Can't create database %s; database exists", c.name),
+		})
+		return sql.RowsToRowIter(), nil
+	}
+
+	if err := CheckDatabasePrivilege(ctx, c.name, "CREATE DATABASE"); err != nil {
+		return nil, err
+	}
+
+	err := DSessFromSess(ctx.Session).databases.CreateDatabase(ctx, c.name)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(sql.Row{sql.OkResult{RowsAffected: 1}}), nil
+}
+
+func (c *createDatabase) String() string { return "CREATE DATABASE " + c.name }
+
+// WithChildren implements the sql.Node interface.
+func (c *createDatabase) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(c, len(children), 0)
+	}
+
+	return c, nil
+}
+
+// Resolved implements the sql.Node interface.
+func (*createDatabase) Resolved() bool { return true }
+
+// Children implements the sql.Node interface.
+func (*createDatabase) Children() []sql.Node { return nil }
+
+// Schema implements the sql.Node interface.
+func (*createDatabase) Schema() sql.Schema { return sql.OkResultSchema }
+
+// dropDatabase is the node for DROP DATABASE.
+type dropDatabase struct {
+	catalog  *sql.Catalog
+	name     string
+	ifExists bool
+}
+
+var _ sql.Node = (*dropDatabase)(nil)
+
+// RowIter implements the sql.Node interface.
+func (d *dropDatabase) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	db, err := d.catalog.Database(d.name)
+	if err != nil {
+		if !d.ifExists {
+			return nil, sql.ErrCannotDropDatabaseDoesntExist.New(d.name)
+		}
+
+		ctx.Session.Warn(&sql.Warning{
+			Level:   "Note",
+			Code:    mysql.ERDbDropExists,
+			Message: fmt.Sprintf("Can't drop database %s; database doesn't exist", d.name),
+		})
+		return sql.RowsToRowIter(), nil
+	}
+
+	if err := CheckDatabasePrivilege(ctx, db.Name(), "DROP DATABASE"); err != nil {
+		return nil, err
+	}
+
+	dsess := DSessFromSess(ctx.Session)
+	err = dsess.databases.DropDatabase(ctx, db.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	RemoveDroppedDatabases(ctx, d.catalog)
+	if !d.catalog.HasDB(ctx.GetCurrentDatabase()) {
+		ctx.SetCurrentDatabase("")
+	}
+
+	return sql.RowsToRowIter(sql.Row{sql.OkResult{RowsAffected: 1}}), nil
+}
+
+func (d *dropDatabase) String() string { return "DROP DATABASE " + d.name }
+
+// WithChildren implements the sql.Node interface.
+func (d *dropDatabase) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(d, len(children), 0)
+	}
+
+	return d, nil
+}
+
+// Resolved implements the sql.Node interface.
+func (*dropDatabase) Resolved() bool { return true }
+
+// Children implements the sql.Node interface.
+func (*dropDatabase) Children() []sql.Node { return nil }
+
+// Schema implements the sql.Node interface.
+func (*dropDatabase) Schema() sql.Schema { return sql.OkResultSchema }
@@ -34,3 +34,27 @@ func TestIsKeyFuncs(t *testing.T) {
 	testKeyFunc(t, IsHeadKey, "dolt_working", false, "")
 	testKeyFunc(t, IsWorkingKey, "dolt_working", true, "dolt")
 }
+
+func TestSplitRevisionDbName(t *testing.T) {
+	tests := []struct {
+		dbName  string
+		srcName string
+		revSpec string
+		ok      bool
+	}{
+		{"mydb", "", "", false},
+		{"mydb/", "", "", false},
+		{"/feature", "", "", false},
+		{"mydb/feature", "mydb", "feature", true},
+		{"mydb/dev/feature", "mydb", "dev/feature", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.dbName, func(t *testing.T) {
+			srcName, revSpec, ok := SplitRevisionDbName(test.dbName)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.srcName, srcName)
+			assert.Equal(t, test.revSpec, revSpec)
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
@@ -39,6 +40,11 @@ func NewCommitFunc(args ...sql.Expression) (sql.Expression, error) {
 // Eval implements the Expression interface.
 func (cf *CommitFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	dbName := ctx.GetCurrentDatabase()
+
+	if err := sqle.CheckBranchPrivilege(ctx, dbName, "COMMIT", auth.WritePerm); err != nil {
+		return nil, err
+	}
+
 	dSess := sqle.DSessFromSess(ctx.Session)
 
 	//  Get the params associated with COMMIT.
@@ -105,6 +111,8 @@ func (cf *CommitFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		return nil, err
 	}
 
+	dSess.NotifyCommitted(dbName)
+
 	return h.String(), nil
 }
 
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
@@ -38,6 +39,10 @@ func (d DoltAddFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		return 1, fmt.Errorf("Empty database name.")
 	}
 
+	if err := sqle.CheckBranchPrivilege(ctx, dbName, "DOLT_ADD", auth.WritePerm); err != nil {
+		return 1, err
+	}
+
 	dSess := sqle.DSessFromSess(ctx.Session)
 	dbData, ok := dSess.GetDbData(dbName)
 
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
 
@@ -44,6 +45,10 @@ func (d DoltCheckoutFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, erro
 		return 1, fmt.Errorf("Empty database name.")
 	}
 
+	if err := sqle.CheckBranchPrivilege(ctx, dbName, "DOLT_CHECKOUT", auth.WritePerm); err != nil {
+		return 1, err
+	}
+
 	dSess := sqle.DSessFromSess(ctx.Session)
 	dbData, ok := dSess.GetDbData(dbName)
 
@@ -142,6 +147,7 @@ func checkoutNewBranch(ctx *sql.Context, dbData env.DbData, branchName, startPt
 	if err != nil {
 		return err
 	}
+	sqle.DSessFromSess(ctx.Session).NotifyBranchUpdated(ctx.GetCurrentDatabase())
 
 	return checkoutBranch(ctx, dbData, branchName)
 }
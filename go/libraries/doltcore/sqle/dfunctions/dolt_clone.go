@@ -0,0 +1,100 @@
+// Copyright 2020 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+const DoltCloneFuncName = "dolt_clone"
+
+var ErrCloneNotSupported = errors.New("DOLT_CLONE is only supported by sql-server")
+
+// DoltCloneFunc clones a remote into a new database of the server, which becomes available to every session.
+type DoltCloneFunc struct {
+	expression.BinaryExpression
+}
+
+// NewDoltCloneFunc creates a new DoltCloneFunc expression cloning the remote at the url |url| into the database named
+// |name|.
+func NewDoltCloneFunc(url, name sql.Expression) sql.Expression {
+	return &DoltCloneFunc{expression.BinaryExpression{Left: url, Right: name}}
+}
+
+// Eval implements the Expression interface.
+func (d *DoltCloneFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	url, err := d.Left.Eval(ctx, row)
+	if err != nil {
+		return 1, err
+	}
+	name, err := d.Right.Eval(ctx, row)
+	if err != nil {
+		return 1, err
+	}
+
+	urlStr, ok := url.(string)
+	if !ok || urlStr == "" {
+		return 1, errors.New("error: DOLT_CLONE requires a remote url")
+	}
+	nameStr, ok := name.(string)
+	if !ok || nameStr == "" {
+		return 1, errors.New("error: DOLT_CLONE requires a database name")
+	}
+
+	provider := sqle.DSessFromSess(ctx.Session).DatabaseProvider()
+	if provider == nil {
+		return 1, ErrCloneNotSupported
+	}
+
+	if err := sqle.CheckDatabasePrivilege(ctx, nameStr, "DOLT_CLONE"); err != nil {
+		return 1, err
+	}
+
+	err = provider.CloneDatabase(ctx, urlStr, nameStr)
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// String implements the Stringer interface.
+func (d *DoltCloneFunc) String() string {
+	return fmt.Sprintf("DOLT_CLONE(%s, %s)", d.Left.String(), d.Right.String())
+}
+
+// IsNullable implements the Expression interface.
+func (d *DoltCloneFunc) IsNullable() bool {
+	return false
+}
+
+// WithChildren implements the Expression interface.
+func (d *DoltCloneFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 2 {
+		return nil, sql.ErrInvalidChildrenNumber.New(d, len(children), 2)
+	}
+	return NewDoltCloneFunc(children[0], children[1]), nil
+}
+
+// Type implements the Expression interface.
+func (d *DoltCloneFunc) Type() sql.Type {
+	return sql.Int8
+}
@@ -17,6 +17,7 @@ package dfunctions
 import (
 	"fmt"
 
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/vitess/go/vt/proto/query"
 
@@ -44,6 +45,11 @@ func NewDoltCommitFunc(args ...sql.Expression) (sql.Expression, error) {
 func (d DoltCommitFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	// Get the information for the sql context.
 	dbName := ctx.GetCurrentDatabase()
+
+	if err := sqle.CheckBranchPrivilege(ctx, dbName, "DOLT_COMMIT", auth.WritePerm); err != nil {
+		return nil, err
+	}
+
 	dSess := sqle.DSessFromSess(ctx.Session)
 	dbData, ok := dSess.GetDbData(dbName)
 
@@ -140,6 +146,8 @@ func (d DoltCommitFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error)
 		return nil, err
 	}
 
+	dSess.NotifyCommitted(dbName)
+
 	return h, nil
 }
 
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
 
@@ -44,6 +45,10 @@ func (d DoltMergeFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error)
 		return 1, fmt.Errorf("Empty database name.")
 	}
 
+	if err := sqle.CheckBranchPrivilege(ctx, dbName, "DOLT_MERGE", auth.WritePerm); err != nil {
+		return 1, err
+	}
+
 	sess := sqle.DSessFromSess(ctx.Session)
 	dbData, ok := sess.GetDbData(dbName)
 
@@ -135,6 +140,8 @@ func (d DoltMergeFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error)
 		if err != nil {
 			return nil, err
 		}
+
+		sess.NotifyMerged(dbName, false)
 		return cmh.String(), err
 	}
 
@@ -169,7 +176,7 @@ func abortMerge(ctx *sql.Context, dbData env.DbData) error {
 }
 
 func executeMerge(ctx *sql.Context, squash bool, parent, cm *doltdb.Commit, dbData env.DbData) error {
-	mergeRoot, mergeStats, err := merge.MergeCommits(ctx, parent, cm)
+	mergeRoot, mergeStats, err := merge.MergeCommits(ctx, parent, cm, sqle.MergeCheckEvaluator{})
 
 	if err != nil {
 		switch err {
@@ -182,7 +189,12 @@ func executeMerge(ctx *sql.Context, squash bool, parent, cm *doltdb.Commit, dbDa
 		}
 	}
 
-	return mergeRootToWorking(ctx, squash, dbData, mergeRoot, cm, mergeStats)
+	err = mergeRootToWorking(ctx, squash, dbData, mergeRoot, cm, mergeStats)
+	if hasConflicts := checkForConflicts(mergeStats); err == nil || hasConflicts {
+		sqle.DSessFromSess(ctx.Session).NotifyMerged(ctx.GetCurrentDatabase(), hasConflicts)
+	}
+
+	return err
 }
 
 func executeFFMerge(ctx *sql.Context, squash bool, dbData env.DbData, cm2 *doltdb.Commit) error {
@@ -289,6 +301,8 @@ func executeNoFFMerge(ctx *sql.Context, dSess *sqle.DoltSession, apr *argparser.
 		return err
 	}
 
+	dSess.NotifyCommitted(ctx.GetCurrentDatabase())
+
 	return setHeadAndWorkingSessionRoot(ctx, h)
 }
 
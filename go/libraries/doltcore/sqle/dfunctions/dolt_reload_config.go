@@ -0,0 +1,84 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"errors"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+)
+
+const DoltReloadConfigFuncName = "dolt_reload_config"
+
+var ErrReloadConfigNotSupported = errors.New("DOLT_RELOAD_CONFIG is only supported by sql-server")
+
+// DoltReloadConfigFunc reloads the config file of the server, applying the settings that can change while the server
+// runs. Settings that require a restart are reported in the server's log.
+type DoltReloadConfigFunc struct{}
+
+// NewDoltReloadConfigFunc creates a new DoltReloadConfigFunc expression.
+func NewDoltReloadConfigFunc() sql.Expression {
+	return &DoltReloadConfigFunc{}
+}
+
+// Children implements the Expression interface.
+func (*DoltReloadConfigFunc) Children() []sql.Expression {
+	return nil
+}
+
+// Eval implements the Expression interface.
+func (*DoltReloadConfigFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	reloader := sqle.DSessFromSess(ctx.Session).ConfigReloader()
+	if reloader == nil {
+		return 1, ErrReloadConfigNotSupported
+	}
+
+	err := reloader.ReloadConfig(ctx)
+	if err != nil {
+		return 1, err
+	}
+
+	return 0, nil
+}
+
+// IsNullable implements the Expression interface.
+func (*DoltReloadConfigFunc) IsNullable() bool {
+	return false
+}
+
+// Resolved implements the Expression interface.
+func (*DoltReloadConfigFunc) Resolved() bool {
+	return true
+}
+
+// String implements the Stringer interface.
+func (*DoltReloadConfigFunc) String() string {
+	return "DOLT_RELOAD_CONFIG()"
+}
+
+// Type implements the Expression interface.
+func (*DoltReloadConfigFunc) Type() sql.Type {
+	return sql.Int8
+}
+
+// WithChildren implements the Expression interface.
+func (d *DoltReloadConfigFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(d, len(children), 0)
+	}
+	return NewDoltReloadConfigFunc(), nil
+}
@@ -27,6 +27,8 @@ var DoltFunctions = []sql.Function{
 	sql.FunctionN{Name: DoltResetFuncName, Fn: NewDoltResetFunc},
 	sql.FunctionN{Name: DoltCheckoutFuncName, Fn: NewDoltCheckoutFunc},
 	sql.FunctionN{Name: DoltMergeFuncName, Fn: NewDoltMergeFunc},
+	sql.Function2{Name: DoltCloneFuncName, Fn: NewDoltCloneFunc},
+	sql.Function0{Name: DoltReloadConfigFuncName, Fn: NewDoltReloadConfigFunc},
 }
 
 // These are the DoltFunctions that get exposed to Dolthub Api.
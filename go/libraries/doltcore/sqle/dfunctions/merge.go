@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
@@ -68,6 +69,11 @@ func (cf *MergeFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	}
 
 	dbName := sess.GetCurrentDatabase()
+
+	if err := sqle.CheckBranchPrivilege(ctx, dbName, "MERGE", auth.WritePerm); err != nil {
+		return nil, err
+	}
+
 	ddb, ok := sess.GetDoltDB(dbName)
 	if !ok {
 		return nil, sql.ErrDatabaseNotFound.New(dbName)
@@ -100,10 +106,11 @@ func (cf *MergeFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	}
 
 	if canFF {
+		sess.NotifyMerged(dbName, false)
 		return cmh.String(), nil
 	}
 
-	mergeRoot, _, err := merge.MergeCommits(ctx, parent, cm)
+	mergeRoot, mergeStats, err := merge.MergeCommits(ctx, parent, cm, sqle.MergeCheckEvaluator{})
 
 	if err != nil {
 		return nil, err
@@ -130,6 +137,9 @@ func (cf *MergeFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 		return nil, err
 	}
 
+	sess.NotifyCommitted(dbName)
+	sess.NotifyMerged(dbName, checkForConflicts(mergeStats))
+
 	return h.String(), nil
 }
 
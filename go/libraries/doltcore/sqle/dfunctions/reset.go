@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
 
@@ -52,6 +53,11 @@ func (rf ResetFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	}
 
 	dbName := ctx.GetCurrentDatabase()
+
+	if err := sqle.CheckBranchPrivilege(ctx, dbName, "RESET", auth.WritePerm); err != nil {
+		return nil, err
+	}
+
 	dSess := sqle.DSessFromSess(ctx.Session)
 
 	var h hash.Hash
@@ -56,6 +56,9 @@ var _ DoltIndex = (*doltIndex)(nil)
 
 // AscendGreaterOrEqual implements sql.AscendIndex
 func (di *doltIndex) AscendGreaterOrEqual(keys ...interface{}) (sql.IndexLookup, error) {
+	if !di.orderedByValue() {
+		return di.allRowsLookup(), nil
+	}
 	tpl, err := di.keysToTuple(keys)
 	if err != nil {
 		return nil, err
@@ -70,6 +73,9 @@ func (di *doltIndex) AscendGreaterOrEqual(keys ...interface{}) (sql.IndexLookup,
 
 // AscendLessThan implements sql.AscendIndex
 func (di *doltIndex) AscendLessThan(keys ...interface{}) (sql.IndexLookup, error) {
+	if !di.orderedByValue() {
+		return di.allRowsLookup(), nil
+	}
 	tpl, err := di.keysToTuple(keys)
 	if err != nil {
 		return nil, err
@@ -85,6 +91,9 @@ func (di *doltIndex) AscendLessThan(keys ...interface{}) (sql.IndexLookup, error
 // AscendRange implements sql.AscendIndex
 // TODO: rename this from AscendRange to BetweenRange or something
 func (di *doltIndex) AscendRange(greaterOrEqual, lessThanOrEqual []interface{}) (sql.IndexLookup, error) {
+	if !di.orderedByValue() {
+		return di.allRowsLookup(), nil
+	}
 	greaterTpl, err := di.keysToTuple(greaterOrEqual)
 	if err != nil {
 		return nil, err
@@ -107,6 +116,9 @@ func (di *doltIndex) AscendRange(greaterOrEqual, lessThanOrEqual []interface{})
 
 // DescendGreater implements sql.DescendIndex
 func (di *doltIndex) DescendGreater(keys ...interface{}) (sql.IndexLookup, error) {
+	if !di.orderedByValue() {
+		return di.allRowsLookup(), nil
+	}
 	tpl, err := di.keysToTuple(keys)
 	if err != nil {
 		return nil, err
@@ -125,6 +137,9 @@ func (di *doltIndex) DescendGreater(keys ...interface{}) (sql.IndexLookup, error
 
 // DescendLessOrEqual implements sql.DescendIndex
 func (di *doltIndex) DescendLessOrEqual(keys ...interface{}) (sql.IndexLookup, error) {
+	if !di.orderedByValue() {
+		return di.allRowsLookup(), nil
+	}
 	tpl, err := di.keysToTuple(keys)
 	if err != nil {
 		return nil, err
@@ -181,6 +196,9 @@ func (di *doltIndex) Get(keys ...interface{}) (sql.IndexLookup, error) {
 
 // Not implements sql.NegateIndex
 func (di *doltIndex) Not(keys ...interface{}) (sql.IndexLookup, error) {
+	if !di.orderedByValue() {
+		return di.allRowsLookup(), nil
+	}
 	tpl, err := di.keysToTuple(keys)
 	if err != nil {
 		return nil, err
@@ -249,6 +267,30 @@ func (di *doltIndex) IndexRowData() types.Map {
 	return di.indexRowData
 }
 
+// orderedByValue returns whether the index rows are ordered by the values of the indexed columns. Blob values, which
+// include TEXT columns, are ordered by their hash, so ranges over them do not match the rows that fall between their
+// bounds. Range and negated lookups on such indexes scan every index row instead, with the filter applied to the
+// result; equality lookups through Get still use the index, since equal values have equal hashes.
+func (di *doltIndex) orderedByValue() bool {
+	for _, col := range di.cols {
+		if col.Kind == types.BlobKind {
+			return false
+		}
+	}
+	return true
+}
+
+// allRowsLookup returns a lookup of every row in the index, for ranges that the index can't narrow down. The filter
+// that requested the lookup is still applied to the rows that it returns.
+func (di *doltIndex) allRowsLookup() sql.IndexLookup {
+	return &doltIndexLookup{
+		idx: di,
+		ranges: []lookup.Range{
+			lookup.AllRange(),
+		},
+	}
+}
+
 func (di *doltIndex) keysToTuple(keys []interface{}) (types.Tuple, error) {
 	nbf := di.indexRowData.Format()
 	if len(di.cols) != len(keys) {
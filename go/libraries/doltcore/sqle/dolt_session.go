@@ -23,6 +23,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dtables"
 	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
 	"github.com/dolthub/dolt/go/store/hash"
 )
@@ -42,8 +43,34 @@ type DoltSession struct {
 	dbEditors map[string]*editor.TableEditSession
 	caches    map[string]TableCache
 
+	// transactions holds the active transaction of each database.
+	transactions map[string]*DoltTransaction
+	// baseRoots holds the working root of each database when the session last read or wrote it. Changes made outside
+	// of a transaction are merged with concurrent changes relative to it.
+	baseRoots map[string]*doltdb.RootValue
+	// explicitTransaction is set when the active transactions were started by BEGIN or START TRANSACTION, which
+	// disable autocommit until the transaction ends.
+	explicitTransaction bool
+
 	Username string
 	Email    string
+
+	// privileges checks the tables and branches that the user of the session reads and writes, if set
+	privileges PrivilegeChecker
+	// accounts are the user accounts that the session's statements which manage accounts are run against, if set
+	accounts *PrivilegeStore
+	// events is notified of the commits and merges made by the session, if set
+	events SessionEventListener
+	// replication reports the replication of the session's databases to remotes, if they are replicated
+	replication dtables.ReplicationStatusProvider
+	// databases creates, clones and drops databases for the session, if set
+	databases DatabaseProvider
+	// configReloader reloads the configuration of the server for DOLT_RELOAD_CONFIG, if set
+	configReloader ConfigReloader
+	// revisionDbs counts the sessions of the engine that use each revision database, if set
+	revisionDbs *RevisionDatabases
+	// rowsExamined counts the rows read from tables by the session's queries, see ResetRowsExamined
+	rowsExamined uint64
 }
 
 // TableCache is a caches for sql.Tables.
@@ -70,8 +97,11 @@ func DefaultDoltSession() *DoltSession {
 		dbDatas:   make(map[string]env.DbData),
 		dbEditors: make(map[string]*editor.TableEditSession),
 		caches:    make(map[string]TableCache),
-		Username:  "",
-		Email:     "",
+
+		transactions: make(map[string]*DoltTransaction),
+		baseRoots:    make(map[string]*doltdb.RootValue),
+		Username:     "",
+		Email:        "",
 	}
 	return sess
 }
@@ -94,6 +124,9 @@ func NewDoltSession(ctx context.Context, sqlSess sql.Session, username, email st
 		Username:  username,
 		Email:     email,
 		caches:    make(map[string]TableCache),
+
+		transactions: make(map[string]*DoltTransaction),
+		baseRoots:    make(map[string]*doltdb.RootValue),
 	}
 	for _, db := range dbs {
 		err := sess.AddDB(ctx, db)
@@ -115,28 +148,218 @@ func TableCacheFromSess(sess sql.Session, dbName string) TableCache {
 	return sess.(*DoltSession).caches[dbName]
 }
 
+// CommitTransaction commits the changes made by the session to every database. The changes are merged into the
+// current working root of each database, relative to the working root when the transaction started, or when the session
+// last read the database if it has no active transaction. If the changes to any database conflict with those of a
+// concurrent transaction, nothing is written, the transaction is rolled back and ErrRetryTransaction is returned.
 func (sess *DoltSession) CommitTransaction(ctx *sql.Context) error {
-	currentDb := sess.GetCurrentDatabase()
-	if currentDb == "" {
-		return sql.ErrNoDatabaseSelected.New()
+	defer sess.endTransaction(ctx)
+
+	txCommitMu.Lock()
+	defer txCommitMu.Unlock()
+
+	// the changes to every database are merged before any of them is written, so that a transaction whose changes to
+	// one database conflict doesn't commit its changes to the others
+	txs := make(map[string]*DoltTransaction, len(sess.dbRoots))
+	newRoots := make(map[string]*doltdb.RootValue, len(sess.dbRoots))
+	writes := make(map[string]bool, len(sess.dbRoots))
+	for dbName, dbRoot := range sess.dbRoots {
+		tx, err := sess.transaction(ctx, dbName)
+		if err != nil {
+			return err
+		}
+
+		newRoot, write, err := tx.merge(ctx, dbRoot.root)
+		if ErrRetryTransaction.Is(err) {
+			if sess.events != nil {
+				sess.events.TransactionConflicted(dbName)
+			}
+
+			rollbackErr := sess.StartTransaction(ctx)
+			if rollbackErr != nil {
+				return rollbackErr
+			}
+
+			return err
+		} else if err != nil {
+			return err
+		}
+
+		txs[dbName] = tx
+		newRoots[dbName] = newRoot
+		writes[dbName] = write
 	}
 
-	dbRoot, ok := sess.dbRoots[currentDb]
-	// It's possible that this returns false if the user has created an in-Memory database. Moreover,
-	// the analyzer will check for us whether a db exists or not.
-	if !ok {
-		return nil
+	for dbName, newRoot := range newRoots {
+		if writes[dbName] {
+			err := txs[dbName].write(ctx, newRoot)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := sess.setRoot(ctx, dbName, newRoot)
+		if err != nil {
+			return err
+		}
+		sess.baseRoots[dbName] = newRoot
+	}
+
+	return nil
+}
+
+// transaction returns the active transaction of the database |dbName|. Databases without one get a transaction that
+// starts at the working root the session last read, or at the current working root if the session never read it.
+func (sess *DoltSession) transaction(ctx *sql.Context, dbName string) (*DoltTransaction, error) {
+	if tx, ok := sess.transactions[dbName]; ok {
+		return tx, nil
+	}
+
+	dbData := sess.dbDatas[dbName]
+	if base, ok := sess.baseRoots[dbName]; ok {
+		return NewDoltTransaction(base, dbData), nil
+	}
+
+	workingRoot, err := env.WorkingRoot(ctx, dbData.Ddb, dbData.Rsr)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDoltTransaction(workingRoot, dbData), nil
+}
+
+// StartTransaction starts a transaction on every database in the session, reloading the working root of each one.
+// Any changes the session made outside of a transaction are discarded.
+func (sess *DoltSession) StartTransaction(ctx *sql.Context) error {
+	for dbName := range sess.dbDatas {
+		err := sess.startDbTransaction(ctx, dbName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (sess *DoltSession) startDbTransaction(ctx *sql.Context, dbName string) error {
+	dbData := sess.dbDatas[dbName]
+	workingHash := dbData.Rsr.WorkingHash()
+
+	root := sess.dbRoots[dbName].root
+	if sess.dbRoots[dbName].hashStr != workingHash.String() {
+		var err error
+		root, err = dbData.Ddb.ReadRootValue(ctx, workingHash)
+		if err != nil {
+			return err
+		}
+
+		err = sess.setRoot(ctx, dbName, root)
+		if err != nil {
+			return err
+		}
 	}
 
-	dbData := sess.dbDatas[currentDb]
+	sess.transactions[dbName] = NewDoltTransaction(root, dbData)
+	sess.baseRoots[dbName] = root
+	return nil
+}
+
+// StartExplicitTransaction implements BEGIN and START TRANSACTION. The active transaction is committed, and a new one
+// is started with autocommit disabled until it is committed or rolled back.
+func (sess *DoltSession) StartExplicitTransaction(ctx *sql.Context) error {
+	err := sess.CommitTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = sess.StartTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if sess.IsAutocommit() {
+		sess.explicitTransaction = true
+		return sess.Session.Set(ctx, sql.AutoCommitSessionVar, sql.Boolean, false)
+	}
+
+	return nil
+}
+
+// RollbackTransaction discards the changes made in the active transaction of every database.
+func (sess *DoltSession) RollbackTransaction(ctx *sql.Context) error {
+	defer sess.endTransaction(ctx)
+
+	for dbName, tx := range sess.transactions {
+		err := sess.setRoot(ctx, dbName, tx.StartRoot())
+		if err != nil {
+			return err
+		}
+
+		// tables cached for the start root may have been updated in place by the statements of the transaction
+		sess.caches[dbName].Clear()
+	}
+
+	return nil
+}
+
+// InTransaction returns whether the session has an active transaction.
+func (sess *DoltSession) InTransaction() bool {
+	return len(sess.transactions) > 0
+}
+
+// FinishStatement must be called after each statement executed by the session. When autocommit is enabled, every
+// statement runs in its own transaction, which ends with the statement.
+func (sess *DoltSession) FinishStatement(ctx *sql.Context) {
+	if sess.IsAutocommit() {
+		sess.endTransaction(ctx)
+	}
+}
+
+func (sess *DoltSession) endTransaction(ctx *sql.Context) {
+	for dbName := range sess.transactions {
+		delete(sess.transactions, dbName)
+	}
+
+	if sess.explicitTransaction {
+		sess.explicitTransaction = false
+		_ = sess.Session.Set(ctx, sql.AutoCommitSessionVar, sql.Boolean, true)
+	}
+}
+
+// IsAutocommit returns whether autocommit is enabled for the session.
+func (sess *DoltSession) IsAutocommit() bool {
+	typ, val := sess.Session.Get(sql.AutoCommitSessionVar)
+	if val == nil {
+		return false
+	}
+
+	switch typ {
+	case sql.Int64:
+		return val.(int64) == 1
+	case sql.Boolean:
+		autocommit, _ := sql.ConvertToBool(val)
+		return autocommit
+	default:
+		return false
+	}
+}
+
+// setRoot sets the working root of the database named in the session
+func (sess *DoltSession) setRoot(ctx *sql.Context, dbName string, newRoot *doltdb.RootValue) error {
+	h, err := newRoot.HashOf()
+	if err != nil {
+		return err
+	}
 
-	root := dbRoot.root
-	h, err := dbData.Ddb.WriteRootValue(ctx, root)
+	hashStr := h.String()
+	err = sess.Session.Set(ctx, dbName+WorkingKeySuffix, hashType, hashStr)
 	if err != nil {
 		return err
 	}
 
-	return dbData.Rsw.SetWorkingHash(ctx, h)
+	sess.dbRoots[dbName] = dbRoot{hashStr, newRoot}
+
+	return sess.dbEditors[dbName].SetRoot(ctx, newRoot)
 }
 
 // GetDoltDB returns the *DoltDB for a given database by name
@@ -323,7 +546,10 @@ func (sess *DoltSession) Set(ctx context.Context, key string, typ sql.Type, valu
 		if err != nil {
 			return err
 		}
-		intVal := convertedVal.(int64)
+		intVal, ok := convertedVal.(int64)
+		if !ok {
+			return fmt.Errorf("variable 'foreign_key_checks' can't be set to the value of 'NULL'")
+		}
 		if intVal == 0 {
 			for _, tableEditSession := range sess.dbEditors {
 				tableEditSession.Props.ForeignKeyChecksDisabled = true
@@ -448,3 +674,27 @@ func (tc tableCache) Clear() {
 		delete(tc.tables, rt)
 	}
 }
+
+// SetReplicationStatusProvider sets the ReplicationStatusProvider that the dolt_replication_status system table of
+// this session's databases reads from. Without one, the table is empty.
+func (sess *DoltSession) SetReplicationStatusProvider(provider dtables.ReplicationStatusProvider) {
+	sess.replication = provider
+}
+
+// ConfigReloader reloads the configuration of a running server, applying the settings that can change without a
+// restart.
+type ConfigReloader interface {
+	// ReloadConfig reloads the server's configuration on behalf of the user of the session of |ctx|.
+	ReloadConfig(ctx *sql.Context) error
+}
+
+// SetConfigReloader sets the ConfigReloader that DOLT_RELOAD_CONFIG uses. Sessions without one can't reload the
+// configuration of a server.
+func (sess *DoltSession) SetConfigReloader(reloader ConfigReloader) {
+	sess.configReloader = reloader
+}
+
+// ConfigReloader returns the ConfigReloader of this session, or nil if it has none.
+func (sess *DoltSession) ConfigReloader() ConfigReloader {
+	return sess.configReloader
+}
@@ -0,0 +1,63 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+)
+
+func TestCommitWithoutTransactionMerges(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	db := NewDatabase("dolt", dEnv.DbData())
+
+	ctx1 := NewTestSQLCtx(context.Background())
+	ctx2 := NewTestSQLCtx(context.Background())
+	for _, ctx := range []*sql.Context{ctx1, ctx2} {
+		require.NoError(t, DSessFromSess(ctx.Session).AddDB(ctx, db))
+		require.NoError(t, db.LoadRootFromRepoState(ctx))
+	}
+
+	base, err := db.GetRoot(ctx1)
+	require.NoError(t, err)
+	rootA, err := ExecuteSql(dEnv, base, "CREATE TABLE a (pk INT PRIMARY KEY);")
+	require.NoError(t, err)
+	rootB, err := ExecuteSql(dEnv, base, "CREATE TABLE b (pk INT PRIMARY KEY);")
+	require.NoError(t, err)
+
+	sess2 := DSessFromSess(ctx2.Session)
+	require.NoError(t, sess2.StartTransaction(ctx2))
+	require.NoError(t, db.SetRoot(ctx2, rootB))
+	require.NoError(t, sess2.CommitTransaction(ctx2))
+
+	// the first session has no transaction, and its change is merged with the commit of the second session
+	require.NoError(t, db.SetRoot(ctx1, rootA))
+	require.NoError(t, DSessFromSess(ctx1.Session).CommitTransaction(ctx1))
+
+	working, err := env.WorkingRoot(context.Background(), dEnv.DoltDB, dEnv.RepoStateReader())
+	require.NoError(t, err)
+	for _, tblName := range []string{"a", "b"} {
+		ok, err := working.HasTable(context.Background(), tblName)
+		require.NoError(t, err)
+		assert.True(t, ok, tblName)
+	}
+}
@@ -0,0 +1,231 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+)
+
+// RetryTransactionMsg prefixes the message of every ErrRetryTransaction error. The server reports these errors with the
+// error code of ER_LOCK_DEADLOCK, which clients recognize as a transaction that can be retried.
+const RetryTransactionMsg = "Transaction conflicts with a concurrent transaction; try restarting transaction"
+
+var ErrRetryTransaction = errors.NewKind(RetryTransactionMsg + ": %s")
+
+// txCommitMu serializes transaction commits, which read and then write the working root of a database.
+var txCommitMu = &sync.Mutex{}
+
+// DoltTransaction is a transaction on a single database. It remembers the working root of the database at the time
+// the transaction started, which is used as the merge base when the transaction commits.
+type DoltTransaction struct {
+	startRoot *doltdb.RootValue
+	dbData    env.DbData
+}
+
+// NewDoltTransaction returns a transaction on the database described by |dbData| which started at |startRoot|.
+func NewDoltTransaction(startRoot *doltdb.RootValue, dbData env.DbData) *DoltTransaction {
+	return &DoltTransaction{startRoot: startRoot, dbData: dbData}
+}
+
+// StartRoot returns the working root of the database at the time the transaction started.
+func (tx *DoltTransaction) StartRoot() *doltdb.RootValue {
+	return tx.startRoot
+}
+
+// merge returns the root that committing |newRoot| writes as the working root of the database, and whether it needs to
+// be written. If other transactions have changed the working root since this transaction started, the changes in
+// |newRoot| are three-way merged into the current working root. Returns ErrRetryTransaction if the changes conflict, or
+// if the merged rows violate check constraints.
+// txCommitMu must be held until the root is written.
+func (tx *DoltTransaction) merge(ctx *sql.Context, newRoot *doltdb.RootValue) (*doltdb.RootValue, bool, error) {
+	newHash, err := newRoot.HashOf()
+	if err != nil {
+		return nil, false, err
+	}
+
+	startHash, err := tx.startRoot.HashOf()
+	if err != nil {
+		return nil, false, err
+	}
+
+	workingHash := tx.dbData.Rsr.WorkingHash()
+	if newHash == workingHash {
+		return newRoot, false, nil
+	}
+
+	if newHash == startHash {
+		// nothing changed in this transaction
+		workingRoot, err := env.WorkingRoot(ctx, tx.dbData.Ddb, tx.dbData.Rsr)
+		return workingRoot, false, err
+	}
+
+	if workingHash == startHash {
+		return newRoot, true, nil
+	}
+
+	workingRoot, err := tx.dbData.Ddb.ReadRootValue(ctx, workingHash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	mergedRoot, stats, err := merge.MergeRoots(ctx, workingRoot, newRoot, tx.startRoot, MergeCheckEvaluator{})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var conflictTbls, violationTbls []string
+	for tblName, stat := range stats {
+		if stat.Conflicts > 0 {
+			conflictTbls = append(conflictTbls, tblName)
+		}
+		if stat.CheckViolations > 0 {
+			violationTbls = append(violationTbls, tblName)
+		}
+	}
+
+	if len(conflictTbls) > 0 {
+		sort.Strings(conflictTbls)
+		return nil, false, ErrRetryTransaction.New("concurrent changes conflict in tables " + strings.Join(conflictTbls, ", "))
+	}
+
+	if len(violationTbls) > 0 {
+		sort.Strings(violationTbls)
+		return nil, false, ErrRetryTransaction.New("concurrent changes violate check constraints in tables " + strings.Join(violationTbls, ", "))
+	}
+
+	return mergedRoot, true, nil
+}
+
+// write writes |root|, returned by merge, as the working root of the database.
+func (tx *DoltTransaction) write(ctx *sql.Context, root *doltdb.RootValue) error {
+	_, err := env.UpdateWorkingRoot(ctx, tx.dbData.Ddb, tx.dbData.Rsw, root)
+	return err
+}
+
+// ResetWorkingRoot replaces the working and staged roots of the database described by |dbData| with |root|, discarding
+// any uncommitted changes. It's serialized with transaction commits, so transactions that start after it returns see
+// |root|.
+func ResetWorkingRoot(ctx context.Context, dbData env.DbData, root *doltdb.RootValue) error {
+	txCommitMu.Lock()
+	defer txCommitMu.Unlock()
+
+	_, err := env.UpdateWorkingRoot(ctx, dbData.Ddb, dbData.Rsw, root)
+	if err != nil {
+		return err
+	}
+
+	_, err = env.UpdateStagedRoot(ctx, dbData.Ddb, dbData.Rsw, root)
+	return err
+}
+
+// ResolveTransactionStatements is an analyzer rule that replaces the BEGIN / START TRANSACTION and ROLLBACK nodes,
+// which are no-ops in go-mysql-server, with nodes that start and roll back the transactions of a DoltSession.
+func ResolveTransactionStatements(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope) (sql.Node, error) {
+	if _, ok := ctx.Session.(*DoltSession); !ok {
+		return n, nil
+	}
+
+	switch n.(type) {
+	case *plan.Begin:
+		return &startTransaction{}, nil
+	case *plan.Rollback:
+		return &rollbackTransaction{}, nil
+	default:
+		return n, nil
+	}
+}
+
+// startTransaction is the node for BEGIN / START TRANSACTION. Like MySQL, it commits the active transaction and
+// disables autocommit until the new transaction ends.
+type startTransaction struct{}
+
+var _ sql.Node = (*startTransaction)(nil)
+
+// RowIter implements the sql.Node interface.
+func (*startTransaction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	err := DSessFromSess(ctx.Session).StartExplicitTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+func (*startTransaction) String() string { return "START TRANSACTION" }
+
+// WithChildren implements the sql.Node interface.
+func (t *startTransaction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(t, len(children), 0)
+	}
+
+	return t, nil
+}
+
+// Resolved implements the sql.Node interface.
+func (*startTransaction) Resolved() bool { return true }
+
+// Children implements the sql.Node interface.
+func (*startTransaction) Children() []sql.Node { return nil }
+
+// Schema implements the sql.Node interface.
+func (*startTransaction) Schema() sql.Schema { return nil }
+
+// rollbackTransaction is the node for ROLLBACK. It discards the changes made in the active transaction.
+type rollbackTransaction struct{}
+
+var _ sql.Node = (*rollbackTransaction)(nil)
+
+// RowIter implements the sql.Node interface.
+func (*rollbackTransaction) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	err := DSessFromSess(ctx.Session).RollbackTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+func (*rollbackTransaction) String() string { return "ROLLBACK" }
+
+// WithChildren implements the sql.Node interface.
+func (r *rollbackTransaction) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(r, len(children), 0)
+	}
+
+	return r, nil
+}
+
+// Resolved implements the sql.Node interface.
+func (*rollbackTransaction) Resolved() bool { return true }
+
+// Children implements the sql.Node interface.
+func (*rollbackTransaction) Children() []sql.Node { return nil }
+
+// Schema implements the sql.Node interface.
+func (*rollbackTransaction) Schema() sql.Schema { return nil }
@@ -0,0 +1,120 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// BranchReplicationStatus is the state of the replication of a branch to a remote.
+type BranchReplicationStatus struct {
+	Remote string
+	Branch string
+	// LocalHash is the hash of the commit at the head of the local branch.
+	LocalHash string
+	// RemoteHash is the hash of the last commit pushed to the remote, if any.
+	RemoteHash string
+	// LastPush is the time of the last successful push, if any.
+	LastPush time.Time
+	// Lag is the time since the branch was first updated without being pushed, or zero if the remote is up to date.
+	Lag time.Duration
+	// Failures is the number of consecutive failed attempts to push the branch.
+	Failures int
+	// LastError is the error of the last failed push, if the last push failed.
+	LastError string
+}
+
+// ReplicationStatusProvider reports the replication of the branches of databases to their remotes.
+type ReplicationStatusProvider interface {
+	// ReplicationStatus returns the replication status of each branch of the database |dbName|, or nil if it isn't
+	// replicated.
+	ReplicationStatus(dbName string) []BranchReplicationStatus
+}
+
+var _ sql.Table = (*ReplicationStatusTable)(nil)
+
+// ReplicationStatusTable is a sql.Table implementation that implements a system table which shows the replication of
+// the branches of a database to a remote
+type ReplicationStatusTable struct {
+	dbName   string
+	provider ReplicationStatusProvider
+}
+
+// NewReplicationStatusTable creates a ReplicationStatusTable. The table is empty if |provider| is nil.
+func NewReplicationStatusTable(_ *sql.Context, dbName string, provider ReplicationStatusProvider) sql.Table {
+	return &ReplicationStatusTable{dbName: dbName, provider: provider}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReplicationStatusTableName
+func (rt *ReplicationStatusTable) Name() string {
+	return doltdb.ReplicationStatusTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReplicationStatusTableName
+func (rt *ReplicationStatusTable) String() string {
+	return doltdb.ReplicationStatusTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the replication status system table
+func (rt *ReplicationStatusTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "remote", Type: sql.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: true, Nullable: false},
+		{Name: "branch", Type: sql.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: true, Nullable: false},
+		{Name: "local_hash", Type: sql.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "remote_hash", Type: sql.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "last_push", Type: sql.Datetime, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false, Nullable: true},
+		{Name: "lag_seconds", Type: sql.Float64, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "failures", Type: sql.Int64, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "last_error", Type: sql.Text, Source: doltdb.ReplicationStatusTableName, PrimaryKey: false, Nullable: true},
+	}
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data.  Currently the data is unpartitioned.
+func (rt *ReplicationStatusTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return sqlutil.NewSinglePartitionIter(types.Map{}), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (rt *ReplicationStatusTable) PartitionRows(*sql.Context, sql.Partition) (sql.RowIter, error) {
+	if rt.provider == nil {
+		return sql.RowsToRowIter(), nil
+	}
+
+	var rows []sql.Row
+	for _, st := range rt.provider.ReplicationStatus(rt.dbName) {
+		var remoteHash, lastPush, lastError interface{}
+		if st.RemoteHash != "" {
+			remoteHash = st.RemoteHash
+		}
+		if !st.LastPush.IsZero() {
+			lastPush = st.LastPush
+		}
+		if st.LastError != "" {
+			lastError = st.LastError
+		}
+
+		rows = append(rows, sql.NewRow(st.Remote, st.Branch, st.LocalHash, remoteHash, lastPush, st.Lag.Seconds(), int64(st.Failures), lastError))
+	}
+
+	return sql.RowsToRowIter(rows...), nil
+}
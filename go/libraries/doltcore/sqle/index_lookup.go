@@ -19,6 +19,7 @@ import (
 	"fmt"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/opentracing/opentracing-go"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/lookup"
@@ -163,12 +164,20 @@ func (il *doltIndexLookup) RowIterForRanges(ctx *sql.Context, rowData types.Map,
 
 	nrr := noms.NewNomsRangeReader(il.idx.IndexSchema(), rowData, readRanges)
 
+	var iter sql.RowIter
 	covers := il.indexCoversCols(columns)
 	if covers {
-		return NewCoveringIndexRowIterAdapter(ctx, il.idx, nrr, columns), nil
+		iter = NewCoveringIndexRowIterAdapter(ctx, il.idx, nrr, columns)
 	} else {
-		return NewIndexLookupRowIterAdapter(ctx, il.idx, nrr), nil
+		iter = NewIndexLookupRowIterAdapter(ctx, il.idx, nrr)
 	}
+
+	return newTableReadIter(ctx, iter, "dolt.index_lookup", opentracing.Tags{
+		"table":    il.idx.Table(),
+		"index":    il.idx.ID(),
+		"ranges":   len(ranges),
+		"covering": covers,
+	}), nil
 }
 
 type nomsKeyIter interface {
@@ -0,0 +1,489 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// matchAll is the pattern of a Grant that matches every database, branch or table
+const matchAll = "*"
+
+// nativePasswordRegex matches mysql_native_password hashes
+var nativePasswordRegex = regexp.MustCompile(`^\*[0-9A-F]{40}$`)
+
+// ErrInvalidPasswordHash is returned for account passwords given as hashes that aren't mysql_native_password hashes.
+var ErrInvalidPasswordHash = errors.NewKind("invalid password hash for user '%s', expected a mysql_native_password hash")
+
+// Grant gives a user permissions on the tables of the branches of the databases that match its patterns. Patterns use
+// the syntax of path.Match, so the branch pattern `dev/*` matches every branch whose name starts with `dev/`, and an
+// empty pattern or `*` matches everything.
+type Grant struct {
+	Database    string   `yaml:"database" json:"database"`
+	Branch      string   `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Table       string   `yaml:"table,omitempty" json:"table,omitempty"`
+	Permissions []string `yaml:"permissions" json:"permissions"`
+}
+
+// UserAccount is a user account of the server, and the privileges granted to it. The password may be given in plain
+// text as Password, or as a mysql_native_password hash as PasswordHash. Accounts are only saved with hashes.
+type UserAccount struct {
+	Name         string  `yaml:"name" json:"name"`
+	Password     string  `yaml:"password,omitempty" json:"password,omitempty"`
+	PasswordHash string  `yaml:"password_hash,omitempty" json:"password_hash,omitempty"`
+	Grants       []Grant `yaml:"grants,omitempty" json:"grants,omitempty"`
+}
+
+// hashed returns the account with its password replaced by its mysql_native_password hash.
+func (user UserAccount) hashed() (UserAccount, error) {
+	if user.PasswordHash == "" {
+		user.PasswordHash = auth.NativePassword(user.Password)
+	} else if user.Password != "" {
+		return UserAccount{}, fmt.Errorf("user '%s' has both a password and a password hash", user.Name)
+	} else if !nativePasswordRegex.MatchString(user.PasswordHash) {
+		return UserAccount{}, ErrInvalidPasswordHash.New(user.Name)
+	}
+
+	user.Password = ""
+	return user, nil
+}
+
+// permissions returns the permissions of the grant, or an error if one of them isn't known.
+func (g Grant) permissions() (auth.Permission, error) {
+	var perms auth.Permission
+	for _, name := range g.Permissions {
+		perm, ok := auth.PermissionNames[strings.ToLower(name)]
+		if !ok {
+			return 0, auth.ErrUnknownPermission.New(name)
+		}
+		perms |= perm
+	}
+	return perms, nil
+}
+
+// sameScope returns whether the grants apply to the same databases, branches and tables.
+func (g Grant) sameScope(other Grant) bool {
+	return strings.EqualFold(normalizePattern(g.Database), normalizePattern(other.Database)) &&
+		normalizePattern(g.Branch) == normalizePattern(other.Branch) &&
+		strings.EqualFold(normalizePattern(g.Table), normalizePattern(other.Table))
+}
+
+// matches returns whether the grant applies to |table| of |branch| of |db|. An empty |table| is only matched by grants
+// on every table, and an empty |branch| only by grants on every branch.
+func (g Grant) matches(db, branch, table string) bool {
+	return matchPattern(strings.ToLower(g.Database), strings.ToLower(db)) &&
+		matchPattern(g.Branch, branch) &&
+		matchPattern(strings.ToLower(g.Table), strings.ToLower(table))
+}
+
+func normalizePattern(pattern string) string {
+	if pattern == "" {
+		return matchAll
+	}
+	return pattern
+}
+
+func matchPattern(pattern, val string) bool {
+	pattern = normalizePattern(pattern)
+	if pattern == matchAll {
+		return true
+	}
+	if val == "" {
+		return false
+	}
+	ok, err := path.Match(pattern, val)
+	return err == nil && ok
+}
+
+// permissionNames returns the names of the permissions in |perms|, in a stable order.
+func permissionNames(perms auth.Permission) []string {
+	var names []string
+	for name, perm := range auth.PermissionNames {
+		if perms&perm != 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PrivilegeStore holds the user accounts of a server. The account of the server's configured user has every privilege
+// and may manage the other accounts, which only have the privileges granted to them. Accounts created or changed while
+// the server runs are saved to the privilege file, if the server has one.
+type PrivilegeStore struct {
+	mu       *sync.RWMutex
+	users    map[string]*UserAccount
+	rootUser UserAccount
+	readOnly bool
+
+	fs       filesys.Filesys
+	filePath string
+}
+
+var _ auth.Auth = (*PrivilegeStore)(nil)
+var _ PrivilegeChecker = (*PrivilegeStore)(nil)
+
+// NewPrivilegeStore returns a PrivilegeStore whose superuser is |rootUser|. The accounts in |users| are added to the
+// store, followed by the accounts saved in the privilege file at |filePath| if it exists. When |readOnly| is true no
+// account may write, regardless of its grants.
+func NewPrivilegeStore(fs filesys.Filesys, filePath string, rootUser, rootPassword string, readOnly bool, users []UserAccount) (*PrivilegeStore, error) {
+	ps := &PrivilegeStore{
+		mu:       &sync.RWMutex{},
+		users:    make(map[string]*UserAccount),
+		rootUser: UserAccount{Name: rootUser, PasswordHash: auth.NativePassword(rootPassword)},
+		readOnly: readOnly,
+		fs:       fs,
+		filePath: filePath,
+	}
+
+	for i := range users {
+		if err := ps.addUser(users[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if filePath == "" {
+		return ps, nil
+	}
+
+	if exists, _ := fs.Exists(filePath); !exists {
+		return ps, nil
+	}
+
+	data, err := fs.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []UserAccount
+	if err = json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse privilege file '%s': %v", filePath, err)
+	}
+
+	for _, user := range saved {
+		delete(ps.users, user.Name)
+		if err = ps.addUser(user); err != nil {
+			return nil, err
+		}
+	}
+
+	return ps, nil
+}
+
+func (ps *PrivilegeStore) addUser(user UserAccount) error {
+	if user.Name == "" {
+		return fmt.Errorf("user accounts must have a name")
+	}
+	if user.Name == ps.rootUser.Name {
+		return auth.ErrDuplicateUser.New(user.Name)
+	}
+	if _, ok := ps.users[user.Name]; ok {
+		return auth.ErrDuplicateUser.New(user.Name)
+	}
+	for _, g := range user.Grants {
+		if _, err := g.permissions(); err != nil {
+			return fmt.Errorf("invalid grant for user '%s': %v", user.Name, err)
+		}
+	}
+
+	user, err := user.hashed()
+	if err != nil {
+		return err
+	}
+
+	ps.users[user.Name] = &user
+	return nil
+}
+
+// save writes the accounts of the store, other than the superuser, to the privilege file.
+func (ps *PrivilegeStore) save() error {
+	if ps.filePath == "" {
+		return nil
+	}
+
+	users := make([]UserAccount, 0, len(ps.users))
+	for _, user := range ps.users {
+		users = append(users, *user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Name < users[j].Name
+	})
+
+	data, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ps.fs.WriteFile(ps.filePath, data)
+}
+
+// IsSuperUser returns whether |user| is the server's configured user, which may manage the other accounts.
+func (ps *PrivilegeStore) IsSuperUser(user string) bool {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return user == ps.rootUser.Name
+}
+
+// SuperUser returns the name of the server's configured user.
+func (ps *PrivilegeStore) SuperUser() string {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+	return ps.rootUser.Name
+}
+
+// Reconfigure changes the superuser of the store to |rootUser|, with the password |rootPassword|, and whether the store
+// is read-only, as when the server's config is reloaded. Connections of the previous superuser lose its privileges.
+func (ps *PrivilegeStore) Reconfigure(rootUser, rootPassword string, readOnly bool) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.users[rootUser]; ok {
+		return auth.ErrDuplicateUser.New(rootUser)
+	}
+
+	ps.rootUser = UserAccount{Name: rootUser, PasswordHash: auth.NativePassword(rootPassword)}
+	ps.readOnly = readOnly
+	return nil
+}
+
+// CreateUser adds |account|, without any privileges.
+func (ps *PrivilegeStore) CreateUser(account UserAccount, ifNotExists bool) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.users[account.Name]; ok || account.Name == ps.rootUser.Name {
+		if ifNotExists {
+			return nil
+		}
+		return fmt.Errorf("Operation CREATE USER failed for '%s'", account.Name)
+	}
+
+	account.Grants = nil
+	if err := ps.addUser(account); err != nil {
+		return err
+	}
+
+	return ps.save()
+}
+
+// DropUser removes an account.
+func (ps *PrivilegeStore) DropUser(name string, ifExists bool) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	if _, ok := ps.users[name]; !ok {
+		if ifExists {
+			return nil
+		}
+		return fmt.Errorf("Operation DROP USER failed for '%s'", name)
+	}
+
+	delete(ps.users, name)
+	return ps.save()
+}
+
+// GrantPrivileges adds the permissions of |grant| to the account |name|.
+func (ps *PrivilegeStore) GrantPrivileges(name string, grant Grant) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	user, ok := ps.users[name]
+	if !ok {
+		return fmt.Errorf("user '%s' does not exist", name)
+	}
+
+	perms, err := grant.permissions()
+	if err != nil {
+		return err
+	}
+
+	for i, g := range user.Grants {
+		if g.sameScope(grant) {
+			existing, _ := g.permissions()
+			user.Grants[i].Permissions = permissionNames(existing | perms)
+			return ps.save()
+		}
+	}
+
+	grant.Permissions = permissionNames(perms)
+	user.Grants = append(user.Grants, grant)
+	return ps.save()
+}
+
+// RevokePrivileges removes the permissions of |grant| from the grant of the account |name| with the same scope.
+func (ps *PrivilegeStore) RevokePrivileges(name string, grant Grant) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	user, ok := ps.users[name]
+	if !ok {
+		return fmt.Errorf("user '%s' does not exist", name)
+	}
+
+	perms, err := grant.permissions()
+	if err != nil {
+		return err
+	}
+
+	for i, g := range user.Grants {
+		if g.sameScope(grant) {
+			existing, _ := g.permissions()
+			if remaining := existing &^ perms; remaining != 0 {
+				user.Grants[i].Permissions = permissionNames(remaining)
+			} else {
+				user.Grants = append(user.Grants[:i:i], user.Grants[i+1:]...)
+			}
+			return ps.save()
+		}
+	}
+
+	return fmt.Errorf("There is no such grant defined for user '%s'", name)
+}
+
+// GrantsFor returns the grants of the account |name|, and false if there is no such account.
+func (ps *PrivilegeStore) GrantsFor(name string) ([]Grant, bool) {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	if name == ps.rootUser.Name {
+		perms := auth.AllPermissions
+		if ps.readOnly {
+			perms = auth.ReadPerm
+		}
+		return []Grant{{Database: matchAll, Branch: matchAll, Table: matchAll, Permissions: permissionNames(perms)}}, true
+	}
+
+	user, ok := ps.users[name]
+	if !ok {
+		return nil, false
+	}
+	return append([]Grant(nil), user.Grants...), true
+}
+
+// HasTablePrivilege implements dsqle.PrivilegeChecker.
+func (ps *PrivilegeStore) HasTablePrivilege(user, db, branch, table string, perm auth.Permission) bool {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	if ps.readOnly && perm&auth.WritePerm != 0 {
+		return false
+	}
+	if user == ps.rootUser.Name {
+		return true
+	}
+
+	account, ok := ps.users[user]
+	if !ok {
+		return false
+	}
+
+	var granted auth.Permission
+	for _, g := range account.Grants {
+		if g.matches(db, branch, table) {
+			p, _ := g.permissions()
+			granted |= p
+		}
+	}
+
+	return granted&perm == perm
+}
+
+// Allowed implements auth.Auth. Statements are only checked against the server's read-only setting here, the tables
+// and branches that they use are checked by HasTablePrivilege as the statements run.
+func (ps *PrivilegeStore) Allowed(ctx *sql.Context, permission auth.Permission) error {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	if ps.readOnly && permission&auth.WritePerm != 0 {
+		return auth.ErrNotAuthorized.Wrap(auth.ErrNoPermission.New(auth.WritePerm))
+	}
+
+	user := ctx.Client().User
+	if _, ok := ps.users[user]; !ok && user != ps.rootUser.Name {
+		return auth.ErrNotAuthorized.Wrap(auth.ErrNoPermission.New(permission))
+	}
+
+	return nil
+}
+
+// Authenticate returns whether |password| is the password of the account |user|, for clients that authenticate
+// outside of the MySQL protocol.
+func (ps *PrivilegeStore) Authenticate(user, password string) bool {
+	ps.mu.RLock()
+	defer ps.mu.RUnlock()
+
+	hash, ok := ps.rootUser.PasswordHash, user == ps.rootUser.Name
+	if account, isUser := ps.users[user]; isUser {
+		hash, ok = account.PasswordHash, true
+	}
+
+	return ok && subtle.ConstantTimeCompare([]byte(hash), []byte(auth.NativePassword(password))) == 1
+}
+
+// Mysql implements auth.Auth.
+func (ps *PrivilegeStore) Mysql() mysql.AuthServer {
+	return &privilegeAuthServer{mysql.NewAuthServerStatic(), ps}
+}
+
+// privilegeAuthServer authenticates users against the accounts of a PrivilegeStore as they are when each user
+// connects, so that accounts created by a running server can be used right away.
+type privilegeAuthServer struct {
+	*mysql.AuthServerStatic
+	ps *PrivilegeStore
+}
+
+// ValidateHash implements mysql.AuthServer.
+func (s *privilegeAuthServer) ValidateHash(salt []byte, user string, authResponse []byte, remoteAddr net.Addr) (mysql.Getter, error) {
+	return s.staticServerFor(user).ValidateHash(salt, user, authResponse, remoteAddr)
+}
+
+// Negotiate implements mysql.AuthServer.
+func (s *privilegeAuthServer) Negotiate(c *mysql.Conn, user string, remoteAddr net.Addr) (mysql.Getter, error) {
+	return s.staticServerFor(user).Negotiate(c, user, remoteAddr)
+}
+
+func (s *privilegeAuthServer) staticServerFor(user string) *mysql.AuthServerStatic {
+	static := mysql.NewAuthServerStatic()
+	static.Method = s.Method
+
+	s.ps.mu.RLock()
+	defer s.ps.mu.RUnlock()
+
+	password, ok := s.ps.rootUser.PasswordHash, user == s.ps.rootUser.Name
+	if account, isUser := s.ps.users[user]; isUser {
+		password, ok = account.PasswordHash, true
+	}
+
+	if ok {
+		static.Entries[user] = []*mysql.AuthServerStaticEntry{{MysqlNativePassword: password, Password: password}}
+	}
+
+	return static
+}
@@ -0,0 +1,236 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestGrantMatches(t *testing.T) {
+	tests := []struct {
+		grant   Grant
+		db      string
+		branch  string
+		table   string
+		matches bool
+	}{
+		{Grant{Database: "*"}, "db", "master", "t", true},
+		{Grant{Database: "db"}, "DB", "master", "t", true},
+		{Grant{Database: "db"}, "other", "master", "t", false},
+		{Grant{Database: "db", Branch: "master"}, "db", "master", "t", true},
+		{Grant{Database: "db", Branch: "master"}, "db", "", "t", false},
+		{Grant{Database: "db", Branch: "dev/*"}, "db", "dev/feature", "t", true},
+		{Grant{Database: "db", Branch: "dev/*"}, "db", "dev", "t", false},
+		{Grant{Database: "db", Branch: "dev/*"}, "db", "master", "t", false},
+		{Grant{Database: "db", Table: "people"}, "db", "master", "PEOPLE", true},
+		{Grant{Database: "db", Table: "people"}, "db", "master", "", false},
+		{Grant{Database: "db", Table: "*"}, "db", "master", "", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.matches, test.grant.matches(test.db, test.branch, test.table), "%+v %s %s %s", test.grant, test.db, test.branch, test.table)
+	}
+}
+
+func TestPrivilegeStore(t *testing.T) {
+	fs := filesys.EmptyInMemFS("/")
+	users := []UserAccount{
+		{
+			Name:     "dev",
+			Password: "pass",
+			Grants: []Grant{
+				{Database: "db", Branch: "master", Permissions: []string{"read"}},
+				{Database: "db", Branch: "dev/*", Permissions: []string{"read", "write"}},
+			},
+		},
+	}
+
+	ps, err := NewPrivilegeStore(fs, "/privileges.json", "root", "", false, users)
+	require.NoError(t, err)
+
+	assert.True(t, ps.HasTablePrivilege("root", "db", "master", "t", auth.AllPermissions))
+	assert.True(t, ps.HasTablePrivilege("dev", "db", "master", "t", auth.ReadPerm))
+	assert.False(t, ps.HasTablePrivilege("dev", "db", "master", "t", auth.WritePerm))
+	assert.True(t, ps.HasTablePrivilege("dev", "db", "dev/x", "t", auth.AllPermissions))
+	assert.False(t, ps.HasTablePrivilege("dev", "other", "master", "t", auth.ReadPerm))
+	assert.False(t, ps.HasTablePrivilege("nobody", "db", "master", "t", auth.ReadPerm))
+
+	require.NoError(t, ps.CreateUser(UserAccount{Name: "reader", Password: "secret"}, false))
+	assert.Error(t, ps.CreateUser(UserAccount{Name: "reader", Password: "secret"}, false))
+	assert.NoError(t, ps.CreateUser(UserAccount{Name: "reader", Password: "secret"}, true))
+	require.NoError(t, ps.GrantPrivileges("reader", Grant{Database: "db", Table: "people", Permissions: []string{"read"}}))
+	require.NoError(t, ps.GrantPrivileges("reader", Grant{Database: "db", Table: "people", Permissions: []string{"write"}}))
+	grants, ok := ps.GrantsFor("reader")
+	require.True(t, ok)
+	require.Len(t, grants, 1)
+	assert.Equal(t, []string{"read", "write"}, grants[0].Permissions)
+
+	require.NoError(t, ps.RevokePrivileges("reader", Grant{Database: "db", Table: "people", Permissions: []string{"write"}}))
+	assert.True(t, ps.HasTablePrivilege("reader", "db", "master", "people", auth.ReadPerm))
+	assert.False(t, ps.HasTablePrivilege("reader", "db", "master", "people", auth.WritePerm))
+	assert.False(t, ps.HasTablePrivilege("reader", "db", "master", "other", auth.ReadPerm))
+
+	// accounts changed at runtime are loaded from the privilege file, and override those of the config
+	loaded, err := NewPrivilegeStore(fs, "/privileges.json", "root", "", true, users)
+	require.NoError(t, err)
+	assert.True(t, loaded.HasTablePrivilege("reader", "db", "master", "people", auth.ReadPerm))
+	assert.False(t, loaded.HasTablePrivilege("dev", "db", "dev/x", "t", auth.WritePerm))
+	assert.Equal(t, auth.NativePassword("secret"), loaded.users["reader"].PasswordHash)
+	assert.Empty(t, loaded.users["reader"].Password)
+	assert.True(t, loaded.Authenticate("reader", "secret"))
+
+	require.NoError(t, loaded.DropUser("reader", false))
+	assert.Error(t, loaded.DropUser("reader", false))
+	assert.NoError(t, loaded.DropUser("reader", true))
+	_, ok = loaded.GrantsFor("reader")
+	assert.False(t, ok)
+
+	// passwords are only taken as hashes when they're given as hashes, whatever they look like
+	hash := auth.NativePassword("secret")
+	require.NoError(t, loaded.CreateUser(UserAccount{Name: "hashed", PasswordHash: hash}, false))
+	assert.True(t, loaded.Authenticate("hashed", "secret"))
+	require.NoError(t, loaded.CreateUser(UserAccount{Name: "literal", Password: hash}, false))
+	assert.True(t, loaded.Authenticate("literal", hash))
+	assert.False(t, loaded.Authenticate("literal", "secret"))
+	assert.True(t, ErrInvalidPasswordHash.Is(loaded.CreateUser(UserAccount{Name: "invalid", PasswordHash: "secret"}, false)))
+}
+
+func TestParseAccountStatement(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected *AccountStatement
+		err      bool
+	}{
+		{"select * from people", nil, false},
+		{"CREATE TABLE user (pk int primary key)", nil, false},
+		{"CREATE USER 'bob'@'%' IDENTIFIED BY 'pw';", &AccountStatement{kind: createUserStatement, user: "bob", password: "pw"}, false},
+		{"create user if not exists bob", &AccountStatement{kind: createUserStatement, user: "bob", ifExists: true}, false},
+		{"DROP USER IF EXISTS `bob`", &AccountStatement{kind: dropUserStatement, user: "bob", ifExists: true}, false},
+		{`CREATE USER bob IDENTIFIED BY 'it''s \'quoted\''`, &AccountStatement{kind: createUserStatement, user: "bob", password: "it's 'quoted'"}, false},
+		{`CREATE USER bob IDENTIFIED BY "a\\b\n"`, &AccountStatement{kind: createUserStatement, user: "bob", password: "a\\b\n"}, false},
+		{
+			"CREATE USER bob IDENTIFIED BY '*14E65567ABDB5135D0CFD9A70B3032C179A49EE7'",
+			&AccountStatement{kind: createUserStatement, user: "bob", password: "*14E65567ABDB5135D0CFD9A70B3032C179A49EE7"},
+			false,
+		},
+		{
+			"CREATE USER bob IDENTIFIED WITH mysql_native_password AS '*14E65567ABDB5135D0CFD9A70B3032C179A49EE7'",
+			&AccountStatement{kind: createUserStatement, user: "bob", passwordHash: "*14E65567ABDB5135D0CFD9A70B3032C179A49EE7"},
+			false,
+		},
+		{"CREATE USER bob IDENTIFIED WITH 'mysql_native_password' BY 'pw'", &AccountStatement{kind: createUserStatement, user: "bob", password: "pw"}, false},
+		{"CREATE USER bob IDENTIFIED WITH mysql_native_password AS 'pw'", nil, true},
+		{"CREATE USER bob IDENTIFIED WITH caching_sha2_password BY 'pw'", nil, true},
+		{"CREATE USER bob IDENTIFIED AS '*14E65567ABDB5135D0CFD9A70B3032C179A49EE7'", nil, true},
+		{
+			"GRANT SELECT, INSERT, UPDATE, DELETE, CREATE, DROP, ALTER, INDEX ON `mydb/dev/*`.people TO bob",
+			&AccountStatement{kind: grantStatement, user: "bob", grant: Grant{Database: "mydb", Branch: "dev/*", Table: "people", Permissions: []string{"read", "write"}}},
+			false,
+		},
+		{
+			"grant all privileges on *.* to 'bob'@'localhost'",
+			&AccountStatement{kind: grantStatement, user: "bob", grant: Grant{Database: "*", Table: "*", Permissions: []string{"read", "write"}}},
+			false,
+		},
+		{
+			"REVOKE index, alter, drop, create, delete, update, insert ON mydb.* FROM bob",
+			&AccountStatement{kind: revokeStatement, user: "bob", grant: Grant{Database: "mydb", Table: "*", Permissions: []string{"write"}}},
+			false,
+		},
+		// write privileges can't be enforced on their own
+		{"GRANT SELECT, INSERT ON mydb.* TO bob", nil, true},
+		{"REVOKE UPDATE ON mydb.* FROM bob", nil, true},
+		{"GRANT EXECUTE ON mydb.* TO bob", nil, true},
+		{"GRANT SELECT ON mydb TO bob", nil, true},
+		{"SHOW GRANTS", &AccountStatement{kind: showGrantsStatement}, false},
+		{"show grants for 'bob'", &AccountStatement{kind: showGrantsStatement, user: "bob"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			stmt, err := ParseAccountStatement(test.query)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, stmt)
+		})
+	}
+}
+
+func TestFormatGrant(t *testing.T) {
+	assert.Equal(t, "GRANT ALL PRIVILEGES ON *.* TO `root`", formatGrant("root", Grant{Permissions: []string{"read", "write"}}))
+	assert.Equal(t, "GRANT SELECT ON `mydb/dev/*`.`people` TO `bob`", formatGrant("bob", Grant{Database: "mydb", Branch: "dev/*", Table: "people", Permissions: []string{"read"}}))
+
+	// the statement SHOW GRANTS displays for write permission can be run to grant it
+	stmt := formatGrant("bob", Grant{Database: "mydb", Permissions: []string{"write"}})
+	assert.Equal(t, "GRANT INSERT, UPDATE, DELETE, CREATE, DROP, ALTER, INDEX ON `mydb`.* TO `bob`", stmt)
+	parsed, err := ParseAccountStatement(stmt)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"write"}, parsed.grant.Permissions)
+}
+
+func TestExecAccountStatement(t *testing.T) {
+	newCtx := func(user string, accounts *PrivilegeStore) *sql.Context {
+		sess := DefaultDoltSession()
+		sess.Session = sql.NewSession("localhost", "", user, 1)
+		sess.SetUserAccounts(accounts)
+		return sql.NewContext(context.Background(), sql.WithSession(sess))
+	}
+	exec := func(ctx *sql.Context, query string) ([]sql.Row, error) {
+		stmt, err := ParseAccountStatement(query)
+		require.NoError(t, err)
+		require.NotNil(t, stmt)
+		_, iter, err := ExecAccountStatement(ctx, stmt)
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowIterToRows(ctx, iter)
+	}
+
+	_, err := exec(newCtx("root", nil), "CREATE USER bob")
+	assert.True(t, ErrNoUserAccounts.Is(err))
+
+	ps, err := NewPrivilegeStore(filesys.EmptyInMemFS("/"), "/privileges.json", "root", "", false, nil)
+	require.NoError(t, err)
+	root := newCtx("root", ps)
+	bob := newCtx("bob", ps)
+
+	_, err = exec(root, "CREATE USER bob IDENTIFIED BY 'pass'")
+	require.NoError(t, err)
+	_, err = exec(root, "GRANT SELECT ON mydb.* TO bob")
+	require.NoError(t, err)
+	assert.True(t, ps.Authenticate("bob", "pass"))
+	assert.True(t, ps.HasTablePrivilege("bob", "mydb", "master", "t", auth.ReadPerm))
+
+	rows, err := exec(bob, "SHOW GRANTS")
+	require.NoError(t, err)
+	assert.Equal(t, []sql.Row{{"GRANT SELECT ON `mydb`.* TO `bob`"}}, rows)
+
+	// only the superuser may manage accounts
+	_, err = exec(bob, "GRANT ALL ON mydb.* TO bob")
+	assert.Error(t, err)
+	_, err = exec(bob, "SHOW GRANTS FOR root")
+	assert.Error(t, err)
+}
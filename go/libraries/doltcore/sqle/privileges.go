@@ -0,0 +1,111 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/dolthub/go-mysql-server/auth"
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+)
+
+var ErrTableAccessDenied = errors.NewKind("%s command denied to user '%s' for table '%s'")
+var ErrBranchAccessDenied = errors.NewKind("%s command denied to user '%s' for branch '%s' of database '%s'")
+var ErrDatabaseAccessDenied = errors.NewKind("%s command denied to user '%s' for database '%s'")
+
+// PrivilegeChecker decides which tables of which branches the user of a session may read and write.
+type PrivilegeChecker interface {
+	// HasTablePrivilege returns whether |user| has |perm| on the table |table| of the branch |branch| of the database
+	// |db|. An empty |branch| refers to a commit rather than a branch, and an empty |table| refers to every table of
+	// the branch, as statements that commit or merge a branch do.
+	HasTablePrivilege(user, db, branch, table string, perm auth.Permission) bool
+}
+
+// SetPrivilegeChecker sets the PrivilegeChecker that the tables and branches used by this session are checked against.
+// Sessions without one may read and write everything.
+func (sess *DoltSession) SetPrivilegeChecker(privileges PrivilegeChecker) {
+	sess.privileges = privileges
+}
+
+// hasPrivilege returns whether the user of the session of |ctx| has |perm| on |table| of the branch |branch| of the
+// database |dbName|. An empty |branch| refers to a commit rather than a branch.
+func hasPrivilege(ctx *sql.Context, dbName, branch, table string, perm auth.Permission) bool {
+	privileges := DSessFromSess(ctx.Session).privileges
+	if privileges == nil {
+		return true
+	}
+
+	if srcName, _, ok := SplitRevisionDbName(dbName); ok {
+		dbName = srcName
+	}
+
+	return privileges.HasTablePrivilege(ctx.Client().User, dbName, branch, table, perm)
+}
+
+// checkedOutBranch returns the name of the branch checked out in |rsr|, or an empty string if a commit is checked
+// out, as it is in the revision databases of commits.
+func checkedOutBranch(rsr env.RepoStateReader) string {
+	if headRef := rsr.CWBHeadRef(); headRef != nil {
+		return headRef.GetPath()
+	}
+	return ""
+}
+
+// checkTablePrivilege returns an error naming |command| if the user of the session of |ctx| does not have |perm| on
+// the table |table| of |db|.
+func (db Database) checkTablePrivilege(ctx *sql.Context, command, table string, perm auth.Permission) error {
+	if !hasPrivilege(ctx, db.name, checkedOutBranch(db.rsr), table, perm) {
+		return ErrTableAccessDenied.New(command, ctx.Client().User, table)
+	}
+	return nil
+}
+
+// CheckBranchPrivilege returns an error naming |command| if the user of the session of |ctx| does not have |perm| on
+// every table of the checked out branch of the database |dbName|. Statements that commit, merge or reset a branch
+// require write privileges on the whole branch.
+func CheckBranchPrivilege(ctx *sql.Context, dbName, command string, perm auth.Permission) error {
+	rsr, ok := DSessFromSess(ctx.Session).GetDoltDBRepoStateReader(dbName)
+	if !ok {
+		return sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	branch := checkedOutBranch(rsr)
+	if !hasPrivilege(ctx, dbName, branch, "", perm) {
+		return ErrBranchAccessDenied.New(command, ctx.Client().User, branch, dbName)
+	}
+	return nil
+}
+
+// withTablePrivileges returns |tbl|, which is read from the branch |branch|, as the user of the session of |ctx| may
+// use it: an error if they can't read it, and a read-only table if they can't write to it.
+func (db Database) withTablePrivileges(ctx *sql.Context, branch, tableName string, tbl sql.Table) (sql.Table, error) {
+	if !hasPrivilege(ctx, db.name, branch, tableName, auth.ReadPerm) {
+		return nil, ErrTableAccessDenied.New("SELECT", ctx.Client().User, tableName)
+	}
+
+	switch t := tbl.(type) {
+	case *WritableDoltTable:
+		if !hasPrivilege(ctx, db.name, branch, tableName, auth.WritePerm) {
+			return &t.DoltTable, nil
+		}
+	case *AlterableDoltTable:
+		if !hasPrivilege(ctx, db.name, branch, tableName, auth.WritePerm) {
+			return &t.DoltTable, nil
+		}
+	}
+
+	return tbl, nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"io"
+	"sync/atomic"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/opentracing/opentracing-go"
+)
+
+// ResetRowsExamined returns the number of rows the session has read from tables and indexes since the last reset, and
+// resets it to zero. Servers call it after each query to report the rows that query examined.
+func (sess *DoltSession) ResetRowsExamined() uint64 {
+	return atomic.SwapUint64(&sess.rowsExamined, 0)
+}
+
+// tableReadIter wraps the rows read from a table or index. Each row is counted as examined by the session, and the
+// read is recorded as a tracing span that is finished when the iterator is closed.
+type tableReadIter struct {
+	sql.RowIter
+	span opentracing.Span
+	sess *DoltSession
+	rows uint64
+}
+
+var _ sql.RowIter = (*tableReadIter)(nil)
+
+// newTableReadIter returns |iter| wrapped in a tableReadIter whose span is named |opName| and tagged with |tags|.
+func newTableReadIter(ctx *sql.Context, iter sql.RowIter, opName string, tags opentracing.Tags) sql.RowIter {
+	span, _ := ctx.Span(opName, tags)
+	sess, _ := ctx.Session.(*DoltSession)
+	return &tableReadIter{RowIter: iter, span: span, sess: sess}
+}
+
+// Next implements sql.RowIter.
+func (itr *tableReadIter) Next() (sql.Row, error) {
+	r, err := itr.RowIter.Next()
+	if err != nil {
+		if err != io.EOF {
+			itr.span.SetTag("error", true)
+		}
+		return nil, err
+	}
+
+	itr.rows++
+	if itr.sess != nil {
+		atomic.AddUint64(&itr.sess.rowsExamined, 1)
+	}
+	return r, nil
+}
+
+// Close implements sql.RowIter.
+func (itr *tableReadIter) Close(ctx *sql.Context) error {
+	itr.span.SetTag("rows", itr.rows)
+	itr.span.Finish()
+	return itr.RowIter.Close(ctx)
+}
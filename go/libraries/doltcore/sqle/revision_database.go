@@ -0,0 +1,598 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/analyzer"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdocs"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// DbRevisionDelimiter separates the name of a database from a branch name or commit hash in the name of a revision
+// database, e.g. `mydb/feature` or `mydb/mh7ssfq6el8v6j4v2ur4ku4rd2q6qsb1`.
+const DbRevisionDelimiter = "/"
+
+var ErrInvalidRevision = errors.NewKind("%s is not a branch or commit of database %s")
+
+// ErrBranchWorkingSetConflict is returned when the uncommitted changes to a branch that isn't checked out can't be
+// merged onto commits made to the branch since.
+var ErrBranchWorkingSetConflict = errors.NewKind("uncommitted changes to branch %s conflict with its new commits in tables %s")
+
+// RevisionDatabases counts the sessions that use each revision database registered with the catalog of an engine, so
+// that revision databases can be removed from the catalog once no session uses them. An engine whose sessions come and
+// go, like a server's, gives all of its sessions the same RevisionDatabases, see DoltSession.SetRevisionDatabases.
+// Revision databases registered by sessions without one stay in the catalog.
+type RevisionDatabases struct {
+	// mu serializes the registration of revision databases with the catalog, and guards sessions
+	mu       *sync.Mutex
+	sessions map[string]int
+}
+
+// NewRevisionDatabases returns a RevisionDatabases for the sessions of one engine.
+func NewRevisionDatabases() *RevisionDatabases {
+	return &RevisionDatabases{mu: &sync.Mutex{}, sessions: make(map[string]int)}
+}
+
+// SetRevisionDatabases sets the RevisionDatabases that the session counts its revision databases in. It's shared by
+// the sessions of an engine.
+func (sess *DoltSession) SetRevisionDatabases(revDbs *RevisionDatabases) {
+	sess.revisionDbs = revDbs
+}
+
+// SplitRevisionDbName splits the name of a revision database into the name of the underlying database and the
+// revision. Returns false if |dbName| does not name a revision database.
+func SplitRevisionDbName(dbName string) (string, string, bool) {
+	idx := strings.Index(dbName, DbRevisionDelimiter)
+	if idx <= 0 || idx == len(dbName)-1 {
+		return "", "", false
+	}
+
+	return dbName[:idx], dbName[idx+1:], true
+}
+
+// NewRevisionDatabase returns a Database for the revision |revSpec| of |srcDb|. When |revSpec| names a branch, the
+// returned database is writable and commits made through it advance that branch. Otherwise |revSpec| must be a commit
+// hash, and the returned database is read-only.
+func NewRevisionDatabase(ctx context.Context, srcDb Database, revSpec string) (Database, error) {
+	name := srcDb.Name() + DbRevisionDelimiter + revSpec
+	ddb := srcDb.GetDoltDB()
+
+	branchRef := ref.NewBranchRef(revSpec)
+	if ref.Equals(branchRef, srcDb.GetStateReader().CWBHeadRef()) {
+		// the checked out branch shares its working set with the database itself
+		return Database{
+			name:      name,
+			ddb:       ddb,
+			rsr:       srcDb.rsr,
+			rsw:       srcDb.rsw,
+			drw:       srcDb.drw,
+			batchMode: srcDb.batchMode,
+		}, nil
+	}
+
+	var headRef ref.DoltRef
+	var cs *doltdb.CommitSpec
+	isBranch, err := ddb.HasRef(ctx, branchRef)
+	if err != nil {
+		return Database{}, err
+	}
+
+	if isBranch {
+		headRef = branchRef
+		cs, err = doltdb.NewCommitSpec("HEAD")
+	} else if hash.IsValid(revSpec) {
+		cs, err = doltdb.NewCommitSpec(revSpec)
+	} else {
+		return Database{}, ErrInvalidRevision.New(revSpec, srcDb.Name())
+	}
+
+	if err != nil {
+		return Database{}, err
+	}
+
+	cm, err := ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		if doltdb.IsNotACommit(err) {
+			return Database{}, ErrInvalidRevision.New(revSpec, srcDb.Name())
+		}
+		return Database{}, err
+	}
+
+	root, err := cm.GetRootValue()
+	if err != nil {
+		return Database{}, err
+	}
+
+	rootHash, err := root.HashOf()
+	if err != nil {
+		return Database{}, err
+	}
+
+	working, staged := rootHash, rootHash
+	if isBranch {
+		working, staged, err = loadBranchWorkingSet(ctx, ddb, headRef, cm, root)
+		if err != nil {
+			return Database{}, err
+		}
+	}
+
+	rs := &revisionRepoState{
+		mu:       &sync.RWMutex{},
+		ddb:      ddb,
+		headRef:  headRef,
+		headSpec: cs,
+		working:  working,
+		staged:   staged,
+	}
+
+	return Database{
+		name:      name,
+		ddb:       ddb,
+		rsr:       rs,
+		rsw:       rs,
+		drw:       rs,
+		batchMode: srcDb.batchMode,
+		readOnly:  !isBranch,
+	}, nil
+}
+
+// loadBranchWorkingSet returns the working and staged roots of |branch|, whose head is |head| with root |headRoot|.
+// These are the roots persisted by an earlier revision database, if there are any. If the branch has moved since they
+// were persisted, their uncommitted changes are merged onto |head| and the merged roots are persisted in their place.
+// Returns ErrBranchWorkingSetConflict, and keeps the persisted roots, if the changes can't be merged.
+func loadBranchWorkingSet(ctx context.Context, ddb *doltdb.DoltDB, branch ref.DoltRef, head *doltdb.Commit, headRoot *doltdb.RootValue) (hash.Hash, hash.Hash, error) {
+	headRootHash, err := headRoot.HashOf()
+	if err != nil {
+		return hash.Hash{}, hash.Hash{}, err
+	}
+
+	working, staged, ok, err := ddb.GetBranchWorkingSet(ctx, branch)
+	if err != nil || !ok {
+		return headRootHash, headRootHash, err
+	}
+
+	headHash, err := head.HashOf()
+	if err != nil {
+		return hash.Hash{}, hash.Hash{}, err
+	}
+
+	moved := false
+	var roots [2]hash.Hash
+	for i, br := range []doltdb.BranchRoot{working, staged} {
+		baseHash, err := br.Base.HashOf()
+		if err != nil {
+			return hash.Hash{}, hash.Hash{}, err
+		}
+
+		if baseHash == headHash {
+			roots[i] = br.Root
+			continue
+		}
+
+		moved = true
+		roots[i], err = mergeBranchRoot(ctx, ddb, branch, headRoot, br)
+		if err != nil {
+			return hash.Hash{}, hash.Hash{}, err
+		}
+	}
+
+	if moved {
+		if roots[0] == headRootHash && roots[1] == headRootHash {
+			err = ddb.DeleteBranchWorkingSet(ctx, branch)
+		} else {
+			err = ddb.SetBranchWorkingSet(ctx, branch, head, roots[0], roots[1])
+		}
+		if err != nil {
+			return hash.Hash{}, hash.Hash{}, err
+		}
+	}
+
+	return roots[0], roots[1], nil
+}
+
+// mergeBranchRoot merges the changes of |br|, a root persisted for |branch|, onto |headRoot|, the root of the branch's
+// new head, and returns the hash of the merged root.
+func mergeBranchRoot(ctx context.Context, ddb *doltdb.DoltDB, branch ref.DoltRef, headRoot *doltdb.RootValue, br doltdb.BranchRoot) (hash.Hash, error) {
+	baseRoot, err := br.Base.GetRootValue()
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	root, err := ddb.ReadRootValue(ctx, br.Root)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	mergedRoot, stats, err := merge.MergeRoots(ctx, headRoot, root, baseRoot, MergeCheckEvaluator{})
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	var conflictTbls []string
+	for tblName, stat := range stats {
+		if stat.Conflicts > 0 || stat.CheckViolations > 0 {
+			conflictTbls = append(conflictTbls, tblName)
+		}
+	}
+
+	if len(conflictTbls) > 0 {
+		sort.Strings(conflictTbls)
+		return hash.Hash{}, ErrBranchWorkingSetConflict.New(branch.GetPath(), strings.Join(conflictTbls, ", "))
+	}
+
+	return ddb.WriteRootValue(ctx, mergedRoot)
+}
+
+// RegisterRevisionDatabase makes the revision database |dbName| available to queries, by adding it to |catalog| if
+// it isn't already registered, and to the session of |ctx| if the session doesn't know about it yet. Names which do
+// not refer to a revision of a Database in |catalog| are ignored. Sessions with RevisionDatabases must call
+// ReleaseRevisionDatabases when they end, so that the database can be removed from |catalog| again.
+func RegisterRevisionDatabase(ctx *sql.Context, catalog *sql.Catalog, dbName string) error {
+	srcName, revSpec, ok := SplitRevisionDbName(dbName)
+	if !ok {
+		return nil
+	}
+
+	dsess, _ := ctx.Session.(*DoltSession)
+	var revDbs *RevisionDatabases
+	if dsess != nil && dsess.revisionDbs != nil {
+		revDbs = dsess.revisionDbs
+		revDbs.mu.Lock()
+		defer revDbs.mu.Unlock()
+	}
+
+	sqlDb, err := catalog.Database(dbName)
+	if err != nil {
+		srcDb, err := catalog.Database(srcName)
+		if err != nil {
+			return err
+		}
+
+		doltDb, ok := srcDb.(Database)
+		if !ok {
+			return nil
+		}
+
+		sqlDb, err = NewRevisionDatabase(ctx, doltDb, revSpec)
+		if err != nil {
+			return err
+		}
+
+		catalog.AddDatabase(sqlDb)
+	}
+
+	if dsess == nil {
+		return nil
+	}
+
+	if _, ok := dsess.dbDatas[sqlDb.Name()]; ok {
+		return nil
+	}
+
+	err = addDatabaseToSession(ctx, sqlDb)
+	if err != nil {
+		return err
+	}
+
+	if revDbs != nil {
+		revDbs.sessions[sqlDb.Name()]++
+	}
+
+	return nil
+}
+
+// ReleaseRevisionDatabases releases the revision databases that the session of |ctx| registered with |catalog|, and
+// removes the ones that no other session uses from |catalog|. It's called when a session ends.
+func ReleaseRevisionDatabases(ctx *sql.Context, catalog *sql.Catalog) {
+	dsess, ok := ctx.Session.(*DoltSession)
+	if !ok || dsess.revisionDbs == nil {
+		return
+	}
+
+	revDbs := dsess.revisionDbs
+	revDbs.mu.Lock()
+	defer revDbs.mu.Unlock()
+
+	for dbName, dbData := range dsess.dbDatas {
+		revDbs.release(catalog, dbName, dbData)
+	}
+}
+
+// release releases the revision database |dbName| for a session that no longer uses it, and removes it from |catalog|
+// if no other session uses it. Databases with a merge in progress are kept, since merge state isn't persisted along
+// with the working sets of branches. revDbs.mu must be held.
+func (revDbs *RevisionDatabases) release(catalog *sql.Catalog, dbName string, dbData env.DbData) {
+	if revDbs.sessions[dbName] == 0 {
+		return
+	}
+
+	revDbs.sessions[dbName]--
+	if revDbs.sessions[dbName] > 0 || dbData.Rsr.IsMergeActive() {
+		return
+	}
+
+	delete(revDbs.sessions, dbName)
+	catalog.RemoveDatabase(dbName)
+}
+
+// addDatabaseToSession adds |sqlDb| to the session of |ctx| if it's a Database that the session doesn't know about
+// yet, such as a revision database or a database created after the session started. The database joins the active
+// transaction of the session, if there is one.
+func addDatabaseToSession(ctx *sql.Context, sqlDb sql.Database) error {
+	db, ok := sqlDb.(Database)
+	if !ok {
+		return nil
+	}
+
+	dsess, ok := ctx.Session.(*DoltSession)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := dsess.dbDatas[db.Name()]; ok {
+		return nil
+	}
+
+	err := dsess.AddDB(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	err = db.LoadRootFromRepoState(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dsess.InTransaction() {
+		return dsess.startDbTransaction(ctx, db.Name())
+	}
+
+	return nil
+}
+
+// ResolveRevisionDatabases is an analyzer rule that registers every revision database referenced by a query, so that
+// the databases and tables of the query can be resolved by the analyzer. Databases created by other sessions are
+// added to the session the same way.
+func ResolveRevisionDatabases(ctx *sql.Context, a *analyzer.Analyzer, n sql.Node, scope *analyzer.Scope) (sql.Node, error) {
+	for _, dbName := range ReferencedDatabaseNames(ctx, n) {
+		if _, _, ok := SplitRevisionDbName(dbName); !ok {
+			db, err := a.Catalog.Database(dbName)
+			if err != nil {
+				// the analyzer reports unknown databases
+				continue
+			}
+
+			err = addDatabaseToSession(ctx, db)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		err := RegisterRevisionDatabase(ctx, a.Catalog, dbName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return n, nil
+}
+
+// ReferencedDatabaseNames returns the names of the databases that the unresolved node |n| uses: the current database
+// of the session of |ctx|, and the databases that |n| or its subqueries name, including the database of a USE
+// statement. Names may repeat, and keep the case they were written in.
+func ReferencedDatabaseNames(ctx *sql.Context, n sql.Node) []string {
+	var dbNames []string
+	if currDb := ctx.GetCurrentDatabase(); currDb != "" {
+		dbNames = append(dbNames, currDb)
+	}
+
+	var inspectNode func(n sql.Node) bool
+	inspectNode = func(n sql.Node) bool {
+		switch n := n.(type) {
+		case *plan.UnresolvedTable:
+			if n.Database != "" {
+				dbNames = append(dbNames, n.Database)
+			}
+		case sql.Databaser:
+			if db, ok := n.Database().(sql.UnresolvedDatabase); ok && db.Name() != "" {
+				dbNames = append(dbNames, db.Name())
+			}
+		}
+
+		return true
+	}
+
+	plan.Inspect(n, inspectNode)
+	plan.InspectExpressions(n, func(e sql.Expression) bool {
+		if sq, ok := e.(*plan.Subquery); ok {
+			plan.Inspect(sq.Query, inspectNode)
+		}
+		return true
+	})
+
+	return dbNames
+}
+
+// revisionRepoState is an implementation of env.RepoStateReader, env.RepoStateWriter and env.DocsReadWriter for
+// revision databases. Docs are read from and written to the working root, since a revision database has no filesystem
+// of its own. The working and staged roots of a branch are persisted in the database, see
+// doltdb.SetBranchWorkingSet, so that uncommitted changes survive restarts of the server. Merge state is only kept in
+// memory.
+type revisionRepoState struct {
+	mu       *sync.RWMutex
+	ddb      *doltdb.DoltDB
+	headRef  ref.DoltRef
+	headSpec *doltdb.CommitSpec
+	working  hash.Hash
+	staged   hash.Hash
+	merge    *env.MergeState
+}
+
+var _ env.RepoStateReader = (*revisionRepoState)(nil)
+var _ env.RepoStateWriter = (*revisionRepoState)(nil)
+var _ env.DocsReadWriter = (*revisionRepoState)(nil)
+
+func (rs *revisionRepoState) CWBHeadRef() ref.DoltRef {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.headRef
+}
+
+func (rs *revisionRepoState) CWBHeadSpec() *doltdb.CommitSpec {
+	return rs.headSpec
+}
+
+func (rs *revisionRepoState) CWBHeadHash(ctx context.Context) (hash.Hash, error) {
+	cm, err := rs.ddb.Resolve(ctx, rs.headSpec, rs.CWBHeadRef())
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	return cm.HashOf()
+}
+
+func (rs *revisionRepoState) WorkingHash() hash.Hash {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.working
+}
+
+func (rs *revisionRepoState) StagedHash() hash.Hash {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.staged
+}
+
+func (rs *revisionRepoState) IsMergeActive() bool {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.merge != nil
+}
+
+func (rs *revisionRepoState) GetMergeCommit() string {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.merge.Commit
+}
+
+func (rs *revisionRepoState) GetPreMergeWorking() string {
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	return rs.merge.PreMergeWorking
+}
+
+func (rs *revisionRepoState) SetStagedHash(ctx context.Context, h hash.Hash) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.staged = h
+	return rs.persist(ctx)
+}
+
+func (rs *revisionRepoState) SetWorkingHash(ctx context.Context, h hash.Hash) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.working = h
+	return rs.persist(ctx)
+}
+
+// persist writes the working and staged roots of the branch to the database, or deletes them once they match the head
+// of the branch. rs.mu must be held.
+func (rs *revisionRepoState) persist(ctx context.Context) error {
+	if rs.headRef == nil {
+		return nil
+	}
+
+	head, err := rs.ddb.ResolveRef(ctx, rs.headRef)
+	if err != nil {
+		return err
+	}
+
+	root, err := head.GetRootValue()
+	if err != nil {
+		return err
+	}
+
+	rootHash, err := root.HashOf()
+	if err != nil {
+		return err
+	}
+
+	if rs.working == rootHash && rs.staged == rootHash {
+		return rs.ddb.DeleteBranchWorkingSet(ctx, rs.headRef)
+	}
+
+	return rs.ddb.SetBranchWorkingSet(ctx, rs.headRef, head, rs.working, rs.staged)
+}
+
+func (rs *revisionRepoState) SetCWBHeadRef(_ context.Context, marshalableRef ref.MarshalableRef) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.headRef = marshalableRef.Ref
+	return nil
+}
+
+func (rs *revisionRepoState) AbortMerge() error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.working = hash.Parse(rs.merge.PreMergeWorking)
+	rs.merge = nil
+	return rs.persist(context.Background())
+}
+
+// ClearMerge is called once a commit moves the branch, so the working set is persisted against the new head.
+func (rs *revisionRepoState) ClearMerge() error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.merge = nil
+	return rs.persist(context.Background())
+}
+
+func (rs *revisionRepoState) StartMerge(commitStr string) error {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	rs.merge = &env.MergeState{Commit: commitStr, PreMergeWorking: rs.working.String()}
+	return nil
+}
+
+// GetDocsOnDisk implements env.DocsReadWriter by reading the docs in the working root.
+func (rs *revisionRepoState) GetDocsOnDisk(docNames ...string) (doltdocs.Docs, error) {
+	ctx := context.Background()
+	root, err := rs.ddb.ReadRootValue(ctx, rs.WorkingHash())
+	if err != nil {
+		return nil, err
+	}
+
+	return doltdocs.GetDocsFromRoot(ctx, root, docNames...)
+}
+
+// WriteDocsToDisk implements env.DocsReadWriter. The docs of a revision database only live in its roots, so there is
+// nothing to write.
+func (rs *revisionRepoState) WriteDocsToDisk(docs doltdocs.Docs) error {
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestLoadBranchWorkingSet(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	ddb := dEnv.DoltDB
+	feature := ref.NewBranchRef("feature")
+
+	commit := func(root *doltdb.RootValue, query string) (*doltdb.Commit, *doltdb.RootValue) {
+		root, err := ExecuteSql(dEnv, root, query)
+		require.NoError(t, err)
+		h, err := ddb.WriteRootValue(ctx, root)
+		require.NoError(t, err)
+		meta, err := doltdb.NewCommitMeta("test", "test@example.com", query)
+		require.NoError(t, err)
+		cm, err := ddb.Commit(ctx, h, feature, meta)
+		require.NoError(t, err)
+		return cm, root
+	}
+	setWorkingSet := func(head *doltdb.Commit, headRoot *doltdb.RootValue, query string) hash.Hash {
+		root, err := ExecuteSql(dEnv, headRoot, query)
+		require.NoError(t, err)
+		h, err := ddb.WriteRootValue(ctx, root)
+		require.NoError(t, err)
+		require.NoError(t, ddb.SetBranchWorkingSet(ctx, feature, head, h, h))
+		return h
+	}
+
+	master, err := ddb.ResolveRef(ctx, ref.NewBranchRef("master"))
+	require.NoError(t, err)
+	require.NoError(t, ddb.NewBranchAtCommit(ctx, feature, master))
+	masterRoot, err := master.GetRootValue()
+	require.NoError(t, err)
+	head, headRoot := commit(masterRoot, "CREATE TABLE t (pk INT PRIMARY KEY, c INT);\nINSERT INTO t VALUES (1, 0), (2, 0);")
+
+	t.Run("unchanged head", func(t *testing.T) {
+		h := setWorkingSet(head, headRoot, "REPLACE INTO t VALUES (1, 1);")
+		working, staged, err := loadBranchWorkingSet(ctx, ddb, feature, head, headRoot)
+		require.NoError(t, err)
+		assert.Equal(t, h, working)
+		assert.Equal(t, h, staged)
+	})
+
+	t.Run("merged onto new head", func(t *testing.T) {
+		newHead, newHeadRoot := commit(headRoot, "REPLACE INTO t VALUES (2, 2);")
+		head, headRoot = newHead, newHeadRoot
+
+		working, _, err := loadBranchWorkingSet(ctx, ddb, feature, head, headRoot)
+		require.NoError(t, err)
+		root, err := ddb.ReadRootValue(ctx, working)
+		require.NoError(t, err)
+		rows, err := ExecuteSelect(dEnv, ddb, root, "SELECT pk, c FROM t ORDER BY pk")
+		require.NoError(t, err)
+		assert.Equal(t, []sql.Row{{int32(1), int32(1)}, {int32(2), int32(2)}}, rows)
+
+		// the merged working set is persisted against the new head
+		persisted, _, ok, err := ddb.GetBranchWorkingSet(ctx, feature)
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, working, persisted.Root)
+	})
+
+	t.Run("conflicting changes", func(t *testing.T) {
+		newHead, newHeadRoot := commit(headRoot, "REPLACE INTO t VALUES (1, 3);")
+		_, _, err := loadBranchWorkingSet(ctx, ddb, feature, newHead, newHeadRoot)
+		assert.True(t, ErrBranchWorkingSetConflict.Is(err))
+
+		// the uncommitted changes are kept
+		_, _, ok, err := ddb.GetBranchWorkingSet(ctx, feature)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
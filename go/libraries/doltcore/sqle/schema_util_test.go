@@ -15,13 +15,17 @@
 package sqle
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 
+	"github.com/dolthub/go-mysql-server/sql"
+
 	"github.com/dolthub/dolt/go/libraries/utils/set"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -121,6 +125,21 @@ func NewRowWithSchema(sch schema.Schema, vals ...types.Value) row.Row {
 	return r
 }
 
+// NewTextValue returns the value that |str| is stored as in a TEXT column, which is a blob.
+func NewTextValue(str string) types.Value {
+	ti, err := typeinfo.FromSqlType(sql.LongText)
+	if err != nil {
+		panic(err)
+	}
+
+	val, err := ti.ConvertValueToNomsValue(context.Background(), types.NewMemoryValueStore(), str)
+	if err != nil {
+		panic(err)
+	}
+
+	return val
+}
+
 // NewSchema creates a new schema with the pairs of column names and types given.
 // Uses the first column as the primary key.
 func NewSchema(colNamesAndTypes ...interface{}) schema.Schema {
@@ -0,0 +1,91 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+// SessionEventListener is notified of the commits and merges made by the sessions it's set on, so that servers can
+// report them.
+type SessionEventListener interface {
+	// Committed is called after the session creates a commit in the database |dbName|.
+	Committed(dbName string)
+	// Merged is called after the session merges a commit into the database |dbName|. |conflicts| is true if the merge
+	// left conflicts to resolve.
+	Merged(dbName string, conflicts bool)
+	// BranchUpdated is called after the session creates or moves a branch of the database |dbName| other than by
+	// committing or merging.
+	BranchUpdated(dbName string)
+	// TransactionConflicted is called when a transaction of the session is rolled back because its changes to the
+	// database |dbName| conflict with those of a concurrent transaction.
+	TransactionConflicted(dbName string)
+}
+
+// SessionEventListeners is a SessionEventListener that notifies each of a list of listeners.
+type SessionEventListeners []SessionEventListener
+
+var _ SessionEventListener = SessionEventListeners(nil)
+
+// Committed implements SessionEventListener.
+func (ls SessionEventListeners) Committed(dbName string) {
+	for _, l := range ls {
+		l.Committed(dbName)
+	}
+}
+
+// Merged implements SessionEventListener.
+func (ls SessionEventListeners) Merged(dbName string, conflicts bool) {
+	for _, l := range ls {
+		l.Merged(dbName, conflicts)
+	}
+}
+
+// BranchUpdated implements SessionEventListener.
+func (ls SessionEventListeners) BranchUpdated(dbName string) {
+	for _, l := range ls {
+		l.BranchUpdated(dbName)
+	}
+}
+
+// TransactionConflicted implements SessionEventListener.
+func (ls SessionEventListeners) TransactionConflicted(dbName string) {
+	for _, l := range ls {
+		l.TransactionConflicted(dbName)
+	}
+}
+
+// SetEventListener sets the SessionEventListener that is notified of the commits and merges made by this session.
+func (sess *DoltSession) SetEventListener(listener SessionEventListener) {
+	sess.events = listener
+}
+
+// NotifyCommitted notifies the session's SessionEventListener, if any, of a commit to the database |dbName|.
+func (sess *DoltSession) NotifyCommitted(dbName string) {
+	if sess.events != nil {
+		sess.events.Committed(dbName)
+	}
+}
+
+// NotifyMerged notifies the session's SessionEventListener, if any, of a merge into the database |dbName|.
+func (sess *DoltSession) NotifyMerged(dbName string, conflicts bool) {
+	if sess.events != nil {
+		sess.events.Merged(dbName, conflicts)
+	}
+}
+
+// NotifyBranchUpdated notifies the session's SessionEventListener, if any, of a branch created or moved in the
+// database |dbName|.
+func (sess *DoltSession) NotifyBranchUpdated(dbName string) {
+	if sess.events != nil {
+		sess.events.BranchUpdated(dbName)
+	}
+}
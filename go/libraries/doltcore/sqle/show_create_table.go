@@ -17,10 +17,15 @@ package sqle
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/auth"
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/vt/sqlparser"
 
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlfmt"
 	"github.com/dolthub/dolt/go/libraries/utils/tracing"
 )
 
@@ -55,5 +60,136 @@ func GetCreateTableStmt(ctx *sql.Context, engine *sqle.Engine, tableName string)
 	if !ok {
 		return "", fmt.Errorf("expected string statement from SHOW CREATE TABLE")
 	}
+
+	stmt, err = addTableChecks(ctx, engine, "", tableName, stmt)
+	if err != nil {
+		return "", err
+	}
+
 	return stmt + ";", nil
 }
+
+// ParseShowCreateTable returns the database and table named by |query| if it's a SHOW CREATE TABLE statement. The
+// database is empty if the table isn't qualified by one.
+func ParseShowCreateTable(query string) (db, table string, ok bool) {
+	toks := tokenizeCheckStatement(query)
+	if len(toks) < 4 || toks[0].typ != sqlparser.SHOW || toks[1].typ != sqlparser.CREATE || toks[2].typ != sqlparser.TABLE {
+		return "", "", false
+	}
+
+	db, table, next, ok := parseTableName(toks, 3)
+	if !ok || next != len(toks) {
+		return "", "", false
+	}
+	return db, table, true
+}
+
+// addTableChecks returns |stmt|, the statement that SHOW CREATE TABLE gives for the table |tableName| of the database
+// |dbName|, with the check constraints of the table added. Tables named without a database are in the current
+// database of |ctx|.
+func addTableChecks(ctx *sql.Context, engine *sqle.Engine, dbName, tableName, stmt string) (string, error) {
+	if dbName == "" {
+		dbName = ctx.GetCurrentDatabase()
+	}
+
+	tbl, err := engine.Catalog.Table(ctx, dbName, tableName)
+	if err != nil {
+		return "", err
+	}
+	return addCheckConstraints(stmt, tableChecks(tbl)), nil
+}
+
+// QueryWithChecks runs |query| with |engine| as engine.Query does, adding the support for check constraints that the
+// engine lacks. Statements that add or drop check constraints are run by CheckStatement, and the statements given by
+// SHOW CREATE TABLE include the check constraints of their tables. Every statement that adds, drops or shows check
+// constraints must be run by it, so that check constraints are refused by read-only engines like other DDL.
+func QueryWithChecks(ctx *sql.Context, engine *sqle.Engine, query string) (sql.Schema, sql.RowIter, error) {
+	checkStmt, err := ParseCheckStatement(query)
+	if err != nil {
+		return nil, nil, err
+	} else if checkStmt != nil {
+		if engine.Auth != nil {
+			err = engine.Auth.Allowed(ctx, auth.WritePerm)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		err = checkStmt.exec(ctx, engine)
+		if err != nil {
+			return nil, nil, err
+		}
+		return sql.OkResultSchema, sql.RowsToRowIter(sql.NewRow(sql.NewOkResult(0))), nil
+	}
+
+	sch, iter, err := engine.Query(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if db, table, ok := ParseShowCreateTable(query); ok {
+		iter = &showCreateTableIter{RowIter: iter, ctx: ctx, engine: engine, db: db, table: table}
+	}
+	return sch, iter, nil
+}
+
+// showCreateTableIter adds the check constraints of a table to the rows of SHOW CREATE TABLE.
+type showCreateTableIter struct {
+	sql.RowIter
+	ctx    *sql.Context
+	engine *sqle.Engine
+	db     string
+	table  string
+}
+
+// Next implements sql.RowIter.
+func (itr *showCreateTableIter) Next() (sql.Row, error) {
+	r, err := itr.RowIter.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r) != 2 {
+		return r, nil
+	}
+
+	if stmt, ok := r[1].(string); ok {
+		stmt, err = addTableChecks(itr.ctx, itr.engine, itr.db, itr.table, stmt)
+		if err != nil {
+			return nil, err
+		}
+		r = sql.NewRow(r[0], stmt)
+	}
+	return r, nil
+}
+
+// addCheckConstraints adds the check constraints given to the end of the table definition of a CREATE TABLE
+// statement, which SHOW CREATE TABLE doesn't include on its own.
+func addCheckConstraints(stmt string, checks []schema.Check) string {
+	end := strings.LastIndex(stmt, "\n)")
+	if len(checks) == 0 || end == -1 {
+		return stmt
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(stmt[:end])
+	for _, check := range checks {
+		sb.WriteString(",\n  ")
+		sb.WriteString(sqlfmt.FmtCheck(check))
+	}
+	sb.WriteString(stmt[end:])
+	return sb.String()
+}
+
+func tableChecks(tbl sql.Table) []schema.Check {
+	switch t := tbl.(type) {
+	case *DoltTable:
+		return t.sch.Checks().AllChecks()
+	case *WritableDoltTable:
+		return t.sch.Checks().AllChecks()
+	case *AlterableDoltTable:
+		return t.sch.Checks().AllChecks()
+	default:
+		return nil
+	}
+}
@@ -1173,7 +1173,7 @@ func TestAlterSystemTables(t *testing.T) {
 		NewRow(types.String("abc123"), types.Uint(1), types.String("example"), types.String("select 2+2 from dual"), types.String("description")))
 	dtestutils.CreateTestTable(t, dEnv, doltdb.SchemasTableName,
 		schemasTableDoltSchema(),
-		NewRowWithPks([]types.Value{types.String("view"), types.String("name")}, types.String("select 2+2 from dual")))
+		NewRowWithPks([]types.Value{NewTextValue("view"), NewTextValue("name")}, NewTextValue("select 2+2 from dual")))
 
 	// The _history and _diff tables give not found errors right now because of https://github.com/dolthub/dolt/issues/373.
 	// We can remove the divergent failure logic when the issue is fixed.
@@ -210,6 +210,8 @@ func valueAsSqlString(ti typeinfo.TypeInfo, value types.Value) (string, error) {
 			return "", fmt.Errorf("typeinfo.VarStringTypeIdentifier is not types.String")
 		}
 		return quoteAndEscapeString(string(s)), nil
+	case typeinfo.BlobStringTypeIdentifier:
+		return quoteAndEscapeString(*str), nil
 	default:
 		return *str, nil
 	}
@@ -125,6 +125,20 @@ func FmtForeignKey(fk doltdb.ForeignKey, sch, parentSch schema.Schema) string {
 	return sb.String()
 }
 
+// FmtCheck creates a string representing a check constraint within a sql create table statement.
+func FmtCheck(check schema.Check) string {
+	sb := strings.Builder{}
+	sb.WriteString("CONSTRAINT ")
+	sb.WriteString(QuoteIdentifier(check.Name()))
+	sb.WriteString(" CHECK (")
+	sb.WriteString(check.Expression())
+	sb.WriteRune(')')
+	if !check.Enforced() {
+		sb.WriteString(" /*!80016 NOT ENFORCED */")
+	}
+	return sb.String()
+}
+
 func DropTableStmt(tableName string) string {
 	var b strings.Builder
 	b.WriteString("DROP TABLE ")
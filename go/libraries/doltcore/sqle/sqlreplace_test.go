@@ -277,11 +277,11 @@ var systemTableReplaceTests = []ReplaceTest{
 		Name: "replace into dolt_schemas",
 		AdditionalSetup: CreateTableFn(doltdb.SchemasTableName,
 			schemasTableDoltSchema(),
-			NewRowWithPks([]types.Value{types.String("view"), types.String("name")}, types.String("select 2+2 from dual"))),
+			NewRowWithPks([]types.Value{NewTextValue("view"), NewTextValue("name")}, NewTextValue("select 2+2 from dual"))),
 		ReplaceQuery: "replace into dolt_schemas (type, name, fragment) values ('view', 'name', 'select 1+1 from dual')",
 		SelectQuery:  "select * from dolt_schemas",
 		ExpectedRows: ToSqlRows(schemasTableDoltSchema(),
-			NewRow(types.String("view"), types.String("name"), types.String("select 1+1 from dual")),
+			NewRow(NewTextValue("view"), NewTextValue("name"), NewTextValue("select 1+1 from dual")),
 		),
 		ExpectedSchema: CompressSchema(schemasTableDoltSchema()),
 	},
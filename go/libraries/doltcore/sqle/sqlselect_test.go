@@ -1497,14 +1497,14 @@ var systemTableSelectTests = []SelectTest{
 		AdditionalSetup: CreateTableFn(doltdb.SchemasTableName,
 			schemasTableDoltSchema(),
 			NewRowWithSchema(schemasTableDoltSchema(),
-				types.String("view"),
-				types.String("name"),
-				types.String("select 2+2 from dual"),
+				NewTextValue("view"),
+				NewTextValue("name"),
+				NewTextValue("select 2+2 from dual"),
 				types.Int(1),
 			)),
 		Query: "select * from dolt_schemas",
 		ExpectedRows: ToSqlRows(CompressSchema(schemasTableDoltSchema()),
-			NewRow(types.String("view"), types.String("name"), types.String("select 2+2 from dual"), types.Int(1)),
+			NewRow(NewTextValue("view"), NewTextValue("name"), NewTextValue("select 2+2 from dual"), types.Int(1)),
 		),
 		ExpectedSchema: CompressSchema(schemasTableDoltSchema()),
 	},
@@ -405,15 +405,15 @@ var systemTableUpdateTests = []UpdateTest{
 		AdditionalSetup: CreateTableFn(doltdb.SchemasTableName,
 			schemasTableDoltSchema(),
 			NewRowWithSchema(schemasTableDoltSchema(),
-				types.String("view"),
-				types.String("name"),
-				types.String("select 2+2 from dual"),
+				NewTextValue("view"),
+				NewTextValue("name"),
+				NewTextValue("select 2+2 from dual"),
 				types.Int(1),
 			)),
 		UpdateQuery: "update dolt_schemas set type = 'not a view'",
 		SelectQuery: "select * from dolt_schemas",
 		ExpectedRows: ToSqlRows(CompressSchema(schemasTableDoltSchema()),
-			NewRow(types.String("not a view"), types.String("name"), types.String("select 2+2 from dual"), types.Int(1)),
+			NewRow(NewTextValue("not a view"), NewTextValue("name"), NewTextValue("select 2+2 from dual"), types.Int(1)),
 		),
 		ExpectedSchema: CompressSchema(schemasTableDoltSchema()),
 	},
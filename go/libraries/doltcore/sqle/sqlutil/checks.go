@@ -0,0 +1,134 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlutil
+
+import (
+	"context"
+	"fmt"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/sql"
+	"gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+)
+
+var ErrCheckConstraintViolated = errors.NewKind("Check constraint %q is violated.")
+var ErrInvalidCheckConstraint = errors.NewKind("Check constraint %q is invalid: %s")
+
+// CheckEvaluator evaluates the enforced check constraints of a table against its rows.
+type CheckEvaluator struct {
+	sch    schema.Schema
+	checks []schema.Check
+	exprs  []sql.Expression
+}
+
+// NewCheckEvaluator returns a CheckEvaluator for the enforced check constraints of the schema given. An error is
+// returned if any of the check expressions, enforced or not, can't be resolved against the columns of the table.
+func NewCheckEvaluator(tableName string, sch schema.Schema) (*CheckEvaluator, error) {
+	ce := &CheckEvaluator{sch: sch}
+	if sch.Checks().Count() == 0 {
+		return ce, nil
+	}
+
+	sqlSch, err := FromDoltSchema(tableName, sch)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check expressions are resolved the same way as column defaults, by appending a column to the table whose
+	// default is the expression. Each expression is resolved on its own so that an invalid one can be named.
+	for _, chk := range sch.Checks().AllChecks() {
+		cols := make([]*sqle.ColumnWithRawDefault, 0, len(sqlSch)+1)
+		for _, col := range sqlSch {
+			c := *col
+			c.Default = nil
+			cols = append(cols, &sqle.ColumnWithRawDefault{SqlColumn: &c})
+		}
+		cols = append(cols, &sqle.ColumnWithRawDefault{
+			SqlColumn: &sql.Column{
+				Name:     "__check_" + chk.Name(),
+				Type:     sql.Boolean,
+				Nullable: true,
+				Source:   tableName,
+			},
+			Default: fmt.Sprintf("(%s)", chk.Expression()),
+		})
+
+		resolved, err := sqle.ResolveDefaults(tableName, cols)
+		if err != nil {
+			return nil, ErrInvalidCheckConstraint.New(chk.Name(), err.Error())
+		}
+
+		if chk.Enforced() {
+			ce.checks = append(ce.checks, chk)
+			ce.exprs = append(ce.exprs, resolved[len(resolved)-1].Default.Expression)
+		}
+	}
+
+	return ce, nil
+}
+
+// ValidateSqlRow returns an error if the sql.Row given violates any of the enforced check constraints. A check whose
+// expression evaluates to NULL is satisfied.
+func (ce *CheckEvaluator) ValidateSqlRow(ctx *sql.Context, r sql.Row) error {
+	if len(ce.exprs) == 0 {
+		return nil
+	}
+
+	// the resolved expressions index into a row that ends with the synthetic check column
+	r = append(r[:len(r):len(r)], nil)
+	for i, expr := range ce.exprs {
+		res, err := expr.Eval(ctx, r)
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			continue
+		}
+		ok, err := sql.ConvertToBool(res)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrCheckConstraintViolated.New(ce.checks[i].Name())
+		}
+	}
+
+	return nil
+}
+
+// ValidateRow returns an error if the row given violates any of the enforced check constraints.
+func (ce *CheckEvaluator) ValidateRow(ctx *sql.Context, r row.Row) error {
+	if len(ce.exprs) == 0 {
+		return nil
+	}
+
+	sqlRow, err := DoltRowToSqlRow(r, ce.sch)
+	if err != nil {
+		return err
+	}
+	return ce.ValidateSqlRow(ctx, sqlRow)
+}
+
+// ViolatesChecks returns whether the row given violates any of the enforced check constraints.
+func (ce *CheckEvaluator) ViolatesChecks(ctx context.Context, r row.Row) (bool, error) {
+	err := ce.ValidateRow(sql.NewContext(ctx), r)
+	if ErrCheckConstraintViolated.Is(err) {
+		return true, nil
+	}
+	return false, err
+}
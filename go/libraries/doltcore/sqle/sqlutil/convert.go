@@ -86,7 +86,7 @@ func ToDoltSchema(ctx context.Context, root *doltdb.RootValue, tableName string,
 	var kinds []types.NomsKind
 	for _, col := range sqlSchema {
 		names = append(names, col.Name)
-		ti, err := typeinfo.FromSqlType(col.Type)
+		ti, err := typeInfoForColumn(col)
 		if err != nil {
 			return nil, err
 		}
@@ -125,7 +125,7 @@ func ToDoltCol(tag uint64, col *sql.Column) (schema.Column, error) {
 	if !col.Nullable {
 		constraints = append(constraints, schema.NotNullConstraint{})
 	}
-	typeInfo, err := typeinfo.FromSqlType(col.Type)
+	typeInfo, err := typeInfoForColumn(col)
 	if err != nil {
 		return schema.Column{}, err
 	}
@@ -133,6 +133,13 @@ func ToDoltCol(tag uint64, col *sql.Column) (schema.Column, error) {
 	return schema.NewColumnWithTypeInfo(col.Name, tag, typeInfo, col.PrimaryKey, col.Default.String(), col.AutoIncrement, col.Comment, constraints...)
 }
 
+func typeInfoForColumn(col *sql.Column) (typeinfo.TypeInfo, error) {
+	if col.PrimaryKey {
+		return typeinfo.FromSqlKeyType(col.Type)
+	}
+	return typeinfo.FromSqlType(col.Type)
+}
+
 func GetColNamesFromSqlSchema(sqlSch sql.Schema) []string {
 	colNames := make([]string, len(sqlSch))
 
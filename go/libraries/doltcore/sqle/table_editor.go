@@ -38,6 +38,7 @@ type sqlTableEditor struct {
 	t           *WritableDoltTable
 	tableEditor editor.TableEditor
 	sess        *editor.TableEditSession
+	checks      *sqlutil.CheckEvaluator
 }
 
 var _ sql.RowReplacer = (*sqlTableEditor)(nil)
@@ -52,14 +53,24 @@ func newSqlTableEditor(ctx *sql.Context, t *WritableDoltTable) (*sqlTableEditor,
 		return nil, err
 	}
 
+	checks, err := sqlutil.NewCheckEvaluator(t.name, t.sch)
+	if err != nil {
+		return nil, err
+	}
+
 	return &sqlTableEditor{
 		t:           t,
 		tableEditor: tableEditor,
 		sess:        sess,
+		checks:      checks,
 	}, nil
 }
 
 func (te *sqlTableEditor) Insert(ctx *sql.Context, sqlRow sql.Row) error {
+	if err := te.checks.ValidateSqlRow(ctx, sqlRow); err != nil {
+		return err
+	}
+
 	if !schema.IsKeyless(te.t.sch) {
 		k, v, tagToVal, err := sqlutil.DoltKeyValueAndMappingFromSqlRow(ctx, te.t.table.ValueReadWriter(), sqlRow, te.t.sch)
 
@@ -89,6 +100,10 @@ func (te *sqlTableEditor) Delete(ctx *sql.Context, sqlRow sql.Row) error {
 }
 
 func (te *sqlTableEditor) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.Row) error {
+	if err := te.checks.ValidateSqlRow(ctx, newRow); err != nil {
+		return err
+	}
+
 	dOldRow, err := sqlutil.SqlRowToDoltRow(ctx, te.t.table.ValueReadWriter(), oldRow, te.t.sch)
 	if err != nil {
 		return err
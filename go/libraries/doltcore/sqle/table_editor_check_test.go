@@ -0,0 +1,130 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/sqlutil"
+)
+
+// addChecks adds check constraints, given as alternating names and expressions, to a table of the root given.
+func addChecks(t *testing.T, root *doltdb.RootValue, tableName string, enforced bool, namesAndExprs ...string) *doltdb.RootValue {
+	ctx := context.Background()
+	tbl, ok, err := root.GetTable(ctx, tableName)
+	require.NoError(t, err)
+	require.True(t, ok)
+	sch, err := tbl.GetSchema(ctx)
+	require.NoError(t, err)
+	for i := 0; i < len(namesAndExprs); i += 2 {
+		_, err = sch.Checks().AddCheck(namesAndExprs[i], namesAndExprs[i+1], enforced)
+		require.NoError(t, err)
+	}
+	tbl, err = tbl.UpdateSchema(ctx, sch)
+	require.NoError(t, err)
+	root, err = root.PutTable(ctx, tableName, tbl)
+	require.NoError(t, err)
+	return root
+}
+
+func TestTableEditorChecks(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+	root, err = ExecuteSql(dEnv, root, `
+CREATE TABLE items (
+  pk BIGINT PRIMARY KEY,
+  price BIGINT,
+  qty BIGINT
+);
+INSERT INTO items VALUES (1, 10, 1);
+`)
+	require.NoError(t, err)
+	root = addChecks(t, root, "items", true, "chk_price", "price > 0", "chk_total", "price * qty < 1000")
+	root = addChecks(t, root, "items", false, "chk_qty", "qty < 5")
+
+	tests := []struct {
+		name        string
+		statement   string
+		expectedErr string
+	}{
+		{"insert satisfying checks", "INSERT INTO items VALUES (2, 20, 10)", ""},
+		{"insert violating check", "INSERT INTO items VALUES (2, -1, 1)", "chk_price"},
+		{"insert violating second check", "INSERT INTO items VALUES (2, 500, 2)", "chk_total"},
+		{"insert with null", "INSERT INTO items VALUES (2, NULL, 1)", ""},
+		{"update violating check", "UPDATE items SET price = 0 WHERE pk = 1", "chk_price"},
+		{"update satisfying checks", "UPDATE items SET qty = 99 WHERE pk = 1", ""},
+		{"replace violating check", "REPLACE INTO items VALUES (1, -10, 1)", "chk_price"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := executeModify(context.Background(), dEnv, root, test.statement)
+			if test.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.True(t, sqlutil.ErrCheckConstraintViolated.Is(err))
+				assert.Contains(t, err.Error(), test.expectedErr)
+			}
+		})
+	}
+}
+
+func TestAlterTableChecks(t *testing.T) {
+	dEnv := dtestutils.CreateTestEnv()
+	root, err := dEnv.WorkingRoot(context.Background())
+	require.NoError(t, err)
+	root, err = ExecuteSql(dEnv, root, "CREATE TABLE items (pk BIGINT PRIMARY KEY, price BIGINT, qty BIGINT);")
+	require.NoError(t, err)
+	root = addChecks(t, root, "items", true, "chk_price", "price > 0")
+
+	_, err = ExecuteSql(dEnv, root, "ALTER TABLE items DROP COLUMN price;")
+	assert.True(t, sqlutil.ErrInvalidCheckConstraint.Is(err))
+	_, err = ExecuteSql(dEnv, root, "ALTER TABLE items RENAME COLUMN price TO cost;")
+	assert.True(t, sqlutil.ErrInvalidCheckConstraint.Is(err))
+
+	newRoot, err := ExecuteSql(dEnv, root, "ALTER TABLE items DROP COLUMN qty;")
+	require.NoError(t, err)
+	tbl, _, err := newRoot.GetTable(context.Background(), "items")
+	require.NoError(t, err)
+	sch, err := tbl.GetSchema(context.Background())
+	require.NoError(t, err)
+	_, ok := sch.Checks().GetByNameCaseInsensitive("chk_price")
+	assert.True(t, ok)
+}
+
+func TestAddCheckConstraints(t *testing.T) {
+	checks := schema.NewCheckCollection()
+	_, err := checks.AddCheck("chk_price", "price > 0", true)
+	require.NoError(t, err)
+	_, err = checks.AddCheck("chk_qty", "qty < 5", false)
+	require.NoError(t, err)
+
+	stmt := "CREATE TABLE `items` (\n  `pk` bigint NOT NULL,\n  `price` bigint,\n  PRIMARY KEY (`pk`)\n) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	expected := "CREATE TABLE `items` (\n  `pk` bigint NOT NULL,\n  `price` bigint,\n  PRIMARY KEY (`pk`),\n" +
+		"  CONSTRAINT `chk_price` CHECK (price > 0),\n" +
+		"  CONSTRAINT `chk_qty` CHECK (qty < 5) /*!80016 NOT ENFORCED */\n" +
+		") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+	assert.Equal(t, expected, addCheckConstraints(stmt, checks.AllChecks()))
+	assert.Equal(t, stmt, addCheckConstraints(stmt, nil))
+}
@@ -27,8 +27,10 @@ import (
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/opentracing/opentracing-go"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/alterschema"
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema/encoding"
@@ -291,18 +293,25 @@ func (t *DoltTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sq
 }
 
 func partitionRows(ctx *sql.Context, t *DoltTable, projCols []string, partition sql.Partition) (sql.RowIter, error) {
+	var iter sql.RowIter
+	var err error
 	switch typedPartition := partition.(type) {
 	case doltTablePartition:
 		if typedPartition.end == 0 {
 			return emptyRowIterator{}, nil
 		}
 
-		return newRowIterator(ctx, t, projCols, &typedPartition)
+		iter, err = newRowIterator(ctx, t, projCols, &typedPartition)
 	case sqlutil.SinglePartition:
-		return newRowIterator(ctx, t, projCols, &doltTablePartition{rowData: typedPartition.RowData, end: NoUpperBound})
+		iter, err = newRowIterator(ctx, t, projCols, &doltTablePartition{rowData: typedPartition.RowData, end: NoUpperBound})
+	default:
+		return nil, errors.New("unsupported partition type")
 	}
 
-	return nil, errors.New("unsupported partition type")
+	if err != nil {
+		return nil, err
+	}
+	return newTableReadIter(ctx, iter, "dolt.table_read", opentracing.Tags{"table": t.Name()}), nil
 }
 
 // WritableDoltTable allows updating, deleting, and inserting new rows. It implements sql.UpdatableTable and friends.
@@ -708,6 +717,10 @@ func (t *AlterableDoltTable) DropColumn(ctx *sql.Context, columnName string) err
 		return err
 	}
 
+	if err = validateTableChecks(ctx, t.name, updatedTable); err != nil {
+		return err
+	}
+
 	newRoot, err := root.PutTable(ctx, t.name, updatedTable)
 	if err != nil {
 		return err
@@ -716,6 +729,17 @@ func (t *AlterableDoltTable) DropColumn(ctx *sql.Context, columnName string) err
 	return t.db.SetRoot(ctx, newRoot)
 }
 
+// validateTableChecks returns an error if the check constraints of a table no longer resolve against its columns, as
+// happens when a column that a check references is dropped or renamed.
+func validateTableChecks(ctx context.Context, tableName string, tbl *doltdb.Table) error {
+	sch, err := tbl.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = sqlutil.NewCheckEvaluator(tableName, sch)
+	return err
+}
+
 // ModifyColumn implements sql.AlterableTable
 func (t *AlterableDoltTable) ModifyColumn(ctx *sql.Context, columnName string, column *sql.Column, order *sql.ColumnOrder) error {
 	root, err := t.db.GetRoot(ctx)
@@ -744,6 +768,13 @@ func (t *AlterableDoltTable) ModifyColumn(ctx *sql.Context, columnName string, c
 		return err
 	}
 
+	// A column that keeps its SQL type also keeps its storage, so that TEXT columns that store their values inline
+	// aren't rewritten by statements that only rename or reorder them
+	if existingCol.TypeInfo.ToSqlType().String() == column.Type.String() {
+		col.TypeInfo = existingCol.TypeInfo
+		col.Kind = existingCol.Kind
+	}
+
 	fkCollection, err := root.GetForeignKeyCollection(ctx)
 	if err != nil {
 		return err
@@ -788,6 +819,10 @@ func (t *AlterableDoltTable) ModifyColumn(ctx *sql.Context, columnName string, c
 		return err
 	}
 
+	if err = validateTableChecks(ctx, t.name, updatedTable); err != nil {
+		return err
+	}
+
 	newRoot, err := root.PutTable(ctx, t.name, updatedTable)
 	if err != nil {
 		return err
@@ -1107,6 +1142,115 @@ func (t *AlterableDoltTable) DropForeignKey(ctx *sql.Context, fkName string) err
 	return t.updateFromRoot(ctx, newRoot)
 }
 
+// CreateCheck adds a check constraint to the table. A name of the form <table>_chk_<n> is generated if |name| is
+// empty. An enforced check must be satisfied by the rows already in the table.
+func (t *AlterableDoltTable) CreateCheck(ctx *sql.Context, name, expression string, enforced bool) error {
+	root, err := t.db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	table, ok, err := root.GetTable(ctx, t.name)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(t.name)
+	}
+
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = generateCheckName(t.name, sch)
+	}
+	_, err = sch.Checks().AddCheck(name, expression, enforced)
+	if err != nil {
+		return err
+	}
+
+	checks, err := sqlutil.NewCheckEvaluator(t.name, sch)
+	if err != nil {
+		return err
+	}
+
+	rowData, err := table.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = rowData.Iter(ctx, func(key, value types.Value) (stop bool, err error) {
+		r, err := row.FromNoms(sch, key.(types.Tuple), value.(types.Tuple))
+		if err != nil {
+			return true, err
+		}
+		return false, checks.ValidateRow(ctx, r)
+	})
+	if err != nil {
+		return err
+	}
+
+	return t.updateSchema(ctx, root, table, sch)
+}
+
+// DropCheck removes a check constraint from the table.
+func (t *AlterableDoltTable) DropCheck(ctx *sql.Context, name string) error {
+	root, err := t.db.GetRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	table, ok, err := root.GetTable(ctx, t.name)
+	if err != nil {
+		return err
+	} else if !ok {
+		return sql.ErrTableNotFound.New(t.name)
+	}
+
+	sch, err := table.GetSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = sch.Checks().DropCheck(name)
+	if err != nil {
+		return err
+	}
+
+	return t.updateSchema(ctx, root, table, sch)
+}
+
+// updateSchema writes |table| with the schema |sch| to |root|, and sets it as the root of the table's database.
+func (t *AlterableDoltTable) updateSchema(ctx *sql.Context, root *doltdb.RootValue, table *doltdb.Table, sch schema.Schema) error {
+	table, err := table.UpdateSchema(ctx, sch)
+	if err != nil {
+		return err
+	}
+
+	newRoot, err := root.PutTable(ctx, t.name, table)
+	if err != nil {
+		return err
+	}
+
+	err = t.db.SetRoot(ctx, newRoot)
+	if err != nil {
+		return err
+	}
+	return t.updateFromRoot(ctx, newRoot)
+}
+
+// generateCheckName returns the first name of the form <table>_chk_<n> that isn't used by a check of |sch|, which is
+// how MySQL names checks that are declared without a name.
+func generateCheckName(tableName string, sch schema.Schema) string {
+	for i := 1; ; i++ {
+		name := fmt.Sprintf("%s_chk_%d", tableName, i)
+		if _, ok := sch.Checks().GetByNameCaseInsensitive(name); !ok {
+			return name
+		}
+	}
+}
+
 func toForeignKeyConstraint(fk doltdb.ForeignKey, childSch, parentSch schema.Schema) (cst sql.ForeignKeyConstraint, err error) {
 	cst = sql.ForeignKeyConstraint{
 		Name:              fk.Name,
@@ -299,7 +299,7 @@ func GetIndexedRows(ctx context.Context, te TableEditor, key types.Tuple, indexN
 			return nil, err
 		}
 		if fieldsVal == nil {
-			keyStr, err := formatKey(ctx, key)
+			keyStr, err := FormatKey(ctx, key)
 			if err != nil {
 				return nil, err
 			}
@@ -341,7 +341,7 @@ func (te *pkTableEditor) InsertKeyVal(ctx context.Context, key, val types.Tuple,
 	if pkExists, err := te.tea.Has(ctx, keyHash, key); err != nil {
 		return err
 	} else if pkExists {
-		keyStr, err := formatKey(ctx, key)
+		keyStr, err := FormatKey(ctx, key)
 		if err != nil {
 			return err
 		}
@@ -403,7 +403,7 @@ func (te *pkTableEditor) InsertRow(ctx context.Context, dRow row.Row) error {
 	if pkExists, err := te.tea.Has(ctx, keyHash, key); err != nil {
 		return err
 	} else if pkExists {
-		keyStr, err := formatKey(ctx, key)
+		keyStr, err := FormatKey(ctx, key)
 		if err != nil {
 			return err
 		}
@@ -493,7 +493,7 @@ func (te *pkTableEditor) UpdateRow(ctx context.Context, dOldRow row.Row, dNewRow
 		if pkExists, err := te.tea.Has(ctx, newHash, dNewKeyVal); err != nil {
 			return err
 		} else if pkExists {
-			keyStr, err := formatKey(ctx, dNewKeyVal)
+			keyStr, err := FormatKey(ctx, dNewKeyVal)
 			if err != nil {
 				return err
 			}
@@ -648,8 +648,8 @@ func (te *pkTableEditor) flushEditAccumulator(ctx context.Context, teaInterface
 	return nil
 }
 
-// formatKey returns a comma-separated string representation of the key given.
-func formatKey(ctx context.Context, key types.Value) (string, error) {
+// FormatKey returns a comma-separated string representation of the key given.
+func FormatKey(ctx context.Context, key types.Value) (string, error) {
 	tuple, ok := key.(types.Tuple)
 	if !ok {
 		return "", fmt.Errorf("Expected types.Tuple but got %T", key)
@@ -19,11 +19,12 @@ import (
 )
 
 // TransformRowFailure is an error implementation that stores the row that failed to transform, the transform that
-// failed and some details of the error
+// failed and some details of the error, along with the properties of the row
 type TransformRowFailure struct {
 	Row           row.Row
 	TransformName string
 	Details       string
+	Props         ReadableMap
 }
 
 // Error returns a string containing details of the error that occurred
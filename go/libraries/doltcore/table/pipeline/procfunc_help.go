@@ -52,7 +52,7 @@ func ProcFuncForSourceFunc(sourceFunc SourceFunc) InFunc {
 						return
 					}
 				} else if table.IsBadRow(err) {
-					badRowChan <- &TransformRowFailure{table.GetBadRowRow(err), "reader", err.Error()}
+					badRowChan <- &TransformRowFailure{table.GetBadRowRow(err), "reader", err.Error(), props}
 				} else {
 					p.StopWithErr(err)
 					return
@@ -74,11 +74,40 @@ func ProcFuncForSourceFunc(sourceFunc SourceFunc) InFunc {
 
 // ProcFuncForReader adapts a standard TableReader to work as an InFunc for a pipeline
 func ProcFuncForReader(ctx context.Context, rd table.TableReader) InFunc {
-	return ProcFuncForSourceFunc(func() (row.Row, ImmutableProperties, error) {
+	return ProcFuncForSourceFunc(SourceFuncForReader(ctx, rd))
+}
+
+// SourceFuncForReader returns a SourceFunc which reads the rows of a TableReader
+func SourceFuncForReader(ctx context.Context, rd table.TableReader) SourceFunc {
+	return func() (row.Row, ImmutableProperties, error) {
 		r, err := rd.ReadRow(ctx)
 
 		return r, NoProps, err
-	})
+	}
+}
+
+// ProcFuncForSourceRows adapts a standard TableReader to work as an InFunc for a pipeline, like ProcFuncForReader. Each
+// row is given the SourceRowProp and LineNumProp properties, so that the rows which fail to be processed can be traced
+// back to the input.
+func ProcFuncForSourceRows(ctx context.Context, rd table.TableReader) InFunc {
+	return ProcFuncForSourceFunc(SourceFuncForSourceRows(ctx, rd))
+}
+
+// SourceFuncForSourceRows returns a SourceFunc which reads the rows of a TableReader, and gives each row the
+// SourceRowProp and LineNumProp properties.
+func SourceFuncForSourceRows(ctx context.Context, rd table.TableReader) SourceFunc {
+	var rowNum int
+	return func() (row.Row, ImmutableProperties, error) {
+		r, err := rd.ReadRow(ctx)
+
+		rowNum++
+		lineNum := rowNum
+		if lnr, ok := rd.(table.LineNumberReader); ok {
+			lineNum = lnr.LineNumber()
+		}
+
+		return r, NoProps.Set(map[string]interface{}{SourceRowProp: r, LineNumProp: lineNum}), err
+	}
 }
 
 // SinkFunc is a function that will process the final transformed rows from a pipeline.  This function will be called
@@ -101,7 +130,7 @@ func ProcFuncForSinkFunc(sinkFunc SinkFunc) OutFunc {
 
 					if err != nil {
 						if table.IsBadRow(err) || sql.ErrPrimaryKeyViolation.Is(err) {
-							badRowChan <- &TransformRowFailure{r.Row, "writer", err.Error()}
+							badRowChan <- &TransformRowFailure{r.Row, "writer", err.Error(), r.Props}
 						} else {
 							p.StopWithErr(err)
 							return
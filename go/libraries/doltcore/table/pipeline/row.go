@@ -26,6 +26,13 @@ type ReadableMap interface {
 	Get(propName string) (interface{}, bool)
 }
 
+const (
+	// SourceRowProp is the property holding a row as it was read from the input, before it was transformed
+	SourceRowProp = "source_row"
+	// LineNumProp is the property holding the line of the input that a row was read from
+	LineNumProp = "line_num"
+)
+
 // NoProps is an empty ImmutableProperties struct
 var NoProps = ImmutableProperties{}
 
@@ -90,10 +97,12 @@ func GetRowConvTransformFunc(rc *rowconv.RowConverter) func(row.Row, ReadableMap
 			if isv, err := row.IsValid(outRow, rc.DestSch); err != nil {
 				return nil, err.Error()
 			} else if !isv {
-				col, err := row.GetInvalidCol(outRow, rc.DestSch)
+				col, cnst, err := row.GetInvalidConstraint(outRow, rc.DestSch)
 
 				if err != nil {
 					return nil, "invalid column"
+				} else if cnst != nil {
+					return nil, "invalid column: " + col.Name + " (" + cnst.String() + " constraint)"
 				} else {
 					return nil, "invalid column: " + col.Name
 				}
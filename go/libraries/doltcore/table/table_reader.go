@@ -49,6 +49,14 @@ type TableReadCloser interface {
 	TableCloser
 }
 
+// LineNumberReader is a TableReader of a text format, which knows the line of its input that a row was read from.
+type LineNumberReader interface {
+	TableReader
+
+	// LineNumber returns the line of the input that the last row returned by ReadRow started on, counting from 1
+	LineNumber() int
+}
+
 // SqlTableReader is a  TableReader that can read rows as sql.Row.
 type SqlTableReader interface {
 	// GetSchema gets the schema of the rows that this reader will return
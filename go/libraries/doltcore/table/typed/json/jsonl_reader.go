@@ -0,0 +1,213 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// JSONLReader is a TableReadCloser for newline delimited JSON, where each line is a JSON object holding a row.
+// Unlike JSONReader, rows are read one line at a time, so files of any size can be read without holding them in
+// memory. Lines that can't be read are returned as bad rows which give their line number.
+type JSONLReader struct {
+	vrw    types.ValueReadWriter
+	closer io.Closer
+	rd     *bufio.Reader
+	sch    schema.Schema
+	line   int
+}
+
+// OpenJSONLReader opens the newline delimited JSON file at |path| in |fs| for reading rows of |sch|.
+func OpenJSONLReader(vrw types.ValueReadWriter, path string, fs filesys.ReadableFS, sch schema.Schema) (*JSONLReader, error) {
+	r, err := fs.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJSONLReader(vrw, r, sch)
+}
+
+// NewJSONLReader returns a JSONLReader that reads rows of |sch| from |r|.
+func NewJSONLReader(vrw types.ValueReadWriter, r io.ReadCloser, sch schema.Schema) (*JSONLReader, error) {
+	if sch == nil {
+		return nil, errors.New("schema must be provided to JSONLReader")
+	}
+
+	return &JSONLReader{vrw: vrw, closer: r, rd: bufio.NewReaderSize(r, ReadBufSize), sch: sch}, nil
+}
+
+// Close should release resources being held
+func (r *JSONLReader) Close(ctx context.Context) error {
+	if r.closer != nil {
+		err := r.closer.Close()
+		r.closer = nil
+
+		return err
+	}
+	return errors.New("already closed")
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (r *JSONLReader) GetSchema() schema.Schema {
+	return r.sch
+}
+
+// ReadRow reads a row from a table. If there is a bad row the returned error will be non nil, and calling
+// IsBadRow(err) will be return true. This is a potentially non-fatal error and callers can decide if they want to
+// continue on a bad row, or fail.
+func (r *JSONLReader) ReadRow(ctx context.Context) (row.Row, error) {
+	for {
+		line, err := r.rd.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if len(line) == 0 && err == io.EOF {
+			return nil, io.EOF
+		}
+		r.line++
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		rowMap, decodeErr := decodeJSONLine(line)
+		if decodeErr != nil {
+			return nil, r.badLine(decodeErr)
+		}
+
+		rw, convErr := r.convToRow(ctx, rowMap)
+		if convErr != nil {
+			return nil, r.badLine(convErr)
+		}
+
+		return rw, nil
+	}
+}
+
+// LineNumber returns the line of the file that the last row read was on, counting from 1
+func (r *JSONLReader) LineNumber() int {
+	return r.line
+}
+
+func (r *JSONLReader) badLine(err error) error {
+	return table.NewBadRow(nil, fmt.Sprintf("line %d: %v", r.line, err))
+}
+
+// decodeJSONLine decodes a line holding a single JSON object. Numbers are kept as json.Number so that they can be
+// parsed according to the type of their column.
+func decodeJSONLine(line []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid json: %v", err)
+	}
+	if dec.More() {
+		return nil, errors.New("invalid json: expected a single object on the line")
+	}
+
+	rowMap, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a json object, found: %s", string(line))
+	}
+	return rowMap, nil
+}
+
+func (r *JSONLReader) convToRow(ctx context.Context, rowMap map[string]interface{}) (row.Row, error) {
+	allCols := r.sch.GetAllCols()
+
+	taggedVals := make(row.TaggedValues, allCols.Size())
+	for k, v := range rowMap {
+		col, ok := allCols.GetByName(k)
+		if !ok {
+			return nil, fmt.Errorf("column %s not found in schema", k)
+		}
+
+		val, err := jsonValueToNomsValue(ctx, r.vrw, col, v)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", k, err)
+		}
+		if !types.IsNull(val) {
+			taggedVals[col.Tag] = val
+		}
+	}
+
+	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		if val, ok := taggedVals.Get(tag); !col.IsNullable() && (!ok || types.IsNull(val)) {
+			return true, fmt.Errorf("column `%s` does not allow null values", col.Name)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return row.New(r.vrw.Format(), r.sch, taggedVals)
+}
+
+// jsonValueToNomsValue converts the decoded JSON value |v| to a value of |col|. Nested objects and arrays are only
+// accepted by string and JSON columns, which store them as JSON text. Every value of a JSON column is a document, so
+// a string is stored as a JSON string rather than being parsed.
+func jsonValueToNomsValue(ctx context.Context, vrw types.ValueReadWriter, col schema.Column, v interface{}) (types.Value, error) {
+	if v == nil {
+		return types.NullValue, nil
+	}
+
+	if col.TypeInfo.GetTypeIdentifier() == typeinfo.JSONTypeIdentifier {
+		return parseMarshalled(ctx, vrw, col, v)
+	}
+
+	switch v := v.(type) {
+	case string:
+		return col.TypeInfo.ParseValue(ctx, vrw, &v)
+	case json.Number:
+		str := v.String()
+		return col.TypeInfo.ParseValue(ctx, vrw, &str)
+	case bool:
+		return col.TypeInfo.ConvertValueToNomsValue(ctx, vrw, v)
+	case map[string]interface{}, []interface{}:
+		switch col.TypeInfo.GetTypeIdentifier() {
+		case typeinfo.VarStringTypeIdentifier, typeinfo.BlobStringTypeIdentifier:
+			return parseMarshalled(ctx, vrw, col, v)
+		}
+		return nil, fmt.Errorf("nested values can only be imported into string or json columns")
+	}
+
+	return nil, fmt.Errorf("unexpected json value %v", v)
+}
+
+func parseMarshalled(ctx context.Context, vrw types.ValueReadWriter, col schema.Column, v interface{}) (types.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	str := string(data)
+	return col.TypeInfo.ParseValue(ctx, vrw, &str)
+}
@@ -0,0 +1,143 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func jsonlTestSchema(t *testing.T) schema.Schema {
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(
+		schema.Column{Name: "id", Tag: 0, Kind: types.IntKind, IsPartOfPK: true, TypeInfo: typeinfo.Int64Type,
+			Constraints: []schema.ColConstraint{schema.NotNullConstraint{}}},
+		schema.Column{Name: "name", Tag: 1, Kind: types.StringKind, TypeInfo: typeinfo.StringDefaultType},
+		schema.Column{Name: "attrs", Tag: 2, Kind: typeinfo.JSONType.NomsKind(), TypeInfo: typeinfo.JSONType},
+	))
+	require.NoError(t, err)
+	return sch
+}
+
+func TestJSONLReader(t *testing.T) {
+	ctx := context.Background()
+	testJSONL := `{"id": 0, "name": "tim", "attrs": {"langs": ["go", "sql"]}}
+
+{"id": 1, "name": {"first": "brian"}}
+{"id": 2, "name": "aaron" bad
+{"id": 3, "unknown": 1}
+{"name": "no id"}
+{"id": 4, "name": "brian", "attrs": "str"}
+`
+
+	fs := filesys.EmptyInMemFS("/")
+	require.NoError(t, fs.WriteFile("file.jsonl", []byte(testJSONL)))
+
+	sch := jsonlTestSchema(t)
+	vrw := types.NewMemoryValueStore()
+	reader, err := OpenJSONLReader(vrw, "file.jsonl", fs, sch)
+	require.NoError(t, err)
+	defer reader.Close(ctx)
+
+	var names []string
+	var attrs []string
+	var badLines []string
+	for {
+		r, err := reader.ReadRow(ctx)
+		if err == io.EOF {
+			break
+		} else if table.IsBadRow(err) {
+			badLines = append(badLines, err.Error())
+			continue
+		}
+		require.NoError(t, err)
+
+		name, _ := r.GetColVal(1)
+		names = append(names, string(name.(types.String)))
+
+		attr, _ := r.GetColVal(2)
+		str, err := typeinfo.JSONType.FormatValue(attr)
+		require.NoError(t, err)
+		if str != nil {
+			attrs = append(attrs, *str)
+		} else {
+			attrs = append(attrs, "")
+		}
+	}
+
+	assert.Equal(t, []string{"tim", `{"first":"brian"}`, "brian"}, names)
+	assert.Equal(t, []string{`{"langs":["go","sql"]}`, "", `"str"`}, attrs)
+	require.Len(t, badLines, 3)
+	assert.Contains(t, badLines[0], "line 4:")
+	assert.Contains(t, badLines[1], "line 5:")
+	assert.Contains(t, badLines[2], "line 6:")
+}
+
+func TestJSONLRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	sch := jsonlTestSchema(t)
+	vrw := types.NewMemoryValueStore()
+
+	attrs, err := typeinfo.JSONType.ParseValue(ctx, vrw, strPtr(`{"a": [1, 2]}`))
+	require.NoError(t, err)
+
+	var rows []row.Row
+	for _, vals := range []row.TaggedValues{
+		{0: types.Int(0), 1: types.String("tim"), 2: attrs},
+		{0: types.Int(1)},
+	} {
+		r, err := row.New(vrw.Format(), sch, vals)
+		require.NoError(t, err)
+		rows = append(rows, r)
+	}
+
+	fs := filesys.EmptyInMemFS("/")
+	wr, err := OpenJSONLWriter("/out/file.jsonl", fs, sch)
+	require.NoError(t, err)
+	for _, r := range rows {
+		require.NoError(t, wr.WriteRow(ctx, r))
+	}
+	require.NoError(t, wr.Close(ctx))
+
+	data, err := fs.ReadFile("/out/file.jsonl")
+	require.NoError(t, err)
+	assert.Equal(t, "{\"attrs\":{\"a\":[1,2]},\"id\":0,\"name\":\"tim\"}\n{\"id\":1}\n", string(data))
+
+	rd, err := OpenJSONLReader(vrw, "/out/file.jsonl", fs, sch)
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	for _, expected := range rows {
+		r, err := rd.ReadRow(ctx)
+		require.NoError(t, err)
+		assert.True(t, row.AreEqual(expected, r, sch))
+	}
+	_, err = rd.ReadRow(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,98 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+// JSONLWriter is a TableWriteCloser that writes newline delimited JSON, with each row written as a JSON object on its
+// own line.
+type JSONLWriter struct {
+	closer io.Closer
+	bWr    *bufio.Writer
+	sch    schema.Schema
+}
+
+// OpenJSONLWriter creates the newline delimited JSON file at |path| in |fs| for writing rows of |outSch|.
+func OpenJSONLWriter(path string, fs filesys.WritableFS, outSch schema.Schema) (*JSONLWriter, error) {
+	err := fs.MkDirs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	wr, err := fs.OpenForWrite(path, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewJSONLWriter(wr, outSch)
+}
+
+// NewJSONLWriter returns a JSONLWriter that writes rows of |outSch| to |wr|.
+func NewJSONLWriter(wr io.WriteCloser, outSch schema.Schema) (*JSONLWriter, error) {
+	return &JSONLWriter{closer: wr, bWr: bufio.NewWriterSize(wr, WriteBufSize), sch: outSch}, nil
+}
+
+// GetSchema gets the schema of the rows that this writer writes
+func (w *JSONLWriter) GetSchema() schema.Schema {
+	return w.sch
+}
+
+// WriteRow will write a row to a table
+func (w *JSONLWriter) WriteRow(ctx context.Context, r row.Row) error {
+	colValMap, err := rowToJSONMap(w.sch, r)
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalToJson(colValMap)
+	if err != nil {
+		return err
+	}
+
+	err = iohelp.WriteAll(w.bWr, data)
+	if err != nil {
+		return err
+	}
+
+	return w.bWr.WriteByte('\n')
+}
+
+// Close should flush all writes, release resources being held
+func (w *JSONLWriter) Close(ctx context.Context) error {
+	if w.closer == nil {
+		return errors.New("already closed")
+	}
+
+	errFl := w.bWr.Flush()
+	errCl := w.closer.Close()
+	w.closer = nil
+
+	if errFl != nil {
+		return errFl
+	}
+	return errCl
+}
@@ -75,44 +75,10 @@ func (jsonw *JSONWriter) GetSchema() schema.Schema {
 
 // WriteRow will write a row to a table
 func (jsonw *JSONWriter) WriteRow(ctx context.Context, r row.Row) error {
-	allCols := jsonw.sch.GetAllCols()
-	colValMap := make(map[string]interface{}, allCols.Size())
-	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
-		val, ok := r.GetColVal(tag)
-		if !ok || types.IsNull(val) {
-			return false, nil
-		}
-
-		switch col.TypeInfo.GetTypeIdentifier() {
-		case typeinfo.DatetimeTypeIdentifier,
-			typeinfo.DecimalTypeIdentifier,
-			typeinfo.EnumTypeIdentifier,
-			typeinfo.InlineBlobTypeIdentifier,
-			typeinfo.SetTypeIdentifier,
-			typeinfo.TimeTypeIdentifier,
-			typeinfo.TupleTypeIdentifier,
-			typeinfo.UuidTypeIdentifier,
-			typeinfo.VarBinaryTypeIdentifier,
-			typeinfo.YearTypeIdentifier:
-			v, err := col.TypeInfo.FormatValue(val)
-			if err != nil {
-				return true, err
-			}
-			val = types.String(*v)
-
-		case typeinfo.BitTypeIdentifier,
-			typeinfo.BoolTypeIdentifier,
-			typeinfo.VarStringTypeIdentifier,
-			typeinfo.UintTypeIdentifier,
-			typeinfo.IntTypeIdentifier,
-			typeinfo.FloatTypeIdentifier:
-			// use primitive type
-		}
-
-		colValMap[col.Name] = val
-
-		return false, nil
-	})
+	colValMap, err := rowToJSONMap(jsonw.sch, r)
+	if err != nil {
+		return err
+	}
 
 	data, err := marshalToJson(colValMap)
 	if err != nil {
@@ -159,6 +125,65 @@ func (jsonw *JSONWriter) Close(ctx context.Context) error {
 
 }
 
+// rowToJSONMap returns a map from the names of the columns of |sch| to the values of |r| that can be marshalled to
+// JSON. Null values are omitted.
+func rowToJSONMap(sch schema.Schema, r row.Row) (map[string]interface{}, error) {
+	allCols := sch.GetAllCols()
+	colValMap := make(map[string]interface{}, allCols.Size())
+	err := allCols.Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		val, ok := r.GetColVal(tag)
+		if !ok || types.IsNull(val) {
+			return false, nil
+		}
+
+		switch col.TypeInfo.GetTypeIdentifier() {
+		case typeinfo.BlobStringTypeIdentifier,
+			typeinfo.DatetimeTypeIdentifier,
+			typeinfo.DecimalTypeIdentifier,
+			typeinfo.EnumTypeIdentifier,
+			typeinfo.InlineBlobTypeIdentifier,
+			typeinfo.SetTypeIdentifier,
+			typeinfo.TimeTypeIdentifier,
+			typeinfo.TupleTypeIdentifier,
+			typeinfo.UuidTypeIdentifier,
+			typeinfo.VarBinaryTypeIdentifier,
+			typeinfo.YearTypeIdentifier:
+			v, err := col.TypeInfo.FormatValue(val)
+			if err != nil {
+				return true, err
+			}
+			colValMap[col.Name] = types.String(*v)
+			return false, nil
+
+		case typeinfo.JSONTypeIdentifier:
+			// json documents are nested rather than quoted
+			v, err := col.TypeInfo.FormatValue(val)
+			if err != nil {
+				return true, err
+			}
+			colValMap[col.Name] = json.RawMessage(*v)
+			return false, nil
+
+		case typeinfo.BitTypeIdentifier,
+			typeinfo.BoolTypeIdentifier,
+			typeinfo.VarStringTypeIdentifier,
+			typeinfo.UintTypeIdentifier,
+			typeinfo.IntTypeIdentifier,
+			typeinfo.FloatTypeIdentifier:
+			// use primitive type
+		}
+
+		colValMap[col.Name] = val
+
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return colValMap, nil
+}
+
 func marshalToJson(valMap interface{}) ([]byte, error) {
 	var jsonBytes []byte
 	var err error
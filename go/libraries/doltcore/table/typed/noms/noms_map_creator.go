@@ -54,6 +54,12 @@ func (nmc *NomsMapCreator) GetSchema() schema.Schema {
 // WriteRow will write a row to a table.  The primary key for each row must be greater than the primary key of the row
 // written before it.
 func (nmc *NomsMapCreator) WriteRow(ctx context.Context, r row.Row) error {
+	return nmc.WriteKV(ctx, r.NomsMapKey(nmc.sch), r.NomsMapValue(nmc.sch))
+}
+
+// WriteKV will write the key and value of a row which have already been encoded.  The key must be greater than the key
+// written before it.
+func (nmc *NomsMapCreator) WriteKV(ctx context.Context, pk types.LesserValuable, fieldVals types.Valuable) error {
 	if nmc.err != nil {
 		return nmc.err
 	}
@@ -72,9 +78,6 @@ func (nmc *NomsMapCreator) WriteRow(ctx context.Context, r row.Row) error {
 	}
 
 	err := func() error {
-		pk := r.NomsMapKey(nmc.sch)
-		fieldVals := r.NomsMapValue(nmc.sch)
-
 		isOK := nmc.lastPK == nil
 		if !isOK {
 			var err error
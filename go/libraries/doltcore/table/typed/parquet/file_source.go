@@ -0,0 +1,95 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/xitongsys/parquet-go/source"
+
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// fileSource is a read only source.ParquetFile for a file in a filesys.ReadableFS. Parquet files are read from the
+// end, and each column is read through its own handle, so files that can't be seeked are read into memory.
+type fileSource struct {
+	fs   filesys.ReadableFS
+	path string
+	rs   io.ReadSeeker
+	// closer is nil when the file was read into memory
+	closer io.Closer
+	// data is the contents of the file, when it was read into memory
+	data []byte
+}
+
+var _ source.ParquetFile = (*fileSource)(nil)
+
+func openFileSource(fs filesys.ReadableFS, path string) (*fileSource, error) {
+	rc, err := fs.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if rs, ok := rc.(io.ReadSeeker); ok {
+		return &fileSource{fs: fs, path: path, rs: rs, closer: rc}, nil
+	}
+
+	defer rc.Close()
+	data, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSource{fs: fs, path: path, rs: bytes.NewReader(data), data: data}, nil
+}
+
+// Open implements source.ParquetFile by opening another handle of the same file. |name| is ignored.
+func (f *fileSource) Open(_ string) (source.ParquetFile, error) {
+	if f.data != nil {
+		return &fileSource{fs: f.fs, path: f.path, rs: bytes.NewReader(f.data), data: f.data}, nil
+	}
+	return openFileSource(f.fs, f.path)
+}
+
+// Create implements source.ParquetFile.
+func (f *fileSource) Create(_ string) (source.ParquetFile, error) {
+	return nil, errors.New("parquet file source is read only")
+}
+
+// Read implements io.Reader.
+func (f *fileSource) Read(p []byte) (int, error) {
+	return f.rs.Read(p)
+}
+
+// Seek implements io.Seeker.
+func (f *fileSource) Seek(offset int64, whence int) (int64, error) {
+	return f.rs.Seek(offset, whence)
+}
+
+// Write implements io.Writer.
+func (f *fileSource) Write(_ []byte) (int, error) {
+	return 0, errors.New("parquet file source is read only")
+}
+
+// Close implements io.Closer.
+func (f *fileSource) Close() error {
+	if f.closer != nil {
+		return f.closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	smallDec, err := typeinfo.FromSqlType(sql.MustCreateDecimalType(10, 2))
+	require.NoError(t, err)
+	bigDec, err := typeinfo.FromSqlType(sql.MustCreateDecimalType(30, 5))
+	require.NoError(t, err)
+	varbinary, err := typeinfo.FromSqlType(sql.LongBlob)
+	require.NoError(t, err)
+
+	mustCol := func(name string, tag uint64, ti typeinfo.TypeInfo, pk bool) schema.Column {
+		var constraints []schema.ColConstraint
+		if pk {
+			constraints = append(constraints, schema.NotNullConstraint{})
+		}
+		col, err := schema.NewColumnWithTypeInfo(name, tag, ti, pk, "", false, "", constraints...)
+		require.NoError(t, err)
+		return col
+	}
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(
+		mustCol("id", 0, typeinfo.Int64Type, true),
+		mustCol("name", 1, typeinfo.StringDefaultType, false),
+		mustCol("age", 2, typeinfo.Uint8Type, false),
+		mustCol("score", 3, typeinfo.Float64Type, false),
+		mustCol("active", 4, typeinfo.BoolType, false),
+		mustCol("born", 5, typeinfo.DateType, false),
+		mustCol("updated", 6, typeinfo.DatetimeType, false),
+		mustCol("wake", 7, typeinfo.TimeType, false),
+		mustCol("price", 8, smallDec, false),
+		mustCol("balance", 9, bigDec, false),
+		mustCol("attrs", 10, typeinfo.JSONType, false),
+		mustCol("data", 11, varbinary, false),
+	))
+	require.NoError(t, err)
+
+	attrs, err := typeinfo.JSONType.ParseValue(ctx, vrw, strPtr(`{"a": [1, 2]}`))
+	require.NoError(t, err)
+
+	data, err := varbinary.ConvertValueToNomsValue(ctx, vrw, "\x00\x01\xff")
+	require.NoError(t, err)
+
+	rows := []row.TaggedValues{
+		{
+			0:  types.Int(1),
+			1:  types.String("tim"),
+			2:  types.Uint(40),
+			3:  types.Float(9.5),
+			4:  types.Bool(true),
+			5:  types.Timestamp(time.Date(1980, 6, 1, 0, 0, 0, 0, time.UTC)),
+			6:  types.Timestamp(time.Date(2021, 7, 14, 10, 30, 15, 123456000, time.UTC)),
+			7:  types.Int(int64(7*time.Hour+15*time.Minute) / int64(time.Microsecond)),
+			8:  types.Decimal(decimal.RequireFromString("-12.34")),
+			9:  types.Decimal(decimal.RequireFromString("-1234567890123456789012.34567")),
+			10: attrs,
+			11: data,
+		},
+		{
+			0: types.Int(2),
+			1: types.String("brian"),
+		},
+	}
+
+	fs := filesys.EmptyInMemFS("/")
+	wr, err := OpenParquetWriter("/data/file.parquet", fs, sch)
+	require.NoError(t, err)
+	for _, taggedVals := range rows {
+		r, err := row.New(vrw.Format(), sch, taggedVals)
+		require.NoError(t, err)
+		require.NoError(t, wr.WriteRow(ctx, r))
+	}
+	require.NoError(t, wr.Close(ctx))
+
+	rd, err := OpenParquetReader(vrw, "/data/file.parquet", fs)
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	rdSch := rd.GetSchema()
+	require.Equal(t, sch.GetAllCols().Size(), rdSch.GetAllCols().Size())
+	_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		rdCol, ok := rdSch.GetAllCols().GetByName(col.Name)
+		require.True(t, ok)
+		assert.True(t, col.TypeInfo.Equals(rdCol.TypeInfo), "column %s: %s != %s", col.Name, col.TypeInfo, rdCol.TypeInfo)
+		assert.Equal(t, col.IsNullable(), rdCol.IsNullable())
+		return false, nil
+	})
+
+	for _, expected := range rows {
+		r, err := rd.ReadRow(ctx)
+		require.NoError(t, err)
+
+		_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+			rdCol, _ := rdSch.GetAllCols().GetByName(col.Name)
+			val, _ := r.GetColVal(rdCol.Tag)
+
+			expectedStr, err := col.TypeInfo.FormatValue(expected[tag])
+			require.NoError(t, err)
+			actualStr, err := rdCol.TypeInfo.FormatValue(val)
+			require.NoError(t, err)
+			assert.Equal(t, expectedStr, actualStr, "column %s", col.Name)
+			return false, nil
+		})
+	}
+
+	_, err = rd.ReadRow(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestWriterUnsupportedColumnName(t *testing.T) {
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("a,b", 0, types.IntKind, true, schema.NotNullConstraint{}),
+	))
+	require.NoError(t, err)
+
+	_, err = OpenParquetWriter("/file.parquet", filesys.EmptyInMemFS("/"), sch)
+	assert.Error(t, err)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,213 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	pq "github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ReadBatchSize is the number of rows that are read from each column of a parquet file at a time
+var ReadBatchSize = 4 * 1024
+
+// parallelism is the number of goroutines that parquet-go uses to read a file
+const parallelism = 4
+
+// ParquetReader is a TableReadCloser implementation for reading parquet files. The schema of the rows it returns is
+// inferred from the schema of the parquet file, which must be flat.
+type ParquetReader struct {
+	vrw      types.ValueReadWriter
+	src      *fileSource
+	pr       *reader.ParquetReader
+	sch      schema.Schema
+	tags     []uint64
+	decoders []columnDecoder
+
+	numRows int64
+	read    int64
+	// batch holds the values of the current batch of rows by column
+	batch    [][]interface{}
+	batchPos int
+}
+
+// OpenParquetReader opens the parquet file at |path| in |fs| for reading.
+func OpenParquetReader(vrw types.ValueReadWriter, path string, fs filesys.ReadableFS) (*ParquetReader, error) {
+	src, err := openFileSource(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := reader.NewParquetColumnReader(src, parallelism)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("'%s' is not a valid parquet file: %w", path, err)
+	}
+
+	sch, decoders, err := schemaFromParquet(pr)
+	if err != nil {
+		pr.ReadStop()
+		src.Close()
+		return nil, err
+	}
+
+	var tags []uint64
+	_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		tags = append(tags, tag)
+		return false, nil
+	})
+
+	return &ParquetReader{
+		vrw:      vrw,
+		src:      src,
+		pr:       pr,
+		sch:      sch,
+		tags:     tags,
+		decoders: decoders,
+		numRows:  pr.GetNumRows(),
+	}, nil
+}
+
+// schemaFromParquet returns the schema of the rows read from |pr| along with the decoders of each of its columns. As
+// with the schemas of untyped files the first column is the primary key, so callers will usually want to choose the
+// primary key of the schema themselves.
+func schemaFromParquet(pr *reader.ParquetReader) (schema.Schema, []columnDecoder, error) {
+	elems := pr.SchemaHandler.SchemaElements
+	if len(elems) < 2 {
+		return nil, nil, errors.New("parquet file has no columns")
+	}
+
+	var cols []schema.Column
+	var decoders []columnDecoder
+	for i, elem := range elems[1:] {
+		name := pr.SchemaHandler.Infos[i+1].ExName
+		if elem.GetNumChildren() > 0 || elem.GetRepetitionType() == pq.FieldRepetitionType_REPEATED {
+			return nil, nil, fmt.Errorf("parquet column '%s' can't be read; nested and repeated columns aren't supported", name)
+		}
+
+		ti, decoder, err := typeInfoForElement(elem)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var constraints []schema.ColConstraint
+		if elem.GetRepetitionType() == pq.FieldRepetitionType_REQUIRED {
+			constraints = append(constraints, schema.NotNullConstraint{})
+		}
+
+		col, err := schema.NewColumnWithTypeInfo(name, uint64(i), ti, i == 0, "", false, "", constraints...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cols = append(cols, col)
+		decoders = append(decoders, decoder)
+	}
+
+	if len(cols) != len(pr.SchemaHandler.ValueColumns) {
+		return nil, nil, errors.New("parquet file has an unsupported schema")
+	}
+
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(cols...))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sch, decoders, nil
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (pr *ParquetReader) GetSchema() schema.Schema {
+	return pr.sch
+}
+
+// ReadRow reads a row from a table. If there is a bad row the returned error will be non nil, and calling
+// IsBadRow(err) will be return true. This is a potentially non-fatal error and callers can decide if they want to
+// continue on a bad row, or fail.
+func (pr *ParquetReader) ReadRow(ctx context.Context) (row.Row, error) {
+	if pr.batch == nil || pr.batchPos >= len(pr.batch[0]) {
+		if err := pr.readBatch(); err != nil {
+			return nil, err
+		}
+	}
+
+	pos := pr.batchPos
+	pr.batchPos++
+
+	taggedVals := make(row.TaggedValues, len(pr.tags))
+	for i, tag := range pr.tags {
+		v := pr.batch[i][pos]
+		if v == nil {
+			continue
+		}
+
+		val, err := pr.decoders[i](ctx, pr.vrw, v)
+		if err != nil {
+			return nil, err
+		}
+		taggedVals[tag] = val
+	}
+
+	return row.New(pr.vrw.Format(), pr.sch, taggedVals)
+}
+
+func (pr *ParquetReader) readBatch() error {
+	if pr.read >= pr.numRows {
+		return io.EOF
+	}
+
+	num := pr.numRows - pr.read
+	if num > int64(ReadBatchSize) {
+		num = int64(ReadBatchSize)
+	}
+
+	batch := make([][]interface{}, len(pr.tags))
+	for i := range batch {
+		vals, _, _, err := pr.pr.ReadColumnByIndex(int64(i), num)
+		if err != nil {
+			return err
+		}
+		if int64(len(vals)) != num {
+			return fmt.Errorf("failed to read parquet column '%s'", pr.pr.SchemaHandler.Infos[i+1].ExName)
+		}
+		batch[i] = vals
+	}
+
+	pr.read += num
+	pr.batch = batch
+	pr.batchPos = 0
+	return nil
+}
+
+// Close should release resources being held
+func (pr *ParquetReader) Close(ctx context.Context) error {
+	if pr.src == nil {
+		return errors.New("already closed")
+	}
+
+	pr.pr.ReadStop()
+	err := pr.src.Close()
+	pr.src = nil
+	return err
+}
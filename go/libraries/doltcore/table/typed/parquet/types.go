@@ -0,0 +1,428 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	"github.com/shopspring/decimal"
+	pq "github.com/xitongsys/parquet-go/parquet"
+	pqtypes "github.com/xitongsys/parquet-go/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// maxInt64DecimalPrecision is the largest precision of a decimal that is written as an INT64, larger decimals are
+// written as a BYTE_ARRAY
+const maxInt64DecimalPrecision = 18
+
+const microsPerDay = int64(24 * time.Hour / time.Microsecond)
+
+// columnEncoder converts the values of a column to the values of a parquet column of a physical type.
+type columnEncoder func(v types.Value) (interface{}, error)
+
+// columnDecoder converts the values of a parquet column of a physical type to the values of a column.
+type columnDecoder func(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error)
+
+// parquetMetadata returns the metadata of the parquet column that |col| is written to, in the format of the
+// metadata of a parquet-go CSVWriter, along with the encoder of its values.
+func parquetMetadata(col schema.Column) (string, columnEncoder, error) {
+	if strings.ContainsAny(col.Name, ",=") {
+		return "", nil, fmt.Errorf("column '%s' can't be written to a parquet file; its name contains ',' or '='", col.Name)
+	}
+
+	repetition := "OPTIONAL"
+	if !col.IsNullable() {
+		repetition = "REQUIRED"
+	}
+	md := func(physical, converted string) string {
+		tag := fmt.Sprintf("name=%s, type=%s", col.Name, physical)
+		if converted != "" {
+			tag += ", convertedtype=" + converted
+		}
+		return tag + ", repetitiontype=" + repetition
+	}
+
+	ti := col.TypeInfo
+	sqlType := ti.ToSqlType().Type()
+	switch ti.GetTypeIdentifier() {
+	case typeinfo.BoolTypeIdentifier:
+		return md("BOOLEAN", ""), func(v types.Value) (interface{}, error) {
+			return bool(v.(types.Bool)), nil
+		}, nil
+
+	case typeinfo.IntTypeIdentifier:
+		switch sqlType {
+		case sqltypes.Int8:
+			return md("INT32", "INT_8"), encodeInt32, nil
+		case sqltypes.Int16:
+			return md("INT32", "INT_16"), encodeInt32, nil
+		case sqltypes.Int24, sqltypes.Int32:
+			return md("INT32", "INT_32"), encodeInt32, nil
+		default:
+			return md("INT64", "INT_64"), func(v types.Value) (interface{}, error) {
+				return int64(v.(types.Int)), nil
+			}, nil
+		}
+
+	case typeinfo.UintTypeIdentifier:
+		switch sqlType {
+		case sqltypes.Uint8:
+			return md("INT32", "UINT_8"), encodeUint32, nil
+		case sqltypes.Uint16:
+			return md("INT32", "UINT_16"), encodeUint32, nil
+		case sqltypes.Uint24, sqltypes.Uint32:
+			return md("INT32", "UINT_32"), encodeUint32, nil
+		default:
+			return md("INT64", "UINT_64"), func(v types.Value) (interface{}, error) {
+				return int64(v.(types.Uint)), nil
+			}, nil
+		}
+
+	case typeinfo.BitTypeIdentifier:
+		return md("INT64", "UINT_64"), func(v types.Value) (interface{}, error) {
+			return int64(v.(types.Uint)), nil
+		}, nil
+
+	case typeinfo.YearTypeIdentifier:
+		return md("INT32", "INT_16"), encodeInt32, nil
+
+	case typeinfo.FloatTypeIdentifier:
+		if sqlType == sqltypes.Float32 {
+			return md("FLOAT", ""), func(v types.Value) (interface{}, error) {
+				return float32(v.(types.Float)), nil
+			}, nil
+		}
+		return md("DOUBLE", ""), func(v types.Value) (interface{}, error) {
+			return float64(v.(types.Float)), nil
+		}, nil
+
+	case typeinfo.DatetimeTypeIdentifier:
+		if sqlType == sqltypes.Date {
+			return md("INT32", "DATE"), func(v types.Value) (interface{}, error) {
+				return int32(time.Time(v.(types.Timestamp)).UTC().Unix() / 86400), nil
+			}, nil
+		}
+		return md("INT64", "TIMESTAMP_MICROS"), func(v types.Value) (interface{}, error) {
+			return time.Time(v.(types.Timestamp)).UTC().UnixNano() / int64(time.Microsecond), nil
+		}, nil
+
+	case typeinfo.TimeTypeIdentifier:
+		// times are stored as a number of microseconds
+		return md("INT64", "TIME_MICROS"), func(v types.Value) (interface{}, error) {
+			return int64(v.(types.Int)), nil
+		}, nil
+
+	case typeinfo.DecimalTypeIdentifier:
+		decType := ti.ToSqlType().(sql.DecimalType)
+		precision, scale := int(decType.Precision()), int32(decType.Scale())
+		if precision <= maxInt64DecimalPrecision {
+			tag := fmt.Sprintf("name=%s, type=INT64, convertedtype=DECIMAL, scale=%d, precision=%d, repetitiontype=%s", col.Name, scale, precision, repetition)
+			return tag, func(v types.Value) (interface{}, error) {
+				return decimal.Decimal(v.(types.Decimal)).Shift(scale).IntPart(), nil
+			}, nil
+		}
+		tag := fmt.Sprintf("name=%s, type=BYTE_ARRAY, convertedtype=DECIMAL, scale=%d, precision=%d, repetitiontype=%s", col.Name, scale, precision, repetition)
+		return tag, func(v types.Value) (interface{}, error) {
+			unscaled := decimal.Decimal(v.(types.Decimal)).Shift(scale).BigInt()
+			return string(twosComplement(unscaled)), nil
+		}, nil
+
+	case typeinfo.JSONTypeIdentifier:
+		return md("BYTE_ARRAY", "JSON"), encodeFormatted(ti), nil
+
+	case typeinfo.VarStringTypeIdentifier,
+		typeinfo.BlobStringTypeIdentifier,
+		typeinfo.EnumTypeIdentifier,
+		typeinfo.SetTypeIdentifier,
+		typeinfo.UuidTypeIdentifier:
+		return md("BYTE_ARRAY", "UTF8"), encodeFormatted(ti), nil
+
+	case typeinfo.VarBinaryTypeIdentifier, typeinfo.InlineBlobTypeIdentifier:
+		return md("BYTE_ARRAY", ""), func(v types.Value) (interface{}, error) {
+			bytes, err := ti.ConvertNomsValueToValue(v)
+			if err != nil {
+				return nil, err
+			}
+			return bytes.(string), nil
+		}, nil
+	}
+
+	return "", nil, fmt.Errorf("column '%s' can't be written to a parquet file; type %s isn't supported", col.Name, ti.String())
+}
+
+func encodeInt32(v types.Value) (interface{}, error) {
+	return int32(v.(types.Int)), nil
+}
+
+func encodeUint32(v types.Value) (interface{}, error) {
+	return int32(v.(types.Uint)), nil
+}
+
+func encodeFormatted(ti typeinfo.TypeInfo) columnEncoder {
+	return func(v types.Value) (interface{}, error) {
+		str, err := ti.FormatValue(v)
+		if err != nil {
+			return nil, err
+		}
+		return *str, nil
+	}
+}
+
+// typeInfoForElement returns the TypeInfo of the column that the values of the parquet column |elem| are read into,
+// along with the decoder of its values. Logical types are preferred to converted types, and converted types to
+// physical types.
+func typeInfoForElement(elem *pq.SchemaElement) (typeinfo.TypeInfo, columnDecoder, error) {
+	physical := elem.GetType()
+	scale := elem.GetScale()
+
+	if lt := elem.GetLogicalType(); lt != nil {
+		switch {
+		case lt.IsSetUUID() && physical == pq.Type_FIXED_LEN_BYTE_ARRAY:
+			return typeinfo.UuidType, decodeUUID, nil
+		case lt.IsSetTIMESTAMP() && physical == pq.Type_INT64:
+			unit := lt.TIMESTAMP.GetUnit()
+			switch {
+			case unit.IsSetMILLIS():
+				return typeinfo.DatetimeType, decodeTimestamp(time.Millisecond), nil
+			case unit.IsSetMICROS():
+				return typeinfo.DatetimeType, decodeTimestamp(time.Microsecond), nil
+			case unit.IsSetNANOS():
+				return typeinfo.DatetimeType, decodeTimestamp(time.Nanosecond), nil
+			}
+		case lt.IsSetTIME():
+			unit := lt.TIME.GetUnit()
+			switch {
+			case unit.IsSetMILLIS() && physical == pq.Type_INT32:
+				return typeinfo.TimeType, decodeTime(time.Millisecond), nil
+			case unit.IsSetMICROS() && physical == pq.Type_INT64:
+				return typeinfo.TimeType, decodeTime(time.Microsecond), nil
+			case unit.IsSetNANOS() && physical == pq.Type_INT64:
+				return typeinfo.TimeType, decodeTime(time.Nanosecond), nil
+			}
+		}
+	}
+
+	if elem.IsSetConvertedType() {
+		switch elem.GetConvertedType() {
+		case pq.ConvertedType_UTF8, pq.ConvertedType_ENUM:
+			return typeinfo.StringDefaultType, decodeString, nil
+		case pq.ConvertedType_JSON:
+			return typeinfo.JSONType, decodeJSON, nil
+		case pq.ConvertedType_INT_8:
+			return typeinfo.Int8Type, decodeInt, nil
+		case pq.ConvertedType_INT_16:
+			return typeinfo.Int16Type, decodeInt, nil
+		case pq.ConvertedType_INT_32:
+			return typeinfo.Int32Type, decodeInt, nil
+		case pq.ConvertedType_INT_64:
+			return typeinfo.Int64Type, decodeInt, nil
+		case pq.ConvertedType_UINT_8:
+			return typeinfo.Uint8Type, decodeUint, nil
+		case pq.ConvertedType_UINT_16:
+			return typeinfo.Uint16Type, decodeUint, nil
+		case pq.ConvertedType_UINT_32:
+			return typeinfo.Uint32Type, decodeUint, nil
+		case pq.ConvertedType_UINT_64:
+			return typeinfo.Uint64Type, decodeUint, nil
+		case pq.ConvertedType_DATE:
+			return typeinfo.DateType, decodeDate, nil
+		case pq.ConvertedType_TIMESTAMP_MILLIS:
+			return typeinfo.DatetimeType, decodeTimestamp(time.Millisecond), nil
+		case pq.ConvertedType_TIMESTAMP_MICROS:
+			return typeinfo.DatetimeType, decodeTimestamp(time.Microsecond), nil
+		case pq.ConvertedType_TIME_MILLIS:
+			return typeinfo.TimeType, decodeTime(time.Millisecond), nil
+		case pq.ConvertedType_TIME_MICROS:
+			return typeinfo.TimeType, decodeTime(time.Microsecond), nil
+		case pq.ConvertedType_DECIMAL:
+			decType, err := sql.CreateDecimalType(uint8(elem.GetPrecision()), uint8(scale))
+			if err != nil {
+				return nil, nil, fmt.Errorf("parquet column '%s' can't be read: %v", elem.GetName(), err)
+			}
+			ti, err := typeinfo.FromSqlType(decType)
+			if err != nil {
+				return nil, nil, err
+			}
+			return ti, decodeDecimal(scale), nil
+		}
+	}
+
+	switch physical {
+	case pq.Type_BOOLEAN:
+		return typeinfo.BoolType, decodeBool, nil
+	case pq.Type_INT32:
+		return typeinfo.Int32Type, decodeInt, nil
+	case pq.Type_INT64:
+		return typeinfo.Int64Type, decodeInt, nil
+	case pq.Type_INT96:
+		return typeinfo.DatetimeType, decodeInt96, nil
+	case pq.Type_FLOAT:
+		return typeinfo.Float32Type, decodeFloat, nil
+	case pq.Type_DOUBLE:
+		return typeinfo.Float64Type, decodeFloat, nil
+	case pq.Type_BYTE_ARRAY, pq.Type_FIXED_LEN_BYTE_ARRAY:
+		ti, err := typeinfo.FromSqlType(sql.LongBlob)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ti, decodeConverted(ti), nil
+	}
+
+	return nil, nil, fmt.Errorf("parquet column '%s' can't be read; type %s isn't supported", elem.GetName(), physical.String())
+}
+
+func decodeBool(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+	return types.Bool(v.(bool)), nil
+}
+
+func decodeInt(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+	switch v := v.(type) {
+	case int32:
+		return types.Int(v), nil
+	case int64:
+		return types.Int(v), nil
+	}
+	return nil, fmt.Errorf("unexpected parquet value %v", v)
+}
+
+func decodeUint(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+	switch v := v.(type) {
+	case int32:
+		return types.Uint(uint32(v)), nil
+	case int64:
+		return types.Uint(uint64(v)), nil
+	}
+	return nil, fmt.Errorf("unexpected parquet value %v", v)
+}
+
+func decodeFloat(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+	switch v := v.(type) {
+	case float32:
+		return types.Float(v), nil
+	case float64:
+		return types.Float(v), nil
+	}
+	return nil, fmt.Errorf("unexpected parquet value %v", v)
+}
+
+func decodeString(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+	return types.String(v.(string)), nil
+}
+
+// decodeConverted returns a decoder which converts values using |ti|.
+func decodeConverted(ti typeinfo.TypeInfo) columnDecoder {
+	return func(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
+		return ti.ConvertValueToNomsValue(ctx, vrw, v)
+	}
+}
+
+func decodeJSON(ctx context.Context, vrw types.ValueReadWriter, v interface{}) (types.Value, error) {
+	str := v.(string)
+	return typeinfo.JSONType.ParseValue(ctx, vrw, &str)
+}
+
+func decodeUUID(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+	var id [16]byte
+	copy(id[:], v.(string))
+	return types.UUID(id), nil
+}
+
+func decodeDate(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+	days := int64(v.(int32))
+	return types.Timestamp(time.Unix(days*86400, 0).UTC()), nil
+}
+
+func decodeTimestamp(unit time.Duration) columnDecoder {
+	return func(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+		return types.Timestamp(time.Unix(0, v.(int64)*int64(unit)).UTC()), nil
+	}
+}
+
+func decodeInt96(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+	return types.Timestamp(pqtypes.INT96ToTime(v.(string)).UTC()), nil
+}
+
+// decodeTime returns a decoder of times in |unit|, which are stored as a number of microseconds.
+func decodeTime(unit time.Duration) columnDecoder {
+	return func(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+		var t int64
+		switch v := v.(type) {
+		case int32:
+			t = int64(v)
+		case int64:
+			t = v
+		default:
+			return nil, fmt.Errorf("unexpected parquet value %v", v)
+		}
+		micros := t * int64(unit) / int64(time.Microsecond)
+		if micros < 0 || micros >= microsPerDay {
+			return nil, fmt.Errorf("parquet time %d is out of range", t)
+		}
+		return types.Int(micros), nil
+	}
+}
+
+func decodeDecimal(scale int32) columnDecoder {
+	return func(_ context.Context, _ types.ValueReadWriter, v interface{}) (types.Value, error) {
+		switch v := v.(type) {
+		case int32:
+			return types.Decimal(decimal.New(int64(v), -scale)), nil
+		case int64:
+			return types.Decimal(decimal.New(v, -scale)), nil
+		case string:
+			return types.Decimal(decimal.NewFromBigInt(fromTwosComplement([]byte(v)), -scale)), nil
+		}
+		return nil, fmt.Errorf("unexpected parquet value %v", v)
+	}
+}
+
+// twosComplement returns the big-endian two's complement representation of |i|, which is how parquet stores the
+// unscaled values of decimals in byte arrays.
+func twosComplement(i *big.Int) []byte {
+	if i.Sign() >= 0 {
+		bytes := i.Bytes()
+		if len(bytes) == 0 || bytes[0]&0x80 != 0 {
+			bytes = append([]byte{0}, bytes...)
+		}
+		return bytes
+	}
+
+	// -i = 2^n - |i| for the smallest n such that the sign bit is set
+	n := uint(i.BitLen()/8+1) * 8
+	comp := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), n), i)
+	bytes := comp.Bytes()
+	for len(bytes) < int(n/8) {
+		bytes = append([]byte{0xff}, bytes...)
+	}
+	return bytes
+}
+
+// fromTwosComplement is the inverse of twosComplement.
+func fromTwosComplement(bytes []byte) *big.Int {
+	i := new(big.Int).SetBytes(bytes)
+	if len(bytes) > 0 && bytes[0]&0x80 != 0 {
+		i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(len(bytes)*8)))
+	}
+	return i
+}
@@ -0,0 +1,129 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquet
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/xitongsys/parquet-go/writer"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// ParquetWriter is a TableWriteCloser implementation for writing parquet files. Each column of its schema is written
+// to a parquet column of the corresponding type.
+type ParquetWriter struct {
+	closer   io.Closer
+	pw       *writer.CSVWriter
+	sch      schema.Schema
+	tags     []uint64
+	encoders []columnEncoder
+}
+
+// OpenParquetWriter creates the parquet file at |path| in |fs| for writing rows of |outSch|.
+func OpenParquetWriter(path string, fs filesys.WritableFS, outSch schema.Schema) (*ParquetWriter, error) {
+	err := fs.MkDirs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	wr, err := fs.OpenForWrite(path, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	pw, err := NewParquetWriter(wr, outSch)
+	if err != nil {
+		wr.Close()
+		return nil, err
+	}
+
+	return pw, nil
+}
+
+// NewParquetWriter returns a ParquetWriter that writes rows of |outSch| to |wr|.
+func NewParquetWriter(wr io.WriteCloser, outSch schema.Schema) (*ParquetWriter, error) {
+	var md []string
+	var tags []uint64
+	var encoders []columnEncoder
+	err := outSch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		colMd, encoder, err := parquetMetadata(col)
+		if err != nil {
+			return true, err
+		}
+
+		md = append(md, colMd)
+		tags = append(tags, tag)
+		encoders = append(encoders, encoder)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pw, err := writer.NewCSVWriterFromWriter(md, wr, parallelism)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParquetWriter{closer: wr, pw: pw, sch: outSch, tags: tags, encoders: encoders}, nil
+}
+
+// GetSchema gets the schema of the rows that this writer writes
+func (pw *ParquetWriter) GetSchema() schema.Schema {
+	return pw.sch
+}
+
+// WriteRow will write a row to a table
+func (pw *ParquetWriter) WriteRow(ctx context.Context, r row.Row) error {
+	rec := make([]interface{}, len(pw.tags))
+	for i, tag := range pw.tags {
+		val, ok := r.GetColVal(tag)
+		if !ok || types.IsNull(val) {
+			continue
+		}
+
+		v, err := pw.encoders[i](val)
+		if err != nil {
+			return err
+		}
+		rec[i] = v
+	}
+
+	return pw.pw.Write(rec)
+}
+
+// Close should flush all writes, release resources being held
+func (pw *ParquetWriter) Close(ctx context.Context) error {
+	if pw.closer == nil {
+		return errors.New("already closed")
+	}
+
+	err := pw.pw.WriteStop()
+	closeErr := pw.closer.Close()
+	pw.closer = nil
+
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
@@ -0,0 +1,346 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	// registers the pure go "sqlite" driver, so that no cgo is needed to read SQLite databases
+	_ "modernc.org/sqlite"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+// SQLiteReader is a TableReadCloser implementation for reading a table of a SQLite database. The schema of the rows
+// it returns is read from the declaration of the table, including its primary key and indexes.
+type SQLiteReader struct {
+	vrw      types.ValueReadWriter
+	db       *sql.DB
+	rows     *sql.Rows
+	sch      schema.Schema
+	cols     []schema.Column
+	typeInfs []typeinfo.TypeInfo
+}
+
+// OpenSQLiteReader opens the table |tableName| of the SQLite database at |path| in |fs| for reading. The database
+// must be a file on the local filesystem.
+func OpenSQLiteReader(ctx context.Context, vrw types.ValueReadWriter, path string, fs filesys.ReadableFS, tableName string) (*SQLiteReader, error) {
+	db, err := openDB(path, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	sch, typeInfs, err := schemaFromTable(ctx, db, tableName)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	var cols []schema.Column
+	var names []string
+	_ = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		cols = append(cols, col)
+		names = append(names, quoteIdentifier(col.Name))
+		return false, nil
+	})
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("SELECT %s FROM %s", strings.Join(names, ", "), quoteIdentifier(tableName)))
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteReader{
+		vrw:      vrw,
+		db:       db,
+		rows:     rows,
+		sch:      sch,
+		cols:     cols,
+		typeInfs: typeInfs,
+	}, nil
+}
+
+// TableNames returns the names of the tables of the SQLite database at |path| in |fs|, excluding SQLite's internal
+// tables.
+func TableNames(ctx context.Context, path string, fs filesys.ReadableFS) ([]string, error) {
+	db, err := openDB(path, fs)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite\\_%' ESCAPE '\\' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+
+	return names, rows.Err()
+}
+
+func openDB(path string, fs filesys.ReadableFS) (*sql.DB, error) {
+	exists, isDir := fs.Exists(path)
+	if !exists {
+		return nil, os.ErrNotExist
+	} else if isDir {
+		return nil, filesys.ErrIsDir
+	}
+
+	absPath, err := fs.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", (&url.URL{Scheme: "file", Opaque: absPath, RawQuery: "mode=ro"}).String())
+	if err != nil {
+		return nil, err
+	}
+
+	// reading the schema fails if the file isn't a SQLite database
+	if _, err = db.Exec("SELECT count(*) FROM sqlite_master"); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("'%s' is not a valid sqlite database: %w", path, err)
+	}
+
+	return db, nil
+}
+
+// schemaFromTable returns the schema of the SQLite table |tableName|, along with the types of each of its columns.
+func schemaFromTable(ctx context.Context, db *sql.DB, tableName string) (schema.Schema, []typeinfo.TypeInfo, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", quoteIdentifier(tableName)))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var cols []schema.Column
+	var typeInfs []typeinfo.TypeInfo
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, decl string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &decl, &notNull, &dflt, &pk); err != nil {
+			return nil, nil, err
+		}
+
+		ti, err := typeInfoForDecl(decl)
+		if err != nil {
+			return nil, nil, fmt.Errorf("column '%s' of sqlite table '%s' has an unsupported type '%s': %w", name, tableName, decl, err)
+		}
+
+		var constraints []schema.ColConstraint
+		if notNull != 0 || pk != 0 {
+			constraints = append(constraints, schema.NotNullConstraint{})
+		}
+
+		col, err := schema.NewColumnWithTypeInfo(name, uint64(cid), ti, pk != 0, "", false, "", constraints...)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cols = append(cols, col)
+		typeInfs = append(typeInfs, ti)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(cols) == 0 {
+		return nil, nil, fmt.Errorf("table '%s' was not found in the sqlite database", tableName)
+	}
+
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(cols...))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = addIndexes(ctx, db, tableName, sch)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sch, typeInfs, nil
+}
+
+// addIndexes adds the indexes of the SQLite table |tableName| to |sch|. The indexes that SQLite creates for primary
+// keys are left out, and the indexes of expressions are skipped as they aren't supported.
+func addIndexes(ctx context.Context, db *sql.DB, tableName string, sch schema.Schema) error {
+	type sqliteIndex struct {
+		name   string
+		unique bool
+		origin string
+	}
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA index_list(%s)", quoteIdentifier(tableName)))
+	if err != nil {
+		return err
+	}
+
+	var indexes []sqliteIndex
+	for rows.Next() {
+		var seq, unique, partial int
+		var idx sqliteIndex
+		if err := rows.Scan(&seq, &idx.name, &unique, &idx.origin, &partial); err != nil {
+			rows.Close()
+			return err
+		}
+		idx.unique = unique != 0
+		if idx.origin != "pk" {
+			indexes = append(indexes, idx)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// index_list lists the most recently created index first, so the indexes are added in the order they were created
+	for i, j := 0, len(indexes)-1; i < j; i, j = i+1, j-1 {
+		indexes[i], indexes[j] = indexes[j], indexes[i]
+	}
+
+	for _, idx := range indexes {
+		colNames, err := indexColumns(ctx, db, idx.name)
+		if err != nil {
+			return err
+		} else if colNames == nil {
+			continue
+		}
+
+		name := idx.name
+		if idx.origin == "u" {
+			// the indexes of unique constraints are named sqlite_autoindex_<table>_<n>, so they're given the name
+			// that MySQL would give them instead
+			name = uniqueIndexName(sch, colNames[0])
+		}
+
+		_, err = sch.Indexes().AddIndexByColNames(name, colNames, schema.IndexProperties{IsUnique: idx.unique, IsUserDefined: true})
+		if err != nil {
+			return fmt.Errorf("failed to add index '%s' of sqlite table '%s': %w", idx.name, tableName, err)
+		}
+	}
+
+	return nil
+}
+
+// indexColumns returns the names of the columns of the SQLite index |indexName|, or nil if it indexes expressions.
+func indexColumns(ctx context.Context, db *sql.DB, indexName string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf("PRAGMA index_info(%s)", quoteIdentifier(indexName)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var colNames []string
+	for rows.Next() {
+		var seqno, cid int
+		var name sql.NullString
+		if err := rows.Scan(&seqno, &cid, &name); err != nil {
+			return nil, err
+		}
+		if !name.Valid {
+			return nil, nil
+		}
+		colNames = append(colNames, name.String)
+	}
+
+	return colNames, rows.Err()
+}
+
+// uniqueIndexName returns a name for the index of a unique constraint on |colName| which isn't used by another index.
+func uniqueIndexName(sch schema.Schema, colName string) string {
+	name := colName
+	for i := 2; sch.Indexes().Contains(name); i++ {
+		name = fmt.Sprintf("%s_%d", colName, i)
+	}
+	return name
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// GetSchema gets the schema of the rows that this reader will return
+func (sr *SQLiteReader) GetSchema() schema.Schema {
+	return sr.sch
+}
+
+// ReadRow reads a row from a table. If there is a bad row the returned error will be non nil, and calling
+// IsBadRow(err) will be return true. This is a potentially non-fatal error and callers can decide if they want to
+// continue on a bad row, or fail.
+func (sr *SQLiteReader) ReadRow(ctx context.Context) (row.Row, error) {
+	if !sr.rows.Next() {
+		if err := sr.rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	vals := make([]interface{}, len(sr.cols))
+	ptrs := make([]interface{}, len(sr.cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+
+	if err := sr.rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+
+	taggedVals := make(row.TaggedValues, len(sr.cols))
+	for i, col := range sr.cols {
+		if vals[i] == nil {
+			continue
+		}
+
+		val, err := sr.typeInfs[i].ConvertValueToNomsValue(ctx, sr.vrw, vals[i])
+		if err != nil {
+			return nil, table.NewBadRow(nil, fmt.Sprintf("column %s: %v", col.Name, err))
+		}
+		taggedVals[col.Tag] = val
+	}
+
+	return row.New(sr.vrw.Format(), sr.sch, taggedVals)
+}
+
+// Close should release resources being held
+func (sr *SQLiteReader) Close(ctx context.Context) error {
+	rowsErr := sr.rows.Close()
+	if err := sr.db.Close(); err != nil {
+		return err
+	}
+	return rowsErr
+}
+
+var _ table.TableReadCloser = (*SQLiteReader)(nil)
@@ -0,0 +1,256 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gmstypes "github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const testDB = `
+CREATE TABLE people (
+  id INTEGER PRIMARY KEY,
+  name VARCHAR(100) NOT NULL,
+  email TEXT UNIQUE,
+  score REAL,
+  balance DECIMAL(10, 2),
+  active BOOLEAN,
+  born DATE,
+  photo BLOB,
+  notes
+);
+CREATE INDEX people_name ON people (name, score);
+CREATE INDEX people_lower_name ON people (lower(name));
+CREATE TABLE events (happened DATETIME, what TEXT);
+INSERT INTO people VALUES (1, 'tim', 'tim@example.com', 1.5, 12.25, 1, '1990-01-02', x'00ff', 42);
+INSERT INTO people VALUES (2, 'aaron', NULL, NULL, NULL, 0, NULL, NULL, 'n/a');
+INSERT INTO events VALUES ('2021-03-04 05:06:07', 'launch');
+`
+
+func createTestDB(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "sqlite_test")
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, "test.sqlite")
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(testDB)
+	require.NoError(t, err)
+
+	return path, func() { os.RemoveAll(dir) }
+}
+
+func TestTypeInfoForDecl(t *testing.T) {
+	blob, err := typeinfo.FromSqlType(gmstypes.LongBlob)
+	require.NoError(t, err)
+	boolean, err := typeinfo.FromSqlType(gmstypes.Boolean)
+	require.NoError(t, err)
+	dec, err := typeinfo.FromSqlType(gmstypes.MustCreateDecimalType(10, 2))
+	require.NoError(t, err)
+	intDec, err := typeinfo.FromSqlType(gmstypes.MustCreateDecimalType(8, 0))
+	require.NoError(t, err)
+
+	tests := []struct {
+		decl     string
+		expected typeinfo.TypeInfo
+	}{
+		{"INTEGER", typeinfo.Int64Type},
+		{"tinyint", typeinfo.Int64Type},
+		{"UNSIGNED BIG INT", typeinfo.Int64Type},
+		{"VARCHAR(255)", typeinfo.StringDefaultType},
+		{"NATIVE CHARACTER(70)", typeinfo.StringDefaultType},
+		{"TEXT", typeinfo.StringDefaultType},
+		{"CLOB", typeinfo.StringDefaultType},
+		{"", typeinfo.StringDefaultType},
+		{"BLOB", blob},
+		{"REAL", typeinfo.Float64Type},
+		{"DOUBLE PRECISION", typeinfo.Float64Type},
+		{"FLOAT", typeinfo.Float64Type},
+		{"NUMERIC", typeinfo.Float64Type},
+		{"BOOLEAN", boolean},
+		{"DATE", typeinfo.DateType},
+		{"DATETIME", typeinfo.DatetimeType},
+		{"TIMESTAMP", typeinfo.DatetimeType},
+		{"DECIMAL(10,2)", dec},
+		{"NUMERIC(8)", intDec},
+		{"DECIMAL", typeinfo.Float64Type},
+	}
+
+	for _, test := range tests {
+		t.Run(test.decl, func(t *testing.T) {
+			ti, err := typeInfoForDecl(test.decl)
+			require.NoError(t, err)
+			assert.True(t, test.expected.Equals(ti), "expected %s, got %s", test.expected.String(), ti.String())
+		})
+	}
+
+	_, err = typeInfoForDecl("DECIMAL(100, 2)")
+	assert.Error(t, err)
+}
+
+func TestTableNames(t *testing.T) {
+	path, cleanup := createTestDB(t)
+	defer cleanup()
+
+	names, err := TableNames(context.Background(), path, filesys.LocalFS)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"events", "people"}, names)
+}
+
+func TestSQLiteReader(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	path, cleanup := createTestDB(t)
+	defer cleanup()
+
+	rd, err := OpenSQLiteReader(ctx, vrw, path, filesys.LocalFS, "people")
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	sch := rd.GetSchema()
+	assert.Equal(t, []string{"id"}, sch.GetPKCols().GetColumnNames())
+	assert.Equal(t, []string{"id", "name", "email", "score", "balance", "active", "born", "photo", "notes"}, sch.GetAllCols().GetColumnNames())
+
+	name, _ := sch.GetAllCols().GetByName("name")
+	assert.False(t, name.IsNullable())
+	email, _ := sch.GetAllCols().GetByName("email")
+	assert.True(t, email.IsNullable())
+
+	indexes := sch.Indexes().AllIndexes()
+	require.Len(t, indexes, 2)
+	assert.Equal(t, "email", indexes[0].Name())
+	assert.True(t, indexes[0].IsUnique())
+	assert.Equal(t, []string{"email"}, indexes[0].ColumnNames())
+	assert.Equal(t, "people_name", indexes[1].Name())
+	assert.False(t, indexes[1].IsUnique())
+	assert.Equal(t, []string{"name", "score"}, indexes[1].ColumnNames())
+
+	r, err := rd.ReadRow(ctx)
+	require.NoError(t, err)
+	expected := map[string]types.Value{
+		"id":     types.Int(1),
+		"name":   types.String("tim"),
+		"email":  types.String("tim@example.com"),
+		"score":  types.Float(1.5),
+		"active": types.Int(1),
+		"born":   types.Timestamp(time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC)),
+		"notes":  types.String("42"),
+	}
+	for colName, expectedVal := range expected {
+		col, _ := sch.GetAllCols().GetByName(colName)
+		val, ok := r.GetColVal(col.Tag)
+		require.True(t, ok, colName)
+		assert.True(t, expectedVal.Equals(val), "column %s: expected %v, got %v", colName, expectedVal, val)
+	}
+	for _, colName := range []string{"balance", "photo"} {
+		col, _ := sch.GetAllCols().GetByName(colName)
+		str, err := col.TypeInfo.FormatValue(mustColVal(t, r, col.Tag))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"balance": "12.25", "photo": "\x00\xff"}[colName], *str)
+	}
+
+	r, err = rd.ReadRow(ctx)
+	require.NoError(t, err)
+	emailVal, ok := r.GetColVal(email.Tag)
+	assert.False(t, ok && !types.IsNull(emailVal))
+
+	_, err = rd.ReadRow(ctx)
+	assert.Equal(t, io.EOF, err)
+}
+
+func mustColVal(t *testing.T, r row.Row, tag uint64) types.Value {
+	val, ok := r.GetColVal(tag)
+	require.True(t, ok)
+	return val
+}
+
+func TestSQLiteReaderKeyless(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	path, cleanup := createTestDB(t)
+	defer cleanup()
+
+	rd, err := OpenSQLiteReader(ctx, vrw, path, filesys.LocalFS, "events")
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	assert.Equal(t, 0, rd.GetSchema().GetPKCols().Size())
+
+	happened, _ := rd.GetSchema().GetAllCols().GetByName("happened")
+	r, err := rd.ReadRow(ctx)
+	require.NoError(t, err)
+	val, _ := r.GetColVal(happened.Tag)
+	assert.True(t, types.Timestamp(time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)).Equals(val), "got %v", val)
+}
+
+func TestSQLiteReaderBadRow(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	path, cleanup := createTestDB(t)
+	defer cleanup()
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+	_, err = db.Exec("DELETE FROM events; INSERT INTO events VALUES ('not a date', 'oops')")
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	rd, err := OpenSQLiteReader(ctx, vrw, path, filesys.LocalFS, "events")
+	require.NoError(t, err)
+	defer rd.Close(ctx)
+
+	_, err = rd.ReadRow(ctx)
+	assert.True(t, table.IsBadRow(err))
+}
+
+func TestOpenSQLiteReaderErrors(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	path, cleanup := createTestDB(t)
+	defer cleanup()
+
+	_, err := OpenSQLiteReader(ctx, vrw, path, filesys.LocalFS, "missing")
+	assert.Error(t, err)
+
+	notDB := filepath.Join(filepath.Dir(path), "not.sqlite")
+	require.NoError(t, ioutil.WriteFile(notDB, []byte("this is not a database, it is just text"), 0644))
+	_, err = OpenSQLiteReader(ctx, vrw, notDB, filesys.LocalFS, "people")
+	assert.Error(t, err)
+
+	_, err = OpenSQLiteReader(ctx, vrw, filepath.Join(filepath.Dir(path), "nope.sqlite"), filesys.LocalFS, "people")
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlite
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+)
+
+// decimalParamsRegex matches the precision and optional scale of a declared type such as DECIMAL(10, 2)
+var decimalParamsRegex = regexp.MustCompile(`\(\s*(\d+)\s*(?:,\s*(\d+)\s*)?\)`)
+
+// typeInfoForDecl returns the type of a column declared with the SQLite type |decl|. SQLite doesn't enforce declared
+// types, instead each column has an affinity which is determined by the rules at
+// https://www.sqlite.org/datatype3.html#determination_of_column_affinity, and the type follows the affinity. INTEGER
+// columns are BIGINTs, TEXT columns are LONGTEXTs, BLOB columns are LONGBLOBs and REAL columns are DOUBLEs. Columns
+// without a declared type may hold any values, so they're LONGTEXTs. NUMERIC columns are DOUBLEs, unless they're
+// declared as a boolean, date, datetime, timestamp or a decimal with a precision, which keep their declared type.
+func typeInfoForDecl(decl string) (typeinfo.TypeInfo, error) {
+	decl = strings.ToUpper(strings.TrimSpace(decl))
+
+	switch {
+	case strings.Contains(decl, "INT"):
+		return typeinfo.Int64Type, nil
+	case strings.Contains(decl, "CHAR"), strings.Contains(decl, "CLOB"), strings.Contains(decl, "TEXT"):
+		return typeinfo.StringDefaultType, nil
+	case strings.Contains(decl, "BLOB"):
+		return typeinfo.FromSqlType(sql.LongBlob)
+	case decl == "":
+		return typeinfo.StringDefaultType, nil
+	case strings.Contains(decl, "REAL"), strings.Contains(decl, "FLOA"), strings.Contains(decl, "DOUB"):
+		return typeinfo.Float64Type, nil
+	}
+
+	// NUMERIC affinity
+	switch {
+	case strings.HasPrefix(decl, "BOOL"):
+		return typeinfo.FromSqlType(sql.Boolean)
+	case strings.HasPrefix(decl, "DATETIME"), strings.HasPrefix(decl, "TIMESTAMP"):
+		return typeinfo.DatetimeType, nil
+	case strings.HasPrefix(decl, "DATE"):
+		return typeinfo.DateType, nil
+	case strings.HasPrefix(decl, "DECIMAL"), strings.HasPrefix(decl, "NUMERIC"):
+		if matches := decimalParamsRegex.FindStringSubmatch(decl); matches != nil {
+			precision, err := strconv.ParseUint(matches[1], 10, 8)
+			if err != nil {
+				return nil, err
+			}
+
+			var scale uint64
+			if matches[2] != "" {
+				scale, err = strconv.ParseUint(matches[2], 10, 8)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			decType, err := sql.CreateDecimalType(uint8(precision), uint8(scale))
+			if err != nil {
+				return nil, err
+			}
+
+			return typeinfo.FromSqlType(decType)
+		}
+	}
+
+	return typeinfo.Float64Type, nil
+}
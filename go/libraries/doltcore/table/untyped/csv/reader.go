@@ -54,6 +54,9 @@ type CSVReader struct {
 	delim           []byte
 	numLine         int
 	fieldsPerRecord int
+
+	// recordLine is the line of the file that the last record read started on
+	recordLine int
 }
 
 // OpenCSVReader opens a reader at a given path within a given filesys.  The CSVFileInfo should describe the csv file
@@ -178,6 +181,11 @@ func (csvr *CSVReader) ReadRow(ctx context.Context) (row.Row, error) {
 	return row.New(csvr.nbf, csvr.sch, taggedVals)
 }
 
+// LineNumber returns the line of the file that the last row read started on, counting from 1
+func (csvr *CSVReader) LineNumber() int {
+	return csvr.recordLine
+}
+
 // GetSchema gets the schema of the rows that this reader will return
 func (csvr *CSVReader) GetSchema() schema.Schema {
 	return csvr.sch
@@ -275,6 +283,9 @@ func (csvr *CSVReader) csvReadRecords(dst []*string) ([]*string, error) {
 		return nil, err
 	}
 
+	// the header line isn't counted by numLine
+	csvr.recordLine = csvr.numLine + 1
+
 	// nullString indicates whether to interpret an empty string as a NULL
 	// only empty strings escaped with double quotes will be non-null
 	nullString := make(map[int]bool)
@@ -17,6 +17,7 @@ package csv
 import (
 	"context"
 	"io"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -167,3 +168,30 @@ func readTestRows(t *testing.T, inputStr string, info *CSVFileInfo) ([]row.Row,
 
 	return rows, badRows, err
 }
+
+func TestReaderLineNumbers(t *testing.T) {
+	const path = "/file.csv"
+	fs := filesys.NewInMemFS(nil, map[string][]byte{path: []byte(PersonDB3)}, "/")
+	csvR, err := OpenCSVReader(types.Format_7_18, path, fs, NewCSVInfo())
+	if err != nil {
+		t.Fatal("Could not open reader", err)
+	}
+	defer csvR.Close(context.Background())
+
+	var lineNums []int
+	for {
+		_, err := csvR.ReadRow(context.Background())
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+		lineNums = append(lineNums, csvR.LineNumber())
+	}
+
+	// blank lines are counted, and a record with a quoted newline is numbered by the line it starts on
+	expected := []int{3, 5, 7, 9}
+	if !reflect.DeepEqual(lineNums, expected) {
+		t.Error("Unexpected line numbers. expected:", expected, "actual:", lineNums)
+	}
+}
@@ -0,0 +1,395 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/shopspring/decimal"
+	"github.com/tealeg/xlsx"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+const (
+	// MaxSheetNameLen is the maximum length of the name of a sheet. Longer table names are truncated.
+	MaxSheetNameLen = 31
+
+	// maxSheetRows is the maximum number of rows of a sheet, including its header row
+	maxSheetRows = 1048576
+
+	// maxExactInt is the largest integer that excel, which stores every number as a double, can store exactly.
+	// Integers of larger magnitude are written as text.
+	maxExactInt = 1 << 53
+
+	// maxExactDecimalDigits is the number of significant digits of a double. Decimals with more digits are written
+	// as text.
+	maxExactDecimalDigits = 15
+
+	// timeFormat is the number format of TIME cells, which can be longer than a day
+	timeFormat = "[h]:mm:ss"
+
+	corePropertiesPart = "docProps/core.xml"
+)
+
+// DocProperties are the core document properties of a workbook
+type DocProperties struct {
+	Title       string
+	Description string
+	// CommitHash is the hash of the commit the tables of the workbook were exported from. It is written as the
+	// identifier of the workbook.
+	CommitHash string
+}
+
+// XLSXWorkbook is an xlsx file being written, with a sheet per XLSXWriter. Nothing is written until the workbook is
+// closed.
+type XLSXWorkbook struct {
+	wr    io.WriteCloser
+	file  *xlsx.File
+	props DocProperties
+}
+
+// OpenXLSXWorkbook creates the xlsx file at |path| in |fs| for writing a workbook.
+func OpenXLSXWorkbook(path string, fs filesys.WritableFS) (*XLSXWorkbook, error) {
+	err := fs.MkDirs(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	wr, err := fs.OpenForWrite(path, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewXLSXWorkbook(wr), nil
+}
+
+// NewXLSXWorkbook returns an XLSXWorkbook that is written to |wr| when it's closed.
+func NewXLSXWorkbook(wr io.WriteCloser) *XLSXWorkbook {
+	return &XLSXWorkbook{wr: wr, file: xlsx.NewFile()}
+}
+
+// SetProperties sets the document properties written with the workbook
+func (wb *XLSXWorkbook) SetProperties(props DocProperties) {
+	wb.props = props
+}
+
+// NewSheetWriter adds a sheet named |name| to the workbook, and returns a writer of rows of |sch| to it. The first
+// row of the sheet is the names of the columns of |sch|.
+func (wb *XLSXWorkbook) NewSheetWriter(name string, sch schema.Schema) (*XLSXWriter, error) {
+	if wb.wr == nil {
+		return nil, errors.New("already closed")
+	}
+
+	sheetName := SheetName(name)
+	sheet, err := wb.file.AddSheet(sheetName)
+	if err != nil {
+		return nil, fmt.Errorf("table '%s' can't be written to sheet '%s': %w", name, sheetName, err)
+	}
+
+	var tags []uint64
+	var encoders []cellEncoder
+	header := sheet.AddRow()
+	err = sch.GetAllCols().Iter(func(tag uint64, col schema.Column) (stop bool, err error) {
+		header.AddCell().SetString(col.Name)
+		tags = append(tags, tag)
+		encoders = append(encoders, cellEncoderForType(col.TypeInfo))
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &XLSXWriter{wb: wb, sheet: sheet, sch: sch, tags: tags, encoders: encoders, rows: 1}, nil
+}
+
+// Close writes the workbook, with a sheet per writer, and closes the file it's written to
+func (wb *XLSXWorkbook) Close(ctx context.Context) error {
+	if wb.wr == nil {
+		return errors.New("already closed")
+	}
+
+	err := wb.write()
+	closeErr := wb.wr.Close()
+	wb.wr = nil
+
+	if err != nil {
+		return err
+	}
+	return closeErr
+}
+
+func (wb *XLSXWorkbook) write() error {
+	parts, err := wb.file.MarshallParts()
+	if err != nil {
+		return err
+	}
+	parts[corePropertiesPart] = coreProperties(wb.props, time.Now())
+
+	partNames := make([]string, 0, len(parts))
+	for partName := range parts {
+		partNames = append(partNames, partName)
+	}
+	sort.Strings(partNames)
+
+	zipWr := zip.NewWriter(wb.wr)
+	for _, partName := range partNames {
+		w, err := zipWr.Create(partName)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(w, parts[partName])
+		if err != nil {
+			return err
+		}
+	}
+
+	return zipWr.Close()
+}
+
+// coreProperties returns the docProps/core.xml part of a workbook with the properties |props|, created at |created|
+func coreProperties(props DocProperties, created time.Time) string {
+	sb := &strings.Builder{}
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcmitype="http://purl.org/dc/dcmitype/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">`)
+
+	element := func(name, val string) {
+		if val == "" {
+			return
+		}
+		buf := &bytes.Buffer{}
+		_ = xml.EscapeText(buf, []byte(val))
+		sb.WriteString(fmt.Sprintf("<%s>%s</%s>", name, buf.String(), name))
+	}
+	element("dc:title", props.Title)
+	element("dc:description", props.Description)
+	element("dc:identifier", props.CommitHash)
+	element("dc:creator", "dolt")
+	sb.WriteString(fmt.Sprintf(`<dcterms:created xsi:type="dcterms:W3CDTF">%s</dcterms:created>`, created.UTC().Format(time.RFC3339)))
+	sb.WriteString(`</cp:coreProperties>`)
+
+	return sb.String()
+}
+
+// SheetName returns the name of the sheet that the table |tableName| is written to
+func SheetName(tableName string) string {
+	runes := []rune(tableName)
+	if len(runes) > MaxSheetNameLen {
+		return string(runes[:MaxSheetNameLen])
+	}
+	return tableName
+}
+
+// XLSXWriter is a TableWriteCloser implementation for writing a sheet of an xlsx workbook. Numbers, dates, times and
+// booleans are written as cells of those types, and values of every other type are written as text.
+type XLSXWriter struct {
+	wb       *XLSXWorkbook
+	owned    bool
+	sheet    *xlsx.Sheet
+	sch      schema.Schema
+	tags     []uint64
+	encoders []cellEncoder
+	rows     int
+}
+
+// OpenXLSXWriter creates the xlsx file at |path| in |fs| for writing rows of |outSch| to a sheet named |sheetName|.
+// The workbook is written when the writer is closed.
+func OpenXLSXWriter(path string, fs filesys.WritableFS, sheetName string, outSch schema.Schema) (*XLSXWriter, error) {
+	wb, err := OpenXLSXWorkbook(path, fs)
+	if err != nil {
+		return nil, err
+	}
+
+	xlw, err := wb.NewSheetWriter(sheetName, outSch)
+	if err != nil {
+		wb.wr.Close()
+		return nil, err
+	}
+	xlw.owned = true
+
+	return xlw, nil
+}
+
+// Workbook returns the workbook that this writer writes a sheet of
+func (xlw *XLSXWriter) Workbook() *XLSXWorkbook {
+	return xlw.wb
+}
+
+// GetSchema gets the schema of the rows that this writer writes
+func (xlw *XLSXWriter) GetSchema() schema.Schema {
+	return xlw.sch
+}
+
+// WriteRow will write a row to a table
+func (xlw *XLSXWriter) WriteRow(ctx context.Context, r row.Row) error {
+	if xlw.sheet == nil {
+		return errors.New("already closed")
+	}
+	if xlw.rows >= maxSheetRows {
+		return fmt.Errorf("sheet '%s' is full; a sheet can have at most %d rows", xlw.sheet.Name, maxSheetRows-1)
+	}
+
+	xlRow := xlw.sheet.AddRow()
+	for i, tag := range xlw.tags {
+		cell := xlRow.AddCell()
+
+		val, ok := r.GetColVal(tag)
+		if !ok || types.IsNull(val) {
+			continue
+		}
+
+		err := xlw.encoders[i](cell, val)
+		if err != nil {
+			return err
+		}
+	}
+
+	xlw.rows++
+	return nil
+}
+
+// Close should flush all writes, release resources being held. The workbook is written if this writer created it,
+// otherwise it's written when the workbook is closed.
+func (xlw *XLSXWriter) Close(ctx context.Context) error {
+	if xlw.sheet == nil {
+		return errors.New("already closed")
+	}
+	xlw.sheet = nil
+
+	if xlw.owned {
+		return xlw.wb.Close(ctx)
+	}
+	return nil
+}
+
+// cellEncoder sets the value of an xlsx cell to a non-null value of a column
+type cellEncoder func(cell *xlsx.Cell, v types.Value) error
+
+// cellEncoderForType returns the cellEncoder of the values of columns of the type |ti|
+func cellEncoderForType(ti typeinfo.TypeInfo) cellEncoder {
+	switch ti.GetTypeIdentifier() {
+	case typeinfo.BoolTypeIdentifier:
+		return func(cell *xlsx.Cell, v types.Value) error {
+			cell.SetBool(bool(v.(types.Bool)))
+			return nil
+		}
+
+	case typeinfo.IntTypeIdentifier, typeinfo.YearTypeIdentifier:
+		return func(cell *xlsx.Cell, v types.Value) error {
+			n := int64(v.(types.Int))
+			if n > maxExactInt || n < -maxExactInt {
+				cell.SetString(fmt.Sprintf("%d", n))
+			} else {
+				cell.SetInt64(n)
+			}
+			return nil
+		}
+
+	case typeinfo.UintTypeIdentifier, typeinfo.BitTypeIdentifier:
+		return func(cell *xlsx.Cell, v types.Value) error {
+			n := uint64(v.(types.Uint))
+			if n > maxExactInt {
+				cell.SetString(fmt.Sprintf("%d", n))
+			} else {
+				cell.SetInt64(int64(n))
+			}
+			return nil
+		}
+
+	case typeinfo.FloatTypeIdentifier:
+		return func(cell *xlsx.Cell, v types.Value) error {
+			f := float64(v.(types.Float))
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				cell.SetString(fmt.Sprintf("%v", f))
+			} else {
+				cell.SetFloat(f)
+			}
+			return nil
+		}
+
+	case typeinfo.DecimalTypeIdentifier:
+		scale := int32(ti.ToSqlType().(sql.DecimalType).Scale())
+		numFmt := "0"
+		if scale > 0 {
+			numFmt += "." + strings.Repeat("0", int(scale))
+		}
+		return func(cell *xlsx.Cell, v types.Value) error {
+			dec := decimal.Decimal(v.(types.Decimal))
+			if len(dec.Coefficient().String()) > maxExactDecimalDigits {
+				cell.SetString(dec.StringFixed(scale))
+			} else {
+				f, _ := dec.Float64()
+				cell.SetFloatWithFormat(f, numFmt)
+			}
+			return nil
+		}
+
+	case typeinfo.DatetimeTypeIdentifier:
+		numFmt := xlsx.DefaultDateTimeFormat
+		if ti.Equals(typeinfo.DateType) {
+			numFmt = xlsx.DefaultDateFormat
+		}
+		return func(cell *xlsx.Cell, v types.Value) error {
+			t := time.Time(v.(types.Timestamp)).UTC()
+			cell.SetDateTimeWithFormat(xlsx.TimeToExcelTime(t, false), numFmt)
+			return nil
+		}
+
+	case typeinfo.TimeTypeIdentifier:
+		// times are stored as a number of microseconds, and excel stores them as a fraction of a day
+		return func(cell *xlsx.Cell, v types.Value) error {
+			micros := int64(v.(types.Int))
+			if micros < 0 {
+				return encodeFormatted(ti)(cell, v)
+			}
+			cell.SetDateTimeWithFormat(float64(micros)/float64(24*time.Hour/time.Microsecond), timeFormat)
+			return nil
+		}
+	}
+
+	return encodeFormatted(ti)
+}
+
+// encodeFormatted returns the cellEncoder which writes values of the type |ti| as text
+func encodeFormatted(ti typeinfo.TypeInfo) cellEncoder {
+	return func(cell *xlsx.Cell, v types.Value) error {
+		str, err := ti.FormatValue(v)
+		if err != nil {
+			return err
+		}
+		if str != nil {
+			cell.SetString(*str)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,261 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tealeg/xlsx"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/row"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestWriteTypedCells(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	dec, err := typeinfo.FromSqlType(sql.MustCreateDecimalType(10, 2))
+	require.NoError(t, err)
+	bigDec, err := typeinfo.FromSqlType(sql.MustCreateDecimalType(30, 5))
+	require.NoError(t, err)
+	enum, err := typeinfo.FromSqlType(sql.MustCreateEnumType([]string{"red", "green"}, sql.Collation_Default))
+	require.NoError(t, err)
+
+	sch := mustSchema(t,
+		mustCol(t, "id", 0, typeinfo.Int64Type, true),
+		mustCol(t, "name", 1, typeinfo.StringDefaultType, false),
+		mustCol(t, "age", 2, typeinfo.Uint8Type, false),
+		mustCol(t, "score", 3, typeinfo.Float64Type, false),
+		mustCol(t, "active", 4, typeinfo.BoolType, false),
+		mustCol(t, "born", 5, typeinfo.DateType, false),
+		mustCol(t, "updated", 6, typeinfo.DatetimeType, false),
+		mustCol(t, "wake", 7, typeinfo.TimeType, false),
+		mustCol(t, "price", 8, dec, false),
+		mustCol(t, "balance", 9, bigDec, false),
+		mustCol(t, "color", 10, enum, false),
+	)
+
+	rows := []row.TaggedValues{
+		{
+			0:  types.Int(1),
+			1:  types.String("tim"),
+			2:  types.Uint(40),
+			3:  types.Float(9.5),
+			4:  types.Bool(true),
+			5:  types.Timestamp(time.Date(1980, 6, 1, 0, 0, 0, 0, time.UTC)),
+			6:  types.Timestamp(time.Date(2021, 7, 14, 10, 30, 15, 0, time.UTC)),
+			7:  types.Int(int64(7*time.Hour+15*time.Minute) / int64(time.Microsecond)),
+			8:  types.Decimal(decimal.RequireFromString("-12.34")),
+			9:  types.Decimal(decimal.RequireFromString("-1234567890123456789012.34567")),
+			10: types.Uint(2),
+		},
+		{
+			0: types.Int(1 << 60),
+			1: types.String("brian"),
+		},
+	}
+
+	buf := &bufferCloser{}
+	wr := newTestWorkbookWriter(t, buf, "people", sch)
+	for _, taggedVals := range rows {
+		r, err := row.New(vrw.Format(), sch, taggedVals)
+		require.NoError(t, err)
+		require.NoError(t, wr.WriteRow(ctx, r))
+	}
+	require.NoError(t, wr.Close(ctx))
+	require.NoError(t, wr.Workbook().Close(ctx))
+
+	f, err := xlsx.OpenBinary(buf.Bytes())
+	require.NoError(t, err)
+	sheet, ok := f.Sheet["people"]
+	require.True(t, ok)
+	require.Len(t, sheet.Rows, 3)
+
+	var header []string
+	for _, cell := range sheet.Rows[0].Cells {
+		header = append(header, cell.Value)
+	}
+	assert.Equal(t, []string{"id", "name", "age", "score", "active", "born", "updated", "wake", "price", "balance", "color"}, header)
+
+	cells := sheet.Rows[1].Cells
+	assert.Equal(t, xlsx.CellTypeNumeric, cells[0].Type())
+	assert.Equal(t, "1", cells[0].Value)
+	assert.Equal(t, xlsx.CellTypeString, cells[1].Type())
+	assert.Equal(t, "tim", cells[1].Value)
+	assert.Equal(t, xlsx.CellTypeNumeric, cells[2].Type())
+	assert.Equal(t, "40", cells[2].Value)
+	assert.Equal(t, "9.5", cells[3].Value)
+	assert.Equal(t, xlsx.CellTypeBool, cells[4].Type())
+	assert.True(t, cells[4].Bool())
+
+	assert.True(t, cells[5].IsTime())
+	born, err := cells[5].GetTime(false)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Date(1980, 6, 1, 0, 0, 0, 0, time.UTC), born, time.Millisecond)
+	assert.True(t, cells[6].IsTime())
+	updated, err := cells[6].GetTime(false)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Date(2021, 7, 14, 10, 30, 15, 0, time.UTC), updated, time.Millisecond)
+	assert.True(t, cells[7].IsTime())
+	wake, err := cells[7].Float()
+	require.NoError(t, err)
+	assert.InDelta(t, 7.25/24, wake, 1e-9)
+
+	assert.Equal(t, xlsx.CellTypeNumeric, cells[8].Type())
+	assert.Equal(t, "-12.34", cells[8].Value)
+	assert.Equal(t, "0.00", cells[8].NumFmt)
+	assert.Equal(t, xlsx.CellTypeString, cells[9].Type())
+	assert.Equal(t, "-1234567890123456789012.34567", cells[9].Value)
+	assert.Equal(t, xlsx.CellTypeString, cells[10].Type())
+	assert.Equal(t, "green", cells[10].Value)
+
+	// integers which excel can't store exactly are written as text, and nulls as empty cells
+	cells = sheet.Rows[2].Cells
+	assert.Equal(t, xlsx.CellTypeString, cells[0].Type())
+	assert.Equal(t, "1152921504606846976", cells[0].Value)
+	for _, cell := range cells[2:] {
+		assert.Equal(t, "", cell.Value)
+	}
+}
+
+func TestWriteWorkbook(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+
+	sch := mustSchema(t,
+		mustCol(t, "id", 0, typeinfo.Int64Type, true),
+		mustCol(t, "name", 1, typeinfo.StringDefaultType, false),
+	)
+
+	fs := filesys.EmptyInMemFS("/")
+	wb, err := OpenXLSXWorkbook("/exports/tables.xlsx", fs)
+	require.NoError(t, err)
+	wb.SetProperties(DocProperties{
+		Title:       "tables",
+		Description: "Exported from commit abcdef",
+		CommitHash:  "abcdef",
+	})
+
+	tableNames := []string{"first", "a_table_name_much_longer_than_a_sheet_name"}
+	for i, tableName := range tableNames {
+		wr, err := wb.NewSheetWriter(tableName, sch)
+		require.NoError(t, err)
+		for j := 0; j <= i; j++ {
+			r, err := row.New(vrw.Format(), sch, row.TaggedValues{0: types.Int(j), 1: types.String(tableName)})
+			require.NoError(t, err)
+			require.NoError(t, wr.WriteRow(ctx, r))
+		}
+		require.NoError(t, wr.Close(ctx))
+	}
+
+	_, err = wb.NewSheetWriter("first", sch)
+	assert.Error(t, err)
+
+	require.NoError(t, wb.Close(ctx))
+	assert.Error(t, wb.Close(ctx))
+
+	data, err := fs.ReadFile("/exports/tables.xlsx")
+	require.NoError(t, err)
+
+	f, err := xlsx.OpenBinary(data)
+	require.NoError(t, err)
+	require.Len(t, f.Sheets, 2)
+	assert.Equal(t, "first", f.Sheets[0].Name)
+	assert.Len(t, f.Sheets[0].Rows, 2)
+	assert.Equal(t, "a_table_name_much_longer_than_a", f.Sheets[1].Name)
+	assert.Len(t, f.Sheets[1].Rows, 3)
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var core string
+	for _, zf := range zr.File {
+		if zf.Name == "docProps/core.xml" {
+			rd, err := zf.Open()
+			require.NoError(t, err)
+			contents, err := ioutil.ReadAll(rd)
+			require.NoError(t, err)
+			core = string(contents)
+		}
+	}
+	assert.Contains(t, core, "<dc:identifier>abcdef</dc:identifier>")
+	assert.Contains(t, core, "<dc:description>Exported from commit abcdef</dc:description>")
+	assert.Contains(t, core, "<dc:title>tables</dc:title>")
+}
+
+func TestOpenXLSXWriter(t *testing.T) {
+	ctx := context.Background()
+	vrw := types.NewMemoryValueStore()
+	sch := mustSchema(t, mustCol(t, "id", 0, typeinfo.Int64Type, true))
+
+	fs := filesys.EmptyInMemFS("/")
+	wr, err := OpenXLSXWriter("/table.xlsx", fs, "table", sch)
+	require.NoError(t, err)
+	r, err := row.New(vrw.Format(), sch, row.TaggedValues{0: types.Int(7)})
+	require.NoError(t, err)
+	require.NoError(t, wr.WriteRow(ctx, r))
+	require.NoError(t, wr.Close(ctx))
+	assert.Error(t, wr.Close(ctx))
+
+	data, err := fs.ReadFile("/table.xlsx")
+	require.NoError(t, err)
+	f, err := xlsx.OpenBinary(data)
+	require.NoError(t, err)
+	require.Len(t, f.Sheets, 1)
+	require.Len(t, f.Sheets[0].Rows, 2)
+	assert.Equal(t, "7", f.Sheets[0].Rows[1].Cells[0].Value)
+}
+
+type bufferCloser struct {
+	bytes.Buffer
+}
+
+func (b *bufferCloser) Close() error {
+	return nil
+}
+
+func newTestWorkbookWriter(t *testing.T, buf *bufferCloser, sheetName string, sch schema.Schema) *XLSXWriter {
+	wr, err := NewXLSXWorkbook(buf).NewSheetWriter(sheetName, sch)
+	require.NoError(t, err)
+	return wr
+}
+
+func mustCol(t *testing.T, name string, tag uint64, ti typeinfo.TypeInfo, pk bool) schema.Column {
+	var constraints []schema.ColConstraint
+	if pk {
+		constraints = append(constraints, schema.NotNullConstraint{})
+	}
+	col, err := schema.NewColumnWithTypeInfo(name, tag, ti, pk, "", false, "", constraints...)
+	require.NoError(t, err)
+	return col
+}
+
+func mustSchema(t *testing.T, cols ...schema.Column) schema.Schema {
+	sch, err := schema.SchemaFromCols(schema.NewColCollection(cols...))
+	require.NoError(t, err)
+	return sch
+}
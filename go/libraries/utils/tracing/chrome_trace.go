@@ -0,0 +1,124 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/uber/jaeger-client-go"
+)
+
+// ChromeTraceReporter is a jaeger.Reporter that writes finished spans to a file in the Trace Event Format read by
+// chrome://tracing, Perfetto and speedscope. The events of each trace share a thread id, so that the spans of a query
+// are displayed together.
+type ChromeTraceReporter struct {
+	mu         sync.Mutex
+	w          io.WriteCloser
+	wroteEvent bool
+	err        error
+}
+
+var _ jaeger.Reporter = (*ChromeTraceReporter)(nil)
+
+// traceEvent is a complete event of the Trace Event Format, which records a span by its start and duration in
+// microseconds.
+type traceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat"`
+	Phase string                 `json:"ph"`
+	Ts    int64                  `json:"ts"`
+	Dur   int64                  `json:"dur"`
+	Pid   int                    `json:"pid"`
+	Tid   uint32                 `json:"tid"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+// NewChromeTraceReporter returns a ChromeTraceReporter that writes to |w|, which is closed when the reporter is.
+func NewChromeTraceReporter(w io.WriteCloser) *ChromeTraceReporter {
+	r := &ChromeTraceReporter{w: w}
+	_, r.err = io.WriteString(w, "[\n")
+	return r
+}
+
+// Report implements jaeger.Reporter.
+func (r *ChromeTraceReporter) Report(span *jaeger.Span) {
+	spanCtx := span.SpanContext()
+	args := make(map[string]interface{}, len(span.Tags())+2)
+	for k, v := range span.Tags() {
+		switch v.(type) {
+		case string, bool, int, int64, uint64, float64:
+			args[k] = v
+		default:
+			args[k] = fmt.Sprint(v)
+		}
+	}
+	args["span_id"] = spanCtx.SpanID().String()
+	if spanCtx.ParentID() != 0 {
+		args["parent_id"] = spanCtx.ParentID().String()
+	}
+
+	data, err := json.Marshal(traceEvent{
+		Name:  span.OperationName(),
+		Cat:   "dolt",
+		Phase: "X",
+		Ts:    span.StartTime().UnixNano() / 1000,
+		Dur:   span.Duration().Microseconds(),
+		Pid:   1,
+		Tid:   uint32(spanCtx.TraceID().Low),
+		Args:  args,
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return
+	} else if err != nil {
+		r.err = err
+		return
+	}
+
+	if r.wroteEvent {
+		_, r.err = io.WriteString(r.w, ",\n")
+	}
+	if r.err == nil {
+		_, r.err = r.w.Write(data)
+		r.wroteEvent = true
+	}
+}
+
+// Close implements jaeger.Reporter. It ends the array of events and closes the underlying writer.
+func (r *ChromeTraceReporter) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err == nil {
+		_, r.err = io.WriteString(r.w, "\n]\n")
+	}
+	closeErr := r.w.Close()
+	if r.err == nil {
+		r.err = closeErr
+	}
+}
+
+// Err returns the first error encountered writing the trace, if any.
+func (r *ChromeTraceReporter) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
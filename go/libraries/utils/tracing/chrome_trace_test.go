@@ -0,0 +1,68 @@
+// Copyright 2021 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uber/jaeger-client-go"
+)
+
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestChromeTraceReporter(t *testing.T) {
+	buf := &closingBuffer{}
+	reporter := NewChromeTraceReporter(buf)
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), reporter)
+
+	root := tracer.StartSpan("query")
+	child := tracer.StartSpan("dolt.table_read", opentracing.ChildOf(root.Context()))
+	child.SetTag("table", "people")
+	child.SetTag("rows", uint64(3))
+	child.Finish()
+	root.Finish()
+	require.NoError(t, closer.Close())
+
+	require.NoError(t, reporter.Err())
+	assert.True(t, buf.closed)
+
+	var events []traceEvent
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &events))
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "dolt.table_read", events[0].Name)
+	assert.Equal(t, "X", events[0].Phase)
+	assert.Equal(t, "people", events[0].Args["table"])
+	assert.Equal(t, float64(3), events[0].Args["rows"])
+	assert.Equal(t, events[1].Args["span_id"], events[0].Args["parent_id"])
+	assert.Equal(t, events[0].Tid, events[1].Tid)
+
+	assert.Equal(t, "query", events[1].Name)
+	assert.NotContains(t, events[1].Args, "parent_id")
+	assert.GreaterOrEqual(t, events[1].Dur, events[0].Dur)
+}
@@ -304,6 +304,15 @@ func (t Tuple) Format() *NomsBinFormat {
 	return t.format()
 }
 
+// Compact returns a copy of the tuple whose data is no larger than its encoding, and isn't shared with any other value.
+// Tuples are encoded into buffers much larger than most of them need, so tuples which are held in memory in large
+// numbers should be compacted.
+func (t Tuple) Compact() Tuple {
+	buff := make([]byte, len(t.buff))
+	copy(buff, t.buff)
+	return Tuple{valueImpl{t.vrw, t.nbf, buff, nil}}
+}
+
 // Value interface
 func (t Tuple) Value(ctx context.Context) (Value, error) {
 	return t, nil
@@ -714,6 +723,10 @@ func (t Tuple) TupleLess(nbf *NomsBinFormat, otherTuple Tuple) (bool, error) {
 			}
 
 		default:
+			// readValue expects to read the kind of the value as well
+			dec.offset--
+			otherDec.offset--
+
 			v, err := dec.readValue(nbf)
 
 			if err != nil {
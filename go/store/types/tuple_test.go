@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
@@ -120,6 +121,20 @@ func TestTupleEquality(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestTupleCompact(t *testing.T) {
+	tpl, err := NewTuple(Format_7_18, String("aoeu"), Int(-1234), Uint(1234))
+	require.NoError(t, err)
+	assert.True(t, cap(tpl.buff) > len(tpl.buff))
+
+	compacted := tpl.Compact()
+	assert.True(t, tpl.Equals(compacted))
+	assert.Equal(t, len(compacted.buff), cap(compacted.buff))
+
+	v, err := compacted.Get(1)
+	require.NoError(t, err)
+	assert.Equal(t, Int(-1234), v)
+}
+
 func TestTupleLess(t *testing.T) {
 	tests := []struct {
 		vals1    []Value
@@ -180,6 +195,34 @@ func TestTupleLess(t *testing.T) {
 	}
 }
 
+func TestTupleLessBlobs(t *testing.T) {
+	vrw := newTestValueStore()
+	blob1, err := NewBlob(context.Background(), vrw, strings.NewReader("abc"))
+	require.NoError(t, err)
+	blob2, err := NewBlob(context.Background(), vrw, strings.NewReader("def"))
+	require.NoError(t, err)
+
+	tpl1, err := NewTuple(Format_7_18, blob1, Int(1234))
+	require.NoError(t, err)
+	tpl2, err := NewTuple(Format_7_18, blob1, Int(1235))
+	require.NoError(t, err)
+
+	less, err := tpl1.Less(Format_7_18, tpl2)
+	require.NoError(t, err)
+	assert.True(t, less)
+	less, err = tpl2.Less(Format_7_18, tpl1)
+	require.NoError(t, err)
+	assert.False(t, less)
+
+	tpl3, err := NewTuple(Format_7_18, blob2, Int(1234))
+	require.NoError(t, err)
+	expected, err := blob1.Less(Format_7_18, blob2)
+	require.NoError(t, err)
+	less, err = tpl1.Less(Format_7_18, tpl3)
+	require.NoError(t, err)
+	assert.Equal(t, expected, less)
+}
+
 func TestTupleStartsWith(t *testing.T) {
 	tests := []struct {
 		full     []Value